@@ -2,13 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/kndrad/piccrack/config"
 	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
 	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
 	"github.com/kndrad/piccrack/pkg/retry"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 
 	"github.com/kndrad/piccrack/cmd/logger"
@@ -30,35 +35,31 @@ var startCmd = &cobra.Command{
 			return fmt.Errorf("config load: %w", err)
 		}
 
-		fmt.Printf("CONFIG: %#v\n", cfg)
-
-		pool, err := database.Pool(ctx, cfg.Database)
+		stopWords := cfg.Filters.StopWords()
+		filter, err := textproc.LoadFilter(stopWords, cfg.Filters.Options())
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
+			l.Error("Loading words filter", "err", err.Error())
 
-			return fmt.Errorf("database pool: %w", err)
+			return fmt.Errorf("load filter: %w", err)
 		}
-		defer pool.Close()
 
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
+		images, err := cfg.Images.Store()
+		if err != nil {
+			l.Error("Loading image store", "err", err.Error())
 
-			return fmt.Errorf("database ping: %w", err)
+			return fmt.Errorf("image store: %w", err)
 		}
 
-		db, err := database.Connect(ctx, pool)
+		store, collectors, closeStore, err := openStore(ctx, cfg, l)
 		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
-
-			return fmt.Errorf("database connection: %w", err)
+			return err
 		}
-		defer db.Close(ctx)
+		defer closeStore()
 
-		q := database.New(db)
-		svc := apiv1.NewService(q, l)
+		svc := apiv1.NewService(store, filter, stopWords, images, l)
 
 		// Create server instance
-		srv, err := apiv1.New(cfg.HTTP, svc, l)
+		srv, err := apiv1.New(cfg.HTTP, svc, l, collectors...)
 		if err != nil {
 			l.Error("Failed to init new http server", "err", err)
 
@@ -76,6 +77,83 @@ var startCmd = &cobra.Command{
 	},
 }
 
+var (
+	autoMigrate bool
+	storageName string
+)
+
 func init() {
+	startCmd.Flags().BoolVar(&autoMigrate, "migrate", false, "apply pending database migrations before start")
+	startCmd.Flags().StringVar(&storageName, "storage", "postgres", "storage of the server: postgres or memory, which keeps data until exit")
+
 	rootCmd.AddCommand(startCmd)
 }
+
+// openStore returns the store of storageName with collectors of its metrics and
+// a function closing it.
+func openStore(ctx context.Context, cfg *config.Config, l *slog.Logger) (database.Store, []prometheus.Collector, func(), error) {
+	switch storageName {
+	case "memory":
+		l.Warn("Serving from memory, data is lost on exit")
+
+		return memory.New(), nil, func() {}, nil
+	case "postgres":
+	default:
+		return nil, nil, nil, fmt.Errorf("%w: %q", errUnknownStorage, storageName)
+	}
+
+	pool, err := database.Pool(ctx, cfg.Database)
+	if err != nil {
+		l.Error("Loading database pool", "err", err.Error())
+
+		return nil, nil, nil, fmt.Errorf("database pool: %w", err)
+	}
+
+	if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
+		pool.Close()
+		l.Error("Pinging database", "err", err.Error())
+
+		return nil, nil, nil, fmt.Errorf("database ping: %w", err)
+	}
+
+	if err := checkSchema(cfg.Database, autoMigrate, l); err != nil {
+		pool.Close()
+		l.Error("Checking database schema", "err", err.Error())
+
+		return nil, nil, nil, fmt.Errorf("database schema: %w", err)
+	}
+
+	// Requests are served concurrently, each query acquires a connection of the pool.
+	return database.NewRepository(pool), []prometheus.Collector{database.NewPoolCollector(pool)}, pool.Close, nil
+}
+
+var errUnknownStorage = errors.New("unknown storage")
+
+// checkSchema applies pending migrations if up is true. Server isn't started against
+// a schema migrated by a newer binary.
+func checkSchema(cfg config.DatabaseConfig, up bool, l *slog.Logger) error {
+	m, err := database.NewMigrator(database.DSN(cfg))
+	if err != nil {
+		return fmt.Errorf("new migrator: %w", err)
+	}
+	defer m.Close()
+
+	if up {
+		if err := m.Up(); err != nil {
+			return err
+		}
+	}
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if err := m.Check(); err != nil {
+		return err
+	}
+	if len(status.Pending) > 0 {
+		l.Warn("Database has pending migrations, run with --migrate or piccrack db migrate up", "version", status.Version, "latest", status.Latest)
+	}
+	l.Info("Database schema", "version", status.Version, "dirty", status.Dirty)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete words|phrases ID...",
+	Short: "Soft deletes batches.",
+	Long: `Soft deletes batches with their word occurrences and salaries, or phrases. Deleted
+batches are hidden until restored with restore command or purged with purge command.`,
+	Example: "piccrack batches delete words 12 13",
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args)
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		for _, id := range ids {
+			if err := svc.DeleteBatch(ctx, kind, id); err != nil {
+				l.Error("Failed to delete batch", "kind", kind, "id", id, "err", err.Error())
+
+				return fmt.Errorf("delete batch: %w", err)
+			}
+			l.Info("Deleted batch", "kind", kind, "id", id)
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+}
@@ -0,0 +1,98 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists batches of words and phrases.",
+	Long: `Lists batches of words and phrases, oldest first, with numbers of distinct words or
+phrases (ITEMS) and their occurrences (TOTAL).`,
+	Example: `piccrack batches list --type words --from 2024-01-01 --prefix backend`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, err := cmd.Flags().GetString("type")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		limit, err := cmd.Flags().GetInt32("limit")
+		if err != nil {
+			return fmt.Errorf("get int32: %w", err)
+		}
+
+		f := apiv1.BatchFilter{Prefix: prefix}
+		if kind != "" {
+			if f.Kind, err = apiv1.ParseBatchKind(kind); err != nil {
+				l.Error("Parsing type flag", "err", err.Error())
+
+				return err
+			}
+		}
+		if from != "" {
+			if f.From, err = apiv1.ParseTime(from); err != nil {
+				return fmt.Errorf("parse from: %w", err)
+			}
+		}
+		if to != "" {
+			if f.To, err = apiv1.ParseTime(to); err != nil {
+				return fmt.Errorf("parse to: %w", err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		rows, err := svc.ListBatches(ctx, limit, 0, f)
+		if err != nil {
+			l.Error("Failed to list batches", "err", err.Error())
+
+			return fmt.Errorf("list batches: %w", err)
+		}
+		for _, row := range rows {
+			fmt.Printf("TYPE: %s | ID: %d | NAME: %s | ITEMS: %d | TOTAL: %d | CREATED: %s\n",
+				row.Kind, row.ID, row.Name, row.Items, row.Total, row.CreatedAt.Time.Format(time.DateOnly),
+			)
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().String("type", "", "List only batches of type, words or phrases")
+	listCmd.Flags().String("from", "", "List batches created since date (YYYY-MM-DD or RFC 3339)")
+	listCmd.Flags().String("to", "", "List batches created before date (YYYY-MM-DD or RFC 3339)")
+	listCmd.Flags().String("prefix", "", "List batches with names starting with prefix")
+	listCmd.Flags().Int32("limit", 100, "Maximal number of listed batches")
+}
@@ -0,0 +1,55 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge words|phrases TARGET_ID SOURCE_ID",
+	Short: "Merges a batch into another batch.",
+	Long: `Moves words and salaries, or phrases, of source batch into target batch and deletes
+source batch. Counts of words in both batches are summed.`,
+	Example: "piccrack batches merge words 12 13",
+	Args:    cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args)
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		moved, err := svc.MergeBatches(ctx, kind, ids[0], ids[1])
+		if err != nil {
+			l.Error("Failed to merge batches", "err", err.Error())
+
+			return fmt.Errorf("merge batches: %w", err)
+		}
+		l.Info("Merged batches", "kind", kind, "target", ids[0], "source", ids[1], "moved", moved)
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,63 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently removes deleted rows.",
+	Long: `Permanently removes postings, batches, words, phrases, salaries and documents
+deleted more than --older-than days ago. Purged rows can't be restored.`,
+	Example: "piccrack batches purge --older-than 30",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		days, err := cmd.Flags().GetUint("older-than")
+		if err != nil {
+			return fmt.Errorf("get uint: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		row, err := svc.Purge(ctx, time.Duration(days)*24*time.Hour)
+		if err != nil {
+			l.Error("Failed to purge deleted rows", "err", err.Error())
+
+			return fmt.Errorf("purge: %w", err)
+		}
+		l.Info("Purged deleted rows",
+			"postings", row.Postings,
+			"word_batches", row.WordBatches,
+			"phrase_batches", row.PhraseBatches,
+			"words", row.Words,
+			"occurrences", row.Occurrences,
+			"phrases", row.Phrases,
+			"salaries", row.Salaries,
+			"documents", row.Documents,
+		)
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().Uint("older-than", 30, "Purge rows deleted more than this many days ago")
+}
@@ -0,0 +1,53 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:     "rename words|phrases ID NAME",
+	Short:   "Renames a batch.",
+	Example: "piccrack batches rename words 12 backend-2024",
+	Args:    cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args[:2])
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		row, err := svc.RenameBatch(ctx, kind, ids[0], args[2])
+		if err != nil {
+			l.Error("Failed to rename batch", "err", err.Error())
+
+			return fmt.Errorf("rename batch: %w", err)
+		}
+		l.Info("Renamed batch", "kind", kind, "id", row.ID, "name", row.Name)
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
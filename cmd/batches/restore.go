@@ -0,0 +1,56 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore words|phrases ID...",
+	Short: "Restores deleted batches.",
+	Long: `Restores deleted batches with rows deleted with them. Batches deleted with their
+posting are restored with the posting.`,
+	Example: "piccrack batches restore words 12 13",
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args)
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		for _, id := range ids {
+			if err := svc.RestoreBatch(ctx, kind, id); err != nil {
+				l.Error("Failed to restore batch", "kind", kind, "id", id, "err", err.Error())
+
+				return fmt.Errorf("restore batch: %w", err)
+			}
+			l.Info("Restored batch", "kind", kind, "id", id)
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+}
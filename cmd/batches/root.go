@@ -0,0 +1,76 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/kndrad/piccrack/config"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "batches",
+	Short: "Manages batches of words and phrases.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Help(); err != nil {
+			return fmt.Errorf("help display: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// connect returns service using storage configured in config file.
+// Returned close func must be called once service is no longer used.
+func connect(ctx context.Context, l *slog.Logger) (apiv1.Service, func(), error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+
+	s, err := storage.Open(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open storage: %w", err)
+	}
+
+	return apiv1.NewService(s, nil, nil, nil, l), func() {
+		s.Close()
+	}, nil
+}
+
+// parseIDs returns batch kind and IDs of args, e.g. words 1 2 3.
+func parseIDs(args []string) (apiv1.BatchKind, []int64, error) {
+	kind, err := apiv1.ParseBatchKind(args[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	ids := make([]int64, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("parse batch id %q: %w", arg, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return kind, ids, nil
+}
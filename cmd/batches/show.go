@@ -0,0 +1,61 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:     "show words|phrases ID",
+	Short:   "Displays a batch with its words or phrases.",
+	Example: "piccrack batches show words 12",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args)
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		batch, err := svc.GetBatch(ctx, kind, ids[0])
+		if err != nil {
+			l.Error("Failed to get batch", "err", err.Error())
+
+			return fmt.Errorf("get batch: %w", err)
+		}
+		fmt.Printf("TYPE: %s | ID: %d | NAME: %s | ITEMS: %d | TOTAL: %d | CREATED: %s\n",
+			batch.Kind, batch.ID, batch.Name, batch.Items, batch.Total, batch.CreatedAt.Time.Format(time.DateOnly),
+		)
+		for _, w := range batch.Words {
+			fmt.Printf("WORD: %s | COUNT: %d\n", w.Value, w.Count)
+		}
+		for _, p := range batch.Phrases {
+			fmt.Printf("PHRASE: %s\n", p.Value)
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,57 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split words|phrases ID NAME VALUE...",
+	Short: "Moves words or phrases of a batch into a new batch.",
+	Long: `Moves words (compared case insensitively) or phrases equal to values out of a batch
+into a new batch named NAME. New word batch has attributes of the batch, its salaries
+stay in the batch.`,
+	Example: `piccrack batches split words 12 frontend react vue
+piccrack batches split phrases 7 benefits "Private healthcare"`,
+	Args: cobra.MinimumNArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		kind, ids, err := parseIDs(args[:2])
+		if err != nil {
+			l.Error("Parsing arguments", "err", err.Error())
+
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		svc, closeConn, err := connect(ctx, l)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		split, err := svc.SplitBatch(ctx, kind, ids[0], args[2], args[3:])
+		if err != nil {
+			l.Error("Failed to split batch", "err", err.Error())
+
+			return fmt.Errorf("split batch: %w", err)
+		}
+		l.Info("Split batch", "kind", kind, "id", ids[0], "new_id", split.ID, "moved", split.Moved)
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+}
@@ -0,0 +1,202 @@
+package compare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/pkg/compare"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compares words or phrases of two sets of batches.",
+	Long: `Compares words (or phrases) of set A to set B and displays terms which appeared,
+disappeared, rose or fell significantly. Sets are selected by batch names, by time range
+of batch creation and by batch attributes. Changes are scored with log-odds z-score
+(positive if term is more frequent in B) and chi-square statistic.`,
+	Example: `piccrack compare --a-from 2024-03-01 --a-to 2024-04-01 --b-from 2024-04-01 --b-to 2024-05-01
+piccrack compare --a-location Warszawa --b-location Berlin --status appeared`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		a, err := setFilter(cmd, "a")
+		if err != nil {
+			return err
+		}
+		b, err := setFilter(cmd, "b")
+		if err != nil {
+			return err
+		}
+
+		opts := compare.DefaultOptions()
+		if opts.MinCount, err = cmd.Flags().GetInt64("min-count"); err != nil {
+			return fmt.Errorf("get int64: %w", err)
+		}
+		if opts.MinScore, err = cmd.Flags().GetFloat64("min-score"); err != nil {
+			return fmt.Errorf("get float64: %w", err)
+		}
+		statusName, err := cmd.Flags().GetString("status")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		status, err := compare.ParseStatus(statusName)
+		if err != nil {
+			return fmt.Errorf("parse status: %w", err)
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		phrases, err := cmd.Flags().GetBool("phrases")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+		asJSON, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+		filter, err := textproc.LoadFilter(cfg.Filters.StopWords(), cfg.Filters.Options())
+		if err != nil {
+			l.Error("Loading words filter", "err", err.Error())
+
+			return fmt.Errorf("load filter: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		store, err := storage.Open(ctx, cfg)
+		if err != nil {
+			l.Error("Opening storage", "driver", cfg.Storage.Driver, "err", err.Error())
+
+			return fmt.Errorf("open storage: %w", err)
+		}
+		defer store.Close()
+
+		svc := apiv1.NewService(store, filter, nil, nil, l)
+
+		var res compare.Result
+		if phrases {
+			res, err = svc.ComparePhrases(ctx, a, b, opts)
+		} else {
+			res, err = svc.CompareWords(ctx, a, b, opts)
+		}
+		if err != nil {
+			l.Error("Failed to compare sets", "err", err.Error())
+
+			return fmt.Errorf("compare: %w", err)
+		}
+		res.Terms = res.Filter(status)
+		if len(res.Terms) > limit {
+			res.Terms = res.Terms[:limit]
+		}
+		l.Info("Compared sets", "total_a", res.TotalA, "total_b", res.TotalB, "terms", len(res.Terms))
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("encode: %w", err)
+			}
+
+			return nil
+		}
+		for _, t := range res.Terms {
+			fmt.Printf("TERM: %s | %s | A: %d | B: %d | LOG-ODDS: %+.2f | CHI2: %.2f\n",
+				t.Term, strings.ToUpper(string(t.Status)), t.A, t.B, t.LogOdds, t.ChiSquare,
+			)
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+
+	for _, set := range []string{"a", "b"} {
+		upper := strings.ToUpper(set)
+		rootCmd.Flags().StringSlice(set+"-batch", nil, "Names of batches in set "+upper)
+		rootCmd.Flags().String(set+"-from", "", "Include batches of set "+upper+" created at or after date (2006-01-02) or RFC 3339 time")
+		rootCmd.Flags().String(set+"-to", "", "Include batches of set "+upper+" created before date (2006-01-02) or RFC 3339 time")
+		rootCmd.Flags().String(set+"-language", "", "Include only terms of set "+upper+" in language (ISO 639-1 code)")
+		rootCmd.Flags().String(set+"-seniority", "", "Include only batches of set "+upper+" of seniority")
+		rootCmd.Flags().String(set+"-location", "", "Include only batches of set "+upper+" of location (city)")
+		rootCmd.Flags().String(set+"-work-mode", "", "Include only batches of set "+upper+" of work mode")
+		rootCmd.Flags().String(set+"-tag", "", "Include only batches of set "+upper+" with tag")
+		rootCmd.Flags().String(set+"-collection", "", "Include only batches of set "+upper+" in collection")
+	}
+	rootCmd.Flags().Bool("phrases", false, "Compare phrases instead of words")
+	rootCmd.Flags().Int64("min-count", 2, "Minimal count of a term which appeared or disappeared")
+	rootCmd.Flags().Float64("min-score", 1.96, "Minimal absolute log-odds z-score of a term which rose or fell")
+	rootCmd.Flags().String("status", "", "Display only terms which appeared, disappeared, rose or fell")
+	rootCmd.Flags().Int("limit", 50, "Maximal number of displayed terms")
+	rootCmd.Flags().Bool("json", false, "Print result as JSON")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// setFilter returns filter of set named set from flags of cmd, e.g. --a-batch, --a-from.
+func setFilter(cmd *cobra.Command, set string) (apiv1.SetFilter, error) {
+	var f apiv1.SetFilter
+
+	batches, err := cmd.Flags().GetStringSlice(set + "-batch")
+	if err != nil {
+		return f, fmt.Errorf("get string slice: %w", err)
+	}
+	f.Batches = batches
+
+	for name, v := range map[string]*string{
+		"language":   &f.Language,
+		"seniority":  &f.Seniority,
+		"location":   &f.Location,
+		"work-mode":  &f.WorkMode,
+		"tag":        &f.Tag,
+		"collection": &f.Collection,
+	} {
+		if *v, err = cmd.Flags().GetString(set + "-" + name); err != nil {
+			return f, fmt.Errorf("get string: %w", err)
+		}
+	}
+
+	for name, t := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		v, err := cmd.Flags().GetString(set + "-" + name)
+		if err != nil {
+			return f, fmt.Errorf("get string: %w", err)
+		}
+		if v == "" {
+			continue
+		}
+		if *t, err = apiv1.ParseTime(v); err != nil {
+			return f, fmt.Errorf("parse --%s-%s: %w", set, name, err)
+		}
+	}
+
+	return f, nil
+}
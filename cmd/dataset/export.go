@@ -0,0 +1,91 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/internal/archive"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [FILE]",
+	Short: "Exports the dataset of the database to an archive.",
+	Long: `Writes every posting, document, batch, word, phrase, salary, tag and collection
+of the database to a zip archive of JSON lines files with a manifest, which can be
+imported into another database with piccrack import. With --images, stored content of
+images is added too. The archive is written to FILE, piccrack-<time>.zip by default,
+or to stdout if FILE is -.`,
+	Example: `piccrack export backup.zip
+piccrack export --images - > backup.zip`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		images, err := cmd.Flags().GetBool("images")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+		name := "piccrack-" + time.Now().UTC().Format("20060102T150405Z") + ".zip"
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "-" {
+			// Keep stdout for the archive
+			l = slog.New(slog.NewTextHandler(os.Stderr, nil))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		repo, _, closeDB, err := connect(ctx)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeDB()
+
+		var w io.Writer = os.Stdout
+		if name != "-" {
+			f, err := os.Create(name)
+			if err != nil {
+				return fmt.Errorf("create archive: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		m, err := archive.Export(ctx, repo, w, archive.ExportOptions{Images: images})
+		if err != nil {
+			l.Error("Failed to export dataset", "err", err.Error())
+			if name != "-" {
+				os.Remove(name)
+			}
+
+			return err
+		}
+		for _, f := range m.Tables {
+			l.Info("Exported table", "table", f.Table, "rows", f.Rows)
+		}
+		l.Info("Exported dataset", "archive", name, "schema_version", m.SchemaVersion, "images", m.Images)
+
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	exportCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+
+	exportCmd.Flags().Bool("images", false, "Add stored content of images to the archive")
+}
+
+func ExportCmd() *cobra.Command {
+	return exportCmd
+}
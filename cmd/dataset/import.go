@@ -0,0 +1,70 @@
+package dataset
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/internal/archive"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Imports an archive written by piccrack export.",
+	Long: `Inserts rows of an archive written by piccrack export which aren't in the database
+yet, in a single transaction. Rows are matched by content hashes, so importing the same
+archive again inserts nothing. Archives exported from older schema versions are imported
+too, the database itself must be migrated to the latest version. Images of the archive
+are kept in the configured image store.`,
+	Example: "piccrack import backup.zip",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		r, err := zip.OpenReader(args[0])
+		if err != nil {
+			l.Error("Opening archive", "err", err.Error())
+
+			return fmt.Errorf("open archive: %w", err)
+		}
+		defer r.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		repo, images, closeDB, err := connect(ctx)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeDB()
+
+		m, results, err := archive.Import(ctx, repo, &r.Reader, archive.ImportOptions{Images: images})
+		if err != nil {
+			l.Error("Failed to import archive", "err", err.Error())
+
+			return err
+		}
+		for _, res := range results {
+			fmt.Printf("TABLE: %s | ROWS: %d | INSERTED: %d | SKIPPED: %d\n",
+				res.Table, res.Rows, res.Inserted, res.Skipped,
+			)
+		}
+		l.Info("Imported archive", "archive", args[0], "schema_version", m.SchemaVersion, "created_at", m.CreatedAt)
+
+		return nil
+	},
+}
+
+func init() {
+	importCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	importCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
+
+func ImportCmd() *cobra.Command {
+	return importCmd
+}
@@ -0,0 +1,52 @@
+// Package dataset implements commands exporting the dataset of the database to an
+// archive and importing it back.
+package dataset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/archive"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+// ErrUnsupportedStorage is returned for storages without Postgres transactions, which
+// archives are read and written with.
+var ErrUnsupportedStorage = errors.New("datasets are archived from postgres storage only")
+
+// connect returns storage configured in config file, which must be able to run
+// transactions of archives, and the configured image store. Returned close func must
+// be called once storage is no longer used.
+func connect(ctx context.Context) (archive.Transactor, *imgstore.Store, func(), error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	images, err := cfg.Images.Store()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("image store: %w", err)
+	}
+
+	s, err := storage.Open(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("open storage: %w", err)
+	}
+	tx, ok := s.(archive.Transactor)
+	if !ok {
+		s.Close()
+
+		return nil, nil, nil, fmt.Errorf("%w: %q driver", ErrUnsupportedStorage, cfg.Storage.Driver)
+	}
+
+	return tx, images, func() {
+		s.Close()
+	}, nil
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Applies database migrations embedded in the binary.",
+	Long: `Applies SQL migrations of db/migrations which are embedded in the binary, so that
+a fresh database can be set up without the migrate container.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Help(); err != nil {
+			return fmt.Errorf("help display: %w", err)
+		}
+
+		return nil
+	},
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:     "up",
+	Short:   "Applies all pending migrations.",
+	Example: "piccrack db migrate up",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		m, err := migrator()
+		if err != nil {
+			l.Error("Creating migrator", "err", err.Error())
+
+			return err
+		}
+		defer m.Close()
+
+		if err := m.Up(); err != nil {
+			l.Error("Failed to apply migrations", "err", err.Error())
+
+			return err
+		}
+		v, _, err := m.Version()
+		if err != nil {
+			return err
+		}
+		l.Info("Applied migrations", "version", v)
+
+		return nil
+	},
+}
+
+var downAll bool
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [steps]",
+	Short: "Reverts last applied migrations.",
+	Long: `Reverts the given number of last applied migrations, one by default.
+All migrations are reverted with --all, which drops all data.`,
+	Example: "piccrack db migrate down 2",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		steps := 1
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid steps %q: must be a positive number", args[0])
+			}
+			steps = n
+		}
+		if downAll {
+			steps = 0
+		}
+
+		m, err := migrator()
+		if err != nil {
+			l.Error("Creating migrator", "err", err.Error())
+
+			return err
+		}
+		defer m.Close()
+
+		if err := m.Down(steps); err != nil {
+			l.Error("Failed to revert migrations", "err", err.Error())
+
+			return err
+		}
+		v, _, err := m.Version()
+		if err != nil {
+			return err
+		}
+		l.Info("Reverted migrations", "version", v)
+
+		return nil
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Prints applied version and pending migrations.",
+	Example: "piccrack db migrate status",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		m, err := migrator()
+		if err != nil {
+			l.Error("Creating migrator", "err", err.Error())
+
+			return err
+		}
+		defer m.Close()
+
+		status, err := m.Status()
+		if err != nil {
+			l.Error("Failed to get migration status", "err", err.Error())
+
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(status); err != nil {
+			return fmt.Errorf("encode status: %w", err)
+		}
+		if err := m.Check(); err != nil {
+			l.Warn("Database schema isn't supported", "err", err.Error())
+		}
+
+		return nil
+	},
+}
+
+var migrateVersionCmd = &cobra.Command{
+	Use:     "version",
+	Short:   "Prints version of the last applied migration.",
+	Example: "piccrack db migrate version",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		m, err := migrator()
+		if err != nil {
+			l.Error("Creating migrator", "err", err.Error())
+
+			return err
+		}
+		defer m.Close()
+
+		v, dirty, err := m.Version()
+		if err != nil {
+			l.Error("Failed to get migration version", "err", err.Error())
+
+			return err
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", v)
+
+			return nil
+		}
+		fmt.Println(v)
+
+		return nil
+	},
+}
+
+func init() {
+	migrateDownCmd.Flags().BoolVar(&downAll, "all", false, "revert all migrations")
+
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migrateVersionCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// migrator returns migrator of database configured in config file.
+func migrator() (*database.Migrator, error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := database.ValidateConfig(cfg.Database); err != nil {
+		return nil, fmt.Errorf("config validation: %w", err)
+	}
+
+	m, err := database.NewMigrator(database.DSN(cfg.Database))
+	if err != nil {
+		return nil, fmt.Errorf("new migrator: %w", err)
+	}
+
+	return m, nil
+}
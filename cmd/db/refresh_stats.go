@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var refreshStatsCmd = &cobra.Command{
+	Use:   "refresh-stats",
+	Short: "Rebuilds word statistics from word occurrences.",
+	Long: `Rebuilds per-term totals, per-batch counts and daily buckets of word statistics
+from word occurrences. Statistics are kept current by database triggers, a full rebuild is
+needed only if they were modified by hand or got out of sync.`,
+	Example: "piccrack db refresh-stats",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		q, closeConn, err := connect(ctx)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		start := time.Now()
+		if err := q.RefreshWordStats(ctx); err != nil {
+			l.Error("Failed to refresh word stats", "err", err.Error())
+
+			return fmt.Errorf("refresh word stats: %w", err)
+		}
+		l.Info("Refreshed word stats", "duration", time.Since(start))
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(refreshStatsCmd)
+}
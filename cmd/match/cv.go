@@ -0,0 +1,179 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/pkg/match"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var cvCmd = &cobra.Command{
+	Use:   "cv FILE",
+	Short: "Displays how skills of a CV cover skills asked for in batches.",
+	Long: `Reads CV from FILE (plain text, PDF, PNG or JPEG image; - reads stdin) and compares
+its skills to skills asked for in selected batches. Displays coverage of the most
+demanded skills, the most demanded skills missing from CV and skills of CV the
+market rarely asks for.`,
+	Example: "piccrack match cv resume.pdf --from 2024-01-01 --location Warszawa",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		var content []byte
+		var err error
+		if args[0] == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(filepath.Clean(args[0]))
+		}
+		if err != nil {
+			return fmt.Errorf("read cv: %w", err)
+		}
+
+		set, err := setFilter(cmd)
+		if err != nil {
+			return err
+		}
+		opts := match.DefaultOptions()
+		if opts.Top, err = cmd.Flags().GetInt("top"); err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		if opts.Missing, err = cmd.Flags().GetInt("missing"); err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		if opts.RareShare, err = cmd.Flags().GetFloat64("rare-share"); err != nil {
+			return fmt.Errorf("get float64: %w", err)
+		}
+		asJSON, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+		filter, err := textproc.LoadFilter(cfg.Filters.StopWords(), cfg.Filters.Options())
+		if err != nil {
+			l.Error("Loading words filter", "err", err.Error())
+
+			return fmt.Errorf("load filter: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		store, err := storage.Open(ctx, cfg)
+		if err != nil {
+			l.Error("Opening storage", "driver", cfg.Storage.Driver, "err", err.Error())
+
+			return fmt.Errorf("open storage: %w", err)
+		}
+		defer store.Close()
+
+		svc := apiv1.NewService(store, filter, nil, nil, l)
+		report, err := svc.MatchCV(ctx, content, set, opts)
+		if err != nil {
+			l.Error("Failed to match cv", "err", err.Error())
+
+			return fmt.Errorf("match cv: %w", err)
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return fmt.Errorf("encode: %w", err)
+			}
+
+			return nil
+		}
+
+		fmt.Printf("BATCHES: %d | COVERAGE: %.2f%%\n", report.Batches, report.Coverage)
+		for _, group := range []struct {
+			name   string
+			skills []match.Skill
+		}{
+			{name: "MATCHED", skills: report.Matched},
+			{name: "MISSING", skills: report.Missing},
+			{name: "RARE", skills: report.Rare},
+		} {
+			for _, s := range group.skills {
+				fmt.Printf("%s: %s | POSTINGS: %d | SHARE: %.2f%%\n", group.name, s.Skill, s.Postings, s.Share)
+			}
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cvCmd)
+
+	cvCmd.Flags().StringSlice("batch", nil, "Names of batches to match against, every batch by default")
+	cvCmd.Flags().String("from", "", "Include batches created at or after date (2006-01-02) or RFC 3339 time")
+	cvCmd.Flags().String("to", "", "Include batches created before date (2006-01-02) or RFC 3339 time")
+	cvCmd.Flags().String("language", "", "Include only words in language (ISO 639-1 code, e.g. en, pl)")
+	cvCmd.Flags().String("seniority", "", "Include only batches of seniority (intern, junior, mid, senior, lead)")
+	cvCmd.Flags().String("location", "", "Include only batches of location (city)")
+	cvCmd.Flags().String("work-mode", "", "Include only batches of work mode (remote, hybrid, onsite)")
+	cvCmd.Flags().String("tag", "", "Include only batches with tag, directly or through their posting")
+	cvCmd.Flags().String("collection", "", "Include only batches in collection, directly or through their posting")
+	cvCmd.Flags().Int("top", 30, "Number of the most demanded skills coverage is computed for")
+	cvCmd.Flags().Int("missing", 10, "Maximal number of displayed missing skills")
+	cvCmd.Flags().Float64("rare-share", 2, "Skills asked for in less than this percentage of batches are rare")
+	cvCmd.Flags().Bool("json", false, "Print report as JSON")
+}
+
+// setFilter returns batches selected by flags of cmd.
+func setFilter(cmd *cobra.Command) (apiv1.SetFilter, error) {
+	var f apiv1.SetFilter
+
+	batches, err := cmd.Flags().GetStringSlice("batch")
+	if err != nil {
+		return f, fmt.Errorf("get string slice: %w", err)
+	}
+	f.Batches = batches
+
+	for name, v := range map[string]*string{
+		"language":   &f.Language,
+		"seniority":  &f.Seniority,
+		"location":   &f.Location,
+		"work-mode":  &f.WorkMode,
+		"tag":        &f.Tag,
+		"collection": &f.Collection,
+	} {
+		if *v, err = cmd.Flags().GetString(name); err != nil {
+			return f, fmt.Errorf("get string: %w", err)
+		}
+	}
+
+	for name, t := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		v, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return f, fmt.Errorf("get string: %w", err)
+		}
+		if v == "" {
+			continue
+		}
+		if *t, err = apiv1.ParseTime(v); err != nil {
+			return f, fmt.Errorf("parse --%s: %w", name, err)
+		}
+	}
+
+	return f, nil
+}
@@ -0,0 +1,33 @@
+package match
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "match",
+	Short: "Matches profiles against skills collected from job postings.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Help(); err != nil {
+			return fmt.Errorf("help display: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
@@ -0,0 +1,155 @@
+package reprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var ErrReprocessFailed = errors.New("reprocess failed")
+
+var rootCmd = &cobra.Command{
+	Use:   "reprocess [POSTING_ID...]",
+	Short: "Derives words and phrases of postings again from stored text or images.",
+	Long: `Derives words, phrases and salaries of postings again from text of their latest
+stored document, e.g. after the tokenizer or filters were improved. With --rescan, stored
+images are scanned with OCR again and recognized text is stored as a new document.
+Batches of reprocessed postings are replaced, old batches are soft deleted.`,
+	Example: `piccrack reprocess 12 13
+piccrack reprocess --all --rescan`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+		rescan, err := cmd.Flags().GetBool("rescan")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+		company, err := cmd.Flags().GetString("company")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		if all == (len(args) > 0) {
+			return errors.New("pass either posting ids or --all")
+		}
+		ids := make([]int64, 0, len(args))
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parse posting id %q: %w", arg, err)
+			}
+			ids = append(ids, id)
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+		filter, err := textproc.LoadFilter(cfg.Filters.StopWords(), cfg.Filters.Options())
+		if err != nil {
+			l.Error("Loading words filter", "err", err.Error())
+
+			return fmt.Errorf("load filter: %w", err)
+		}
+		images, err := cfg.Images.Store()
+		if err != nil {
+			l.Error("Loading image store", "err", err.Error())
+
+			return fmt.Errorf("image store: %w", err)
+		}
+
+		// Scanning many images with OCR takes a while
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		store, err := storage.Open(ctx, cfg)
+		if err != nil {
+			l.Error("Opening storage", "driver", cfg.Storage.Driver, "err", err.Error())
+
+			return fmt.Errorf("open storage: %w", err)
+		}
+		defer store.Close()
+
+		svc := apiv1.NewService(store, filter, nil, images, l)
+
+		if all {
+			if ids, err = postingIDs(ctx, svc, company); err != nil {
+				l.Error("Failed to list postings", "err", err.Error())
+
+				return err
+			}
+		}
+
+		var failed int
+		for _, id := range ids {
+			row, err := svc.ReprocessPosting(ctx, id, rescan)
+			if err != nil {
+				l.Error("Failed to reprocess posting", "id", id, "err", err.Error())
+				failed++
+
+				continue
+			}
+			fmt.Printf("POSTING: %d | WORD BATCH: %s | PHRASE BATCH: %s\n",
+				row.ID, row.WordBatchName.String, row.PhraseBatchName.String,
+			)
+		}
+		l.Info("Reprocessed postings", "total", len(ids), "failed", failed, "rescan", rescan)
+		if failed > 0 {
+			return fmt.Errorf("%w: %d of %d postings", ErrReprocessFailed, failed, len(ids))
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+
+	rootCmd.Flags().Bool("all", false, "Reprocess every posting")
+	rootCmd.Flags().Bool("rescan", false, "Scan stored images with OCR again instead of using stored text")
+	rootCmd.Flags().String("company", "", "Reprocess only postings of company, used with --all")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// postingIDs returns ids of every posting of company, or of every posting if company is empty.
+func postingIDs(ctx context.Context, svc apiv1.Service, company string) ([]int64, error) {
+	const limit = 1000
+
+	ids := make([]int64, 0)
+	for offset := int32(0); ; offset += limit {
+		rows, err := svc.ListPostings(ctx, limit, offset, company)
+		if err != nil {
+			return nil, fmt.Errorf("list postings: %w", err)
+		}
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		if len(rows) < limit {
+			return ids, nil
+		}
+	}
+}
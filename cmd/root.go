@@ -5,7 +5,16 @@ import (
 	"os"
 
 	"github.com/kndrad/piccrack/cmd/api"
+	"github.com/kndrad/piccrack/cmd/batches"
+	"github.com/kndrad/piccrack/cmd/compare"
+	"github.com/kndrad/piccrack/cmd/dataset"
+	"github.com/kndrad/piccrack/cmd/db"
+	"github.com/kndrad/piccrack/cmd/match"
+	"github.com/kndrad/piccrack/cmd/reprocess"
+	"github.com/kndrad/piccrack/cmd/salaries"
 	"github.com/kndrad/piccrack/cmd/scan"
+	"github.com/kndrad/piccrack/cmd/search"
+	"github.com/kndrad/piccrack/cmd/stopwords"
 	"github.com/kndrad/piccrack/cmd/words"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -51,7 +60,17 @@ func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
 	rootCmd.AddCommand(api.RootCmd())
+	rootCmd.AddCommand(batches.RootCmd())
+	rootCmd.AddCommand(compare.RootCmd())
+	rootCmd.AddCommand(db.RootCmd())
+	rootCmd.AddCommand(dataset.ExportCmd())
+	rootCmd.AddCommand(dataset.ImportCmd())
+	rootCmd.AddCommand(match.RootCmd())
+	rootCmd.AddCommand(reprocess.RootCmd())
+	rootCmd.AddCommand(salaries.RootCmd())
 	rootCmd.AddCommand(scan.RootCmd())
+	rootCmd.AddCommand(search.RootCmd())
+	rootCmd.AddCommand(stopwords.RootCmd())
 	rootCmd.AddCommand(words.RootCmd())
 }
 
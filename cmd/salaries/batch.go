@@ -0,0 +1,52 @@
+package salaries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var batchCmd = &cobra.Command{
+	Use:     "batch",
+	Short:   "Lists salaries stored for a batch.",
+	Example: "piccrack salaries batch [BATCH NAME]",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		q, closeConn, err := connect(ctx)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		rows, err := q.ListSalariesByBatchName(ctx, args[0])
+		if err != nil {
+			l.Error("Failed to list batch salaries", "err", err.Error())
+
+			return fmt.Errorf("list salaries by batch name: %w", err)
+		}
+
+		for _, row := range rows {
+			fmt.Printf("SALARY: %.0f-%.0f %s/%s | CONTRACT: %s | BASIS: %s | SENIORITY: %s\n",
+				row.MinAmount, row.MaxAmount, row.Currency, row.Period, row.Contract, row.Basis, row.Seniority,
+			)
+		}
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+}
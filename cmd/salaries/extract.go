@@ -0,0 +1,46 @@
+package salaries
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var extractCmd = &cobra.Command{
+	Use:     "extract",
+	Short:   "Prints salaries found in a text file.",
+	Example: "piccrack salaries extract [TEXT FILE PATH]",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		path := filepath.Clean(args[0])
+		data, err := os.ReadFile(path)
+		if err != nil {
+			l.Error("Failed to read file", "path", path, "err", err.Error())
+
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		text := string(data)
+		seniority := textproc.DetectSeniority(text)
+		salaries := textproc.ExtractSalaries(text)
+		l.Info("Extracted salaries", "total", len(salaries), "seniority", seniority)
+
+		for _, s := range salaries {
+			fmt.Printf("SALARY: %.0f-%.0f %s/%s | CONTRACT: %s | BASIS: %s | SENIORITY: %s\n",
+				s.Min, s.Max, s.Currency, s.Period, s.Contract, s.Basis, seniority,
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(extractCmd)
+}
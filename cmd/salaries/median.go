@@ -0,0 +1,99 @@
+package salaries
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var medianCmd = &cobra.Command{
+	Use:     "median",
+	Short:   "Displays median monthly salaries by skill or by seniority.",
+	Example: "piccrack salaries median --by seniority --contract b2b",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		by, err := cmd.Flags().GetString("by")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		skill, err := cmd.Flags().GetString("skill")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		contract, err := cmd.Flags().GetString("contract")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		q, closeConn, err := connect(ctx)
+		if err != nil {
+			l.Error("Connecting to database", "err", err.Error())
+
+			return err
+		}
+		defer closeConn()
+
+		switch by {
+		case "skill":
+			names := textproc.SkillNames()
+			params := database.ListSalaryMediansBySkillParams{
+				Limit:           30,
+				Skill:           pgtype.Text{String: textproc.CanonicalSkill(skill), Valid: skill != ""},
+				Contract:        pgtype.Text{String: contract, Valid: contract != ""},
+				Skills:          names,
+				CanonicalSkills: textproc.CanonicalSkills(names),
+			}
+			if len(args) > 0 {
+				limit, err := strconv.ParseInt(args[0], 10, 32)
+				if err != nil {
+					return fmt.Errorf("parse int: %w", err)
+				}
+				params.Limit = int32(limit)
+			}
+			rows, err := q.ListSalaryMediansBySkill(ctx, params)
+			if err != nil {
+				l.Error("Failed to list salary medians by skill", "err", err.Error())
+
+				return fmt.Errorf("list salary medians by skill: %w", err)
+			}
+			for _, row := range rows {
+				fmt.Printf("SKILL: %s | MEDIAN: %.0f %s/month | SALARIES: %d\n", row.Skill, row.Median, row.Currency, row.Total)
+			}
+		case "seniority":
+			rows, err := q.ListSalaryMediansBySeniority(ctx, pgtype.Text{String: contract, Valid: contract != ""})
+			if err != nil {
+				l.Error("Failed to list salary medians by seniority", "err", err.Error())
+
+				return fmt.Errorf("list salary medians by seniority: %w", err)
+			}
+			for _, row := range rows {
+				fmt.Printf("SENIORITY: %s | MEDIAN: %.0f %s/month | SALARIES: %d\n", row.Seniority, row.Median, row.Currency, row.Total)
+			}
+		default:
+			return fmt.Errorf("unknown grouping %q, use skill or seniority", by)
+		}
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(medianCmd)
+
+	medianCmd.Flags().String("by", "skill", "Group salaries by skill or seniority")
+	medianCmd.Flags().String("skill", "", "Display median of a single skill")
+	medianCmd.Flags().String("contract", "", "Include only salaries of contract type (b2b, uop)")
+}
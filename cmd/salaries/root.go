@@ -0,0 +1,55 @@
+package salaries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "salaries",
+	Short: "Extracts salaries from text and displays salary statistics.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Help(); err != nil {
+			return fmt.Errorf("help display: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// connect returns queries using storage configured in config file.
+// Returned close func must be called once queries are no longer used.
+func connect(ctx context.Context) (database.Querier, func(), error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+
+	s, err := storage.Open(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open storage: %w", err)
+	}
+
+	return s, func() {
+		s.Close()
+	}, nil
+}
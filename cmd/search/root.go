@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	apiv1 "github.com/kndrad/piccrack/internal/api/v1"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "search QUERY",
+	Short: "Searches stored phrases or words.",
+	Long: `Searches stored phrases with full-text search and trigram similarity, so misspelled
+words match too. Query supports web search syntax: "quoted phrases", OR and -excluded words.
+With --words, words similar to query are displayed instead.`,
+	Example: `piccrack search "remote work"
+piccrack search kubernets --words`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		words, err := cmd.Flags().GetBool("words")
+		if err != nil {
+			return fmt.Errorf("get bool: %w", err)
+		}
+		language, err := cmd.Flags().GetString("language")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		limit, err := cmd.Flags().GetInt32("limit")
+		if err != nil {
+			return fmt.Errorf("get int32: %w", err)
+		}
+		query := strings.Join(args, " ")
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		store, err := storage.Open(ctx, cfg)
+		if err != nil {
+			l.Error("Opening storage", "driver", cfg.Storage.Driver, "err", err.Error())
+
+			return fmt.Errorf("open storage: %w", err)
+		}
+		defer store.Close()
+
+		svc := apiv1.NewService(store, nil, nil, nil, l)
+
+		if words {
+			rows, err := svc.SearchWords(ctx, query, language, limit, 0)
+			if err != nil {
+				l.Error("Failed to search words", "err", err.Error())
+
+				return fmt.Errorf("search words: %w", err)
+			}
+			for _, row := range rows {
+				fmt.Printf("WORD: %s | LANGUAGE: %s | TOTAL: %d | SIMILARITY: %.2f\n",
+					row.Value, row.Language, row.Total, row.Similarity,
+				)
+			}
+		} else {
+			rows, err := svc.SearchPhrases(ctx, query, language, limit, 0)
+			if err != nil {
+				l.Error("Failed to search phrases", "err", err.Error())
+
+				return fmt.Errorf("search phrases: %w", err)
+			}
+			for _, row := range rows {
+				fmt.Printf("PHRASE: %s | BATCH: %s | RANK: %.3f | SIMILARITY: %.2f\n",
+					row.Value, row.BatchName, row.Rank, row.Similarity,
+				)
+			}
+		}
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+
+	rootCmd.Flags().Bool("words", false, "Search words instead of phrases")
+	rootCmd.Flags().String("language", "", "Search only terms in language (ISO 639-1 code)")
+	rootCmd.Flags().Int32("limit", 20, "Maximal number of displayed results")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
@@ -0,0 +1,40 @@
+package stopwords
+
+import (
+	"fmt"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:     "add",
+	Short:   "Adds stop words to the stop words file.",
+	Example: "piccrack stopwords add [WORD...]",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		file, err := stopWordsFile()
+		if err != nil {
+			l.Error("Loading stop words file", "err", err.Error())
+
+			return err
+		}
+		words, err := file.Add(args...)
+		if err != nil {
+			l.Error("Adding stop words", "err", err.Error())
+
+			return fmt.Errorf("add stop words: %w", err)
+		}
+		l.Info("Added stop words", "path", file.Path(), "total", len(words))
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+}
@@ -0,0 +1,40 @@
+package stopwords
+
+import (
+	"fmt"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove",
+	Short:   "Removes stop words from the stop words file.",
+	Example: "piccrack stopwords remove [WORD...]",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		file, err := stopWordsFile()
+		if err != nil {
+			l.Error("Loading stop words file", "err", err.Error())
+
+			return err
+		}
+		words, err := file.Remove(args...)
+		if err != nil {
+			l.Error("Removing stop words", "err", err.Error())
+
+			return fmt.Errorf("remove stop words: %w", err)
+		}
+		l.Info("Removed stop words", "path", file.Path(), "total", len(words))
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+}
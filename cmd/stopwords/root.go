@@ -0,0 +1,67 @@
+package stopwords
+
+import (
+	"fmt"
+
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Verbose bool
+	cfgFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:     "stopwords",
+	Short:   "Lists extra stop words dropped from scanned text.",
+	Example: "piccrack stopwords",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		file, err := stopWordsFile()
+		if err != nil {
+			l.Error("Loading stop words file", "err", err.Error())
+
+			return err
+		}
+		words, err := file.Read()
+		if err != nil {
+			l.Error("Reading stop words", "err", err.Error())
+
+			return fmt.Errorf("read stop words: %w", err)
+		}
+		l.Info("Read stop words", "path", file.Path(), "total", len(words))
+
+		for _, w := range words {
+			fmt.Println(w)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
+
+func RootCmd() *cobra.Command {
+	return rootCmd
+}
+
+// stopWordsFile returns stop words file configured in config file.
+func stopWordsFile() (*textproc.StopWordsFile, error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("config load: %w", err)
+	}
+	file := cfg.Filters.StopWords()
+	if file == nil {
+		return nil, fmt.Errorf("filters.stop_words_file is not set in %s", cfgFile)
+	}
+
+	return file, nil
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/kndrad/piccrack/cmd/logger"
 	"github.com/kndrad/piccrack/config"
 	"github.com/kndrad/piccrack/internal/database"
-	"github.com/kndrad/piccrack/pkg/retry"
+	"github.com/kndrad/piccrack/pkg/textproc"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +21,7 @@ var addCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		l := logger.New(Verbose)
 
-		cfg, err := config.Load("config/development.yaml")
+		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			l.Error("Loading database config", "err", err.Error())
 
@@ -31,32 +31,18 @@ var addCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		pool, err := database.Pool(ctx, cfg.Database)
+		q, err := openStorage(ctx, cfg, l)
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
-
-			return fmt.Errorf("database pool: %w", err)
-		}
-		defer pool.Close()
-
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
-
-			return fmt.Errorf("database ping: %w", err)
-		}
-
-		conn, err := database.Connect(ctx, pool)
-		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
-
-			return fmt.Errorf("database connection: %w", err)
+			return err
 		}
-		defer conn.Close(ctx)
-
-		q := database.New(conn)
-
-		value := args[0]
-		word, err := q.CreateWord(ctx, value)
+		defer q.Close()
+
+		value := textproc.NewWord(args[0])
+		word, err := q.CreateWord(ctx, database.CreateWordParams{
+			Value:    value.Value,
+			Lemma:    value.Lemma,
+			Language: value.Language,
+		})
 		if err != nil {
 			l.Error("Inserting word failed", "err", err.Error())
 
@@ -1,8 +1,6 @@
 package words
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,13 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/kndrad/piccrack/cmd/logger"
 	"github.com/kndrad/piccrack/config"
 	"github.com/kndrad/piccrack/internal/database"
-	"github.com/kndrad/piccrack/pkg/retry"
 	"github.com/kndrad/piccrack/pkg/textproc"
 	"github.com/spf13/cobra"
 )
@@ -28,7 +24,7 @@ var addManyCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		l := logger.New(Verbose)
 
-		cfg, err := config.Load("config/development.yaml")
+		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			l.Error("Loading database config", "err", err.Error())
 
@@ -37,30 +33,15 @@ var addManyCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		pool, err := database.Pool(ctx, cfg.Database)
+		q, err := openStorage(ctx, cfg, l)
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
-
-			return fmt.Errorf("database pool: %w", err)
+			return err
 		}
-		defer pool.Close()
-
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
+		defer q.Close()
 
-			return fmt.Errorf("database ping: %w", err)
-		}
-
-		conn, err := database.Connect(ctx, pool)
-		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
-
-			return fmt.Errorf("database connection: %w", err)
-		}
-		defer conn.Close(ctx)
-
-		// Read words from a json file
+		// Read words from a json or text file
 		analysis := new(textproc.TextAnalysis)
+		var words []textproc.Word
 		path := filepath.Clean(args[0])
 
 		switch filepath.Ext(path) {
@@ -78,6 +59,12 @@ var addManyCmd = &cobra.Command{
 
 				return fmt.Errorf("unmarshal json: %w", err)
 			}
+			// Frequencies have no lines, so language of all words is detected together
+			values := make([]string, 0, len(analysis.WordFrequency))
+			for value := range analysis.WordFrequency {
+				values = append(values, value)
+			}
+			words = textproc.NewWords(values)
 		case ".txt":
 			data, err := os.ReadFile(args[0])
 			if err != nil {
@@ -87,40 +74,35 @@ var addManyCmd = &cobra.Command{
 
 				return fmt.Errorf("read file: %w", err)
 			}
-			scanner := bufio.NewScanner(bytes.NewReader(data))
-			scanner.Split(bufio.ScanWords)
-
-			for scanner.Scan() {
-				word := strings.Trim(scanner.Text(), " ")
-				analysis.IncWordCount(word)
-			}
-
-			if err := scanner.Err(); err != nil {
-				l.Error("Scanner returned an error", "err", err.Error())
-
-				return fmt.Errorf("scanner err: %w", err)
+			// Language and stop words are detected for every line
+			words = textproc.Words(string(data))
+			for _, word := range words {
+				analysis.IncWordCount(word.Value)
 			}
 		}
 		if Verbose {
 			printWords(analysis)
 		}
 
-		// Query db to insert each word
-		q := database.New(conn)
-		for word := range analysis.WordFrequency {
-			row, err := q.CreateWord(ctx, word)
-			if err != nil {
-				l.Error("Failed to insert word",
-					slog.String("word", word),
-				)
+		// Insert words all or nothing
+		ingested := make([]database.IngestedWord, 0, len(words))
+		for _, word := range words {
+			ingested = append(ingested, database.IngestedWord{
+				Value:    word.Value,
+				Lemma:    word.Lemma,
+				Language: word.Language,
+			})
+		}
+		res, err := q.Ingest(ctx, database.Ingestion{Words: ingested})
+		if err != nil {
+			l.Error("Failed to insert words", "err", err.Error())
 
-				return fmt.Errorf("word insert: %w", err)
-			}
-			l.Info("Inserted row to a database",
-				slog.Int64("id", row.ID),
-				slog.String("word", row.Value),
-			)
+			return fmt.Errorf("words insert: %w", err)
 		}
+		l.Info("Inserted words to a database",
+			slog.Int64("words", res.Words),
+			slog.Int64("terms", res.Terms),
+		)
 
 		l.Info("Program completed successfully.")
 
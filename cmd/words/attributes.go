@@ -0,0 +1,47 @@
+package words
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spf13/cobra"
+)
+
+// batchAttributes filters words by attributes of their batches and by tags and
+// collections batches belong to.
+type batchAttributes struct {
+	Seniority  pgtype.Text
+	Location   pgtype.Text
+	WorkMode   pgtype.Text
+	Tag        pgtype.Text
+	Collection pgtype.Text
+}
+
+// addBatchAttributeFlags adds flags filtering words by attributes of their batches to cmd.
+func addBatchAttributeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("seniority", "", "Include only batches of seniority (intern, junior, mid, senior, lead)")
+	cmd.Flags().String("location", "", "Include only batches of location (city)")
+	cmd.Flags().String("work-mode", "", "Include only batches of work mode (remote, hybrid, onsite)")
+	cmd.Flags().String("tag", "", "Include only batches with tag, directly or through their posting")
+	cmd.Flags().String("collection", "", "Include only batches in collection, directly or through their posting")
+}
+
+// batchAttributeParams returns batch attributes from flags of cmd.
+func batchAttributeParams(cmd *cobra.Command) (batchAttributes, error) {
+	var attrs batchAttributes
+	for name, param := range map[string]*pgtype.Text{
+		"seniority":  &attrs.Seniority,
+		"location":   &attrs.Location,
+		"work-mode":  &attrs.WorkMode,
+		"tag":        &attrs.Tag,
+		"collection": &attrs.Collection,
+	} {
+		v, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return attrs, fmt.Errorf("get string: %w", err)
+		}
+		*param = pgtype.Text{String: v, Valid: v != ""}
+	}
+
+	return attrs, nil
+}
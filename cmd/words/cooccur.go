@@ -0,0 +1,161 @@
+package words
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/cooccur"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var cooccurCmd = &cobra.Command{
+	Use:     "cooccur",
+	Short:   "Displays pairs of skills appearing together in batches or exports them as a graph.",
+	Example: "piccrack words cooccur 50 --metric lift --format graphml --out cooccurrence.graphml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		limit := 30
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("parse limit: %w", err)
+			}
+			limit = n
+		}
+		minCount, err := cmd.Flags().GetInt("min-count")
+		if err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		metricName, err := cmd.Flags().GetString("metric")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		metric, err := cooccur.ParseMetric(metricName)
+		if err != nil {
+			return fmt.Errorf("parse metric: %w", err)
+		}
+		formatName, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		var format cooccur.Format
+		if formatName != "" {
+			format, err = cooccur.ParseFormat(formatName)
+			if err != nil {
+				return fmt.Errorf("parse format: %w", err)
+			}
+		}
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+
+		language, err := cmd.Flags().GetString("language")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		attrs, err := batchAttributeParams(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+		filter, err := textproc.LoadFilter(cfg.Filters.StopWords(), cfg.Filters.Options())
+		if err != nil {
+			l.Error("Loading words filter", "err", err.Error())
+
+			return fmt.Errorf("load filter: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		q, err := openStorage(ctx, cfg, l)
+		if err != nil {
+			return err
+		}
+		defer q.Close()
+
+		rows, err := q.ListBatchWordSets(ctx, database.ListBatchWordSetsParams{
+			Language:   pgtype.Text{String: language, Valid: language != ""},
+			Seniority:  attrs.Seniority,
+			Location:   attrs.Location,
+			WorkMode:   attrs.WorkMode,
+			Tag:        attrs.Tag,
+			Collection: attrs.Collection,
+			Excluded:   filter.StopWords(),
+			Skills:     textproc.SkillNames(),
+		})
+		if err != nil {
+			l.Error("Failed to list batch words", "err", err.Error())
+
+			return fmt.Errorf("list batch word sets: %w", err)
+		}
+
+		m := cooccur.New(nil)
+		for _, row := range rows {
+			m.Add(textproc.Skills(row.Words))
+		}
+		pairs := m.Pairs(minCount, metric)
+		if len(pairs) > limit {
+			pairs = pairs[:limit]
+		}
+		l.Info("Counted co-occurring pairs", "batches", m.Batches(), "pairs", len(pairs))
+
+		if format == "" {
+			for _, p := range pairs {
+				fmt.Printf("PAIR: %s + %s | COUNT: %d | SUPPORT: %.4f | LIFT: %.4f | JACCARD: %.4f\n",
+					p.Source, p.Target, p.Count, p.Support, p.Lift, p.Jaccard,
+				)
+			}
+
+			return nil
+		}
+
+		w := os.Stdout
+		if out != "" {
+			f, err := os.Create(filepath.Clean(out))
+			if err != nil {
+				return fmt.Errorf("create file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := cooccur.Write(w, m.Graph(pairs), format); err != nil {
+			l.Error("Failed to write graph", "err", err.Error())
+
+			return fmt.Errorf("write graph: %w", err)
+		}
+		if out != "" {
+			l.Info("Exported graph", "path", out, "format", format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cooccurCmd)
+
+	addBatchAttributeFlags(cooccurCmd)
+	cooccurCmd.Flags().String("language", "", "Include only words in language (ISO 639-1 code, e.g. en, pl)")
+	cooccurCmd.Flags().Int("min-count", 2, "Minimal number of batches a pair must appear in")
+	cooccurCmd.Flags().String("metric", "count", "Order pairs by count, support, lift or jaccard")
+	cooccurCmd.Flags().String("format", "", "Export pairs as a graph in format: json, graphml or dot")
+	cooccurCmd.Flags().String("out", "", "Write exported graph to file instead of stdout")
+}
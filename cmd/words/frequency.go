@@ -7,10 +7,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/kndrad/piccrack/cmd/logger"
 	"github.com/kndrad/piccrack/config"
 	"github.com/kndrad/piccrack/internal/database"
-	"github.com/kndrad/piccrack/pkg/retry"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +21,7 @@ var frequencyCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		l := logger.New(Verbose)
 
-		cfg, err := config.Load("config/development.yaml")
+		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			l.Error("Loading database config", "err", err.Error())
 
@@ -31,41 +31,68 @@ var frequencyCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		pool, err := database.Pool(ctx, cfg.Database)
+		q, err := openStorage(ctx, cfg, l)
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
-
-			return fmt.Errorf("database pool: %w", err)
+			return err
 		}
-		defer pool.Close()
+		defer q.Close()
 
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
+		var limit int32 = 30
+		params := database.ListWordFrequenciesParams{Limit: limit}
 
-			return fmt.Errorf("database ping: %w", err)
+		if len(args) > 0 {
+			limit, err := strconv.ParseInt(args[0], 10, 32)
+			if err != nil {
+				l.Error("Failed to strconv", "err", err.Error())
+			}
+			params.Limit = int32(limit)
 		}
 
-		conn, err := database.Connect(ctx, pool)
+		language, err := cmd.Flags().GetString("language")
 		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
+			l.Error("Failed to get language string flag", "err", err)
 
-			return fmt.Errorf("database connection: %w", err)
+			return fmt.Errorf("get string: %w", err)
 		}
-		defer conn.Close(ctx)
+		params.Language = pgtype.Text{String: language, Valid: language != ""}
 
-		// Query db to get word frequency count.
-		q := database.New(conn)
+		attrs, err := batchAttributeParams(cmd)
+		if err != nil {
+			l.Error("Failed to get batch attribute flags", "err", err)
 
-		var limit int32 = 30
-		params := database.ListWordFrequenciesParams{Limit: limit}
+			return err
+		}
+		params.Seniority, params.Location, params.WorkMode = attrs.Seniority, attrs.Location, attrs.WorkMode
+		params.Tag, params.Collection = attrs.Tag, attrs.Collection
 
-		if len(args) > 0 {
-			limit, err := strconv.ParseInt(args[0], 10, 32)
+		lemma, err := cmd.Flags().GetBool("lemma")
+		if err != nil {
+			l.Error("Failed to get lemma bool flag", "err", err)
+
+			return fmt.Errorf("get bool: %w", err)
+		}
+		if lemma {
+			rows, err := q.ListLemmaFrequencies(ctx, database.ListLemmaFrequenciesParams(params))
 			if err != nil {
-				l.Error("Failed to strconv", "err", err.Error())
+				l.Error("Failed to analyze lemma frequency count", "err", err.Error())
+
+				return fmt.Errorf("getting lemma frequency count: %w", err)
 			}
-			params.Limit = int32(limit)
+			l.Info("Got lemma frequency count rows",
+				slog.Int("len", len(rows)),
+			)
+
+			if Verbose {
+				for i, row := range rows {
+					fmt.Printf("%v: ROW: [%v, %v] \n", i, row.Lemma, row.Total)
+				}
+			}
+
+			l.Info("Program completed successfully.")
+
+			return nil
 		}
+
 		rows, err := q.ListWordFrequencies(ctx, params)
 		if err != nil {
 			l.Error("Failed to analyze word frequency count", "err", err.Error())
@@ -90,4 +117,8 @@ var frequencyCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(frequencyCmd)
+
+	frequencyCmd.Flags().Bool("lemma", false, "Group words by their lemma instead of value")
+	addBatchAttributeFlags(frequencyCmd)
+	frequencyCmd.Flags().String("language", "", "Count only words in language (ISO 639-1 code, e.g. en, pl)")
 }
@@ -6,10 +6,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/kndrad/piccrack/cmd/logger"
 	"github.com/kndrad/piccrack/config"
 	"github.com/kndrad/piccrack/internal/database"
-	"github.com/kndrad/piccrack/pkg/retry"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +19,7 @@ var rankCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		l := logger.New(Verbose)
 
-		cfg, err := config.Load("config/development.yaml")
+		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			l.Error("Loading database config", "err", err.Error())
 
@@ -29,29 +29,11 @@ var rankCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		pool, err := database.Pool(ctx, cfg.Database)
+		q, err := openStorage(ctx, cfg, l)
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
-
-			return fmt.Errorf("database pool: %w", err)
+			return err
 		}
-		defer pool.Close()
-
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
-
-			return fmt.Errorf("database ping: %w", err)
-		}
-
-		conn, err := database.Connect(ctx, pool)
-		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
-
-			return fmt.Errorf("database connection: %w", err)
-		}
-		defer conn.Close(ctx)
-
-		q := database.New(conn)
+		defer q.Close()
 
 		var limit int32 = 30
 		params := database.ListWordRankingsParams{
@@ -66,6 +48,22 @@ var rankCmd = &cobra.Command{
 			params.Limit = int32(limit)
 		}
 
+		language, err := cmd.Flags().GetString("language")
+		if err != nil {
+			l.Error("Failed to get language string flag", "err", err)
+
+			return fmt.Errorf("get string: %w", err)
+		}
+		params.Language = pgtype.Text{String: language, Valid: language != ""}
+
+		attrs, err := batchAttributeParams(cmd)
+		if err != nil {
+			l.Error("Failed to get batch attribute flags", "err", err)
+
+			return err
+		}
+		params.Seniority, params.Location, params.WorkMode = attrs.Seniority, attrs.Location, attrs.WorkMode
+
 		rows, err := q.ListWordRankings(ctx, params)
 		if err != nil {
 			l.Error("Failed to get words rank", "err", err.Error())
@@ -85,4 +83,7 @@ var rankCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(rankCmd)
+
+	addBatchAttributeFlags(rankCmd)
+	rankCmd.Flags().String("language", "", "Rank only words in language (ISO 639-1 code, e.g. en, pl)")
 }
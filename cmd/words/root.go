@@ -3,6 +3,7 @@ package words
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"strconv"
 	"time"
@@ -10,11 +11,14 @@ import (
 	"github.com/kndrad/piccrack/cmd/logger"
 	"github.com/kndrad/piccrack/config"
 	"github.com/kndrad/piccrack/internal/database"
-	"github.com/kndrad/piccrack/pkg/retry"
+	"github.com/kndrad/piccrack/internal/storage"
 	"github.com/spf13/cobra"
 )
 
-var Verbose bool
+var (
+	Verbose bool
+	cfgFile string
+)
 
 var rootCmd = &cobra.Command{
 	Use:     "words",
@@ -23,7 +27,7 @@ var rootCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		l := logger.New(Verbose)
 
-		cfg, err := config.Load("config/development.yaml")
+		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			l.Error("Loading onfig", "err", err.Error())
 
@@ -33,29 +37,11 @@ var rootCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		pool, err := database.Pool(ctx, cfg.Database)
+		q, err := openStorage(ctx, cfg, l)
 		if err != nil {
-			l.Error("Loading database pool", "err", err.Error())
-
-			return fmt.Errorf("database pool: %w", err)
-		}
-		defer pool.Close()
-
-		if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
-			l.Error("Pinging database", "err", err.Error())
-
-			return fmt.Errorf("database ping: %w", err)
-		}
-
-		conn, err := database.Connect(ctx, pool)
-		if err != nil {
-			l.Error("Connecting to database", "err", err.Error())
-
-			return fmt.Errorf("database connection: %w", err)
+			return err
 		}
-		defer conn.Close(ctx)
-
-		q := database.New(conn)
+		defer q.Close()
 
 		limit := math.MaxInt32
 		if len(args) > 0 {
@@ -91,6 +77,23 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// openStorage opens storage of words selected by cfg, either Postgres or a local SQLite file.
+func openStorage(ctx context.Context, cfg *config.Config, l *slog.Logger) (storage.Storage, error) {
+	s, err := storage.Open(ctx, cfg)
+	if err != nil {
+		l.Error("Opening storage", "driver", cfg.Storage.Driver, "err", err.Error())
+
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+
+	return s, nil
+}
+
 func RootCmd() *cobra.Command {
 	return rootCmd
 }
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "./config/development.yaml", "config file path")
+	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "print verbose actions")
+}
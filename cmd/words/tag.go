@@ -0,0 +1,89 @@
+package words
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:     "tag",
+	Short:   "Overrides seniority, location and work mode of a words batch.",
+	Example: "piccrack words tag [BATCH NAME] --seniority senior --location Warszawa --work-mode hybrid",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		params := database.UpdateWordBatchAttributesParams{Name: args[0]}
+
+		// Only flags set by the user override attributes, empty value clears an attribute
+		for name, param := range map[string]*pgtype.Text{
+			"seniority": &params.Seniority,
+			"location":  &params.Location,
+			"work-mode": &params.WorkMode,
+		} {
+			if !cmd.Flags().Changed(name) {
+				continue
+			}
+			v, err := cmd.Flags().GetString(name)
+			if err != nil {
+				return fmt.Errorf("get string: %w", err)
+			}
+			*param = pgtype.Text{String: v, Valid: true}
+		}
+		if v := params.Seniority.String; v != "" && !textproc.IsSeniority(v) {
+			return fmt.Errorf("unknown seniority %q", v)
+		}
+		if v := params.WorkMode.String; v != "" && !textproc.IsWorkMode(v) {
+			return fmt.Errorf("unknown work mode %q", v)
+		}
+		if params.Location.Valid {
+			params.Location.String = textproc.NormalizeLocation(params.Location.String)
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		q, err := openStorage(ctx, cfg, l)
+		if err != nil {
+			return err
+		}
+		defer q.Close()
+
+		row, err := q.UpdateWordBatchAttributes(ctx, params)
+		if err != nil {
+			l.Error("Failed to update batch attributes", "err", err.Error())
+
+			return fmt.Errorf("update word batch attributes: %w", err)
+		}
+		fmt.Printf("BATCH: %s | SENIORITY: %s | LOCATION: %s | WORK MODE: %s\n",
+			row.Name, row.Seniority, row.Location, row.WorkMode,
+		)
+
+		l.Info("Program completed successfully.")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+
+	tagCmd.Flags().String("seniority", "", "Seniority of the batch (intern, junior, mid, senior, lead)")
+	tagCmd.Flags().String("location", "", "Location (city) of the batch")
+	tagCmd.Flags().String("work-mode", "", "Work mode of the batch (remote, hybrid, onsite)")
+}
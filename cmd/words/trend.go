@@ -0,0 +1,161 @@
+package words
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/cmd/logger"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/kndrad/piccrack/pkg/trend"
+	"github.com/spf13/cobra"
+)
+
+var trendCmd = &cobra.Command{
+	Use:   "trend [WORD...]",
+	Short: "Displays frequency of words over time and whether they are rising or falling.",
+	Long: `Displays share of postings (batches) containing words per day, week or month.
+Mean share of the last recent buckets is compared to baseline buckets before them.
+Aliases of skills are merged under their canonical name, e.g. golang is counted as go.
+Without words, every word is classified and words with the biggest change are displayed.`,
+	Example: "piccrack words trend go kubernetes --bucket week --recent 4 --baseline 8",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l := logger.New(Verbose)
+
+		window := trend.DefaultWindow()
+		bucketName, err := cmd.Flags().GetString("bucket")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		if window.Bucket, err = trend.ParseBucket(bucketName); err != nil {
+			return fmt.Errorf("parse bucket: %w", err)
+		}
+		if window.Recent, err = cmd.Flags().GetInt("recent"); err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		if window.Baseline, err = cmd.Flags().GetInt("baseline"); err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+		if window.Recent < 1 || window.Baseline < 1 {
+			return fmt.Errorf("recent and baseline must be positive, got %d and %d", window.Recent, window.Baseline)
+		}
+		if window.Threshold, err = cmd.Flags().GetFloat64("threshold"); err != nil {
+			return fmt.Errorf("get float64: %w", err)
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return fmt.Errorf("get int: %w", err)
+		}
+
+		language, err := cmd.Flags().GetString("language")
+		if err != nil {
+			return fmt.Errorf("get string: %w", err)
+		}
+		attrs, err := batchAttributeParams(cmd)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(args))
+		for _, arg := range args {
+			names = append(names, textproc.SkillAliases(arg)...)
+		}
+
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			l.Error("Loading database config", "err", err.Error())
+
+			return fmt.Errorf("loading config: %w", err)
+		}
+		filter, err := textproc.LoadFilter(cfg.Filters.StopWords(), cfg.Filters.Options())
+		if err != nil {
+			l.Error("Loading words filter", "err", err.Error())
+
+			return fmt.Errorf("load filter: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		q, err := openStorage(ctx, cfg, l)
+		if err != nil {
+			return err
+		}
+		defer q.Close()
+
+		now := time.Now()
+		since := window.Since(now)
+		rows, err := q.ListWordTrend(ctx, database.ListWordTrendParams{
+			Bucket:     string(window.Bucket),
+			Since:      pgtype.Timestamptz{Time: since, Valid: true},
+			Words:      names,
+			Language:   pgtype.Text{String: language, Valid: language != ""},
+			Seniority:  attrs.Seniority,
+			Location:   attrs.Location,
+			WorkMode:   attrs.WorkMode,
+			Tag:        attrs.Tag,
+			Collection: attrs.Collection,
+			Excluded:   filter.StopWords(),
+		})
+		if err != nil {
+			l.Error("Failed to list word trend", "err", err.Error())
+
+			return fmt.Errorf("list word trend: %w", err)
+		}
+
+		counts := make([]trend.Count, 0, len(rows))
+		for _, row := range rows {
+			if !filter.Keep(row.Value) {
+				continue
+			}
+			counts = append(counts, trend.Count{
+				Bucket:         row.Bucket.Time,
+				Word:           row.Value,
+				Total:          row.Total,
+				Postings:       row.Postings,
+				BucketPostings: row.BucketPostings,
+			})
+		}
+		series := trend.Build(counts, window.Bucket, since, now, textproc.CanonicalSkill)
+		trends := window.DetectAll(series)
+		if len(trends) > limit {
+			trends = trends[:limit]
+		}
+		l.Info("Detected word trends", "words", len(series), "bucket", window.Bucket, "since", since.Format(time.DateOnly))
+
+		// Series are only printed for requested words, every word would flood the output.
+		if len(args) > 0 {
+			for _, s := range series {
+				fmt.Printf("WORD: %s\n", s.Word)
+				for _, p := range s.Points {
+					fmt.Printf("  %s | TOTAL: %d | POSTINGS: %d | SHARE: %.2f%%\n",
+						p.Bucket.Format(time.DateOnly), p.Total, p.Postings, p.Share,
+					)
+				}
+			}
+		}
+		for _, t := range trends {
+			fmt.Printf("WORD: %s | TREND: %s | RECENT: %.2f%% | BASELINE: %.2f%% | CHANGE: %+.2f pp\n",
+				t.Word, strings.ToUpper(string(t.Direction)), t.Recent, t.Baseline, t.Change,
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trendCmd)
+
+	addBatchAttributeFlags(trendCmd)
+	trendCmd.Flags().String("language", "", "Include only words in language (ISO 639-1 code, e.g. en, pl)")
+	trendCmd.Flags().String("bucket", "week", "Group counts by day, week or month")
+	trendCmd.Flags().Int("recent", 4, "Number of recent buckets compared to baseline")
+	trendCmd.Flags().Int("baseline", 8, "Number of baseline buckets preceding recent buckets")
+	trendCmd.Flags().Float64("threshold", 0.2, "Minimal relative change of share for a word to be rising or falling")
+	trendCmd.Flags().Int("limit", 30, "Maximal number of displayed trends")
+}
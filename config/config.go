@@ -1,9 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
 	"path/filepath"
+	"time"
 
+	"github.com/kndrad/piccrack/pkg/imgstore"
+	"github.com/kndrad/piccrack/pkg/textproc"
 	"github.com/spf13/viper"
 )
 
@@ -11,6 +17,9 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	HTTP     API            `mapstructure:"http"`
 	App      AppConfig      `mapstructure:"app"`
+	Filters  FiltersConfig  `mapstructure:"filters"`
+	Images   ImagesConfig   `mapstructure:"images"`
+	Storage  StorageConfig  `mapstructure:"storage"`
 }
 
 func Load(path string) (*Config, error) {
@@ -19,16 +28,30 @@ func Load(path string) (*Config, error) {
 	v.SetConfigType("yaml")
 	v.SetConfigFile(filepath.Clean(path))
 
-	v.SetDefault("Database.Pool.MaxConns", 25)
-	v.SetDefault("Database.Pool.MinConns", 5)
-	v.SetDefault("Database.Pool.MaxConnLifetime", "1h")
-	v.SetDefault("Database.Pool.MaxConnIdleTime", "30m")
-	v.SetDefault("Database.Pool.ConnectTimeout", "10s")
-	v.SetDefault("Database.Pool.DialerKeepAlive", "5s")
+	v.SetDefault("database.pool.max_conns", 25)
+	v.SetDefault("database.pool.min_conns", 5)
+	v.SetDefault("database.pool.max_conn_lifetime", "1h")
+	v.SetDefault("database.pool.max_conn_idle_time", "30m")
+	v.SetDefault("database.pool.connect_timeout", "10s")
+	v.SetDefault("database.pool.dialer_keep_alive", "5s")
+	v.SetDefault("database.tls.mode", "disable")
+	// DATABASE_URL overrides connection string of the config file.
+	if err := v.BindEnv("database.dsn", "DATABASE_URL"); err != nil {
+		return nil, fmt.Errorf("bind env: %w", err)
+	}
 
 	v.SetDefault("HTTP.Host", "0.0.0.0")
 	v.SetDefault("HTTP.Port", "8080")
 
+	v.SetDefault("filters.min_length", 2)
+	v.SetDefault("filters.drop_numbers", true)
+
+	v.SetDefault("Images.Storage", "none")
+	v.SetDefault("Images.Dir", "data/images")
+
+	v.SetDefault("Storage.Driver", "postgres")
+	v.SetDefault("Storage.Path", "data/piccrack.db")
+
 	v.SetDefault("App.Environment", "development")
 	v.SetDefault("App.LogLevel", "info")
 
@@ -40,6 +63,12 @@ func Load(path string) (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
+	if err := cfg.Database.Validate(); err != nil {
+		return nil, fmt.Errorf("database config: %w", err)
+	}
+	if err := cfg.Storage.Validate(); err != nil {
+		return nil, fmt.Errorf("storage config: %w", err)
+	}
 
 	return &cfg, nil
 }
@@ -49,21 +78,78 @@ type AppConfig struct {
 }
 
 type DatabaseConfig struct {
+	// DSN is a postgres:// connection string which, if set, is used instead of
+	// connection and TLS settings below.
+	DSN      string     `mapstructure:"dsn"`
 	User     string     `mapstructure:"user"`
 	Password string     `mapstructure:"password"`
 	Host     string     `mapstructure:"host"`
 	Port     string     `mapstructure:"port"`
 	Name     string     `mapstructure:"name"`
-	Pool     poolConfig `mapstructure:"pool"`
+	TLS      TLSConfig  `mapstructure:"tls"`
+	Pool     PoolConfig `mapstructure:"pool"`
 }
 
-type poolConfig struct {
-	MaxConns        int    `mapstructure:"max_conns"`
-	MinConns        int    `mapstructure:"min_conns"`
-	MaxConnLifetime string `mapstructure:"max_conn_lifetime"`
-	MaxConnIdleTime string `mapstructure:"max_conn_idle_time"`
-	ConnectTimeout  string `mapstructure:"connect_timeout"`
-	DialerKeepAlive string `mapstructure:"dialer_keep_alive"`
+// TLSConfig configures TLS of database connections.
+type TLSConfig struct {
+	// One of disable, allow, prefer, require, verify-ca or verify-full.
+	Mode string `mapstructure:"mode"`
+	// Root certificate file used to verify the server.
+	RootCert string `mapstructure:"root_cert"`
+	// Client certificate and key files, both or neither must be set.
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+// PoolConfig configures database connection pool. Zero values leave pool defaults.
+type PoolConfig struct {
+	MaxConns        int           `mapstructure:"max_conns"`
+	MinConns        int           `mapstructure:"min_conns"`
+	MaxConnLifetime time.Duration `mapstructure:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `mapstructure:"max_conn_idle_time"`
+	ConnectTimeout  time.Duration `mapstructure:"connect_timeout"`
+	DialerKeepAlive time.Duration `mapstructure:"dialer_keep_alive"`
+}
+
+var ErrInvalidDatabaseConfig = errors.New("invalid database config")
+
+// Validate reports invalid DSN, TLS and pool settings.
+func (c DatabaseConfig) Validate() error {
+	if c.DSN != "" {
+		u, err := url.Parse(c.DSN)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			return fmt.Errorf("%w: dsn must be a postgres:// url", ErrInvalidDatabaseConfig)
+		}
+	}
+
+	switch c.TLS.Mode {
+	case "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		return fmt.Errorf("%w: unknown tls mode %q", ErrInvalidDatabaseConfig, c.TLS.Mode)
+	}
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		return fmt.Errorf("%w: tls cert and key must be set together", ErrInvalidDatabaseConfig)
+	}
+
+	p := c.Pool
+	if p.MaxConns < 0 || p.MinConns < 0 || p.MaxConns > math.MaxInt32 || p.MinConns > math.MaxInt32 {
+		return fmt.Errorf("%w: pool connections out of range", ErrInvalidDatabaseConfig)
+	}
+	if p.MaxConns > 0 && p.MinConns > p.MaxConns {
+		return fmt.Errorf("%w: pool min_conns %d exceeds max_conns %d", ErrInvalidDatabaseConfig, p.MinConns, p.MaxConns)
+	}
+	for name, d := range map[string]time.Duration{
+		"max_conn_lifetime":  p.MaxConnLifetime,
+		"max_conn_idle_time": p.MaxConnIdleTime,
+		"connect_timeout":    p.ConnectTimeout,
+		"dialer_keep_alive":  p.DialerKeepAlive,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%w: negative pool %s %s", ErrInvalidDatabaseConfig, name, d)
+		}
+	}
+
+	return nil
 }
 
 type API struct {
@@ -71,3 +157,73 @@ type API struct {
 	Port       string `mapstructure:"port"`
 	TLSEnabled bool   `mapstructure:"tls_enabled"`
 }
+
+// FiltersConfig configures noise filtering applied to words during ingestion and at query time.
+type FiltersConfig struct {
+	StopWordsFile     string   `mapstructure:"stop_words_file"`
+	MinLength         int      `mapstructure:"min_length"`
+	DropNumbers       bool     `mapstructure:"drop_numbers"`
+	MaxNonLetterRatio float64  `mapstructure:"max_non_letter_ratio"`
+	Patterns          []string `mapstructure:"patterns"`
+}
+
+// StopWords returns user managed stop words file or nil if it's not configured.
+func (c FiltersConfig) StopWords() *textproc.StopWordsFile {
+	if c.StopWordsFile == "" {
+		return nil
+	}
+
+	return textproc.NewStopWordsFile(c.StopWordsFile)
+}
+
+// Options returns filter options of the config.
+func (c FiltersConfig) Options() textproc.FilterOptions {
+	return textproc.FilterOptions{
+		MinLength:         c.MinLength,
+		DropNumbers:       c.DropNumbers,
+		MaxNonLetterRatio: c.MaxNonLetterRatio,
+		Patterns:          c.Patterns,
+	}
+}
+
+// ImagesConfig configures where content of captured images is kept for reprocessing.
+type ImagesConfig struct {
+	// One of none, postgres or disk.
+	Storage string `mapstructure:"storage"`
+	// Directory images are written to with disk storage.
+	Dir string `mapstructure:"dir"`
+}
+
+// Store returns image store of the config.
+func (c ImagesConfig) Store() (*imgstore.Store, error) {
+	mode, err := imgstore.ParseMode(c.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return imgstore.New(mode, c.Dir)
+}
+
+// StorageConfig selects where CLI commands keep their data.
+type StorageConfig struct {
+	// One of postgres, configured by Database, or sqlite.
+	Driver string `mapstructure:"driver"`
+	// Path of SQLite database file.
+	Path string `mapstructure:"path"`
+}
+
+var ErrInvalidStorageConfig = errors.New("invalid storage config")
+
+func (c StorageConfig) Validate() error {
+	switch c.Driver {
+	case "postgres":
+	case "sqlite":
+		if c.Path == "" {
+			return fmt.Errorf("%w: empty sqlite path", ErrInvalidStorageConfig)
+		}
+	default:
+		return fmt.Errorf("%w: unknown driver %q, use postgres or sqlite", ErrInvalidStorageConfig, c.Driver)
+	}
+
+	return nil
+}
@@ -2,9 +2,12 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/pkg/imgstore"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,8 +62,185 @@ database:
 
 	require.Equal(t, 25, cfg.Database.Pool.MaxConns)
 	require.Equal(t, 5, cfg.Database.Pool.MinConns)
-	require.Equal(t, "1h", cfg.Database.Pool.MaxConnLifetime)
-	require.Equal(t, "30m", cfg.Database.Pool.MaxConnIdleTime)
-	require.Equal(t, "10s", cfg.Database.Pool.ConnectTimeout)
-	require.Equal(t, "5s", cfg.Database.Pool.DialerKeepAlive)
+	require.Equal(t, time.Hour, cfg.Database.Pool.MaxConnLifetime)
+	require.Equal(t, 30*time.Minute, cfg.Database.Pool.MaxConnIdleTime)
+	require.Equal(t, 10*time.Second, cfg.Database.Pool.ConnectTimeout)
+	require.Equal(t, 5*time.Second, cfg.Database.Pool.DialerKeepAlive)
+	require.Equal(t, "disable", cfg.Database.TLS.Mode)
+
+	// Images aren't kept by default
+	require.Equal(t, "none", cfg.Images.Storage)
+	store, err := cfg.Images.Store()
+	require.NoError(t, err)
+	require.Equal(t, imgstore.ModeNone, store.Mode())
+}
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	return path
+}
+
+func TestLoadingDatabaseConfig(t *testing.T) {
+	testCases := []struct {
+		desc string
+
+		data string
+		env  string
+		err  bool
+	}{
+		{
+			desc: "defaults",
+
+			data: "database:\n  host: localhost\n",
+		},
+		{
+			desc: "tls",
+
+			data: "database:\n  tls:\n    mode: verify-full\n    root_cert: ca.pem\n    cert: client.pem\n    key: client.key\n",
+		},
+		{
+			desc: "database_url",
+
+			data: "database:\n  host: localhost\n",
+			env:  "postgres://user:secret@db:5432/piccrack?sslmode=require",
+		},
+		{
+			desc: "invalid_duration",
+
+			data: "database:\n  pool:\n    connect_timeout: 10 seconds\n",
+			err:  true,
+		},
+		{
+			desc: "negative_duration",
+
+			data: "database:\n  pool:\n    max_conn_lifetime: -1h\n",
+			err:  true,
+		},
+		{
+			desc: "min_conns_exceed_max_conns",
+
+			data: "database:\n  pool:\n    max_conns: 2\n    min_conns: 3\n",
+			err:  true,
+		},
+		{
+			desc: "unknown_tls_mode",
+
+			data: "database:\n  tls:\n    mode: strict\n",
+			err:  true,
+		},
+		{
+			desc: "tls_cert_without_key",
+
+			data: "database:\n  tls:\n    cert: client.pem\n",
+			err:  true,
+		},
+		{
+			desc: "invalid_database_url",
+
+			data: "database:\n  host: localhost\n",
+			env:  "host=db user=postgres",
+			err:  true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Setenv("DATABASE_URL", tC.env)
+
+			cfg, err := config.Load(writeConfig(t, tC.data))
+			if tC.err {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.env, cfg.Database.DSN)
+			require.Equal(t, 25, cfg.Database.Pool.MaxConns)
+			require.Equal(t, 10*time.Second, cfg.Database.Pool.ConnectTimeout)
+		})
+	}
+}
+
+func TestLoadingStorageConfig(t *testing.T) {
+	testCases := []struct {
+		desc string
+
+		data   string
+		driver string
+		err    bool
+	}{
+		{
+			desc: "defaults",
+
+			data:   "database:\n  host: localhost\n",
+			driver: "postgres",
+		},
+		{
+			desc: "sqlite",
+
+			data:   "storage:\n  driver: sqlite\n  path: words.db\n",
+			driver: "sqlite",
+		},
+		{
+			desc: "unknown_driver",
+
+			data: "storage:\n  driver: mysql\n",
+			err:  true,
+		},
+		{
+			desc: "sqlite_empty_path",
+
+			data: "storage:\n  driver: sqlite\n  path: \"\"\n",
+			err:  true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cfg, err := config.Load(writeConfig(t, tC.data))
+			if tC.err {
+				require.ErrorIs(t, err, config.ErrInvalidStorageConfig)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.driver, cfg.Storage.Driver)
+			require.NotEmpty(t, cfg.Storage.Path)
+		})
+	}
+}
+
+func TestLoadingFiltersConfig(t *testing.T) {
+	testCases := []struct {
+		desc string
+
+		data        string
+		minLength   int
+		dropNumbers bool
+	}{
+		{
+			desc: "defaults_without_filters",
+
+			data:        "database:\n  host: localhost\n",
+			minLength:   2,
+			dropNumbers: true,
+		},
+		{
+			desc: "overridden",
+
+			data:        "filters:\n  min_length: 3\n  drop_numbers: false\n",
+			minLength:   3,
+			dropNumbers: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cfg, err := config.Load(writeConfig(t, tC.data))
+			require.NoError(t, err)
+			require.Equal(t, tC.minLength, cfg.Filters.MinLength)
+			require.Equal(t, tC.dropNumbers, cfg.Filters.DropNumbers)
+		})
+	}
 }
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_words_lemma;
+
+ALTER TABLE words
+DROP COLUMN IF EXISTS lemma;
@@ -0,0 +1,5 @@
+ALTER TABLE words
+ADD COLUMN IF NOT EXISTS lemma TEXT;
+
+CREATE INDEX idx_words_lemma ON words (lemma)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_phrases_language;
+DROP INDEX IF EXISTS idx_words_language;
+
+ALTER TABLE phrases
+DROP COLUMN IF EXISTS language;
+
+ALTER TABLE words
+DROP COLUMN IF EXISTS language;
@@ -0,0 +1,11 @@
+ALTER TABLE words
+ADD COLUMN IF NOT EXISTS language TEXT;
+
+ALTER TABLE phrases
+ADD COLUMN IF NOT EXISTS language TEXT;
+
+CREATE INDEX idx_words_language ON words (language)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_phrases_language ON phrases (language)
+WHERE deleted_at IS NULL;
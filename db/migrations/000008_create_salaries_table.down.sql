@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_salaries_batch_id;
+
+DROP TABLE IF EXISTS salaries;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS salaries (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id BIGINT NOT NULL,
+    min_amount DOUBLE PRECISION NOT NULL,
+    max_amount DOUBLE PRECISION NOT NULL,
+    currency TEXT NOT NULL,
+    period TEXT NOT NULL,
+    monthly_min DOUBLE PRECISION NOT NULL,
+    monthly_max DOUBLE PRECISION NOT NULL,
+    contract TEXT,
+    basis TEXT,
+    seniority TEXT,
+    raw TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT salaries_batch_fkey
+    FOREIGN KEY (batch_id) REFERENCES word_batches (id)
+);
+
+CREATE INDEX idx_salaries_batch_id ON salaries (batch_id)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_word_batches_work_mode;
+DROP INDEX IF EXISTS idx_word_batches_location;
+DROP INDEX IF EXISTS idx_word_batches_seniority;
+
+ALTER TABLE word_batches
+DROP COLUMN IF EXISTS work_mode,
+DROP COLUMN IF EXISTS location,
+DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,13 @@
+ALTER TABLE word_batches
+ADD COLUMN IF NOT EXISTS seniority TEXT,
+ADD COLUMN IF NOT EXISTS location TEXT,
+ADD COLUMN IF NOT EXISTS work_mode TEXT;
+
+CREATE INDEX idx_word_batches_seniority ON word_batches (seniority)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_word_batches_location ON word_batches (LOWER(location))
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_word_batches_work_mode ON word_batches (work_mode)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_phrase_batches_posting_id;
+
+DROP INDEX IF EXISTS idx_word_batches_posting_id;
+
+ALTER TABLE phrase_batches
+DROP COLUMN IF EXISTS posting_id;
+
+ALTER TABLE word_batches
+DROP COLUMN IF EXISTS posting_id;
+
+DROP TABLE IF EXISTS postings;
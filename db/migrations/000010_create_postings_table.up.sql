@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS postings (
+    id BIGSERIAL PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    company TEXT NOT NULL DEFAULT '',
+    source_url TEXT NOT NULL DEFAULT '',
+    captured_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    image_hash TEXT,
+    raw_text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_postings_captured_at ON postings (captured_at)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_postings_company ON postings (LOWER(company))
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_postings_image_hash ON postings (image_hash)
+WHERE deleted_at IS NULL;
+
+ALTER TABLE word_batches
+ADD COLUMN IF NOT EXISTS posting_id BIGINT
+REFERENCES postings (id) ON DELETE CASCADE;
+
+ALTER TABLE phrase_batches
+ADD COLUMN IF NOT EXISTS posting_id BIGINT
+REFERENCES postings (id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id);
+
+CREATE UNIQUE INDEX idx_phrase_batches_posting_id ON phrase_batches (posting_id);
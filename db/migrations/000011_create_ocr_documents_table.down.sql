@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_phrase_batches_posting_id;
+
+DROP INDEX IF EXISTS idx_word_batches_posting_id;
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id);
+
+CREATE UNIQUE INDEX idx_phrase_batches_posting_id ON phrase_batches (posting_id);
+
+DROP TABLE IF EXISTS ocr_documents;
+
+DROP TABLE IF EXISTS images;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS images (
+    id BIGSERIAL PRIMARY KEY,
+    hash TEXT NOT NULL,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    format TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    filename TEXT NOT NULL DEFAULT '',
+    content BYTEA,
+    path TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_images_hash ON images (hash)
+WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS ocr_documents (
+    id BIGSERIAL PRIMARY KEY,
+    posting_id BIGINT REFERENCES postings (id) ON DELETE CASCADE,
+    image_id BIGINT REFERENCES images (id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    engine TEXT NOT NULL,
+    engine_version TEXT NOT NULL DEFAULT '',
+    languages TEXT [] NOT NULL DEFAULT '{}',
+    settings JSONB NOT NULL DEFAULT '{}',
+    confidence DOUBLE PRECISION,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_ocr_documents_posting_id ON ocr_documents (posting_id, created_at)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_ocr_documents_image_id ON ocr_documents (image_id)
+WHERE deleted_at IS NULL;
+
+-- Reprocessed postings own new batches, while replaced ones are kept soft deleted.
+DROP INDEX IF EXISTS idx_word_batches_posting_id;
+
+DROP INDEX IF EXISTS idx_phrase_batches_posting_id;
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id)
+WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX idx_phrase_batches_posting_id ON phrase_batches (posting_id)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS words (
+    id BIGSERIAL PRIMARY KEY,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    batch_id BIGINT,
+    lemma TEXT,
+    language TEXT,
+    CONSTRAINT words_batch_fkey
+    FOREIGN KEY (batch_id) REFERENCES word_batches (id)
+);
+
+CREATE INDEX idx_words_value ON words (value)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_words_batch_id ON words (batch_id) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_words_lemma ON words (lemma)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_words_language ON words (language)
+WHERE deleted_at IS NULL;
+
+-- Expand every occurrence back to a row per word.
+INSERT INTO words (value, lemma, language, batch_id, created_at, deleted_at)
+SELECT
+    vocabulary.raw,
+    NULLIF(vocabulary.lemma, ''),
+    NULLIF(vocabulary.language, ''),
+    occurrences.batch_id,
+    occurrences.created_at,
+    occurrences.deleted_at
+FROM occurrences
+INNER JOIN vocabulary ON occurrences.term_id = vocabulary.id
+CROSS JOIN GENERATE_SERIES(1, occurrences.count);
+
+DROP TABLE IF EXISTS occurrences;
+
+DROP TABLE IF EXISTS vocabulary;
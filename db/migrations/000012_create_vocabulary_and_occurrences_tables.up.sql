@@ -0,0 +1,66 @@
+-- Unique terms. Language and lemma are empty if unknown, so terms stay unique.
+CREATE TABLE IF NOT EXISTS vocabulary (
+    id BIGSERIAL PRIMARY KEY,
+    raw TEXT NOT NULL,
+    normalized TEXT NOT NULL,
+    lemma TEXT NOT NULL DEFAULT '',
+    language TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT vocabulary_raw_language_unique UNIQUE (raw, language),
+    CHECK (LENGTH(raw) > 0)
+);
+
+CREATE INDEX idx_vocabulary_normalized ON vocabulary (normalized);
+
+CREATE INDEX idx_vocabulary_lemma ON vocabulary (lemma)
+WHERE lemma <> '';
+
+CREATE INDEX idx_vocabulary_language ON vocabulary (language);
+
+-- Count of a term in a batch. Terms added without a batch are counted in a row
+-- with NULL batch.
+CREATE TABLE IF NOT EXISTS occurrences (
+    id BIGSERIAL PRIMARY KEY,
+    term_id BIGINT NOT NULL REFERENCES vocabulary (id) ON DELETE CASCADE,
+    batch_id BIGINT REFERENCES word_batches (id) ON DELETE CASCADE,
+    count INTEGER NOT NULL CHECK (count > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT occurrences_term_batch_unique UNIQUE NULLS NOT DISTINCT (
+        term_id, batch_id
+    )
+);
+
+CREATE INDEX idx_occurrences_batch_id ON occurrences (batch_id)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_occurrences_created_at ON occurrences (created_at)
+WHERE deleted_at IS NULL;
+
+-- Convert existing words, soft deleted words are dropped.
+INSERT INTO vocabulary (raw, normalized, lemma, language, created_at)
+SELECT
+    words.value,
+    LOWER(words.value),
+    COALESCE(MAX(words.lemma), ''),
+    COALESCE(words.language, ''),
+    MIN(words.created_at)
+FROM words
+WHERE words.deleted_at IS NULL AND LENGTH(words.value) > 0
+GROUP BY words.value, COALESCE(words.language, '');
+
+INSERT INTO occurrences (term_id, batch_id, count, created_at)
+SELECT
+    vocabulary.id,
+    words.batch_id,
+    COUNT(*),
+    MIN(words.created_at)
+FROM words
+INNER JOIN vocabulary
+    ON
+        words.value = vocabulary.raw
+        AND COALESCE(words.language, '') = vocabulary.language
+WHERE words.deleted_at IS NULL
+GROUP BY vocabulary.id, words.batch_id;
+
+DROP TABLE IF EXISTS words;
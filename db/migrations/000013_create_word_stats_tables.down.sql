@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS occurrences_stats ON occurrences;
+
+DROP FUNCTION IF EXISTS refresh_word_stats();
+
+DROP FUNCTION IF EXISTS occurrences_stats_trigger();
+
+DROP FUNCTION IF EXISTS apply_occurrence_stats(
+    BIGINT, BIGINT, TIMESTAMP WITH TIME ZONE, BIGINT, INTEGER
+);
+
+DROP TABLE IF EXISTS daily_term_stats;
+
+DROP TABLE IF EXISTS batch_stats;
+
+DROP TABLE IF EXISTS term_stats;
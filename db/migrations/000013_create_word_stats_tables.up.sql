@@ -0,0 +1,146 @@
+-- Summaries of occurrences which aren't deleted, kept current by triggers on occurrences.
+-- Postings are numbers of batches, so occurrences without a batch aren't counted as postings.
+CREATE TABLE IF NOT EXISTS term_stats (
+    term_id BIGINT PRIMARY KEY REFERENCES vocabulary (id) ON DELETE CASCADE,
+    total BIGINT NOT NULL DEFAULT 0,
+    postings BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_term_stats_total ON term_stats (total);
+
+CREATE TABLE IF NOT EXISTS batch_stats (
+    batch_id BIGINT PRIMARY KEY REFERENCES word_batches (id) ON DELETE CASCADE,
+    terms BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Days are UTC days of occurrence creation.
+CREATE TABLE IF NOT EXISTS daily_term_stats (
+    day DATE NOT NULL,
+    term_id BIGINT NOT NULL REFERENCES vocabulary (id) ON DELETE CASCADE,
+    total BIGINT NOT NULL DEFAULT 0,
+    postings BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, term_id)
+);
+
+CREATE INDEX idx_daily_term_stats_term_id ON daily_term_stats (term_id, day);
+
+-- Adds count of an occurrence to summaries, or subtracts it if sign is negative.
+-- Subtracting only updates existing rows, as rows of terms and batches deleted
+-- in the same statement are already gone.
+CREATE OR REPLACE FUNCTION apply_occurrence_stats(
+    term BIGINT,
+    batch BIGINT,
+    created TIMESTAMP WITH TIME ZONE,
+    delta BIGINT,
+    sign INTEGER
+) RETURNS VOID AS $$
+DECLARE
+    posting INTEGER := CASE WHEN batch IS NULL THEN 0 ELSE sign END;
+    created_day DATE := (created AT TIME ZONE 'UTC')::date;
+BEGIN
+    IF sign > 0 THEN
+        INSERT INTO term_stats AS s (term_id, total, postings)
+        VALUES (term, delta, posting)
+        ON CONFLICT (term_id) DO UPDATE
+        SET
+            total = s.total + excluded.total,
+            postings = s.postings + excluded.postings,
+            updated_at = CURRENT_TIMESTAMP;
+
+        INSERT INTO daily_term_stats AS s (day, term_id, total, postings)
+        VALUES (created_day, term, delta, posting)
+        ON CONFLICT (day, term_id) DO UPDATE
+        SET
+            total = s.total + excluded.total,
+            postings = s.postings + excluded.postings;
+
+        IF batch IS NOT NULL THEN
+            INSERT INTO batch_stats AS s (batch_id, terms, total)
+            VALUES (batch, 1, delta)
+            ON CONFLICT (batch_id) DO UPDATE
+            SET
+                terms = s.terms + 1,
+                total = s.total + excluded.total,
+                updated_at = CURRENT_TIMESTAMP;
+        END IF;
+    ELSE
+        UPDATE term_stats
+        SET
+            total = total + delta,
+            postings = postings + posting,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE term_id = term;
+
+        UPDATE daily_term_stats
+        SET
+            total = total + delta,
+            postings = postings + posting
+        WHERE day = created_day AND term_id = term;
+
+        UPDATE batch_stats
+        SET
+            terms = terms - 1,
+            total = total + delta,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE batch_id = batch;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION occurrences_stats_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        PERFORM apply_occurrence_stats(
+            OLD.term_id, OLD.batch_id, OLD.created_at, -OLD.count, -1
+        );
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        PERFORM apply_occurrence_stats(
+            NEW.term_id, NEW.batch_id, NEW.created_at, NEW.count, 1
+        );
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER occurrences_stats
+AFTER INSERT OR DELETE OR UPDATE OF term_id, batch_id, count, created_at, deleted_at
+ON occurrences
+FOR EACH ROW EXECUTE FUNCTION occurrences_stats_trigger();
+
+-- Rebuilds summaries from scratch.
+CREATE OR REPLACE FUNCTION refresh_word_stats() RETURNS VOID AS $$
+BEGIN
+    DELETE FROM term_stats;
+    DELETE FROM daily_term_stats;
+    DELETE FROM batch_stats;
+
+    INSERT INTO term_stats (term_id, total, postings)
+    SELECT term_id, SUM(count), COUNT(batch_id)
+    FROM occurrences
+    WHERE deleted_at IS NULL
+    GROUP BY term_id;
+
+    INSERT INTO daily_term_stats (day, term_id, total, postings)
+    SELECT
+        (created_at AT TIME ZONE 'UTC')::date,
+        term_id,
+        SUM(count),
+        COUNT(batch_id)
+    FROM occurrences
+    WHERE deleted_at IS NULL
+    GROUP BY (created_at AT TIME ZONE 'UTC')::date, term_id;
+
+    INSERT INTO batch_stats (batch_id, terms, total)
+    SELECT batch_id, COUNT(*), SUM(count)
+    FROM occurrences
+    WHERE deleted_at IS NULL AND batch_id IS NOT NULL
+    GROUP BY batch_id;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT refresh_word_stats();
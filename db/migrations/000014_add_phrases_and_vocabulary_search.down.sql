@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_vocabulary_normalized_trgm;
+
+DROP INDEX IF EXISTS idx_phrases_value_trgm;
+
+DROP INDEX IF EXISTS idx_phrases_search;
+
+ALTER TABLE phrases
+DROP COLUMN IF EXISTS search;
+
+DROP FUNCTION IF EXISTS search_config(TEXT);
+
+DROP EXTENSION IF EXISTS pg_trgm;
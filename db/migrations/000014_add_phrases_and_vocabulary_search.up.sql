@@ -0,0 +1,46 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Text search configuration of a language code. Postgres doesn't ship a polish
+-- configuration, polish phrases are searched with simple configuration unless
+-- a configuration named polish was installed before this migration.
+CREATE OR REPLACE FUNCTION search_config(language TEXT) RETURNS REGCONFIG AS $$
+    SELECT (
+        CASE language
+            WHEN 'en' THEN 'english'
+            ELSE 'simple'
+        END
+    )::regconfig;
+$$ LANGUAGE sql IMMUTABLE;
+
+DO $migration$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'polish') THEN
+        CREATE OR REPLACE FUNCTION search_config(language TEXT) RETURNS REGCONFIG AS $$
+            SELECT (
+                CASE language
+                    WHEN 'en' THEN 'english'
+                    WHEN 'pl' THEN 'polish'
+                    ELSE 'simple'
+                END
+            )::regconfig;
+        $$ LANGUAGE sql IMMUTABLE;
+    END IF;
+END;
+$migration$;
+
+-- Lexemes of phrase language are weighted above unstemmed lexemes, so phrases match
+-- queries in any language.
+ALTER TABLE phrases
+ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    SETWEIGHT(TO_TSVECTOR(search_config(language), value), 'A')
+    || SETWEIGHT(TO_TSVECTOR('simple', value), 'B')
+) STORED;
+
+CREATE INDEX idx_phrases_search ON phrases USING gin (search)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_phrases_value_trgm ON phrases USING gin (value gin_trgm_ops)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_vocabulary_normalized_trgm ON vocabulary
+USING gin (normalized gin_trgm_ops);
@@ -0,0 +1,9 @@
+ALTER TABLE salaries
+DROP CONSTRAINT IF EXISTS salaries_batch_fkey;
+
+ALTER TABLE salaries
+ADD CONSTRAINT salaries_batch_fkey
+FOREIGN KEY (batch_id) REFERENCES word_batches (id);
+
+ALTER TABLE vocabulary
+DROP COLUMN IF EXISTS deleted_at;
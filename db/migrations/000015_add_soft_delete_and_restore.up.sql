@@ -0,0 +1,61 @@
+-- Soft deleted rows and rows deleted with them share deleted_at, so a restore brings
+-- back exactly the rows deleted together.
+ALTER TABLE vocabulary
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Purged batches take their salaries with them.
+ALTER TABLE salaries
+DROP CONSTRAINT IF EXISTS salaries_batch_fkey;
+
+ALTER TABLE salaries
+ADD CONSTRAINT salaries_batch_fkey
+FOREIGN KEY (batch_id) REFERENCES word_batches (id) ON DELETE CASCADE;
+
+-- Batches of deleted postings were deleted without their rows.
+UPDATE word_batches
+SET deleted_at = postings.deleted_at
+FROM postings
+WHERE
+    word_batches.posting_id = postings.id
+    AND postings.deleted_at IS NOT NULL
+    AND word_batches.deleted_at IS NULL;
+
+UPDATE phrase_batches
+SET deleted_at = postings.deleted_at
+FROM postings
+WHERE
+    phrase_batches.posting_id = postings.id
+    AND postings.deleted_at IS NOT NULL
+    AND phrase_batches.deleted_at IS NULL;
+
+UPDATE occurrences
+SET deleted_at = word_batches.deleted_at
+FROM word_batches
+WHERE
+    occurrences.batch_id = word_batches.id
+    AND word_batches.deleted_at IS NOT NULL
+    AND occurrences.deleted_at IS NULL;
+
+UPDATE salaries
+SET deleted_at = word_batches.deleted_at
+FROM word_batches
+WHERE
+    salaries.batch_id = word_batches.id
+    AND word_batches.deleted_at IS NOT NULL
+    AND salaries.deleted_at IS NULL;
+
+UPDATE phrases
+SET deleted_at = phrase_batches.deleted_at
+FROM phrase_batches
+WHERE
+    phrases.batch_id = phrase_batches.id
+    AND phrase_batches.deleted_at IS NOT NULL
+    AND phrases.deleted_at IS NULL;
+
+UPDATE ocr_documents
+SET deleted_at = postings.deleted_at
+FROM postings
+WHERE
+    ocr_documents.posting_id = postings.id
+    AND postings.deleted_at IS NOT NULL
+    AND ocr_documents.deleted_at IS NULL;
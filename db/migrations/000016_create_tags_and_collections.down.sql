@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS phrase_batch_collections;
+
+DROP VIEW IF EXISTS word_batch_collections;
+
+DROP VIEW IF EXISTS phrase_batch_tags;
+
+DROP VIEW IF EXISTS word_batch_tags;
+
+DROP TABLE IF EXISTS collection_members;
+
+DROP TABLE IF EXISTS tag_members;
+
+DROP TABLE IF EXISTS collections;
+
+DROP TABLE IF EXISTS tags;
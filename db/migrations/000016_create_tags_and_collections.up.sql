@@ -0,0 +1,91 @@
+-- Tags label postings and batches freely, collections group them under a name with
+-- a description. Batches of a tagged posting or a posting in a collection belong to
+-- the tag or the collection too.
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT tags_name_unique UNIQUE (name),
+    CHECK (LENGTH(name) > 0)
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT collections_name_unique UNIQUE (name),
+    CHECK (LENGTH(name) > 0)
+);
+
+-- Members are exactly one of a posting, a word batch or a phrase batch.
+CREATE TABLE IF NOT EXISTS tag_members (
+    tag_id BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    posting_id BIGINT REFERENCES postings (id) ON DELETE CASCADE,
+    word_batch_id BIGINT REFERENCES word_batches (id) ON DELETE CASCADE,
+    phrase_batch_id BIGINT REFERENCES phrase_batches (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT tag_members_unique UNIQUE NULLS NOT DISTINCT (
+        tag_id, posting_id, word_batch_id, phrase_batch_id
+    ),
+    CHECK (NUM_NONNULLS(posting_id, word_batch_id, phrase_batch_id) = 1)
+);
+
+CREATE TABLE IF NOT EXISTS collection_members (
+    collection_id BIGINT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    posting_id BIGINT REFERENCES postings (id) ON DELETE CASCADE,
+    word_batch_id BIGINT REFERENCES word_batches (id) ON DELETE CASCADE,
+    phrase_batch_id BIGINT REFERENCES phrase_batches (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT collection_members_unique UNIQUE NULLS NOT DISTINCT (
+        collection_id, posting_id, word_batch_id, phrase_batch_id
+    ),
+    CHECK (NUM_NONNULLS(posting_id, word_batch_id, phrase_batch_id) = 1)
+);
+
+CREATE INDEX idx_tag_members_posting_id ON tag_members (posting_id);
+
+CREATE INDEX idx_collection_members_posting_id ON collection_members (
+    posting_id
+);
+
+-- Batches of tags and collections, directly or through their posting.
+CREATE OR REPLACE VIEW word_batch_tags AS
+SELECT
+    word_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE OR REPLACE VIEW phrase_batch_tags AS
+SELECT
+    phrase_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN phrase_batches
+    ON
+        m.phrase_batch_id = phrase_batches.id
+        OR m.posting_id = phrase_batches.posting_id;
+
+CREATE OR REPLACE VIEW word_batch_collections AS
+SELECT
+    word_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE OR REPLACE VIEW phrase_batch_collections AS
+SELECT
+    phrase_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN phrase_batches
+    ON
+        m.phrase_batch_id = phrase_batches.id
+        OR m.posting_id = phrase_batches.posting_id;
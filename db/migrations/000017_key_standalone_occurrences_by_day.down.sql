@@ -0,0 +1,34 @@
+DROP INDEX IF EXISTS occurrences_term_day_unique;
+
+DROP INDEX IF EXISTS occurrences_term_batch_unique;
+
+-- Occurrences of a term without a batch are merged into its first row.
+WITH merged AS (
+    SELECT
+        term_id,
+        MIN(id) AS id,
+        COALESCE(SUM(count) FILTER (WHERE deleted_at IS NULL), 0) AS total
+    FROM occurrences
+    WHERE batch_id IS NULL
+    GROUP BY term_id
+    HAVING COUNT(*) > 1
+),
+
+removed AS (
+    DELETE FROM occurrences
+    USING merged
+    WHERE
+        occurrences.term_id = merged.term_id
+        AND occurrences.batch_id IS NULL
+        AND occurrences.id <> merged.id
+)
+
+UPDATE occurrences
+SET
+    count = merged.total,
+    deleted_at = NULL
+FROM merged
+WHERE occurrences.id = merged.id AND merged.total > 0;
+
+ALTER TABLE occurrences ADD CONSTRAINT occurrences_term_batch_unique
+UNIQUE NULLS NOT DISTINCT (term_id, batch_id);
@@ -0,0 +1,13 @@
+-- Terms added without a batch are counted in a row per day (UTC) instead of a single
+-- row, so that trends and daily stats get dates of every ingestion.
+ALTER TABLE occurrences DROP CONSTRAINT IF EXISTS occurrences_term_batch_unique;
+
+CREATE UNIQUE INDEX occurrences_term_batch_unique ON occurrences (
+    term_id, batch_id
+)
+WHERE batch_id IS NOT NULL;
+
+CREATE UNIQUE INDEX occurrences_term_day_unique ON occurrences (
+    term_id, ((created_at AT TIME ZONE 'UTC')::date)
+)
+WHERE batch_id IS NULL;
@@ -0,0 +1,10 @@
+// Package migrations embeds SQL migrations of the database schema so that the binary can
+// apply them without the migrate container.
+package migrations
+
+import "embed"
+
+// FS holds up and down migrations named as expected by golang-migrate.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/cooccur"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+// Cooccurrences counts pairs of known skills asked for together in batches. Aliases are
+// merged under canonical names, other words aren't paired.
+func (svc *service) Cooccurrences(ctx context.Context, f WordFilter) (*cooccur.Matrix, error) {
+	rows, err := svc.q.ListBatchWordSets(ctx, database.ListBatchWordSetsParams{
+		Language:   textParam(f.Language),
+		Seniority:  textParam(f.Seniority),
+		Location:   textParam(f.Location),
+		WorkMode:   textParam(f.WorkMode),
+		Tag:        textParam(f.Tag),
+		Collection: textParam(f.Collection),
+		Excluded:   svc.filter.StopWords(),
+		Skills:     textproc.SkillNames(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list batch word sets: %w", err)
+	}
+
+	sets := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		sets = append(sets, textproc.Skills(row.Words))
+	}
+
+	return cooccur.New(sets), nil
+}
+
+// minCountValue returns minimal number of batches a pair must appear in, 2 by default.
+func minCountValue(values url.Values) (int, error) {
+	v := values.Get("min_count")
+	if v == "" {
+		return 2, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse uint: %w", err)
+	}
+
+	return int(n), nil
+}
+
+// cooccurrenceHandler serves top pairs of words appearing together in batches.
+// With format query value (json, graphml, dot) pairs are exported as a graph.
+func cooccurrenceHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Batches int            `json:"batches"`
+		Rows    []cooccur.Pair `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		minCount, err := minCountValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get min_count query value", err, http.StatusBadRequest)
+
+			return
+		}
+		metric, err := cooccur.ParseMetric(query.Get("metric"))
+		if err != nil {
+			respondJSON(w, "Failed to get metric query value", err, http.StatusBadRequest)
+
+			return
+		}
+		var format cooccur.Format
+		if v := query.Get("format"); v != "" {
+			format, err = cooccur.ParseFormat(v)
+			if err != nil {
+				respondJSON(w, "Failed to get format query value", err, http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		m, err := svc.Cooccurrences(r.Context(), wordFilterValue(query))
+		if err != nil {
+			respondJSON(w, "Failed to count co-occurrences", err, http.StatusInternalServerError)
+
+			return
+		}
+		pairs := m.Pairs(minCount, metric)
+		if len(pairs) > int(limit) {
+			pairs = pairs[:limit]
+		}
+		l.Info("Got co-occurring pairs", "total", len(pairs), "batches", m.Batches(), "metric", metric)
+
+		if format != "" {
+			w.Header().Set("Content-Type", format.ContentType())
+			w.WriteHeader(http.StatusOK)
+			if err := cooccur.Write(w, m.Graph(pairs), format); err != nil {
+				l.Error("Failed to write graph", "err", err.Error())
+			}
+
+			return
+		}
+
+		if err := encode(w, r, http.StatusOK, response{Batches: m.Batches(), Rows: pairs}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
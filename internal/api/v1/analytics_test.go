@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/cooccur"
+	"github.com/stretchr/testify/require"
+)
+
+// newWordSetsStore returns a memory store with three batches asking for skills and
+// generic words, go and kubernetes are asked for together twice under their aliases.
+func newWordSetsStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	q := memory.New()
+	for name, values := range map[string][]string{
+		"backend":  {"experience", "go", "grpc", "kubernetes", "team"},
+		"platform": {"golang", "k8s"},
+		"data":     {"django", "experience", "python", "team"},
+	} {
+		ingest(t, q, database.Ingestion{Name: name, Words: englishWords(values...)})
+	}
+
+	return q
+}
+
+func TestCooccurrenceHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		query       string
+		statusCode  int
+		contentType string
+	}{
+		{
+			desc: "top_pairs",
+
+			query:       "?min_count=2&metric=lift",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			desc: "graphml_export",
+
+			query:       "?min_count=1&format=graphml",
+			statusCode:  http.StatusOK,
+			contentType: "application/graphml+xml",
+		},
+		{
+			desc: "dot_export",
+
+			query:       "?format=dot",
+			statusCode:  http.StatusOK,
+			contentType: "text/vnd.graphviz",
+		},
+		{
+			desc: "unknown_metric",
+
+			query:      "?metric=pagerank",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "unknown_format",
+
+			query:      "?format=svg",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newWordSetsStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/"+tC.query, nil)
+			rr := httptest.NewRecorder()
+			cooccurrenceHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode == http.StatusOK {
+				require.Equal(t, tC.contentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestCooccurrenceHandlerPairs(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newWordSetsStore(t), nil, nil, nil, testLogger())
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/?min_count=2", nil)
+	rr := httptest.NewRecorder()
+	cooccurrenceHandler(svc, testLogger())(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Batches int            `json:"batches"`
+		Rows    []cooccur.Pair `json:"rows"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Equal(t, 3, body.Batches)
+	// Aliases are merged and generic words like "experience" and "team" aren't paired
+	require.Len(t, body.Rows, 1)
+	require.Equal(t, "go", body.Rows[0].Source)
+	require.Equal(t, "kubernetes", body.Rows[0].Target)
+}
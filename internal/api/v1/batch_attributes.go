@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+var (
+	ErrInvalidAttribute = errors.New("invalid batch attribute")
+	ErrBatchNotFound    = errors.New("batch not found")
+)
+
+// AttributesUpdate overrides attributes of a batch. Nil fields are left unchanged,
+// empty fields clear the attribute.
+type AttributesUpdate struct {
+	Seniority *string `json:"seniority"`
+	Location  *string `json:"location"`
+	WorkMode  *string `json:"work_mode"`
+}
+
+// Validate checks that seniority and work mode are known values.
+func (u AttributesUpdate) Validate() error {
+	if u.Seniority != nil && *u.Seniority != "" && !textproc.IsSeniority(*u.Seniority) {
+		return fmt.Errorf("%w: unknown seniority %q", ErrInvalidAttribute, *u.Seniority)
+	}
+	if u.WorkMode != nil && *u.WorkMode != "" && !textproc.IsWorkMode(*u.WorkMode) {
+		return fmt.Errorf("%w: unknown work mode %q", ErrInvalidAttribute, *u.WorkMode)
+	}
+
+	return nil
+}
+
+func (svc *service) UpdateWordBatchAttributes(ctx context.Context, name string, update AttributesUpdate) (database.UpdateWordBatchAttributesRow, error) {
+	if err := update.Validate(); err != nil {
+		return database.UpdateWordBatchAttributesRow{}, err
+	}
+	if update.Location != nil {
+		location := textproc.NormalizeLocation(*update.Location)
+		update.Location = &location
+	}
+
+	row, err := svc.q.UpdateWordBatchAttributes(ctx, database.UpdateWordBatchAttributesParams{
+		Name:      name,
+		Seniority: optionalTextParam(update.Seniority),
+		Location:  optionalTextParam(update.Location),
+		WorkMode:  optionalTextParam(update.WorkMode),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return row, fmt.Errorf("%w: %s", ErrBatchNotFound, name)
+		}
+
+		return row, fmt.Errorf("update word batch attributes: %w", err)
+	}
+
+	return row, nil
+}
+
+// optionalTextParam converts optional value into a nullable query param. Unlike textParam,
+// empty string is a valid value.
+func optionalTextParam(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func updateWordBatchAttributesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.UpdateWordBatchAttributesRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		update, err := decode[AttributesUpdate](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.UpdateWordBatchAttributes(r.Context(), name, update)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidAttribute):
+				respondJSON(w, "Invalid batch attributes", err, http.StatusBadRequest)
+			case errors.Is(err, ErrBatchNotFound):
+				respondJSON(w, "Batch not found", err, http.StatusNotFound)
+			default:
+				respondJSON(w, "Failed to update batch attributes", err, http.StatusInternalServerError)
+			}
+
+			return
+		}
+		l.Info("Updated batch attributes", "batch", row.Name, "seniority", row.Seniority, "location", row.Location, "work_mode", row.WorkMode)
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateWordBatchAttributesHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		batch      string
+		body       string
+		statusCode int
+		want       database.UpdateWordBatchAttributesRow
+	}{
+		{
+			desc: "overrides_attributes_and_normalizes_location",
+
+			batch:      "test_batch",
+			body:       `{"seniority":"lead","location":"warsaw","work_mode":"remote"}`,
+			statusCode: http.StatusOK,
+			want: database.UpdateWordBatchAttributesRow{
+				ID:        1,
+				Name:      "test_batch",
+				Seniority: "lead",
+				Location:  "Warszawa",
+				WorkMode:  "remote",
+			},
+		},
+		{
+			desc: "rejects_unknown_seniority",
+
+			batch:      "test_batch",
+			body:       `{"seniority":"rockstar"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "missing_batch",
+
+			batch:      "missing",
+			body:       `{"work_mode":"hybrid"}`,
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPatch, "/", strings.NewReader(tC.body))
+			req.SetPathValue("name", tC.batch)
+			rr := httptest.NewRecorder()
+			updateWordBatchAttributesHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+			var body struct {
+				Row database.UpdateWordBatchAttributesRow `json:"row"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+			require.Equal(t, tC.want, body.Row)
+		})
+	}
+}
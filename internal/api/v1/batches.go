@@ -0,0 +1,691 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+var (
+	ErrUnknownBatchKind = errors.New("unknown batch kind")
+	ErrInvalidBatch     = errors.New("invalid batch")
+	ErrBatchNameTaken   = errors.New("batch name taken")
+	ErrWordNotFound     = errors.New("word not found")
+	// Restored batch would replace a batch of its posting which isn't deleted.
+	ErrRestoreConflict = errors.New("restore conflicts with existing row")
+	ErrInvalidPurgeAge = errors.New("purge age must not be negative")
+)
+
+// BatchKind is a kind of batch, words or phrases.
+type BatchKind string
+
+const (
+	BatchWords   BatchKind = "words"
+	BatchPhrases BatchKind = "phrases"
+)
+
+func ParseBatchKind(s string) (BatchKind, error) {
+	switch k := BatchKind(s); k {
+	case BatchWords, BatchPhrases:
+		return k, nil
+	default:
+		return "", fmt.Errorf("%w: %q, use words or phrases", ErrUnknownBatchKind, s)
+	}
+}
+
+// BatchFilter selects listed batches. Zero fields don't filter.
+type BatchFilter struct {
+	Kind BatchKind
+	// Batches created in [From, To).
+	From time.Time
+	To   time.Time
+	// Prefix of batch names.
+	Prefix string
+}
+
+// BatchDetails is a batch with its words or phrases, depending on its kind.
+type BatchDetails struct {
+	database.ListBatchesRow
+
+	Words   []database.ListBatchWordsRow   `json:"words,omitempty"`
+	Phrases []database.ListBatchPhrasesRow `json:"phrases,omitempty"`
+}
+
+// BatchSplit is a batch created by split with number of words or phrases moved into it.
+type BatchSplit struct {
+	ID    int64 `json:"id"`
+	Moved int64 `json:"moved"`
+}
+
+// ListBatches returns batches of both kinds, oldest first.
+func (svc *service) ListBatches(ctx context.Context, limit, offset int32, f BatchFilter) ([]database.ListBatchesRow, error) {
+	params := database.ListBatchesParams{
+		Limit:  limit,
+		Offset: offset,
+		Kind:   textParam(string(f.Kind)),
+		Prefix: textParam(f.Prefix),
+	}
+	if !f.From.IsZero() {
+		params.CreatedFrom = timestamptzParam(f.From)
+	}
+	if !f.To.IsZero() {
+		params.CreatedTo = timestamptzParam(f.To)
+	}
+
+	rows, err := svc.q.ListBatches(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("list batches: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetBatch returns batch of kind with its stats and words, most frequent first, or phrases.
+func (svc *service) GetBatch(ctx context.Context, kind BatchKind, id int64) (BatchDetails, error) {
+	var details BatchDetails
+
+	row, err := svc.getBatch(ctx, kind, id)
+	if err != nil {
+		return details, err
+	}
+	details.ListBatchesRow = row
+
+	switch kind {
+	case BatchWords:
+		details.Words, err = svc.q.ListBatchWords(ctx, id)
+		if err != nil {
+			return details, fmt.Errorf("list batch words: %w", err)
+		}
+	case BatchPhrases:
+		details.Phrases, err = svc.q.ListBatchPhrases(ctx, id)
+		if err != nil {
+			return details, fmt.Errorf("list batch phrases: %w", err)
+		}
+	}
+
+	return details, nil
+}
+
+func (svc *service) getBatch(ctx context.Context, kind BatchKind, id int64) (database.ListBatchesRow, error) {
+	var (
+		row database.ListBatchesRow
+		err error
+	)
+	switch kind {
+	case BatchWords:
+		var wb database.GetWordBatchRow
+		wb, err = svc.q.GetWordBatch(ctx, id)
+		row = database.ListBatchesRow(wb)
+	case BatchPhrases:
+		var pb database.GetPhraseBatchRow
+		pb, err = svc.q.GetPhraseBatch(ctx, id)
+		row = database.ListBatchesRow(pb)
+	default:
+		return row, fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return row, fmt.Errorf("%w: %s %d", ErrBatchNotFound, kind, id)
+		}
+
+		return row, fmt.Errorf("get %s batch: %w", kind, err)
+	}
+
+	return row, nil
+}
+
+// RenameBatch renames batch of kind and returns it. Names of phrase batches are unique.
+func (svc *service) RenameBatch(ctx context.Context, kind BatchKind, id int64, name string) (database.ListBatchesRow, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return database.ListBatchesRow{}, fmt.Errorf("%w: empty name", ErrInvalidBatch)
+	}
+
+	var err error
+	switch kind {
+	case BatchWords:
+		_, err = svc.q.RenameWordBatch(ctx, database.RenameWordBatchParams{ID: id, Name: name})
+	case BatchPhrases:
+		_, err = svc.q.RenamePhraseBatch(ctx, database.RenamePhraseBatchParams{ID: id, Name: name})
+	default:
+		return database.ListBatchesRow{}, fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		return database.ListBatchesRow{}, batchErr("rename", kind, id, name, err)
+	}
+
+	return svc.getBatch(ctx, kind, id)
+}
+
+// MergeBatches moves words and salaries, or phrases, of source batch into target batch
+// and deletes source batch. Counts of words in both batches are summed. Returns number of
+// moved words or phrases.
+func (svc *service) MergeBatches(ctx context.Context, kind BatchKind, target, source int64) (int64, error) {
+	if target == source {
+		return 0, fmt.Errorf("%w: batch %d merged into itself", ErrInvalidBatch, target)
+	}
+
+	var (
+		moved int64
+		err   error
+	)
+	switch kind {
+	case BatchWords:
+		moved, err = svc.q.MergeWordBatches(ctx, database.MergeWordBatchesParams{Target: target, Source: source})
+	case BatchPhrases:
+		moved, err = svc.q.MergePhraseBatches(ctx, database.MergePhraseBatchesParams{Target: target, Source: source})
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %s %d or %d", ErrBatchNotFound, kind, target, source)
+		}
+
+		return 0, fmt.Errorf("merge %s batches: %w", kind, err)
+	}
+
+	return moved, nil
+}
+
+// SplitBatch moves words or phrases equal to values out of batch into a new batch named
+// name. Words are compared case insensitively. New word batch has attributes of batch,
+// its salaries stay in batch.
+func (svc *service) SplitBatch(ctx context.Context, kind BatchKind, id int64, name string, values []string) (BatchSplit, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return BatchSplit{}, fmt.Errorf("%w: empty name", ErrInvalidBatch)
+	}
+	if len(values) == 0 {
+		return BatchSplit{}, fmt.Errorf("%w: no values to split", ErrInvalidBatch)
+	}
+
+	var (
+		split BatchSplit
+		err   error
+	)
+	switch kind {
+	case BatchWords:
+		normalized := make([]string, 0, len(values))
+		for _, v := range values {
+			normalized = append(normalized, strings.ToLower(v))
+		}
+		var row database.SplitWordBatchRow
+		row, err = svc.q.SplitWordBatch(ctx, database.SplitWordBatchParams{ID: id, Name: name, Values: normalized})
+		split = BatchSplit(row)
+	case BatchPhrases:
+		var row database.SplitPhraseBatchRow
+		row, err = svc.q.SplitPhraseBatch(ctx, database.SplitPhraseBatchParams{ID: id, Name: name, Values: values})
+		split = BatchSplit(row)
+	default:
+		return split, fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		return split, batchErr("split", kind, id, name, err)
+	}
+
+	return split, nil
+}
+
+// batchErr wraps err of op on batch named name into a sentinel error.
+func batchErr(op string, kind BatchKind, id int64, name string, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("%w: %s %d", ErrBatchNotFound, kind, id)
+	case isUniqueViolation(err):
+		return fmt.Errorf("%w: %s %q", ErrBatchNameTaken, kind, name)
+	default:
+		return fmt.Errorf("%s %s batch: %w", op, kind, err)
+	}
+}
+
+// Deleted rows are soft deleted, so they're hidden from queries until restored or purged.
+// Rows of a batch, posting or word are deleted and restored with it.
+
+// DeleteBatch soft deletes batch of kind with its words (occurrences) and salaries, or phrases.
+func (svc *service) DeleteBatch(ctx context.Context, kind BatchKind, id int64) error {
+	var err error
+	switch kind {
+	case BatchWords:
+		_, err = svc.q.DeleteWordBatch(ctx, id)
+	case BatchPhrases:
+		_, err = svc.q.DeletePhraseBatch(ctx, id)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s %d", ErrBatchNotFound, kind, id)
+		}
+
+		return fmt.Errorf("delete %s batch: %w", kind, err)
+	}
+
+	return nil
+}
+
+// RestoreBatch restores deleted batch of kind with rows deleted with it. Batches of
+// deleted postings are restored with their posting.
+func (svc *service) RestoreBatch(ctx context.Context, kind BatchKind, id int64) error {
+	var err error
+	switch kind {
+	case BatchWords:
+		_, err = svc.q.RestoreWordBatch(ctx, id)
+	case BatchPhrases:
+		_, err = svc.q.RestorePhraseBatch(ctx, id)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownBatchKind, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: deleted %s %d", ErrBatchNotFound, kind, id)
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: posting of %s batch %d has another batch", ErrRestoreConflict, kind, id)
+		}
+
+		return fmt.Errorf("restore %s batch: %w", kind, err)
+	}
+
+	return nil
+}
+
+// DeleteWord soft deletes word with its occurrences in every batch. Word stored again is restored.
+func (svc *service) DeleteWord(ctx context.Context, id int64) error {
+	if _, err := svc.q.DeleteWord(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %d", ErrWordNotFound, id)
+		}
+
+		return fmt.Errorf("delete word: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreWord restores deleted word with occurrences deleted with it.
+func (svc *service) RestoreWord(ctx context.Context, id int64) error {
+	if _, err := svc.q.RestoreWord(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: deleted %d", ErrWordNotFound, id)
+		}
+
+		return fmt.Errorf("restore word: %w", err)
+	}
+
+	return nil
+}
+
+// Purge hard deletes rows soft deleted more than age ago.
+func (svc *service) Purge(ctx context.Context, age time.Duration) (database.PurgeDeletedRow, error) {
+	if age < 0 {
+		return database.PurgeDeletedRow{}, ErrInvalidPurgeAge
+	}
+
+	row, err := svc.q.PurgeDeleted(ctx, timestamptzParam(time.Now().Add(-age)))
+	if err != nil {
+		return row, fmt.Errorf("purge deleted: %w", err)
+	}
+
+	return row, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// respondBatchErr responds with status code matching err of batch, delete and restore
+// service methods.
+func respondBatchErr(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownBatchKind):
+		respondJSON(w, "Unknown batch kind", err, http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidBatch):
+		respondJSON(w, "Invalid batch", err, http.StatusBadRequest)
+	case errors.Is(err, ErrBatchNameTaken):
+		respondJSON(w, "Batch name taken", err, http.StatusConflict)
+	case errors.Is(err, ErrBatchNotFound):
+		respondJSON(w, "Batch not found", err, http.StatusNotFound)
+	case errors.Is(err, ErrWordNotFound):
+		respondJSON(w, "Word not found", err, http.StatusNotFound)
+	case errors.Is(err, ErrRestoreConflict):
+		respondJSON(w, "Restore conflicts with existing batch", err, http.StatusConflict)
+	default:
+		respondPostingErr(w, msg, err)
+	}
+}
+
+// batchValue returns kind and ID of batch of request path.
+func batchValue(r *http.Request) (BatchKind, int64, error) {
+	kind, err := ParseBatchKind(r.PathValue("kind"))
+	if err != nil {
+		return "", 0, err
+	}
+	id, err := idValue(r)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: id: %w", ErrInvalidBatch, err)
+	}
+
+	return kind, id, nil
+}
+
+// batchFilterValue returns batch filter from type, from, to and prefix query values.
+func batchFilterValue(values url.Values) (BatchFilter, error) {
+	f := BatchFilter{Prefix: values.Get("prefix")}
+
+	if v := values.Get("type"); v != "" {
+		kind, err := ParseBatchKind(v)
+		if err != nil {
+			return f, err
+		}
+		f.Kind = kind
+	}
+	var err error
+	if f.From, err = timeValue(values, "from"); err != nil {
+		return f, err
+	}
+	if f.To, err = timeValue(values, "to"); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+func listBatchesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Rows []database.ListBatchesRow `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		offset, err := offsetValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
+
+			return
+		}
+		f, err := batchFilterValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get batch filter query values", err, http.StatusBadRequest)
+
+			return
+		}
+
+		rows, err := svc.ListBatches(r.Context(), limit, offset, f)
+		if err != nil {
+			respondJSON(w, "Failed to list batches", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Listed batches", "total", len(rows))
+
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func getBatchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row BatchDetails `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to get batch", err)
+
+			return
+		}
+
+		details, err := svc.GetBatch(r.Context(), kind, id)
+		if err != nil {
+			respondBatchErr(w, "Failed to get batch", err)
+
+			return
+		}
+		l.Info("Got batch", "kind", kind, "id", id)
+
+		if err := encode(w, r, http.StatusOK, response{Row: details}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func renameBatchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type request struct {
+		Name string `json:"name"`
+	}
+	type response struct {
+		Row database.ListBatchesRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to rename batch", err)
+
+			return
+		}
+		req, err := decode[request](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.RenameBatch(r.Context(), kind, id, req.Name)
+		if err != nil {
+			respondBatchErr(w, "Failed to rename batch", err)
+
+			return
+		}
+		l.Info("Renamed batch", "kind", kind, "id", id, "name", row.Name)
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+// mergeBatchesHandler merges batch of source request value into batch of request path.
+func mergeBatchesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type request struct {
+		Source int64 `json:"source"`
+	}
+	type response struct {
+		Moved int64 `json:"moved"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to merge batches", err)
+
+			return
+		}
+		req, err := decode[request](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		moved, err := svc.MergeBatches(r.Context(), kind, id, req.Source)
+		if err != nil {
+			respondBatchErr(w, "Failed to merge batches", err)
+
+			return
+		}
+		l.Info("Merged batches", "kind", kind, "target", id, "source", req.Source, "moved", moved)
+
+		if err := encode(w, r, http.StatusOK, response{Moved: moved}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func splitBatchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type request struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+	type response struct {
+		Row BatchSplit `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to split batch", err)
+
+			return
+		}
+		req, err := decode[request](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		split, err := svc.SplitBatch(r.Context(), kind, id, req.Name, req.Values)
+		if err != nil {
+			respondBatchErr(w, "Failed to split batch", err)
+
+			return
+		}
+		l.Info("Split batch", "kind", kind, "id", id, "new_id", split.ID, "moved", split.Moved)
+
+		if err := encode(w, r, http.StatusCreated, response{Row: split}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func deleteBatchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to get batch", err)
+
+			return
+		}
+
+		if err := svc.DeleteBatch(r.Context(), kind, id); err != nil {
+			respondBatchErr(w, "Failed to delete batch", err)
+
+			return
+		}
+		l.Info("Deleted batch", "kind", kind, "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func restoreBatchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind, id, err := batchValue(r)
+		if err != nil {
+			respondBatchErr(w, "Failed to get batch", err)
+
+			return
+		}
+
+		if err := svc.RestoreBatch(r.Context(), kind, id); err != nil {
+			respondBatchErr(w, "Failed to restore batch", err)
+
+			return
+		}
+		l.Info("Restored batch", "kind", kind, "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func deleteWordHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get word id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := svc.DeleteWord(r.Context(), id); err != nil {
+			respondBatchErr(w, "Failed to delete word", err)
+
+			return
+		}
+		l.Info("Deleted word", "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func restoreWordHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get word id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := svc.RestoreWord(r.Context(), id); err != nil {
+			respondBatchErr(w, "Failed to restore word", err)
+
+			return
+		}
+		l.Info("Restored word", "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func restorePostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := svc.RestorePosting(r.Context(), id); err != nil {
+			respondBatchErr(w, "Failed to restore posting", err)
+
+			return
+		}
+		l.Info("Restored posting", "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,323 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler    func(Service, *slog.Logger) http.HandlerFunc
+		method     string
+		kind       string
+		id         string
+		query      string
+		body       string
+		statusCode int
+	}{
+		{
+			desc: "list",
+
+			handler:    listBatchesHandler,
+			method:     http.MethodGet,
+			query:      "?type=phrases&prefix=test&from=2024-01-01",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "list_unknown_type",
+
+			handler:    listBatchesHandler,
+			method:     http.MethodGet,
+			query:      "?type=salaries",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "list_invalid_from",
+
+			handler:    listBatchesHandler,
+			method:     http.MethodGet,
+			query:      "?from=yesterday",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "get_word_batch",
+
+			handler:    getBatchHandler,
+			method:     http.MethodGet,
+			kind:       "words",
+			id:         "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "get_missing_phrase_batch",
+
+			handler:    getBatchHandler,
+			method:     http.MethodGet,
+			kind:       "phrases",
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "rename",
+
+			handler:    renameBatchHandler,
+			method:     http.MethodPatch,
+			kind:       "words",
+			id:         "1",
+			body:       `{"name": "renamed"}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "rename_to_empty_name",
+
+			handler:    renameBatchHandler,
+			method:     http.MethodPatch,
+			kind:       "words",
+			id:         "1",
+			body:       `{"name": " "}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "rename_to_taken_name",
+
+			handler:    renameBatchHandler,
+			method:     http.MethodPatch,
+			kind:       "phrases",
+			id:         "1",
+			body:       `{"name": "taken"}`,
+			statusCode: http.StatusConflict,
+		},
+		{
+			desc: "merge",
+
+			handler:    mergeBatchesHandler,
+			method:     http.MethodPost,
+			kind:       "words",
+			id:         "1",
+			body:       `{"source": 2}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "merge_into_itself",
+
+			handler:    mergeBatchesHandler,
+			method:     http.MethodPost,
+			kind:       "phrases",
+			id:         "1",
+			body:       `{"source": 1}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "merge_missing_source",
+
+			handler:    mergeBatchesHandler,
+			method:     http.MethodPost,
+			kind:       "phrases",
+			id:         "1",
+			body:       `{"source": 99}`,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "split",
+
+			handler:    splitBatchHandler,
+			method:     http.MethodPost,
+			kind:       "words",
+			id:         "1",
+			body:       `{"name": "split", "values": ["Go"]}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			desc: "split_without_values",
+
+			handler:    splitBatchHandler,
+			method:     http.MethodPost,
+			kind:       "words",
+			id:         "1",
+			body:       `{"name": "split"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "split_into_taken_name",
+
+			handler:    splitBatchHandler,
+			method:     http.MethodPost,
+			kind:       "phrases",
+			id:         "1",
+			body:       `{"name": "taken", "values": ["Remote work"]}`,
+			statusCode: http.StatusConflict,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), tC.method, "/"+tC.query, strings.NewReader(tC.body))
+			req.SetPathValue("kind", tC.kind)
+			req.SetPathValue("id", tC.id)
+			rr := httptest.NewRecorder()
+			tC.handler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestGetBatch(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	words, err := svc.GetBatch(context.Background(), BatchWords, 1)
+	require.NoError(t, err)
+	require.Equal(t, "words", words.Kind)
+	require.Len(t, words.Words, 2)
+	require.Empty(t, words.Phrases)
+
+	phrases, err := svc.GetBatch(context.Background(), BatchPhrases, 1)
+	require.NoError(t, err)
+	require.Equal(t, "phrases", phrases.Kind)
+	require.Len(t, phrases.Phrases, 1)
+	require.Empty(t, phrases.Words)
+}
+
+func TestDeletionHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler    func(Service, *slog.Logger) http.HandlerFunc
+		method     string
+		kind       string
+		id         string
+		statusCode int
+	}{
+		{
+			desc: "delete_word_batch",
+
+			handler:    deleteBatchHandler,
+			method:     http.MethodDelete,
+			kind:       "words",
+			id:         "1",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "delete_missing_phrase_batch",
+
+			handler:    deleteBatchHandler,
+			method:     http.MethodDelete,
+			kind:       "phrases",
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "delete_unknown_batch_kind",
+
+			handler:    deleteBatchHandler,
+			method:     http.MethodDelete,
+			kind:       "salaries",
+			id:         "1",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "restore_phrase_batch",
+
+			handler:    restoreBatchHandler,
+			method:     http.MethodPost,
+			kind:       "phrases",
+			id:         "4",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "restore_conflicting_word_batch",
+
+			handler:    restoreBatchHandler,
+			method:     http.MethodPost,
+			kind:       "words",
+			id:         "3",
+			statusCode: http.StatusConflict,
+		},
+		{
+			desc: "restore_batch_with_invalid_id",
+
+			handler:    restoreBatchHandler,
+			method:     http.MethodPost,
+			kind:       "words",
+			id:         "one",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "delete_word",
+
+			handler:    deleteWordHandler,
+			method:     http.MethodDelete,
+			id:         "1",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "restore_missing_word",
+
+			handler:    restoreWordHandler,
+			method:     http.MethodPost,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "restore_posting",
+
+			handler:    restorePostingHandler,
+			method:     http.MethodPost,
+			id:         "3",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "restore_missing_posting",
+
+			handler:    restorePostingHandler,
+			method:     http.MethodPost,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), tC.method, "/", nil)
+			req.SetPathValue("kind", tC.kind)
+			req.SetPathValue("id", tC.id)
+			rr := httptest.NewRecorder()
+			tC.handler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestPurgeRejectsNegativeAge(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	_, err := svc.Purge(context.Background(), -time.Hour)
+	require.ErrorIs(t, err, ErrInvalidPurgeAge)
+
+	_, err = svc.Purge(context.Background(), 30*24*time.Hour)
+	require.NoError(t, err)
+}
@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/compare"
+)
+
+var ErrEmptySet = errors.New("compared set selects no batches")
+
+// SetFilter selects batches of a compared set by their names, by time range of their
+// creation [From, To) and by attributes. Empty fields don't filter.
+type SetFilter struct {
+	Batches []string
+	From    time.Time
+	To      time.Time
+	WordFilter
+}
+
+// Empty reports whether filter selects every batch.
+func (f SetFilter) Empty() bool {
+	return len(f.Batches) == 0 && f.From.IsZero() && f.To.IsZero() && f.WordFilter == WordFilter{}
+}
+
+// CompareWords compares words of batches selected by a to words of batches selected by b.
+func (svc *service) CompareWords(ctx context.Context, a, b SetFilter, opts compare.Options) (compare.Result, error) {
+	if a.Empty() || b.Empty() {
+		return compare.Result{}, ErrEmptySet
+	}
+
+	sets := make([]map[string]int64, 0, 2)
+	for _, f := range []SetFilter{a, b} {
+		rows, err := svc.q.ListWordCountsInSet(ctx, database.ListWordCountsInSetParams{
+			Batches:     f.Batches,
+			CreatedFrom: timestamptzParam(f.From),
+			CreatedTo:   timestamptzParam(f.To),
+			Language:    textParam(f.Language),
+			Seniority:   textParam(f.Seniority),
+			Location:    textParam(f.Location),
+			WorkMode:    textParam(f.WorkMode),
+			Tag:         textParam(f.Tag),
+			Collection:  textParam(f.Collection),
+			Excluded:    svc.filter.StopWords(),
+		})
+		if err != nil {
+			return compare.Result{}, fmt.Errorf("list word counts in set: %w", err)
+		}
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			if svc.filter.Keep(row.Value) {
+				counts[row.Value] = row.Total
+			}
+		}
+		sets = append(sets, counts)
+	}
+
+	return compare.Compare(sets[0], sets[1], opts), nil
+}
+
+// ComparePhrases compares phrases of batches selected by a to phrases of batches selected by b.
+// Phrase batches have no attributes, so only names, time range and language are used.
+func (svc *service) ComparePhrases(ctx context.Context, a, b SetFilter, opts compare.Options) (compare.Result, error) {
+	if a.Empty() || b.Empty() {
+		return compare.Result{}, ErrEmptySet
+	}
+
+	sets := make([]map[string]int64, 0, 2)
+	for _, f := range []SetFilter{a, b} {
+		rows, err := svc.q.ListPhraseCountsInSet(ctx, database.ListPhraseCountsInSetParams{
+			Batches:     f.Batches,
+			CreatedFrom: timestamptzParam(f.From),
+			CreatedTo:   timestamptzParam(f.To),
+			Language:    textParam(f.Language),
+			Tag:         textParam(f.Tag),
+			Collection:  textParam(f.Collection),
+		})
+		if err != nil {
+			return compare.Result{}, fmt.Errorf("list phrase counts in set: %w", err)
+		}
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			counts[row.Value] = row.Total
+		}
+		sets = append(sets, counts)
+	}
+
+	return compare.Compare(sets[0], sets[1], opts), nil
+}
+
+// timestamptzParam converts optional time into a nullable query param.
+func timestamptzParam(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// ParseTime parses v as a date (2006-01-02) or RFC 3339 time.
+func ParseTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return t, fmt.Errorf("parse time: %w", err)
+	}
+
+	return t, nil
+}
+
+// timeValue parses key query value with ParseTime. Missing value is zero time.
+func timeValue(values url.Values, key string) (time.Time, error) {
+	v := values.Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := ParseTime(v)
+	if err != nil {
+		return t, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return t, nil
+}
+
+// setFilterValue returns filter of set from query values prefixed with prefix,
+// e.g. a_batch, a_from, a_to, a_location, a_tag.
+func setFilterValue(values url.Values, prefix string) (SetFilter, error) {
+	f := SetFilter{
+		Batches: listValue(values, prefix+"batch"),
+		WordFilter: WordFilter{
+			Language:   values.Get(prefix + "language"),
+			Seniority:  values.Get(prefix + "seniority"),
+			Location:   values.Get(prefix + "location"),
+			WorkMode:   values.Get(prefix + "work_mode"),
+			Tag:        values.Get(prefix + "tag"),
+			Collection: values.Get(prefix + "collection"),
+		},
+	}
+
+	var err error
+	if f.From, err = timeValue(values, prefix+"from"); err != nil {
+		return f, err
+	}
+	if f.To, err = timeValue(values, prefix+"to"); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// compareOptionsValue returns comparison options from min_count and min_score query values.
+func compareOptionsValue(values url.Values) (compare.Options, error) {
+	opts := compare.DefaultOptions()
+
+	if v := values.Get("min_count"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return opts, fmt.Errorf("parse min_count: %w", err)
+		}
+		opts.MinCount = int64(n)
+	}
+	if v := values.Get("min_score"); v != "" {
+		score, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, fmt.Errorf("parse min_score: %w", err)
+		}
+		opts.MinScore = score
+	}
+
+	return opts, nil
+}
+
+// compareHandler serves terms which appeared, disappeared, rose or fell between set A
+// and set B. Sets are selected by query values prefixed with a_ and b_. Kind query value
+// compares words (default) or phrases, status keeps only terms of that status.
+func compareHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		opts, err := compareOptionsValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get comparison options", err, http.StatusBadRequest)
+
+			return
+		}
+		status, err := compare.ParseStatus(query.Get("status"))
+		if err != nil {
+			respondJSON(w, "Failed to get status query value", err, http.StatusBadRequest)
+
+			return
+		}
+		a, err := setFilterValue(query, "a_")
+		if err != nil {
+			respondJSON(w, "Failed to get set A query values", err, http.StatusBadRequest)
+
+			return
+		}
+		b, err := setFilterValue(query, "b_")
+		if err != nil {
+			respondJSON(w, "Failed to get set B query values", err, http.StatusBadRequest)
+
+			return
+		}
+
+		var res compare.Result
+		switch kind := query.Get("kind"); kind {
+		case "", "words":
+			res, err = svc.CompareWords(r.Context(), a, b, opts)
+		case "phrases":
+			res, err = svc.ComparePhrases(r.Context(), a, b, opts)
+		default:
+			respondJSON(w, fmt.Sprintf("Unknown kind query value %q, use words or phrases", kind), nil, http.StatusBadRequest)
+
+			return
+		}
+		if err != nil {
+			if errors.Is(err, ErrEmptySet) {
+				respondJSON(w, "Both sets must select batches", err, http.StatusBadRequest)
+
+				return
+			}
+			respondJSON(w, "Failed to compare sets", err, http.StatusInternalServerError)
+
+			return
+		}
+
+		res.Terms = res.Filter(status)
+		if len(res.Terms) > int(limit) {
+			res.Terms = res.Terms[:limit]
+		}
+		l.Info("Compared sets", "total_a", res.TotalA, "total_b", res.TotalB, "terms", len(res.Terms))
+
+		if err := encode(w, r, http.StatusOK, res); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
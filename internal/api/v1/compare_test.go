@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/compare"
+	"github.com/stretchr/testify/require"
+)
+
+// newCompareStore returns a memory store with word and phrase batches march and april.
+// Word batch march is of a posting in Warsaw and april of a posting in Berlin.
+func newCompareStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	q := memory.New()
+	ingest(t, q, database.Ingestion{
+		Name:     "march",
+		Location: "Warsaw",
+		Words:    repeatedWords(map[string]int{"go": 20, "java": 100, "php": 30}),
+	})
+	ingest(t, q, database.Ingestion{
+		Name:     "april",
+		Location: "Berlin",
+		Words:    repeatedWords(map[string]int{"go": 100, "java": 20, "rust": 10}),
+	})
+	createPhrases(t, q, "march", slices.Repeat([]string{"office in warsaw"}, 4)...)
+	createPhrases(t, q, "april", slices.Repeat([]string{"remote work"}, 5)...)
+
+	return q
+}
+
+func TestCompareHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		query      string
+		statusCode int
+		terms      map[string]compare.Status
+	}{
+		{
+			desc: "batches",
+
+			query:      "?a_batch=march&b_batch=april",
+			statusCode: http.StatusOK,
+			terms: map[string]compare.Status{
+				"go":   compare.Rose,
+				"java": compare.Fell,
+				"php":  compare.Disappeared,
+				"rust": compare.Appeared,
+			},
+		},
+		{
+			desc: "locations_filtered_by_status",
+
+			query:      "?a_location=Warsaw&b_location=Berlin&status=appeared",
+			statusCode: http.StatusOK,
+			terms:      map[string]compare.Status{"rust": compare.Appeared},
+		},
+		{
+			desc: "time_range_before_batches",
+
+			query:      "?a_to=2024-04-01&b_batch=april",
+			statusCode: http.StatusOK,
+			terms: map[string]compare.Status{
+				"go":   compare.Appeared,
+				"java": compare.Appeared,
+				"rust": compare.Appeared,
+			},
+		},
+		{
+			desc: "phrases",
+
+			query:      "?kind=phrases&a_batch=march&b_batch=april",
+			statusCode: http.StatusOK,
+			terms: map[string]compare.Status{
+				"office in warsaw": compare.Disappeared,
+				"remote work":      compare.Appeared,
+			},
+		},
+		{
+			desc: "empty_set",
+
+			query:      "?a_batch=march",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid_time",
+
+			query:      "?a_from=march&b_batch=april",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "unknown_kind",
+
+			query:      "?kind=lines&a_batch=march&b_batch=april",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "unknown_status",
+
+			query:      "?status=grew&a_batch=march&b_batch=april",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newCompareStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/"+tC.query, nil)
+			rr := httptest.NewRecorder()
+			compareHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+
+			var res compare.Result
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&res))
+
+			terms := make(map[string]compare.Status)
+			for _, term := range res.Terms {
+				terms[term.Term] = term.Status
+			}
+			require.Equal(t, tC.terms, terms)
+		})
+	}
+}
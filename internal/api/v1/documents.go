@@ -0,0 +1,308 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/imgsniff"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+	"github.com/kndrad/piccrack/pkg/match"
+	"github.com/kndrad/piccrack/pkg/ocr"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+var ErrImageNotStored = errors.New("image not stored")
+
+// Engines recognizing text of documents which aren't images.
+const (
+	EngineText = "text"
+	EnginePDF  = "pdf"
+)
+
+// Document is text of a captured image, PDF or text file with details of how it was recognized.
+type Document struct {
+	// Text as it was recognized, stored verbatim.
+	Text string
+	// Text with OCR misreads corrected, which words and phrases are derived from.
+	Corrected string
+	// OCR engine for images, EnginePDF or EngineText otherwise.
+	Engine        string
+	EngineVersion string
+	Settings      ocr.Settings
+	// Mean OCR confidence (0-100), zero if unknown.
+	Confidence float64
+	Duration   time.Duration
+	// Metadata of captured image, nil if capture isn't an image.
+	Image *imgsniff.Metadata
+}
+
+// recognize returns document of plain text, PDF or image content.
+func (svc *service) recognize(content []byte) (Document, error) {
+	kind, err := match.DetectKind(content)
+	if err != nil {
+		return Document{}, fmt.Errorf("detect kind: %w", err)
+	}
+
+	switch kind {
+	case match.KindPDF:
+		start := time.Now()
+		text, err := match.PDFText(content)
+		if err != nil {
+			return Document{}, fmt.Errorf("pdf text: %w", err)
+		}
+
+		return Document{Text: text, Corrected: text, Engine: EnginePDF, Duration: time.Since(start)}, nil
+	case match.KindImage:
+		meta, err := imgsniff.Inspect(content)
+		if err != nil {
+			return Document{}, fmt.Errorf("inspect image: %w", err)
+		}
+
+		c := ocr.NewClient()
+		defer c.Close()
+
+		res, err := ocr.ScanFrom(c, bytes.NewReader(content))
+		if err != nil {
+			return Document{}, fmt.Errorf("ocr scan: %w", err)
+		}
+		return Document{
+			Text:          res.Text(),
+			Corrected:     svc.correct(res.Text(), ocr.Engine, res.Confidence),
+			Engine:        ocr.Engine,
+			EngineVersion: ocr.EngineVersion(),
+			Settings:      res.Settings(),
+			Confidence:    res.MeanConfidence(),
+			Duration:      res.Duration(),
+			Image:         &meta,
+		}, nil
+	default:
+		return Document{Text: string(content), Corrected: string(content), Engine: EngineText}, nil
+	}
+}
+
+// correct returns text recognized by engine with OCR misreads corrected. Text of other
+// engines is returned as it is. Confidence returns OCR confidence of a word, nil if unknown.
+func (svc *service) correct(text, engine string, confidence func(word string) (float64, bool)) string {
+	if engine != ocr.Engine {
+		return text
+	}
+	corrected, corrections := textproc.DefaultCorrector().CorrectText(text, confidence)
+	textproc.LogCorrections(svc.logger, corrections)
+
+	return corrected
+}
+
+// ingestedDocument returns document recognized from content of a posting, ingested with
+// the posting. Content of image captures is kept if image store keeps it, and their
+// metadata is stored with the document.
+func (svc *service) ingestedDocument(content []byte, filename string, doc Document) (*database.IngestedDocument, error) {
+	params, err := ocrDocumentParams(doc)
+	if err != nil {
+		return nil, err
+	}
+	in := &database.IngestedDocument{Document: params}
+	if doc.Image == nil {
+		return in, nil
+	}
+
+	stored, err := svc.images.Put(doc.Image.Hash, doc.Image.Format, content)
+	if err != nil {
+		return nil, fmt.Errorf("put image: %w", err)
+	}
+	in.Image = &database.CreateImageParams{
+		Hash:      doc.Image.Hash,
+		Width:     int32(doc.Image.Width),
+		Height:    int32(doc.Image.Height),
+		Format:    doc.Image.Format,
+		SizeBytes: doc.Image.Size,
+		Filename:  filename,
+		Content:   stored.Content,
+		Path:      stored.Path,
+	}
+
+	return in, nil
+}
+
+// ocrDocumentParams returns params of doc. Posting and image IDs are set on ingestion.
+func ocrDocumentParams(doc Document) (database.CreateOcrDocumentParams, error) {
+	settings, err := json.Marshal(doc.Settings)
+	if err != nil {
+		return database.CreateOcrDocumentParams{}, fmt.Errorf("marshal settings: %w", err)
+	}
+
+	return database.CreateOcrDocumentParams{
+		Text:          doc.Text,
+		Engine:        doc.Engine,
+		EngineVersion: doc.EngineVersion,
+		Languages:     doc.Settings.Languages,
+		Settings:      settings,
+		Confidence:    pgtype.Float8{Float64: doc.Confidence, Valid: doc.Confidence > 0},
+		DurationMs:    doc.Duration.Milliseconds(),
+	}, nil
+}
+
+// ListPostingDocuments returns documents of posting, latest first.
+func (svc *service) ListPostingDocuments(ctx context.Context, id int64) ([]database.ListOcrDocumentsRow, error) {
+	if _, err := svc.GetPosting(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := svc.q.ListOcrDocuments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list ocr documents: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ReprocessPosting derives words, phrases and salaries of posting again from text of its
+// latest document, or from its raw text if it has no documents. OCR misreads of documents
+// are corrected without confidences, which aren't stored. With rescan, stored image
+// of the latest document is scanned with OCR again and stored as a new document.
+// Batches of posting are replaced by new ones in the same transaction, old batches are
+// soft deleted.
+func (svc *service) ReprocessPosting(ctx context.Context, id int64, rescan bool) (database.GetPostingRow, error) {
+	posting, err := svc.GetPosting(ctx, id)
+	if err != nil {
+		return posting, err
+	}
+
+	text := posting.RawText
+	latest, err := svc.q.GetLatestOcrDocument(ctx, id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if rescan {
+			return posting, fmt.Errorf("%w: posting %d has no documents", ErrImageNotStored, id)
+		}
+	case err != nil:
+		return posting, fmt.Errorf("get latest ocr document: %w", err)
+	default:
+		text = svc.correct(latest.Text, latest.Engine, nil)
+	}
+
+	var document *database.IngestedDocument
+	if rescan {
+		content, err := imgstore.Load(imgstore.Stored{
+			Content: latest.ImageContent,
+			Path:    latest.ImagePath.String,
+		})
+		if err != nil {
+			return posting, fmt.Errorf("%w: posting %d: %w", ErrImageNotStored, id, err)
+		}
+		doc, err := svc.recognize(content)
+		if err != nil {
+			return posting, err
+		}
+		params, err := ocrDocumentParams(doc)
+		if err != nil {
+			return posting, err
+		}
+		// Image of the latest document is stored already
+		params.ImageID = latest.ImageID
+		document = &database.IngestedDocument{Document: params}
+		text = doc.Corrected
+	}
+
+	in := svc.postingIngestion(text)
+	// Phrase batch names are unique, including soft deleted batches.
+	in.Name = fmt.Sprintf("%s_%d", database.PostingBatchName(id), time.Now().UnixNano())
+	in.PostingID = pgtype.Int8{Int64: id, Valid: true}
+	in.ReplaceBatches = true
+	in.Document = document
+	if _, err := svc.ingestPosting(ctx, in); err != nil {
+		return posting, err
+	}
+
+	return svc.GetPosting(ctx, id)
+}
+
+func listPostingDocumentsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type document struct {
+		database.ListOcrDocumentsRow
+		Settings json.RawMessage `json:"settings"`
+	}
+	type response struct {
+		Rows []document `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		rows, err := svc.ListPostingDocuments(r.Context(), id)
+		if err != nil {
+			respondPostingErr(w, "Failed to list posting documents", err)
+
+			return
+		}
+		docs := make([]document, 0, len(rows))
+		for _, row := range rows {
+			docs = append(docs, document{ListOcrDocumentsRow: row, Settings: row.Settings})
+		}
+		l.Info("Got posting documents", "id", id, "total", len(docs))
+
+		if err := encode(w, r, http.StatusOK, response{Rows: docs}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+// reprocessPostingHandler derives batches of posting again. Stored image is scanned
+// again if rescan query value is true.
+func reprocessPostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.GetPostingRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+		var rescan bool
+		if v := r.URL.Query().Get("rescan"); v != "" {
+			rescan, err = strconv.ParseBool(v)
+			if err != nil {
+				respondJSON(w, "Failed to get rescan query value", err, http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		row, err := svc.ReprocessPosting(r.Context(), id, rescan)
+		if err != nil {
+			if errors.Is(err, ErrImageNotStored) {
+				respondJSON(w, "Image of posting not stored", err, http.StatusConflict)
+
+				return
+			}
+			respondPostingErr(w, "Failed to reprocess posting", err)
+
+			return
+		}
+		l.Info("Reprocessed posting", "id", row.ID, "word_batch_id", row.WordBatchID.Int64, "rescan", rescan)
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
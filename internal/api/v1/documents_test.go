@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/ocr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostingDocumentHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler    func(Service, *slog.Logger) http.HandlerFunc
+		method     string
+		id         string
+		query      string
+		statusCode int
+	}{
+		{
+			desc: "list_documents",
+
+			handler:    listPostingDocumentsHandler,
+			method:     http.MethodGet,
+			id:         "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "list_documents_of_missing_posting",
+
+			handler:    listPostingDocumentsHandler,
+			method:     http.MethodGet,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "reprocess_from_stored_text",
+
+			handler:    reprocessPostingHandler,
+			method:     http.MethodPost,
+			id:         "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "rescan_without_stored_image",
+
+			handler:    reprocessPostingHandler,
+			method:     http.MethodPost,
+			id:         "1",
+			query:      "?rescan=true",
+			statusCode: http.StatusConflict,
+		},
+		{
+			desc: "reprocess_invalid_rescan",
+
+			handler:    reprocessPostingHandler,
+			method:     http.MethodPost,
+			id:         "1",
+			query:      "?rescan=maybe",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "reprocess_missing_posting",
+
+			handler:    reprocessPostingHandler,
+			method:     http.MethodPost,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), tC.method, "/"+tC.query, nil)
+			req.SetPathValue("id", tC.id)
+			rr := httptest.NewRecorder()
+			tC.handler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestListPostingDocumentsHandlerSettings(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+	listPostingDocumentsHandler(svc, testLogger())(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Rows []struct {
+			Engine   string         `json:"engine"`
+			Settings map[string]any `json:"settings"`
+		} `json:"rows"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Len(t, body.Rows, 1)
+	require.Equal(t, "tesseract", body.Rows[0].Engine)
+	// Settings are served as JSON object, not encoded bytes
+	require.Equal(t, true, body.Rows[0].Settings["trim"])
+}
+
+func TestCapturePostingHandlerText(t *testing.T) {
+	t.Parallel()
+
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	require.NoError(t, w.WriteField("title", "Go Developer"))
+	part, err := w.CreateFormFile("image", "posting.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("Senior Go Developer\nKubernetes, PostgreSQL\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rr := httptest.NewRecorder()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+	capturePostingHandler(svc, testLogger())(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+}
+
+func TestReprocessPostingCorrectsOnlyDerivedWords(t *testing.T) {
+	t.Parallel()
+
+	const text = "Kubemetes, Pyth0n"
+	q := memory.New()
+	res := ingest(t, q, database.Ingestion{
+		Posting:  &database.CreatePostingParams{Title: "Go Developer", RawText: text},
+		Document: &database.IngestedDocument{Document: database.CreateOcrDocumentParams{Text: text, Engine: ocr.Engine, Settings: []byte(`{}`)}},
+	})
+	svc := NewService(q, nil, nil, nil, testLogger())
+
+	row, err := svc.ReprocessPosting(context.Background(), res.PostingID.Int64, false)
+	require.NoError(t, err)
+	require.Equal(t, text, row.RawText)
+
+	// Recognized text is kept verbatim
+	doc, err := q.GetLatestOcrDocument(context.Background(), row.ID)
+	require.NoError(t, err)
+	require.Equal(t, text, doc.Text)
+
+	batch, err := q.GetWordBatch(context.Background(), row.WordBatchID.Int64)
+	require.NoError(t, err)
+	words, err := q.ListWordsByBatchName(context.Background(), batch.Name)
+	require.NoError(t, err)
+	values := make([]string, 0, len(words))
+	for _, w := range words {
+		values = append(values, w.WordValue)
+	}
+	require.ElementsMatch(t, []string{"kubernetes", "python"}, values)
+}
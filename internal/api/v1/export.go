@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kndrad/piccrack/internal/archive"
+)
+
+var ErrExportUnsupported = errors.New("export isn't supported by the storage")
+
+func (svc *service) Export(ctx context.Context, w io.Writer, opts archive.ExportOptions) (archive.Manifest, error) {
+	db, ok := svc.q.(archive.Transactor)
+	if !ok {
+		return archive.Manifest{}, ErrExportUnsupported
+	}
+	m, err := archive.Export(ctx, db, w, opts)
+	if err != nil {
+		return m, fmt.Errorf("export: %w", err)
+	}
+
+	return m, nil
+}
+
+// exportHandler streams an archive of the dataset. Content of images is added with
+// images=true query param. Failures after the archive started streaming can't change
+// the status anymore, so they're only logged and the client gets a truncated archive.
+func exportHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var opts archive.ExportOptions
+		if v := r.URL.Query().Get("images"); v != "" {
+			images, err := strconv.ParseBool(v)
+			if err != nil {
+				respondJSON(w, "Invalid images param", err, http.StatusBadRequest)
+
+				return
+			}
+			opts.Images = images
+		}
+
+		sw := &startedWriter{w: w, header: func() {
+			name := "piccrack-" + time.Now().UTC().Format("20060102T150405Z") + ".zip"
+			w.Header().Set("Content-Type", "application/zip")
+			w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+			w.WriteHeader(http.StatusOK)
+		}}
+		m, err := svc.Export(r.Context(), sw, opts)
+		if err != nil {
+			if sw.started {
+				l.Error("Failed to stream export", "err", err.Error())
+
+				return
+			}
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, ErrExportUnsupported):
+				status = http.StatusNotImplemented
+			case errors.Is(err, archive.ErrSchemaMismatch):
+				status = http.StatusConflict
+			}
+			respondJSON(w, "Failed to export", err, status)
+
+			return
+		}
+		l.Info("Exported dataset", "schema_version", m.SchemaVersion, "tables", len(m.Tables), "images", m.Images)
+	}
+}
+
+// startedWriter writes headers of a response before its first write, so that a
+// response which fails before writing anything can still get an error status.
+type startedWriter struct {
+	w       io.Writer
+	header  func()
+	started bool
+}
+
+func (sw *startedWriter) Write(p []byte) (int, error) {
+	if !sw.started {
+		sw.started = true
+		sw.header()
+	}
+
+	return sw.w.Write(p)
+}
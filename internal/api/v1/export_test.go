@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHandler(t *testing.T) {
+	t.Parallel()
+
+	l := testLogger()
+	svc := NewService(newTestStore(t), nil, nil, nil, l)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{name: "unsupported storage", target: "/api/v1/export", status: http.StatusNotImplemented},
+		{name: "with images", target: "/api/v1/export?images=true", status: http.StatusNotImplemented},
+		{name: "invalid images param", target: "/api/v1/export?images=maybe", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, tt.target, nil)
+			rr := httptest.NewRecorder()
+			exportHandler(svc, l)(rr, req)
+
+			require.Equal(t, tt.status, rr.Code)
+			require.NotEqual(t, "application/zip", rr.Header().Get("Content-Type"))
+		})
+	}
+}
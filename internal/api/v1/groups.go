@@ -0,0 +1,476 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+var (
+	ErrInvalidGroup   = errors.New("invalid tag or collection")
+	ErrGroupNotFound  = errors.New("tag or collection not found")
+	ErrGroupNameTaken = errors.New("tag or collection name taken")
+	ErrMemberNotFound = errors.New("member not found")
+)
+
+// GroupKind is a kind of named group of postings and batches. Tags are created when
+// first used, collections have to be created before members are added to them.
+type GroupKind string
+
+const (
+	GroupTags        GroupKind = "tags"
+	GroupCollections GroupKind = "collections"
+)
+
+// MemberPosting is a type of member which is a posting, batches are members of their kind.
+const MemberPosting = "posting"
+
+// Group is a tag or a collection with numbers of its members.
+type Group struct {
+	ID            int64              `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	Postings      int64              `json:"postings"`
+	WordBatches   int64              `json:"word_batches"`
+	PhraseBatches int64              `json:"phrase_batches"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+// GroupMember is a posting, a word batch or a phrase batch of a group.
+type GroupMember struct {
+	Type      string             `json:"type"`
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// GroupDetails is a group with its members.
+type GroupDetails struct {
+	Group
+
+	Members []GroupMember `json:"members"`
+}
+
+// Member refers to a posting (posting), a word batch (words) or a phrase batch (phrases).
+type Member struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+// params returns member as nullable posting, word batch and phrase batch IDs, exactly
+// one of which is valid.
+func (m Member) params() (posting, words, phrases pgtype.Int8, err error) {
+	id := pgtype.Int8{Int64: m.ID, Valid: true}
+	switch m.Type {
+	case MemberPosting:
+		posting = id
+	case string(BatchWords):
+		words = id
+	case string(BatchPhrases):
+		phrases = id
+	default:
+		err = fmt.Errorf("%w: unknown member type %q, use posting, words or phrases", ErrInvalidGroup, m.Type)
+	}
+
+	return posting, words, phrases, err
+}
+
+func (svc *service) CreateGroup(ctx context.Context, kind GroupKind, name, description string) (Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Group{}, fmt.Errorf("%w: empty name", ErrInvalidGroup)
+	}
+
+	var err error
+	switch kind {
+	case GroupTags:
+		if description != "" {
+			return Group{}, fmt.Errorf("%w: tags have no description", ErrInvalidGroup)
+		}
+		_, err = svc.q.CreateTag(ctx, name)
+	case GroupCollections:
+		_, err = svc.q.CreateCollection(ctx, database.CreateCollectionParams{Name: name, Description: description})
+	default:
+		return Group{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return Group{}, fmt.Errorf("%w: %s %q", ErrGroupNameTaken, kind, name)
+		}
+
+		return Group{}, fmt.Errorf("create %s: %w", kind, err)
+	}
+
+	return svc.getGroup(ctx, kind, name)
+}
+
+// ListGroups returns groups of kind ordered by name.
+func (svc *service) ListGroups(ctx context.Context, kind GroupKind, limit, offset int32) ([]Group, error) {
+	var groups []Group
+	switch kind {
+	case GroupTags:
+		rows, err := svc.q.ListTags(ctx, database.ListTagsParams{Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("list tags: %w", err)
+		}
+		groups = make([]Group, 0, len(rows))
+		for _, row := range rows {
+			groups = append(groups, Group(row))
+		}
+	case GroupCollections:
+		rows, err := svc.q.ListCollections(ctx, database.ListCollectionsParams{Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("list collections: %w", err)
+		}
+		groups = make([]Group, 0, len(rows))
+		for _, row := range rows {
+			groups = append(groups, Group(row))
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+
+	return groups, nil
+}
+
+// GetGroup returns group of kind named name with its members which aren't deleted.
+func (svc *service) GetGroup(ctx context.Context, kind GroupKind, name string) (GroupDetails, error) {
+	g, err := svc.getGroup(ctx, kind, name)
+	if err != nil {
+		return GroupDetails{}, err
+	}
+	details := GroupDetails{Group: g, Members: make([]GroupMember, 0)}
+
+	switch kind {
+	case GroupTags:
+		rows, err := svc.q.ListTagMembers(ctx, name)
+		if err != nil {
+			return details, fmt.Errorf("list tag members: %w", err)
+		}
+		for _, row := range rows {
+			details.Members = append(details.Members, GroupMember(row))
+		}
+	case GroupCollections:
+		rows, err := svc.q.ListCollectionMembers(ctx, name)
+		if err != nil {
+			return details, fmt.Errorf("list collection members: %w", err)
+		}
+		for _, row := range rows {
+			details.Members = append(details.Members, GroupMember(row))
+		}
+	}
+
+	return details, nil
+}
+
+func (svc *service) getGroup(ctx context.Context, kind GroupKind, name string) (Group, error) {
+	var (
+		g   Group
+		err error
+	)
+	switch kind {
+	case GroupTags:
+		var row database.GetTagRow
+		row, err = svc.q.GetTag(ctx, name)
+		g = Group(row)
+	case GroupCollections:
+		var row database.GetCollectionRow
+		row, err = svc.q.GetCollection(ctx, name)
+		g = Group(row)
+	default:
+		return g, fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return g, fmt.Errorf("%w: %s %q", ErrGroupNotFound, kind, name)
+		}
+
+		return g, fmt.Errorf("get %s: %w", kind, err)
+	}
+
+	return g, nil
+}
+
+// DeleteGroup deletes group of kind named name. Its members are left intact.
+func (svc *service) DeleteGroup(ctx context.Context, kind GroupKind, name string) error {
+	var err error
+	switch kind {
+	case GroupTags:
+		_, err = svc.q.DeleteTag(ctx, name)
+	case GroupCollections:
+		_, err = svc.q.DeleteCollection(ctx, name)
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s %q", ErrGroupNotFound, kind, name)
+		}
+
+		return fmt.Errorf("delete %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+// AddGroupMember adds m to group of kind named name. Adding a member again does nothing.
+func (svc *service) AddGroupMember(ctx context.Context, kind GroupKind, name string, m Member) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidGroup)
+	}
+	posting, words, phrases, err := m.params()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case GroupTags:
+		_, err = svc.q.AddTagMember(ctx, database.AddTagMemberParams{
+			Name:          name,
+			PostingID:     posting,
+			WordBatchID:   words,
+			PhraseBatchID: phrases,
+		})
+	case GroupCollections:
+		_, err = svc.q.AddCollectionMember(ctx, database.AddCollectionMemberParams{
+			Name:          name,
+			PostingID:     posting,
+			WordBatchID:   words,
+			PhraseBatchID: phrases,
+		})
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s %q", ErrGroupNotFound, kind, name)
+		}
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("%w: %s %d", ErrMemberNotFound, m.Type, m.ID)
+		}
+
+		return fmt.Errorf("add %s member: %w", kind, err)
+	}
+
+	return nil
+}
+
+// RemoveGroupMember removes m from group of kind named name.
+func (svc *service) RemoveGroupMember(ctx context.Context, kind GroupKind, name string, m Member) error {
+	posting, words, phrases, err := m.params()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case GroupTags:
+		_, err = svc.q.RemoveTagMember(ctx, database.RemoveTagMemberParams{
+			Name:          name,
+			PostingID:     posting,
+			WordBatchID:   words,
+			PhraseBatchID: phrases,
+		})
+	case GroupCollections:
+		_, err = svc.q.RemoveCollectionMember(ctx, database.RemoveCollectionMemberParams{
+			Name:          name,
+			PostingID:     posting,
+			WordBatchID:   words,
+			PhraseBatchID: phrases,
+		})
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidGroup, kind)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s %d in %s %q", ErrMemberNotFound, m.Type, m.ID, kind, name)
+		}
+
+		return fmt.Errorf("remove %s member: %w", kind, err)
+	}
+
+	return nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// respondGroupErr responds with status code matching err of group service methods.
+func respondGroupErr(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidGroup):
+		respondJSON(w, "Invalid tag or collection", err, http.StatusBadRequest)
+	case errors.Is(err, ErrGroupNotFound):
+		respondJSON(w, "Tag or collection not found", err, http.StatusNotFound)
+	case errors.Is(err, ErrMemberNotFound):
+		respondJSON(w, "Member not found", err, http.StatusNotFound)
+	case errors.Is(err, ErrGroupNameTaken):
+		respondJSON(w, "Tag or collection name taken", err, http.StatusConflict)
+	default:
+		respondJSON(w, msg, err, http.StatusInternalServerError)
+	}
+}
+
+// Group handlers serve tags or collections, depending on kind of their route.
+
+func listGroupsHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	type response struct {
+		Rows []Group `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, err := limitValue(r.URL.Query())
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		offset, err := offsetValue(r.URL.Query())
+		if err != nil {
+			respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
+
+			return
+		}
+
+		rows, err := svc.ListGroups(r.Context(), kind, limit, offset)
+		if err != nil {
+			respondGroupErr(w, "Failed to list "+string(kind), err)
+
+			return
+		}
+		l.Info("Listed groups", "kind", kind, "total", len(rows))
+
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func createGroupHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	type request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	type response struct {
+		Row Group `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decode[request](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.CreateGroup(r.Context(), kind, req.Name, req.Description)
+		if err != nil {
+			respondGroupErr(w, "Failed to create "+string(kind), err)
+
+			return
+		}
+		l.Info("Created group", "kind", kind, "name", row.Name)
+
+		if err := encode(w, r, http.StatusCreated, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func getGroupHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	type response struct {
+		Row GroupDetails `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		row, err := svc.GetGroup(r.Context(), kind, name)
+		if err != nil {
+			respondGroupErr(w, "Failed to get "+string(kind), err)
+
+			return
+		}
+		l.Info("Got group", "kind", kind, "name", name, "members", len(row.Members))
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func deleteGroupHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		if err := svc.DeleteGroup(r.Context(), kind, name); err != nil {
+			respondGroupErr(w, "Failed to delete "+string(kind), err)
+
+			return
+		}
+		l.Info("Deleted group", "kind", kind, "name", name)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func addGroupMemberHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		m, err := decode[Member](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := svc.AddGroupMember(r.Context(), kind, name, m); err != nil {
+			respondGroupErr(w, "Failed to add member", err)
+
+			return
+		}
+		l.Info("Added group member", "kind", kind, "name", name, "type", m.Type, "id", m.ID)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// removeGroupMemberHandler removes member of type and id path values.
+func removeGroupMemberHandler(svc Service, l *slog.Logger, kind GroupKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get member id", err, http.StatusBadRequest)
+
+			return
+		}
+		m := Member{Type: r.PathValue("type"), ID: id}
+
+		if err := svc.RemoveGroupMember(r.Context(), kind, name, m); err != nil {
+			respondGroupErr(w, "Failed to remove member", err)
+
+			return
+		}
+		l.Info("Removed group member", "kind", kind, "name", name, "type", m.Type, "id", m.ID)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
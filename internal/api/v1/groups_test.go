@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler    func(Service, *slog.Logger, GroupKind) http.HandlerFunc
+		method     string
+		kind       GroupKind
+		name       string
+		member     string
+		id         string
+		body       string
+		statusCode int
+	}{
+		{
+			desc: "list_tags",
+
+			handler:    listGroupsHandler,
+			method:     http.MethodGet,
+			kind:       GroupTags,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "create_collection",
+
+			handler:    createGroupHandler,
+			method:     http.MethodPost,
+			kind:       GroupCollections,
+			body:       `{"name": "remote", "description": "Remote postings"}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			desc: "create_tag_with_description",
+
+			handler:    createGroupHandler,
+			method:     http.MethodPost,
+			kind:       GroupTags,
+			body:       `{"name": "remote", "description": "Remote postings"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "create_taken_tag",
+
+			handler:    createGroupHandler,
+			method:     http.MethodPost,
+			kind:       GroupTags,
+			body:       `{"name": "taken"}`,
+			statusCode: http.StatusConflict,
+		},
+		{
+			desc: "get_tag",
+
+			handler:    getGroupHandler,
+			method:     http.MethodGet,
+			kind:       GroupTags,
+			name:       "test",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "get_missing_collection",
+
+			handler:    getGroupHandler,
+			method:     http.MethodGet,
+			kind:       GroupCollections,
+			name:       "missing",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "delete_collection",
+
+			handler:    deleteGroupHandler,
+			method:     http.MethodDelete,
+			kind:       GroupCollections,
+			name:       "test",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "add_posting_to_new_tag",
+
+			handler:    addGroupMemberHandler,
+			method:     http.MethodPost,
+			kind:       GroupTags,
+			name:       "new",
+			body:       `{"type": "posting", "id": 1}`,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "add_batch_to_missing_collection",
+
+			handler:    addGroupMemberHandler,
+			method:     http.MethodPost,
+			kind:       GroupCollections,
+			name:       "missing",
+			body:       `{"type": "words", "id": 1}`,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "add_missing_batch",
+
+			handler:    addGroupMemberHandler,
+			method:     http.MethodPost,
+			kind:       GroupCollections,
+			name:       "test",
+			body:       `{"type": "phrases", "id": 99}`,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "add_unknown_member_type",
+
+			handler:    addGroupMemberHandler,
+			method:     http.MethodPost,
+			kind:       GroupTags,
+			name:       "test",
+			body:       `{"type": "salaries", "id": 1}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "remove_member",
+
+			handler:    removeGroupMemberHandler,
+			method:     http.MethodDelete,
+			kind:       GroupTags,
+			name:       "test",
+			member:     "words",
+			id:         "1",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "remove_missing_member",
+
+			handler:    removeGroupMemberHandler,
+			method:     http.MethodDelete,
+			kind:       GroupCollections,
+			name:       "test",
+			member:     "posting",
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), tC.method, "/", strings.NewReader(tC.body))
+			req.SetPathValue("name", tC.name)
+			req.SetPathValue("type", tC.member)
+			req.SetPathValue("id", tC.id)
+			rr := httptest.NewRecorder()
+			tC.handler(svc, testLogger(), tC.kind)(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestGetGroup(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	tag, err := svc.GetGroup(context.Background(), GroupTags, "test")
+	require.NoError(t, err)
+	require.Equal(t, "test", tag.Name)
+	require.Equal(t, int64(1), tag.Postings)
+	for i := range tag.Members {
+		require.True(t, tag.Members[i].CreatedAt.Valid)
+		tag.Members[i].CreatedAt = pgtype.Timestamptz{}
+	}
+	require.Equal(t, []GroupMember{
+		{Type: MemberPosting, ID: 1, Name: "Go Developer"},
+		{Type: "words", ID: 1, Name: "test_batch"},
+	}, tag.Members)
+
+	_, err = svc.GetGroup(context.Background(), "labels", "test")
+	require.ErrorIs(t, err, ErrInvalidGroup)
+}
@@ -1,8 +1,8 @@
 package v1
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,6 +13,7 @@ import (
 
 	"github.com/kndrad/piccrack/internal/database"
 	"github.com/kndrad/piccrack/pkg/ocr"
+	"github.com/kndrad/piccrack/pkg/textproc"
 )
 
 func limitValue(values url.Values) (int32, error) {
@@ -34,6 +35,18 @@ func limitValue(values url.Values) (int32, error) {
 	return int32(n), nil
 }
 
+// wordFilterValue returns word statistics filter from query values.
+func wordFilterValue(values url.Values) WordFilter {
+	return WordFilter{
+		Language:   values.Get("language"),
+		Seniority:  values.Get("seniority"),
+		Location:   values.Get("location"),
+		WorkMode:   values.Get("work_mode"),
+		Tag:        values.Get("tag"),
+		Collection: values.Get("collection"),
+	}
+}
+
 func offsetValue(values url.Values) (int32, error) {
 	var v string
 	const defaultOffset = "0"
@@ -189,19 +202,26 @@ func uploadWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc {
 
 		if err := r.ParseMultipartForm(MaxSize); err != nil {
 			respondJSON(w, "File too big", err, http.StatusBadRequest)
+
+			return
 		}
 		f, fheader, err := r.FormFile("file")
 		if err != nil {
 			respondJSON(w, "Failed to get file", err, http.StatusBadRequest)
+
+			return
 		}
 		defer f.Close()
 
 		// Detect content type of file and check if it's a text
 		data := make([]byte, 512)
-		if _, err := f.Read(data); err != nil {
+		n, err := f.Read(data)
+		if err != nil && !errors.Is(err, io.EOF) {
 			respondJSON(w, "Failed to read file into buffer", err, http.StatusInternalServerError)
+
+			return
 		}
-		contentType := http.DetectContentType(data)
+		contentType := http.DetectContentType(data[:n])
 		allowed := func() bool {
 			types := map[string]bool{
 				"text/plain":                true,
@@ -218,44 +238,49 @@ func uploadWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc {
 				nil,
 				http.StatusBadRequest,
 			)
+
+			return
 		}
 		// Return pointer back to the start of the file after content type detection
 		if _, err := f.Seek(0, io.SeekStart); err != nil {
 			respondJSON(w, "Failed to seek to start of the file", err, http.StatusInternalServerError)
+
+			return
 		}
 		logger.Info("Received form",
 			slog.String("filename", fheader.Filename),
 		)
 
-		// Read many words one by one from file
-		scanner := bufio.NewScanner(f)
-		scanner.Split(bufio.ScanWords)
+		text, err := io.ReadAll(f)
+		if err != nil {
+			respondJSON(w, "Failed to read file", err, http.StatusInternalServerError)
 
-		var words []string
-		for scanner.Scan() {
-			words = append(words, scanner.Text())
-		}
-		if err := scanner.Err(); err != nil {
-			respondJSON(w, "Scanner returned an error", err, http.StatusInternalServerError)
+			return
 		}
-		count := 0
-		for _, word := range words {
-			_, err := svc.CreateWord(r.Context(), word)
-			if err != nil {
-				respondJSON(w, "Failed to insert row", err, http.StatusInternalServerError)
-			}
-			count++
+
+		// Language and stop words are detected for every line, words are inserted all
+		// or nothing and noise words are dropped
+		res, err := svc.CreateWords(r.Context(), textproc.Words(string(text)))
+		if err != nil {
+			respondJSON(w, "Failed to insert words", err, http.StatusInternalServerError)
+
+			return
 		}
+		logger.Info("Inserted words", "words", res.Words, "terms", res.Terms)
+
 		type Response struct {
-			Count int `json:"count"`
+			Count int64 `json:"count"`
+			Terms int64 `json:"terms"`
 		}
 		resp := Response{
-			Count: count,
+			Count: res.Words,
+			Terms: res.Terms,
 		}
 		if err := encode(w, r, http.StatusOK, resp); err != nil {
 			respondJSON(w, "Failed to insert row", err, http.StatusInternalServerError)
+
+			return
 		}
-		logger.Info("Inserted words", slog.Int("count", count))
 	}
 }
 
@@ -267,10 +292,14 @@ func uploadImageWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc
 
 		if err := r.ParseMultipartForm(maxSize); err != nil {
 			respondJSON(w, "Image file too big", err, http.StatusBadRequest)
+
+			return
 		}
 		f, header, err := r.FormFile("image")
 		if err != nil {
 			respondJSON(w, "Failed to get image file", err, http.StatusBadRequest)
+
+			return
 		}
 		defer f.Close()
 
@@ -278,6 +307,8 @@ func uploadImageWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc
 		data := make([]byte, 512)
 		if _, err := f.Read(data); err != nil {
 			respondJSON(w, "Failed to read image file into buffer", err, http.StatusInternalServerError)
+
+			return
 		}
 		contentType := http.DetectContentType(data)
 
@@ -292,6 +323,8 @@ func uploadImageWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc
 		// Return pointer back to the start of the file after content type detection
 		if _, err := f.Seek(0, io.SeekStart); err != nil {
 			respondJSON(w, "Failed to seek to start of the file", err, http.StatusInternalServerError)
+
+			return
 		}
 		if !allowed {
 			respondJSON(w,
@@ -299,53 +332,80 @@ func uploadImageWordsHandler(svc Service, logger *slog.Logger) http.HandlerFunc
 				nil,
 				http.StatusBadRequest,
 			)
-		}
-		logger.Info("Received form", slog.String("header_filename", header.Filename))
 
-		content := make([]byte, ocr.MaxImageSize)
-		if _, err := f.Read(content); err != nil {
-			respondJSON(w,
-				"Failed to read file content for image words recognition.",
-				err,
-				http.StatusInternalServerError,
-			)
+			return
 		}
-		c := ocr.NewClient()
-		defer c.Close()
+		logger.Info("Received form", slog.String("header_filename", header.Filename))
 
-		result, err := ocr.ScanFile(c, header.Filename)
+		content, err := io.ReadAll(io.LimitReader(f, int64(ocr.MaxImageSize)))
 		if err != nil {
 			respondJSON(w,
-				"Failed to recognize words from an image",
+				"Failed to read file content for image words recognition.",
 				err,
 				http.StatusInternalServerError,
 			)
-		}
 
-		var words []string
-		for w := range result.Words() {
-			words = append(words, w)
+			return
 		}
 
-		row, err := svc.CreateWordsBatch(r.Context(), header.Filename, words)
+		// Words, salaries and the document are inserted all or nothing
+		res, err := svc.CaptureWordsBatch(r.Context(), content, header.Filename)
 		if err != nil {
 			respondJSON(w, "Failed to insert words batch", err, http.StatusInternalServerError)
+
+			return
 		}
+		logger.Info("Inserted words batch",
+			"batch_id", res.WordBatchID.Int64,
+			"posting_id", res.PostingID.Int64,
+			"document_id", res.DocumentID.Int64,
+			"words", res.Words,
+			"salaries", res.Salaries,
+		)
 
 		response := struct {
-			Row database.CreateWordsBatchRow `json:"row"`
+			Row database.IngestResult `json:"row"`
 		}{
-			Row: row,
+			Row: res,
 		}
 		if err := encode(w, r, http.StatusOK, response); err != nil {
 			respondJSON(w, "Failed to encode response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func listWordsByBatchNameHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Rows []database.ListWordsByBatchNameRow `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+
+		l.Info("Searching words batch", "name", name)
+
+		rows, err := svc.ListWordsByBatchName(r.Context(), name)
+		if err != nil {
+			respondJSON(w, "Failed to list words by batch name with word service", err, http.StatusInternalServerError)
+
+			return
+		}
+		resp := response{
+			Rows: rows,
+		}
+		if err := encode(w, r, http.StatusOK, resp); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
 		}
 	}
 }
 
-func listWordBatchesHandler(svc Service, logger *slog.Logger) http.HandlerFunc {
+func listWordFrequenciesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
 	type response struct {
-		Results []database.ListWordBatchesRow `json:"word_batches"`
+		Rows []database.ListWordFrequenciesRow `json:"rows"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -361,20 +421,17 @@ func listWordBatchesHandler(svc Service, logger *slog.Logger) http.HandlerFunc {
 
 			return
 		}
+		filter := wordFilterValue(r.URL.Query())
 
-		rows, err := svc.ListWordBatches(r.Context(), limit, offset)
+		rows, err := svc.ListWordFrequencies(r.Context(), limit, offset, filter)
 		if err != nil {
-			respondJSON(w, "Failed to list word batches via word service", err, http.StatusInternalServerError)
+			respondJSON(w, "Failed to list word frequencies", err, http.StatusInternalServerError)
 
 			return
 		}
+		l.Info("Got word frequencies", "total", len(rows), "filter", filter)
 
-		resp := response{
-			Results: rows,
-		}
-		logger.Info("Got word batches", "total", len(rows))
-
-		if err := encode(w, r, http.StatusOK, resp); err != nil {
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
 			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
 
 			return
@@ -382,26 +439,35 @@ func listWordBatchesHandler(svc Service, logger *slog.Logger) http.HandlerFunc {
 	}
 }
 
-func listWordsByBatchNameHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+func listWordRankingsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
 	type response struct {
-		Rows []database.ListWordsByBatchNameRow `json:"rows"`
+		Rows []database.ListWordRankingsRow `json:"rows"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		name := r.URL.Query().Get("name")
-
-		l.Info("Searching words batch", "name", name)
+		limit, err := limitValue(r.URL.Query())
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
 
-		rows, err := svc.ListWordsByBatchName(r.Context(), name)
+			return
+		}
+		offset, err := offsetValue(r.URL.Query())
 		if err != nil {
-			respondJSON(w, "Failed to list words by batch name with word service", err, http.StatusInternalServerError)
+			respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
 
 			return
 		}
-		resp := response{
-			Rows: rows,
+		filter := wordFilterValue(r.URL.Query())
+
+		rows, err := svc.ListWordRankings(r.Context(), limit, offset, filter)
+		if err != nil {
+			respondJSON(w, "Failed to list word rankings", err, http.StatusInternalServerError)
+
+			return
 		}
-		if err := encode(w, r, http.StatusOK, resp); err != nil {
+		l.Info("Got word rankings", "total", len(rows), "filter", filter)
+
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
 			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
 
 			return
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/rand/v2"
 	"mime/multipart"
@@ -17,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,7 +158,7 @@ func TestAllWordsHandler(t *testing.T) {
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
 			svc := &service{
-				q: NewQueriesMock(NewWordsMock()...),
+				q: newTestStore(t),
 			}
 			handler := listWordsHandler(svc, testLogger())
 
@@ -201,7 +203,7 @@ func TestInsertWordHandler(t *testing.T) {
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
 			svc := &service{
-				q: NewQueriesMock(NewWordsMock()...),
+				q: newTestStore(t),
 			}
 			handler := createWordHandler(svc, testLogger())
 
@@ -334,7 +336,7 @@ func TestUploadWordsHandler(t *testing.T) {
 
 			// Underlying db of this service does not contain any words
 			svc: &service{
-				q:      NewQueriesMock(),
+				q:      memory.New(),
 				logger: testLogger(),
 			},
 		},
@@ -380,6 +382,102 @@ func TestUploadWordsHandler(t *testing.T) {
 			resp.Body.Close()
 
 			t.Logf("Got data: %s", string(data))
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var got struct {
+				Count int64 `json:"count"`
+			}
+			require.NoError(t, json.Unmarshal(data, &got))
+			require.Equal(t, int64(n), got.Count)
+		})
+	}
+}
+
+func TestUploadWordsHandlerRemovesStopWordsOfLines(t *testing.T) {
+	t.Parallel()
+
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", "words.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("Experience with the cloud and containers\nZnajomość języka angielskiego dla chmury\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rr := httptest.NewRecorder()
+	q := memory.New()
+	uploadWordsHandler(&service{q: q, logger: testLogger()}, testLogger())(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rows, err := q.ListWordFrequencies(context.Background(), database.ListWordFrequenciesParams{Limit: 100})
+	require.NoError(t, err)
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row.Value)
+	}
+	// Stop words of both languages are removed, each line with its own list
+	require.ElementsMatch(t, []string{"experience", "cloud", "containers", "znajomość", "języka", "angielskiego", "chmury"}, values)
+}
+
+func TestWordStatsHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler func(Service, *slog.Logger) http.HandlerFunc
+		query   string
+	}{
+		{
+			desc: "lists_word_frequencies_in_language",
+
+			handler: listWordFrequenciesHandler,
+			query:   "?limit=10&language=en",
+		},
+		{
+			desc: "lists_word_rankings_in_language",
+
+			handler: listWordRankingsHandler,
+			query:   "?limit=10&language=pl",
+		},
+		{
+			desc: "lists_word_frequencies_of_batches_with_attributes",
+
+			handler: listWordFrequenciesHandler,
+			query:   "?seniority=senior&location=Warszawa&work_mode=remote",
+		},
+		{
+			desc: "lists_word_rankings_without_language",
+
+			handler: listWordRankingsHandler,
+			query:   "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+			handler := tC.handler(svc, testLogger())
+
+			req := httptest.NewRequestWithContext(
+				context.Background(),
+				http.MethodGet,
+				"/"+tC.query,
+				nil,
+			)
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			resp := rr.Result()
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var body struct {
+				Rows []json.RawMessage `json:"rows"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.NotEmpty(t, body.Rows)
 		})
 	}
 }
@@ -25,7 +25,9 @@ type server struct {
 	l   *slog.Logger
 }
 
-func New(cfg config.API, svc Service, logger *slog.Logger) (*server, error) {
+// New returns server of svc. Collectors, e.g. of database pool statistics, are
+// exported with server metrics.
+func New(cfg config.API, svc Service, logger *slog.Logger, collectors ...prometheus.Collector) (*server, error) {
 	if logger == nil {
 		panic("logger cannot be nil")
 	}
@@ -35,6 +37,7 @@ func New(cfg config.API, svc Service, logger *slog.Logger) (*server, error) {
 
 	reg := prometheus.NewRegistry()
 	m := NewMetrics(reg)
+	reg.MustRegister(collectors...)
 
 	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
 	mux.Handle("GET "+prefix+"/healthz", m.WrapHandlerFunc(healthzHandler(logger)))
@@ -49,7 +52,52 @@ func New(cfg config.API, svc Service, logger *slog.Logger) (*server, error) {
 	mux.Handle("POST "+prefix+"/words", createWordHandler(svc, logger))
 	mux.Handle("POST "+prefix+"/words/file", uploadWordsHandler(svc, logger))
 	mux.Handle("POST "+prefix+"/words/image", uploadImageWordsHandler(svc, logger))
+	mux.Handle("DELETE "+prefix+"/words/{id}", deleteWordHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/words/{id}/restore", restoreWordHandler(svc, logger))
 	mux.Handle("GET "+prefix+"/words/batches", middleware.LogTime(listWordsByBatchNameHandler(svc, logger), logger))
+	mux.Handle("PATCH "+prefix+"/words/batches/{name}", updateWordBatchAttributesHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/words/frequencies", middleware.LogTime(listWordFrequenciesHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/words/rankings", middleware.LogTime(listWordRankingsHandler(svc, logger), logger))
+
+	mux.Handle("GET "+prefix+"/analytics/cooccurrence", middleware.LogTime(cooccurrenceHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/analytics/compare", middleware.LogTime(compareHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/analytics/trends", middleware.LogTime(wordTrendsHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/batches", middleware.LogTime(listBatchesHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/batches/{kind}/{id}", getBatchHandler(svc, logger))
+	mux.Handle("PATCH "+prefix+"/batches/{kind}/{id}", renameBatchHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/batches/{kind}/{id}/merge", mergeBatchesHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/batches/{kind}/{id}/split", splitBatchHandler(svc, logger))
+	mux.Handle("DELETE "+prefix+"/batches/{kind}/{id}", deleteBatchHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/batches/{kind}/{id}/restore", restoreBatchHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/tags", listGroupsHandler(svc, logger, GroupTags))
+	mux.Handle("POST "+prefix+"/tags", createGroupHandler(svc, logger, GroupTags))
+	mux.Handle("GET "+prefix+"/tags/{name}", getGroupHandler(svc, logger, GroupTags))
+	mux.Handle("DELETE "+prefix+"/tags/{name}", deleteGroupHandler(svc, logger, GroupTags))
+	mux.Handle("POST "+prefix+"/tags/{name}/members", addGroupMemberHandler(svc, logger, GroupTags))
+	mux.Handle("DELETE "+prefix+"/tags/{name}/members/{type}/{id}", removeGroupMemberHandler(svc, logger, GroupTags))
+	mux.Handle("GET "+prefix+"/collections", listGroupsHandler(svc, logger, GroupCollections))
+	mux.Handle("POST "+prefix+"/collections", createGroupHandler(svc, logger, GroupCollections))
+	mux.Handle("GET "+prefix+"/collections/{name}", getGroupHandler(svc, logger, GroupCollections))
+	mux.Handle("DELETE "+prefix+"/collections/{name}", deleteGroupHandler(svc, logger, GroupCollections))
+	mux.Handle("POST "+prefix+"/collections/{name}/members", addGroupMemberHandler(svc, logger, GroupCollections))
+	mux.Handle("DELETE "+prefix+"/collections/{name}/members/{type}/{id}", removeGroupMemberHandler(svc, logger, GroupCollections))
+	mux.Handle("GET "+prefix+"/search", middleware.LogTime(searchHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/postings", listPostingsHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/postings", createPostingHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/postings/image", capturePostingHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/postings/{id}", getPostingHandler(svc, logger))
+	mux.Handle("PATCH "+prefix+"/postings/{id}", updatePostingHandler(svc, logger))
+	mux.Handle("DELETE "+prefix+"/postings/{id}", deletePostingHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/postings/{id}/restore", restorePostingHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/postings/{id}/documents", listPostingDocumentsHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/postings/{id}/reprocess", reprocessPostingHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/match", middleware.LogTime(matchHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/salaries", listBatchSalariesHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/salaries/medians", middleware.LogTime(listSalaryMediansHandler(svc, logger), logger))
+	mux.Handle("GET "+prefix+"/stopwords", listStopWordsHandler(svc, logger))
+	mux.Handle("POST "+prefix+"/stopwords", updateStopWordsHandler(svc, logger))
+	mux.Handle("DELETE "+prefix+"/stopwords", updateStopWordsHandler(svc, logger))
+	mux.Handle("GET "+prefix+"/export", exportHandler(svc, logger))
 
 	var handler http.Handler = mux
 
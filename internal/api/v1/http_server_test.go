@@ -44,7 +44,7 @@ func TestServerStart(t *testing.T) {
 			srv, err := New(
 				mockConfig(),
 				&service{
-					q:      NewQueriesMock(NewWordsMock()...),
+					q:      newTestStore(t),
 					logger: testLogger(),
 				},
 				testLogger(),
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/match"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+// MatchCV matches skills of CV content against skills asked for in batches selected by set.
+// Content is plain text, PDF or an image, which is scanned with OCR. Empty set selects
+// every batch.
+func (svc *service) MatchCV(ctx context.Context, content []byte, set SetFilter, opts match.Options) (match.Report, error) {
+	doc, err := svc.recognize(content)
+	if err != nil {
+		return match.Report{}, err
+	}
+
+	batches, err := svc.q.CountWordBatchesInSet(ctx, database.CountWordBatchesInSetParams{
+		Batches:     set.Batches,
+		CreatedFrom: timestamptzParam(set.From),
+		CreatedTo:   timestamptzParam(set.To),
+		Seniority:   textParam(set.Seniority),
+		Location:    textParam(set.Location),
+		WorkMode:    textParam(set.WorkMode),
+		Tag:         textParam(set.Tag),
+		Collection:  textParam(set.Collection),
+	})
+	if err != nil {
+		return match.Report{}, fmt.Errorf("count word batches in set: %w", err)
+	}
+	rows, err := svc.q.ListWordCountsInSet(ctx, database.ListWordCountsInSetParams{
+		Batches:     set.Batches,
+		CreatedFrom: timestamptzParam(set.From),
+		CreatedTo:   timestamptzParam(set.To),
+		Language:    textParam(set.Language),
+		Seniority:   textParam(set.Seniority),
+		Location:    textParam(set.Location),
+		WorkMode:    textParam(set.WorkMode),
+		Tag:         textParam(set.Tag),
+		Collection:  textParam(set.Collection),
+		Excluded:    svc.filter.StopWords(),
+	})
+	if err != nil {
+		return match.Report{}, fmt.Errorf("list word counts in set: %w", err)
+	}
+	postings := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		if svc.filter.Keep(row.Value) {
+			postings[row.Value] = row.Postings
+		}
+	}
+
+	words := svc.filter.Words(textproc.Words(doc.Corrected))
+	values := make([]string, 0, len(words))
+	for _, w := range words {
+		values = append(values, w.Value)
+	}
+
+	return match.Match(values, match.NewMarket(batches, postings), opts), nil
+}
+
+// matchOptionsValue returns matching options from top, missing and rare_share query values.
+func matchOptionsValue(values url.Values) (match.Options, error) {
+	opts := match.DefaultOptions()
+
+	for key, n := range map[string]*int{"top": &opts.Top, "missing": &opts.Missing} {
+		v := values.Get(key)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return opts, fmt.Errorf("parse %s: %w", key, err)
+		}
+		*n = int(parsed)
+	}
+	if v := values.Get("rare_share"); v != "" {
+		share, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, fmt.Errorf("parse rare_share: %w", err)
+		}
+		opts.RareShare = share
+	}
+
+	return opts, nil
+}
+
+// matchHandler matches CV uploaded as cv form file (text, PDF or image) or sent as text
+// form value against batches selected by batch, from, to and attribute query values.
+func matchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	var maxSize int64 = 1024 * 1024 * 20 // 20 MB
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+		query := r.URL.Query()
+		set, err := setFilterValue(query, "")
+		if err != nil {
+			respondJSON(w, "Failed to get batches query values", err, http.StatusBadRequest)
+
+			return
+		}
+		opts, err := matchOptionsValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get matching options", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := r.ParseMultipartForm(maxSize); err != nil {
+			respondJSON(w, "Failed to parse form", err, http.StatusBadRequest)
+
+			return
+		}
+		var content []byte
+		if text := r.FormValue("text"); text != "" {
+			content = []byte(text)
+		} else {
+			f, header, err := r.FormFile("cv")
+			if err != nil {
+				respondJSON(w, "Failed to get cv file or text value", err, http.StatusBadRequest)
+
+				return
+			}
+			defer f.Close()
+
+			content, err = io.ReadAll(f)
+			if err != nil {
+				respondJSON(w, "Failed to read cv file", err, http.StatusInternalServerError)
+
+				return
+			}
+			l.Info("Received cv", slog.String("header_filename", header.Filename))
+		}
+
+		report, err := svc.MatchCV(r.Context(), content, set, opts)
+		if err != nil {
+			if errors.Is(err, match.ErrUnsupportedDocument) {
+				respondJSON(w, "Unsupported cv file, upload text, PDF, PNG or JPEG", err, http.StatusBadRequest)
+
+				return
+			}
+			respondJSON(w, "Failed to match cv", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Matched cv", "batches", report.Batches, "coverage", report.Coverage)
+
+		if err := encode(w, r, http.StatusOK, report); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/match"
+	"github.com/stretchr/testify/require"
+)
+
+// newMatchStore returns a memory store with 20 batches. Java is asked for in 10 of them,
+// php in 6 and go in 5. Generic words are asked for in more batches than any skill,
+// experience in 18 and team in 12.
+func newMatchStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	q := memory.New()
+	for i := range 20 {
+		var values []string
+		if i < 10 {
+			values = append(values, "java")
+		}
+		if i >= 10 && i < 15 {
+			values = append(values, "go")
+		}
+		if i >= 14 {
+			values = append(values, "php")
+		}
+		if i < 18 {
+			values = append(values, "experience")
+		}
+		if i < 12 {
+			values = append(values, "team")
+		}
+		ingest(t, q, database.Ingestion{Name: fmt.Sprintf("batch_%d", i), Words: englishWords(values...)})
+	}
+
+	return q
+}
+
+func TestMatchHandler(t *testing.T) {
+	t.Parallel()
+
+	const cv = "Backend developer. Golang, Java and Python, 5 years of experience."
+
+	testCases := []struct {
+		desc string
+
+		query      string
+		text       string
+		file       []byte
+		statusCode int
+	}{
+		{
+			desc: "text_value",
+
+			query:      "?top=3&missing=5",
+			text:       cv,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "text_file",
+
+			query:      "?top=3&from=2024-01-01",
+			file:       []byte(cv),
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "unsupported_file",
+
+			file:       []byte{0x00, 0x01, 0x02, 0xFF},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "missing_cv",
+
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "invalid_top",
+
+			query:      "?top=many",
+			text:       cv,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			body := new(bytes.Buffer)
+			w := multipart.NewWriter(body)
+			if tC.text != "" {
+				require.NoError(t, w.WriteField("text", tC.text))
+			}
+			if tC.file != nil {
+				part, err := w.CreateFormFile("cv", "cv.txt")
+				require.NoError(t, err)
+				_, err = part.Write(tC.file)
+				require.NoError(t, err)
+			}
+			require.NoError(t, w.Close())
+
+			svc := NewService(newMatchStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/"+tC.query, body)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			rr := httptest.NewRecorder()
+			matchHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+
+			var report match.Report
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+			require.Equal(t, int64(20), report.Batches)
+			// (25 + 50) / (50 + 30 + 25)
+			require.InDelta(t, 71.4286, report.Coverage, 0.0001)
+			require.Equal(t, []match.Skill{{Skill: "php", Postings: 6, Share: 30}}, report.Missing)
+			require.Equal(t, []match.Skill{{Skill: "python"}}, report.Rare)
+		})
+	}
+}
+
+func TestMatchCVGenericWords(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newMatchStore(t), nil, nil, nil, testLogger())
+
+	report, err := svc.MatchCV(context.Background(), []byte("Golang developer, team player with experience."), SetFilter{}, match.DefaultOptions())
+	require.NoError(t, err)
+	require.Equal(t, []match.Skill{{Skill: "go", Postings: 5, Share: 25}}, report.Matched)
+	require.Equal(t, []match.Skill{
+		{Skill: "java", Postings: 10, Share: 50},
+		{Skill: "php", Postings: 6, Share: 30},
+	}, report.Missing)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kndrad/piccrack/pkg/picphrase"
+	"github.com/kndrad/piccrack/pkg/textproc"
 )
 
 func uploadImagePhrasesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
@@ -46,13 +47,13 @@ func uploadImagePhrasesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
 			return
 		}
 
-		values := make([]string, 0)
+		lines := make([]textproc.Line, 0)
 		for phrase := range phrases {
-			values = append(values, phrase.String())
+			lines = append(lines, phrase.Line())
 		}
 
 		name := strings.Split(header.Filename, ".")[0] + "_" + time.Now().Format("20060102_150405")
-		row, err := svc.CreatePhrasesBatch(r.Context(), name, values)
+		row, err := svc.CreatePhrasesBatch(r.Context(), name, lines)
 		if err != nil {
 			respondJSON(w, "Failed to create phrases batch", err, http.StatusInternalServerError)
 
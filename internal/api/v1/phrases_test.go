@@ -29,7 +29,7 @@ func TestUploadPhrasesHandler(t *testing.T) {
 			desc: "uploads_phrases_from_an_image",
 			path: filepath.Join("testdata", "0.png"),
 
-			svc: NewService(NewQueriesMock(NewWordsMock()...), l),
+			svc: NewService(newTestStore(t), nil, nil, nil, l),
 		},
 	}
 	for _, tC := range testCases {
@@ -0,0 +1,484 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/imgsniff"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+var (
+	ErrInvalidPosting  = errors.New("invalid posting")
+	ErrPostingNotFound = errors.New("posting not found")
+)
+
+// NewPosting is a job posting captured from a job board. Words and phrases of its raw
+// text are stored in a word batch and a phrase batch owned by the posting.
+type NewPosting struct {
+	Title     string `json:"title"`
+	Company   string `json:"company"`
+	SourceURL string `json:"source_url"`
+	// Time of capture, time of creation if zero.
+	CapturedAt time.Time `json:"captured_at"`
+	// Hex encoded SHA-256 of captured image.
+	ImageHash string `json:"image_hash"`
+	// Text of posting, usually recognized from an image with OCR.
+	RawText string `json:"raw_text"`
+}
+
+// Validate checks that source URL is an absolute http(s) URL.
+func (p NewPosting) Validate() error {
+	return validateSourceURL(p.SourceURL)
+}
+
+// PostingUpdate overrides details of a posting. Nil fields are left unchanged.
+type PostingUpdate struct {
+	Title      *string    `json:"title"`
+	Company    *string    `json:"company"`
+	SourceURL  *string    `json:"source_url"`
+	CapturedAt *time.Time `json:"captured_at"`
+}
+
+// Validate checks that source URL is an absolute http(s) URL.
+func (u PostingUpdate) Validate() error {
+	if u.SourceURL == nil {
+		return nil
+	}
+
+	return validateSourceURL(*u.SourceURL)
+}
+
+func validateSourceURL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: source url %q is not an absolute http(s) url", ErrInvalidPosting, s)
+	}
+
+	return nil
+}
+
+// CreatePosting stores posting. Words, phrases and salaries of its raw text are stored
+// in batches owned by the posting, named posting_<id>, in the same transaction.
+func (svc *service) CreatePosting(ctx context.Context, p NewPosting) (database.GetPostingRow, error) {
+	return svc.createPosting(ctx, p, p.RawText, nil)
+}
+
+// createPosting stores posting with batches derived from text and its document, nil if it
+// has none, in a single transaction.
+func (svc *service) createPosting(ctx context.Context, p NewPosting, text string, doc *database.IngestedDocument) (database.GetPostingRow, error) {
+	if err := p.Validate(); err != nil {
+		return database.GetPostingRow{}, err
+	}
+
+	in := svc.postingIngestion(text)
+	in.Posting = &database.CreatePostingParams{
+		Title:      strings.TrimSpace(p.Title),
+		Company:    strings.TrimSpace(p.Company),
+		SourceUrl:  p.SourceURL,
+		CapturedAt: timestamptzParam(p.CapturedAt),
+		ImageHash:  p.ImageHash,
+		RawText:    p.RawText,
+	}
+	in.Document = doc
+	res, err := svc.ingestPosting(ctx, in)
+	if err != nil {
+		return database.GetPostingRow{}, err
+	}
+
+	return svc.GetPosting(ctx, res.PostingID.Int64)
+}
+
+// postingIngestion returns ingestion of words, phrases and salaries of text of a posting.
+// Batches without words or phrases aren't created.
+func (svc *service) postingIngestion(text string) database.Ingestion {
+	var in database.Ingestion
+
+	if words := svc.filter.Words(textproc.Words(text)); len(words) > 0 {
+		attrs := textproc.ExtractAttributes(text)
+		in.Seniority, in.Location, in.WorkMode = attrs.Seniority, attrs.Location, attrs.WorkMode
+		in.Words = ingestedWords(words)
+		// Batch ID is set on ingestion
+		in.Salaries = salaryParams(0, text)
+	}
+
+	for _, l := range textproc.DetectLines(text) {
+		if strings.TrimSpace(l.Text) == "" {
+			continue
+		}
+		in.Phrases = append(in.Phrases, database.IngestedPhrase{
+			Value:    l.Text,
+			Language: l.LanguageCode(),
+		})
+	}
+
+	return in
+}
+
+// ingestPosting ingests batches of a posting, or the posting itself, and logs counts of result.
+func (svc *service) ingestPosting(ctx context.Context, in database.Ingestion) (database.IngestResult, error) {
+	res, err := svc.q.Ingest(ctx, in)
+	if err != nil {
+		return res, fmt.Errorf("ingest posting: %w", err)
+	}
+	if res.PostingID.Valid {
+		in.PostingID = res.PostingID
+	}
+	svc.logger.Info("Ingested posting",
+		"posting_id", in.PostingID.Int64,
+		"document_id", res.DocumentID.Int64,
+		"words", res.Words,
+		"terms", res.Terms,
+		"phrases", res.Phrases,
+		"salaries", res.Salaries,
+	)
+
+	return res, nil
+}
+
+// CapturePosting stores posting of a captured document (image, PDF or text) uploaded as
+// filename. Raw text and image hash of the posting are set from content. Recognized text
+// is also stored as a document of the posting, so it can be reprocessed later. Batches
+// are derived from recognized text with OCR misreads corrected.
+func (svc *service) CapturePosting(ctx context.Context, content []byte, filename string, p NewPosting) (database.GetPostingRow, error) {
+	doc, err := svc.recognize(content)
+	if err != nil {
+		return database.GetPostingRow{}, err
+	}
+	p.RawText = doc.Text
+	p.ImageHash = imgsniff.Hash(content)
+
+	in, err := svc.ingestedDocument(content, filename, doc)
+	if err != nil {
+		return database.GetPostingRow{}, err
+	}
+
+	return svc.createPosting(ctx, p, doc.Corrected, in)
+}
+
+func (svc *service) GetPosting(ctx context.Context, id int64) (database.GetPostingRow, error) {
+	row, err := svc.q.GetPosting(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return row, fmt.Errorf("%w: %d", ErrPostingNotFound, id)
+		}
+
+		return row, fmt.Errorf("get posting: %w", err)
+	}
+
+	return row, nil
+}
+
+func (svc *service) ListPostings(ctx context.Context, limit, offset int32, company string) ([]database.ListPostingsRow, error) {
+	rows, err := svc.q.ListPostings(ctx, database.ListPostingsParams{
+		Limit:   limit,
+		Offset:  offset,
+		Company: textParam(company),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list postings: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (svc *service) UpdatePosting(ctx context.Context, id int64, update PostingUpdate) (database.UpdatePostingRow, error) {
+	if err := update.Validate(); err != nil {
+		return database.UpdatePostingRow{}, err
+	}
+
+	params := database.UpdatePostingParams{
+		ID:        id,
+		Title:     optionalTextParam(update.Title),
+		Company:   optionalTextParam(update.Company),
+		SourceUrl: optionalTextParam(update.SourceURL),
+	}
+	if update.CapturedAt != nil {
+		params.CapturedAt = timestamptzParam(*update.CapturedAt)
+	}
+
+	row, err := svc.q.UpdatePosting(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return row, fmt.Errorf("%w: %d", ErrPostingNotFound, id)
+		}
+
+		return row, fmt.Errorf("update posting: %w", err)
+	}
+
+	return row, nil
+}
+
+// DeletePosting soft deletes posting with its batches, rows of the batches and documents.
+func (svc *service) DeletePosting(ctx context.Context, id int64) error {
+	if _, err := svc.q.DeletePosting(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %d", ErrPostingNotFound, id)
+		}
+
+		return fmt.Errorf("delete posting: %w", err)
+	}
+
+	return nil
+}
+
+// RestorePosting restores deleted posting with its batches, rows of the batches and documents.
+// Batches replaced on reprocessing stay deleted.
+func (svc *service) RestorePosting(ctx context.Context, id int64) error {
+	if _, err := svc.q.RestorePosting(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: deleted %d", ErrPostingNotFound, id)
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: posting %d has another batch", ErrRestoreConflict, id)
+		}
+
+		return fmt.Errorf("restore posting: %w", err)
+	}
+
+	return nil
+}
+
+// idValue returns ID of request path, e.g. ID of a posting or a batch.
+func idValue(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse int: %w", err)
+	}
+
+	return id, nil
+}
+
+// respondPostingErr responds with status code matching err of posting service methods.
+func respondPostingErr(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidPosting):
+		respondJSON(w, "Invalid posting", err, http.StatusBadRequest)
+	case errors.Is(err, ErrPostingNotFound):
+		respondJSON(w, "Posting not found", err, http.StatusNotFound)
+	default:
+		respondJSON(w, msg, err, http.StatusInternalServerError)
+	}
+}
+
+func createPostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.GetPostingRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := decode[NewPosting](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.CreatePosting(r.Context(), p)
+		if err != nil {
+			respondPostingErr(w, "Failed to create posting", err)
+
+			return
+		}
+		l.Info("Created posting", "id", row.ID, "word_batch_id", row.WordBatchID.Int64, "phrase_batch_id", row.PhraseBatchID.Int64)
+
+		if err := encode(w, r, http.StatusCreated, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+// capturePostingHandler creates posting of a capture uploaded as image form file (image,
+// PDF or text). Title, company, source_url and captured_at form values describe posting.
+func capturePostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.GetPostingRow `json:"row"`
+	}
+	var maxSize int64 = 1024 * 1024 * 50 // 50 MB
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+		if err := r.ParseMultipartForm(maxSize); err != nil {
+			respondJSON(w, "Failed to parse form", err, http.StatusBadRequest)
+
+			return
+		}
+		f, header, err := r.FormFile("image")
+		if err != nil {
+			respondJSON(w, "Failed to get image file", err, http.StatusBadRequest)
+
+			return
+		}
+		defer f.Close()
+
+		content, err := io.ReadAll(f)
+		if err != nil {
+			respondJSON(w, "Failed to read image file", err, http.StatusInternalServerError)
+
+			return
+		}
+		capturedAt, err := timeValue(r.MultipartForm.Value, "captured_at")
+		if err != nil {
+			respondJSON(w, "Failed to get captured_at form value", err, http.StatusBadRequest)
+
+			return
+		}
+		l.Info("Received capture", slog.String("header_filename", header.Filename))
+
+		row, err := svc.CapturePosting(r.Context(), content, header.Filename, NewPosting{
+			Title:      r.FormValue("title"),
+			Company:    r.FormValue("company"),
+			SourceURL:  r.FormValue("source_url"),
+			CapturedAt: capturedAt,
+		})
+		if err != nil {
+			respondPostingErr(w, "Failed to capture posting", err)
+
+			return
+		}
+		l.Info("Captured posting", "id", row.ID, "image_hash", row.ImageHash)
+
+		if err := encode(w, r, http.StatusCreated, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func getPostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.GetPostingRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.GetPosting(r.Context(), id)
+		if err != nil {
+			respondPostingErr(w, "Failed to get posting", err)
+
+			return
+		}
+		l.Info("Got posting", "id", row.ID)
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func listPostingsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Rows []database.ListPostingsRow `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		offset, err := offsetValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
+
+			return
+		}
+
+		rows, err := svc.ListPostings(r.Context(), limit, offset, query.Get("company"))
+		if err != nil {
+			respondJSON(w, "Failed to list postings", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Got postings", "total", len(rows))
+
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func updatePostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Row database.UpdatePostingRow `json:"row"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+		update, err := decode[PostingUpdate](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+
+		row, err := svc.UpdatePosting(r.Context(), id, update)
+		if err != nil {
+			respondPostingErr(w, "Failed to update posting", err)
+
+			return
+		}
+		l.Info("Updated posting", "id", row.ID)
+
+		if err := encode(w, r, http.StatusOK, response{Row: row}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+func deletePostingHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := idValue(r)
+		if err != nil {
+			respondJSON(w, "Failed to get posting id", err, http.StatusBadRequest)
+
+			return
+		}
+
+		if err := svc.DeletePosting(r.Context(), id); err != nil {
+			respondPostingErr(w, "Failed to delete posting", err)
+
+			return
+		}
+		l.Info("Deleted posting", "id", id)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostingHandlers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		handler    func(Service, *slog.Logger) http.HandlerFunc
+		method     string
+		id         string
+		body       string
+		statusCode int
+	}{
+		{
+			desc: "create",
+
+			handler:    createPostingHandler,
+			method:     http.MethodPost,
+			body:       `{"title": "Go Developer", "company": "Acme", "source_url": "https://jobs.example.com/1", "raw_text": "Senior Go Developer\nKubernetes, PostgreSQL\n20 000 - 25 000 PLN B2B"}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			desc: "create_with_invalid_source_url",
+
+			handler:    createPostingHandler,
+			method:     http.MethodPost,
+			body:       `{"title": "Go Developer", "source_url": "jobs.example.com/1"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "get",
+
+			handler:    getPostingHandler,
+			method:     http.MethodGet,
+			id:         "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "get_missing",
+
+			handler:    getPostingHandler,
+			method:     http.MethodGet,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "get_invalid_id",
+
+			handler:    getPostingHandler,
+			method:     http.MethodGet,
+			id:         "first",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "list",
+
+			handler:    listPostingsHandler,
+			method:     http.MethodGet,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "update",
+
+			handler:    updatePostingHandler,
+			method:     http.MethodPatch,
+			id:         "1",
+			body:       `{"company": "Acme Corp"}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc: "update_missing",
+
+			handler:    updatePostingHandler,
+			method:     http.MethodPatch,
+			id:         "99",
+			body:       `{"company": "Acme Corp"}`,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc: "delete",
+
+			handler:    deletePostingHandler,
+			method:     http.MethodDelete,
+			id:         "1",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc: "delete_missing",
+
+			handler:    deletePostingHandler,
+			method:     http.MethodDelete,
+			id:         "99",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), tC.method, "/", strings.NewReader(tC.body))
+			req.SetPathValue("id", tC.id)
+			rr := httptest.NewRecorder()
+			tC.handler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+// ingestionRecorder records ingestions and fails queries which would store rows of
+// a posting outside of them.
+type ingestionRecorder struct {
+	*memory.Store
+
+	ingestions []database.Ingestion
+}
+
+func (q *ingestionRecorder) Ingest(ctx context.Context, in database.Ingestion) (database.IngestResult, error) {
+	q.ingestions = append(q.ingestions, in)
+
+	return q.Store.Ingest(ctx, in)
+}
+
+func (q *ingestionRecorder) CreatePosting(context.Context, database.CreatePostingParams) (database.CreatePostingRow, error) {
+	return database.CreatePostingRow{}, errors.New("posting created outside of ingestion")
+}
+
+func (q *ingestionRecorder) CreateOcrDocument(context.Context, database.CreateOcrDocumentParams) (database.CreateOcrDocumentRow, error) {
+	return database.CreateOcrDocumentRow{}, errors.New("document created outside of ingestion")
+}
+
+func (q *ingestionRecorder) CreateSalary(context.Context, database.CreateSalaryParams) (database.CreateSalaryRow, error) {
+	return database.CreateSalaryRow{}, errors.New("salary created outside of ingestion")
+}
+
+func TestCapturePostingSingleIngestion(t *testing.T) {
+	t.Parallel()
+
+	q := &ingestionRecorder{Store: memory.New()}
+	svc := NewService(q, nil, nil, nil, testLogger())
+
+	text := "Senior Go Developer\nKubernetes, PostgreSQL\n20 000 - 25 000 PLN B2B"
+	row, err := svc.CapturePosting(context.Background(), []byte(text), "posting.txt", NewPosting{Title: " Go Developer "})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), row.ID)
+
+	require.Len(t, q.ingestions, 1)
+	in := q.ingestions[0]
+	require.NotNil(t, in.Posting)
+	require.Equal(t, "Go Developer", in.Posting.Title)
+	require.Equal(t, text, in.Posting.RawText)
+	require.NotEmpty(t, in.Words)
+	require.NotEmpty(t, in.Phrases)
+	require.NotEmpty(t, in.Salaries)
+	require.NotNil(t, in.Document)
+	require.Nil(t, in.Document.Image)
+	require.Equal(t, EngineText, in.Document.Document.Engine)
+}
+
+func TestCreateWordsBatchSingleIngestion(t *testing.T) {
+	t.Parallel()
+
+	q := &ingestionRecorder{Store: memory.New()}
+	svc := NewService(q, nil, nil, nil, testLogger())
+
+	res, err := svc.CreateWordsBatch(context.Background(), "golang_0.png", "Senior Go Developer, remote\n20 000 - 25 000 PLN B2B")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.Salaries)
+
+	require.Len(t, q.ingestions, 1)
+	require.Equal(t, "golang_0.png", q.ingestions[0].Name)
+	require.Equal(t, "remote", q.ingestions[0].WorkMode)
+}
+
+func TestCaptureWordsBatchSingleIngestion(t *testing.T) {
+	t.Parallel()
+
+	q := &ingestionRecorder{Store: memory.New()}
+	svc := NewService(q, nil, nil, nil, testLogger())
+
+	text := "Senior Go Developer, remote\n20 000 - 25 000 PLN B2B"
+	res, err := svc.CaptureWordsBatch(context.Background(), []byte(text), "golang_0.txt")
+	require.NoError(t, err)
+	require.True(t, res.PostingID.Valid)
+	require.True(t, res.DocumentID.Valid)
+	require.Equal(t, int64(1), res.Salaries)
+
+	require.Len(t, q.ingestions, 1)
+	in := q.ingestions[0]
+	require.Equal(t, "golang_0.txt", in.Name)
+	require.Equal(t, "remote", in.WorkMode)
+	require.Equal(t, text, in.Posting.RawText)
+	require.Equal(t, text, in.Document.Document.Text)
+
+	// Uploaded content is recognized, not a file named by the client
+	doc, err := q.GetLatestOcrDocument(context.Background(), res.PostingID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, EngineText, doc.Engine)
+	batch, err := q.GetWordBatch(context.Background(), res.WordBatchID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, res.PostingID, batch.PostingID)
+}
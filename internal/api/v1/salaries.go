@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+// CreateSalaries extracts salary ranges from text and stores them in batch.
+func (svc *service) CreateSalaries(ctx context.Context, batchID int64, text string) ([]database.CreateSalaryRow, error) {
+	rows := make([]database.CreateSalaryRow, 0)
+	for _, params := range salaryParams(batchID, text) {
+		row, err := svc.q.CreateSalary(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("create salary: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// salaryParams returns params of salary ranges extracted from text, stored in batch.
+// Seniority of the posting is detected from the whole text.
+func salaryParams(batchID int64, text string) []database.CreateSalaryParams {
+	seniority := textproc.DetectSeniority(text)
+
+	params := make([]database.CreateSalaryParams, 0)
+	for _, s := range textproc.ExtractSalaries(text) {
+		monthlyMin, monthlyMax := s.Monthly()
+		params = append(params, database.CreateSalaryParams{
+			BatchID:    batchID,
+			MinAmount:  s.Min,
+			MaxAmount:  s.Max,
+			Currency:   s.Currency,
+			Period:     s.Period,
+			MonthlyMin: monthlyMin,
+			MonthlyMax: monthlyMax,
+			Contract:   s.Contract,
+			Basis:      s.Basis,
+			Seniority:  seniority,
+			Raw:        s.Text,
+		})
+	}
+
+	return params
+}
+
+func (svc *service) ListSalariesByBatchName(ctx context.Context, name string) ([]database.ListSalariesByBatchNameRow, error) {
+	rows, err := svc.q.ListSalariesByBatchName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("list salaries by batch name: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ListSalaryMediansBySkill groups salaries by known skills of their batches. Aliases are
+// merged under canonical names, so "golang" is searched and listed as "go".
+func (svc *service) ListSalaryMediansBySkill(ctx context.Context, limit, offset int32, skill, contract string) ([]database.ListSalaryMediansBySkillRow, error) {
+	if skill != "" {
+		skill = textproc.CanonicalSkill(skill)
+	}
+	names := textproc.SkillNames()
+	rows, err := svc.q.ListSalaryMediansBySkill(ctx, database.ListSalaryMediansBySkillParams{
+		Limit:           limit,
+		Offset:          offset,
+		Skill:           textParam(skill),
+		Contract:        textParam(contract),
+		Excluded:        svc.filter.StopWords(),
+		Skills:          names,
+		CanonicalSkills: textproc.CanonicalSkills(names),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list salary medians by skill: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (svc *service) ListSalaryMediansBySeniority(ctx context.Context, contract string) ([]database.ListSalaryMediansBySeniorityRow, error) {
+	rows, err := svc.q.ListSalaryMediansBySeniority(ctx, textParam(contract))
+	if err != nil {
+		return nil, fmt.Errorf("list salary medians by seniority: %w", err)
+	}
+
+	return rows, nil
+}
+
+func listBatchSalariesHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Rows []database.ListSalariesByBatchNameRow `json:"rows"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("batch")
+		if name == "" {
+			respondJSON(w, "Batch name query value is required", errors.New("empty batch"), http.StatusBadRequest)
+
+			return
+		}
+
+		rows, err := svc.ListSalariesByBatchName(r.Context(), name)
+		if err != nil {
+			respondJSON(w, "Failed to list batch salaries", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Got batch salaries", "total", len(rows), "batch", name)
+
+		if err := encode(w, r, http.StatusOK, response{Rows: rows}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+// listSalaryMediansHandler serves median monthly salaries grouped by skill
+// (by=skill, default) or by seniority (by=seniority).
+func listSalaryMediansHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		contract := query.Get("contract")
+
+		var rows any
+		switch by := query.Get("by"); by {
+		case "", "skill":
+			limit, err := limitValue(query)
+			if err != nil {
+				respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+				return
+			}
+			offset, err := offsetValue(query)
+			if err != nil {
+				respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
+
+				return
+			}
+			rows, err = svc.ListSalaryMediansBySkill(r.Context(), limit, offset, query.Get("skill"), contract)
+			if err != nil {
+				respondJSON(w, "Failed to list salary medians by skill", err, http.StatusInternalServerError)
+
+				return
+			}
+		case "seniority":
+			var err error
+			rows, err = svc.ListSalaryMediansBySeniority(r.Context(), contract)
+			if err != nil {
+				respondJSON(w, "Failed to list salary medians by seniority", err, http.StatusInternalServerError)
+
+				return
+			}
+		default:
+			respondJSON(w, fmt.Sprintf("Unknown by query value %q, use skill or seniority", by), nil, http.StatusBadRequest)
+
+			return
+		}
+		l.Info("Got salary medians", "by", query.Get("by"), "contract", contract)
+
+		response := struct {
+			Rows any `json:"rows"`
+		}{
+			Rows: rows,
+		}
+		if err := encode(w, r, http.StatusOK, response); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSalaryMediansHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		query      string
+		statusCode int
+		rows       int
+	}{
+		{
+			desc: "medians_by_skill_by_default",
+
+			query:      "?limit=10",
+			statusCode: http.StatusOK,
+			rows:       3,
+		},
+		{
+			desc: "medians_by_seniority_of_b2b_contracts",
+
+			query:      "?by=seniority&contract=b2b",
+			statusCode: http.StatusOK,
+			rows:       1,
+		},
+		{
+			desc: "unknown_grouping",
+
+			query:      "?by=city",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+			handler := listSalaryMediansHandler(svc, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/"+tC.query, nil)
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+			var body struct {
+				Rows []json.RawMessage `json:"rows"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+			require.Len(t, body.Rows, tC.rows)
+		})
+	}
+}
+
+func TestListSalaryMediansBySkillMergesAliases(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	// Posting 1 has words golang, kubernetes and postgresql
+	rows, err := svc.ListSalaryMediansBySkill(context.Background(), 10, 0, "", "")
+	require.NoError(t, err)
+	skills := make([]string, 0, len(rows))
+	for _, row := range rows {
+		skills = append(skills, row.Skill)
+	}
+	require.Equal(t, []string{"go", "kubernetes", "postgresql"}, skills)
+
+	rows, err = svc.ListSalaryMediansBySkill(context.Background(), 10, 0, "Golang", "b2b")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, "go", rows[0].Skill)
+	require.InDelta(t, 22500.0, rows[0].Median, 0)
+}
+
+func TestCreateSalaries(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+	rows, err := svc.CreateSalaries(context.Background(), 1, "Senior Go Developer\n18 000 - 24 000 PLN netto B2B")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(1), rows[0].BatchID)
+	require.Equal(t, "PLN", rows[0].Currency)
+	require.Equal(t, "b2b", rows[0].Contract.String)
+	require.Equal(t, "senior", rows[0].Seniority.String)
+}
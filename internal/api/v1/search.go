@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+var ErrEmptyQuery = errors.New("empty search query")
+
+// Search results are ranked by full-text search of phrases in their language (and
+// unstemmed), then by trigram similarity to the query, which matches misspelled words.
+
+// SearchPhrases returns phrases matching query, best matches first. Non-empty language
+// keeps only phrases in language and stems query in its configuration.
+func (svc *service) SearchPhrases(ctx context.Context, query, language string, limit, offset int32) ([]database.SearchPhrasesRow, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	rows, err := svc.q.SearchPhrases(ctx, database.SearchPhrasesParams{
+		Limit:    limit,
+		Offset:   offset,
+		Query:    query,
+		Language: textParam(language),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search phrases: %w", err)
+	}
+	for i := range rows {
+		rows[i].Snippet = escapeSnippet(rows[i].Snippet)
+	}
+
+	return rows, nil
+}
+
+// SearchWords returns words similar to query, most similar first.
+func (svc *service) SearchWords(ctx context.Context, query, language string, limit, offset int32) ([]database.SearchWordsRow, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	rows, err := svc.q.SearchWords(ctx, database.SearchWordsParams{
+		Limit:    limit,
+		Offset:   offset,
+		Query:    query,
+		Language: textParam(language),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search words: %w", err)
+	}
+
+	return rows, nil
+}
+
+const (
+	markStart = "<mark>"
+	markStop  = "</mark>"
+)
+
+// escapeSnippet escapes HTML of snippet except <mark> tags which highlight matches,
+// so snippets of stored text can be rendered as HTML.
+func escapeSnippet(s string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(s, markStart) {
+		if i > 0 {
+			b.WriteString(markStart)
+		}
+		b.WriteString(strings.ReplaceAll(html.EscapeString(part), html.EscapeString(markStop), markStop))
+	}
+
+	return b.String()
+}
+
+// searchHandler serves phrases (default) or words matching q query value, selected
+// by kind query value.
+func searchHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		offset, err := offsetValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get offset query value", err, http.StatusBadRequest)
+
+			return
+		}
+		q, language := query.Get("q"), query.Get("language")
+
+		var (
+			rows  any
+			total int
+		)
+		switch kind := query.Get("kind"); kind {
+		case "", "phrases":
+			var phrases []database.SearchPhrasesRow
+			phrases, err = svc.SearchPhrases(r.Context(), q, language, limit, offset)
+			rows, total = phrases, len(phrases)
+		case "words":
+			var words []database.SearchWordsRow
+			words, err = svc.SearchWords(r.Context(), q, language, limit, offset)
+			rows, total = words, len(words)
+		default:
+			respondJSON(w, fmt.Sprintf("Unknown kind query value %q, use phrases or words", kind), nil, http.StatusBadRequest)
+
+			return
+		}
+		if err != nil {
+			if errors.Is(err, ErrEmptyQuery) {
+				respondJSON(w, "Missing q query value", err, http.StatusBadRequest)
+
+				return
+			}
+			respondJSON(w, "Failed to search", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Searched", "q", q, "total", total)
+
+		response := struct {
+			Rows any `json:"rows"`
+		}{
+			Rows: rows,
+		}
+		if err := encode(w, r, http.StatusOK, response); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		query      string
+		statusCode int
+		values     []string
+	}{
+		{
+			desc: "phrases",
+
+			query:      "?q=remote",
+			statusCode: http.StatusOK,
+			values:     []string{"Remote work"},
+		},
+		{
+			desc: "words",
+
+			query:      "?q=kubernetes&kind=words",
+			statusCode: http.StatusOK,
+			values:     []string{"kubernetes"},
+		},
+		{
+			desc: "missing_query",
+
+			query:      "?q=%20",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "unknown_kind",
+
+			query:      "?q=go&kind=salaries",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTestStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/"+tC.query, nil)
+			rr := httptest.NewRecorder()
+			searchHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code, rr.Body.String())
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Rows []struct {
+					Value string `json:"value"`
+				} `json:"rows"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+			values := make([]string, 0, len(body.Rows))
+			for _, row := range body.Rows {
+				values = append(values, row.Value)
+			}
+			require.Subset(t, values, tC.values)
+		})
+	}
+}
+
+func TestEscapeSnippet(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		"Office &lt;b&gt;in&lt;/b&gt; <mark>Warsaw</mark> &amp; <mark>Berlin</mark>",
+		escapeSnippet("Office <b>in</b> <mark>Warsaw</mark> & <mark>Berlin</mark>"),
+	)
+}
@@ -0,0 +1,114 @@
+//go:build integration
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// TestServerConcurrentRequests serves requests in parallel with a database pool. Requests
+// sharing a single connection would fail with conn busy errors.
+func TestServerConcurrentRequests(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:17",
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpassword"),
+		postgres.WithDatabase("piccrack"),
+		postgres.BasicWaitStrategies(),
+		postgres.WithSQLDriver("pgx"),
+	)
+	require.NoError(t, err)
+	defer container.Terminate(ctx)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	m, err := database.NewMigrator(dsn)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	require.NoError(t, m.Close())
+
+	pool, err := database.Pool(ctx, config.DatabaseConfig{
+		DSN:  dsn,
+		Pool: config.PoolConfig{MaxConns: 4},
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	svc := NewService(database.NewRepository(pool), nil, nil, nil, testLogger())
+	srv, err := New(config.API{}, svc, testLogger(), database.NewPoolCollector(pool))
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.srv.Handler)
+	defer ts.Close()
+
+	const (
+		workers  = 16
+		requests = 20
+	)
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*requests)
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range requests {
+				var (
+					resp *http.Response
+					err  error
+				)
+				switch i % 3 {
+				case 0:
+					body := fmt.Sprintf(`{"value": "concurrent%d_%d"}`, w, i)
+					resp, err = http.Post(ts.URL+"/api/v1/words", "application/json", strings.NewReader(body))
+				case 1:
+					resp, err = http.Get(ts.URL + "/api/v1/words/frequencies?limit=10")
+				default:
+					resp, err = http.Get(ts.URL + "/api/v1/batches")
+				}
+				if err != nil {
+					errs <- err
+
+					continue
+				}
+				data, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					errs <- fmt.Errorf("%s %s: %d %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, data)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var words int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM vocabulary WHERE raw LIKE 'concurrent%'").Scan(&words))
+	require.Equal(t, workers*((requests+2)/3), words)
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	metrics, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(metrics), "db_pool_max_conns 4")
+	require.Contains(t, string(metrics), "db_pool_acquires_total")
+}
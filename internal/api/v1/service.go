@@ -3,33 +3,119 @@ package v1
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/archive"
 	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/compare"
+	"github.com/kndrad/piccrack/pkg/cooccur"
+	"github.com/kndrad/piccrack/pkg/imgsniff"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+	"github.com/kndrad/piccrack/pkg/match"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/kndrad/piccrack/pkg/trend"
 )
 
 type Service interface {
 	ListWords(ctx context.Context, limit, offset int32) ([]database.ListWordsRow, error)
 	CreateWord(ctx context.Context, value string) (database.CreateWordRow, error)
-	ListWordBatches(ctx context.Context, limit, offset int32) ([]database.ListWordBatchesRow, error)
-	CreateWordsBatch(ctx context.Context, name string, values []string) (database.CreateWordsBatchRow, error)
+	CreateWords(ctx context.Context, words []textproc.Word) (database.IngestResult, error)
+	CreateWordsBatch(ctx context.Context, name, text string) (database.IngestResult, error)
+	CaptureWordsBatch(ctx context.Context, content []byte, filename string) (database.IngestResult, error)
+	CreatePosting(ctx context.Context, p NewPosting) (database.GetPostingRow, error)
+	CapturePosting(ctx context.Context, content []byte, filename string, p NewPosting) (database.GetPostingRow, error)
+	GetPosting(ctx context.Context, id int64) (database.GetPostingRow, error)
+	ListPostings(ctx context.Context, limit, offset int32, company string) ([]database.ListPostingsRow, error)
+	UpdatePosting(ctx context.Context, id int64, update PostingUpdate) (database.UpdatePostingRow, error)
+	DeletePosting(ctx context.Context, id int64) error
+	RestorePosting(ctx context.Context, id int64) error
+	ListPostingDocuments(ctx context.Context, id int64) ([]database.ListOcrDocumentsRow, error)
+	ReprocessPosting(ctx context.Context, id int64, rescan bool) (database.GetPostingRow, error)
+	UpdateWordBatchAttributes(ctx context.Context, name string, update AttributesUpdate) (database.UpdateWordBatchAttributesRow, error)
 	ListWordsByBatchName(ctx context.Context, name string) ([]database.ListWordsByBatchNameRow, error)
-	CreatePhrasesBatch(ctx context.Context, name string, values []string) (database.CreatePhrasesBatchRow, error)
+	CreatePhrasesBatch(ctx context.Context, name string, lines []textproc.Line) (database.CreatePhrasesBatchRow, error)
+	ListBatches(ctx context.Context, limit, offset int32, f BatchFilter) ([]database.ListBatchesRow, error)
+	GetBatch(ctx context.Context, kind BatchKind, id int64) (BatchDetails, error)
+	RenameBatch(ctx context.Context, kind BatchKind, id int64, name string) (database.ListBatchesRow, error)
+	MergeBatches(ctx context.Context, kind BatchKind, target, source int64) (int64, error)
+	SplitBatch(ctx context.Context, kind BatchKind, id int64, name string, values []string) (BatchSplit, error)
+	DeleteBatch(ctx context.Context, kind BatchKind, id int64) error
+	RestoreBatch(ctx context.Context, kind BatchKind, id int64) error
+	DeleteWord(ctx context.Context, id int64) error
+	RestoreWord(ctx context.Context, id int64) error
+	Purge(ctx context.Context, age time.Duration) (database.PurgeDeletedRow, error)
+	CreateGroup(ctx context.Context, kind GroupKind, name, description string) (Group, error)
+	ListGroups(ctx context.Context, kind GroupKind, limit, offset int32) ([]Group, error)
+	GetGroup(ctx context.Context, kind GroupKind, name string) (GroupDetails, error)
+	DeleteGroup(ctx context.Context, kind GroupKind, name string) error
+	AddGroupMember(ctx context.Context, kind GroupKind, name string, m Member) error
+	RemoveGroupMember(ctx context.Context, kind GroupKind, name string, m Member) error
+	SearchPhrases(ctx context.Context, query, language string, limit, offset int32) ([]database.SearchPhrasesRow, error)
+	SearchWords(ctx context.Context, query, language string, limit, offset int32) ([]database.SearchWordsRow, error)
+	ListWordFrequencies(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordFrequenciesRow, error)
+	ListWordRankings(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordRankingsRow, error)
+	Cooccurrences(ctx context.Context, f WordFilter) (*cooccur.Matrix, error)
+	CompareWords(ctx context.Context, a, b SetFilter, opts compare.Options) (compare.Result, error)
+	ComparePhrases(ctx context.Context, a, b SetFilter, opts compare.Options) (compare.Result, error)
+	MatchCV(ctx context.Context, content []byte, set SetFilter, opts match.Options) (match.Report, error)
+	WordTrends(ctx context.Context, words []string, w trend.Window, f WordFilter) ([]trend.Series, error)
+	CreateSalaries(ctx context.Context, batchID int64, text string) ([]database.CreateSalaryRow, error)
+	ListSalariesByBatchName(ctx context.Context, name string) ([]database.ListSalariesByBatchNameRow, error)
+	ListSalaryMediansBySkill(ctx context.Context, limit, offset int32, skill, contract string) ([]database.ListSalaryMediansBySkillRow, error)
+	ListSalaryMediansBySeniority(ctx context.Context, contract string) ([]database.ListSalaryMediansBySeniorityRow, error)
+	FilterWords(words []textproc.Word) []textproc.Word
+	ListStopWords(ctx context.Context) ([]string, error)
+	AddStopWords(ctx context.Context, words ...string) ([]string, error)
+	RemoveStopWords(ctx context.Context, words ...string) ([]string, error)
+	Export(ctx context.Context, w io.Writer, opts archive.ExportOptions) (archive.Manifest, error)
+}
+
+// WordFilter narrows word statistics to words in language and to words of batches
+// tagged with seniority, location and work mode, or belonging to a tag or a collection
+// (directly or through their posting). Empty fields don't filter.
+type WordFilter struct {
+	Language   string
+	Seniority  string
+	Location   string
+	WorkMode   string
+	Tag        string
+	Collection string
+}
+
+// batchFiltered reports whether f narrows statistics to batches with attributes,
+// of a tag or of a collection.
+func (f WordFilter) batchFiltered() bool {
+	return f.Seniority != "" || f.Location != "" || f.WorkMode != "" || f.Tag != "" || f.Collection != ""
 }
 
 type service struct {
-	q      database.Querier
+	q      database.Store
 	logger *slog.Logger
+
+	// Drops noise words during ingestion and at query time. Nil filter keeps every word.
+	filter *textproc.Filter
+	// User managed stop words of the filter. Nil if not configured.
+	stopWords *textproc.StopWordsFile
+	// Keeps content of captured images. Nil store keeps only image metadata.
+	images *imgstore.Store
 }
 
 var _ Service = (*service)(nil)
 
-func NewService(q database.Querier, l *slog.Logger) Service {
+// NewService creates service querying q. Filter, stop words file and image store are optional.
+func NewService(q database.Store, filter *textproc.Filter, stopWords *textproc.StopWordsFile, images *imgstore.Store, l *slog.Logger) Service {
 	return &service{
-		q:      q,
-		logger: l,
+		q:         q,
+		logger:    l,
+		filter:    filter,
+		stopWords: stopWords,
+		images:    images,
 	}
 }
 
@@ -49,7 +135,12 @@ func (svc *service) CreateWord(ctx context.Context, value string) (database.Crea
 	if value == "" {
 		panic("value cannot be empty")
 	}
-	row, err := svc.q.CreateWord(ctx, value)
+	word := textproc.NewWord(value)
+	row, err := svc.q.CreateWord(ctx, database.CreateWordParams{
+		Value:    word.Value,
+		Lemma:    word.Lemma,
+		Language: word.Language,
+	})
 	if err != nil {
 		return row, fmt.Errorf("insert word: %w", err)
 	}
@@ -57,28 +148,90 @@ func (svc *service) CreateWord(ctx context.Context, value string) (database.Crea
 	return row, nil
 }
 
-func (svc *service) ListWordBatches(ctx context.Context, limit, offset int32) ([]database.ListWordBatchesRow, error) {
-	rows, err := svc.q.ListWordBatches(ctx, database.ListWordBatchesParams{
-		Limit:  limit,
-		Offset: offset,
+// CreateWords adds words without a batch in a single transaction. Noise words are dropped.
+func (svc *service) CreateWords(ctx context.Context, words []textproc.Word) (database.IngestResult, error) {
+	res, err := svc.q.Ingest(ctx, database.Ingestion{
+		Words: ingestedWords(svc.filter.Words(words)),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("list word batches: %w", err)
+		return res, fmt.Errorf("ingest words: %w", err)
 	}
 
-	return rows, nil
+	return res, nil
 }
 
-func (svc *service) CreateWordsBatch(ctx context.Context, name string, values []string) (database.CreateWordsBatchRow, error) {
-	row, err := svc.q.CreateWordsBatch(ctx, database.CreateWordsBatchParams{
-		Name:    name,
-		Column2: values,
-	})
+// ingestedWords converts words into words of ingestion.
+func ingestedWords(words []textproc.Word) []database.IngestedWord {
+	ingested := make([]database.IngestedWord, 0, len(words))
+	for _, w := range words {
+		ingested = append(ingested, database.IngestedWord{
+			Value:    w.Value,
+			Lemma:    w.Lemma,
+			Language: w.Language,
+		})
+	}
+
+	return ingested
+}
+
+// CreateWordsBatch stores words of text in a batch named name, with attributes and
+// salaries detected from text, in a single transaction.
+func (svc *service) CreateWordsBatch(ctx context.Context, name, text string) (database.IngestResult, error) {
+	res, err := svc.q.Ingest(ctx, svc.wordsBatchIngestion(name, text))
 	if err != nil {
-		return row, fmt.Errorf("create word batch: %w", err)
+		return res, fmt.Errorf("ingest word batch: %w", err)
 	}
 
-	return row, nil
+	return res, nil
+}
+
+// CaptureWordsBatch stores words of a captured document (image, PDF or text) uploaded as
+// filename in a batch named filename, like CreateWordsBatch. Recognized text is stored as
+// a document of a new posting titled filename, which owns the batch, in the same transaction.
+func (svc *service) CaptureWordsBatch(ctx context.Context, content []byte, filename string) (database.IngestResult, error) {
+	doc, err := svc.recognize(content)
+	if err != nil {
+		return database.IngestResult{}, err
+	}
+	document, err := svc.ingestedDocument(content, filename, doc)
+	if err != nil {
+		return database.IngestResult{}, err
+	}
+
+	in := svc.wordsBatchIngestion(filename, doc.Corrected)
+	in.Posting = &database.CreatePostingParams{
+		Title:     filename,
+		ImageHash: imgsniff.Hash(content),
+		RawText:   doc.Text,
+	}
+	in.Document = document
+	res, err := svc.q.Ingest(ctx, in)
+	if err != nil {
+		return res, fmt.Errorf("ingest word batch: %w", err)
+	}
+
+	return res, nil
+}
+
+// wordsBatchIngestion returns ingestion of words of text in a batch named name, with
+// attributes and salaries detected from text.
+func (svc *service) wordsBatchIngestion(name, text string) database.Ingestion {
+	words := svc.filter.Words(textproc.Words(text))
+	attrs := textproc.ExtractAttributes(text)
+
+	in := database.Ingestion{
+		Name:      name,
+		Seniority: attrs.Seniority,
+		Location:  attrs.Location,
+		WorkMode:  attrs.WorkMode,
+		Words:     ingestedWords(words),
+	}
+	if len(words) > 0 {
+		// Batch ID is set on ingestion
+		in.Salaries = salaryParams(0, text)
+	}
+
+	return in
 }
 
 func (svc *service) ListWordsByBatchName(ctx context.Context, name string) ([]database.ListWordsByBatchNameRow, error) {
@@ -90,19 +243,148 @@ func (svc *service) ListWordsByBatchName(ctx context.Context, name string) ([]da
 	return rows, nil
 }
 
-func (svc *service) CreatePhrasesBatch(ctx context.Context, name string, values []string) (database.CreatePhrasesBatchRow, error) {
+func (svc *service) CreatePhrasesBatch(ctx context.Context, name string, lines []textproc.Line) (database.CreatePhrasesBatchRow, error) {
+	return svc.createPhrasesBatch(ctx, name, lines, pgtype.Int8{})
+}
+
+// createPhrasesBatch creates batch of phrases owned by posting. Null posting ID creates a standalone batch.
+func (svc *service) createPhrasesBatch(ctx context.Context, name string, lines []textproc.Line, postingID pgtype.Int8) (database.CreatePhrasesBatchRow, error) {
 	// filter empty values
-	values = slices.DeleteFunc(values, func(s string) bool {
-		return strings.Trim(s, " ") == ""
+	lines = slices.DeleteFunc(lines, func(l textproc.Line) bool {
+		return strings.Trim(l.Text, " ") == ""
 	})
 
-	row, err := svc.q.CreatePhrasesBatch(ctx, database.CreatePhrasesBatchParams{
-		Name:    name,
-		Phrases: values,
-	})
+	params := database.CreatePhrasesBatchParams{
+		Name:      name,
+		PostingID: postingID,
+		Phrases:   make([]string, 0, len(lines)),
+		Languages: make([]string, 0, len(lines)),
+	}
+	for _, l := range lines {
+		params.Phrases = append(params.Phrases, l.Text)
+		params.Languages = append(params.Languages, l.LanguageCode())
+	}
+
+	row, err := svc.q.CreatePhrasesBatch(ctx, params)
 	if err != nil {
 		return row, fmt.Errorf("create word batch: %w", err)
 	}
 
 	return row, nil
 }
+
+// ListWordFrequencies returns word frequencies from summary tables, or aggregates occurrences
+// if f filters batches, which summaries don't keep.
+func (svc *service) ListWordFrequencies(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordFrequenciesRow, error) {
+	if !f.batchFiltered() {
+		return svc.listWordStatsFrequencies(ctx, limit, offset, f)
+	}
+
+	return keptPage(limit, offset, svc.filter, func(limit, offset int32) ([]database.ListWordFrequenciesRow, error) {
+		rows, err := svc.q.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{
+			Limit:      limit,
+			Offset:     offset,
+			Language:   textParam(f.Language),
+			Seniority:  textParam(f.Seniority),
+			Location:   textParam(f.Location),
+			WorkMode:   textParam(f.WorkMode),
+			Tag:        textParam(f.Tag),
+			Collection: textParam(f.Collection),
+			Excluded:   svc.filter.StopWords(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list word frequencies: %w", err)
+		}
+
+		return rows, nil
+	}, func(row database.ListWordFrequenciesRow) string { return row.Value })
+}
+
+// ListWordRankings returns word rankings from summary tables, or aggregates occurrences
+// if f filters batches, which summaries don't keep.
+func (svc *service) ListWordRankings(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordRankingsRow, error) {
+	if !f.batchFiltered() {
+		return svc.listWordStatsRankings(ctx, limit, offset, f)
+	}
+
+	rows, err := keptPage(limit, offset, svc.filter, func(limit, offset int32) ([]database.ListWordRankingsRow, error) {
+		rows, err := svc.q.ListWordRankings(ctx, database.ListWordRankingsParams{
+			Limit:      limit,
+			Offset:     offset,
+			Language:   textParam(f.Language),
+			Seniority:  textParam(f.Seniority),
+			Location:   textParam(f.Location),
+			WorkMode:   textParam(f.WorkMode),
+			Tag:        textParam(f.Tag),
+			Collection: textParam(f.Collection),
+			Excluded:   svc.filter.StopWords(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list word rankings: %w", err)
+		}
+
+		return rows, nil
+	}, func(row database.ListWordRankingsRow) string { return row.Value })
+	if err != nil {
+		return nil, err
+	}
+
+	return rerank(rows, offset), nil
+}
+
+// fetchPageSize is the minimal number of rows fetched at once by keptPage.
+const fetchPageSize = 100
+
+// keptPage returns limit rows from offset among rows of fetch whose value filter keeps.
+// Noise words can't be dropped in SQL, so rows are fetched from the first one in pages
+// until the page is full, instead of dropping them from a page of SQL LIMIT and OFFSET.
+func keptPage[T any](limit, offset int32, filter *textproc.Filter, fetch func(limit, offset int32) ([]T, error), value func(T) string) ([]T, error) {
+	if filter == nil {
+		return fetch(limit, offset)
+	}
+	if limit <= 0 {
+		return []T{}, nil
+	}
+
+	kept := make([]T, 0, limit)
+	skip := offset
+	size := int32(min(max(2*(int64(limit)+int64(offset)), fetchPageSize), math.MaxInt32))
+	for from := int32(0); ; from += size {
+		rows, err := fetch(size, from)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if !filter.Keep(value(row)) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+
+				continue
+			}
+			kept = append(kept, row)
+			if int32(len(kept)) == limit {
+				return kept, nil
+			}
+		}
+		if int32(len(rows)) < size || from > math.MaxInt32-size {
+			return kept, nil
+		}
+	}
+}
+
+// rerank numbers rankings of rows of a page at offset consecutively, as rankings of rows
+// dropped by the filter were skipped.
+func rerank(rows []database.ListWordRankingsRow, offset int32) []database.ListWordRankingsRow {
+	for i := range rows {
+		rows[i].Ranking = int64(offset) + int64(i) + 1
+	}
+
+	return rows
+}
+
+// textParam converts optional string filter into a nullable query param.
+func textParam(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// Word statistics read from summary tables, which are kept current by database triggers
+// and can be rebuilt with RefreshWordStats query.
+
+func (svc *service) listWordStatsFrequencies(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordFrequenciesRow, error) {
+	return keptPage(limit, offset, svc.filter, func(limit, offset int32) ([]database.ListWordFrequenciesRow, error) {
+		stats, err := svc.q.ListWordStatsFrequencies(ctx, database.ListWordStatsFrequenciesParams{
+			Limit:    limit,
+			Offset:   offset,
+			Language: textParam(f.Language),
+			Excluded: svc.filter.StopWords(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list word stats frequencies: %w", err)
+		}
+
+		rows := make([]database.ListWordFrequenciesRow, 0, len(stats))
+		for _, row := range stats {
+			rows = append(rows, database.ListWordFrequenciesRow(row))
+		}
+
+		return rows, nil
+	}, func(row database.ListWordFrequenciesRow) string { return row.Value })
+}
+
+func (svc *service) listWordStatsRankings(ctx context.Context, limit, offset int32, f WordFilter) ([]database.ListWordRankingsRow, error) {
+	rows, err := keptPage(limit, offset, svc.filter, func(limit, offset int32) ([]database.ListWordRankingsRow, error) {
+		stats, err := svc.q.ListWordStatsRankings(ctx, database.ListWordStatsRankingsParams{
+			Limit:    limit,
+			Offset:   offset,
+			Language: textParam(f.Language),
+			Excluded: svc.filter.StopWords(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list word stats rankings: %w", err)
+		}
+
+		rows := make([]database.ListWordRankingsRow, 0, len(stats))
+		for _, row := range stats {
+			rows = append(rows, database.ListWordRankingsRow(row))
+		}
+
+		return rows, nil
+	}, func(row database.ListWordRankingsRow) string { return row.Value })
+	if err != nil {
+		return nil, err
+	}
+
+	return rerank(rows, offset), nil
+}
+
+// listWordTrend returns counts of words in buckets since time, from daily summaries if
+// f doesn't filter batch attributes.
+func (svc *service) listWordTrend(ctx context.Context, bucket string, since pgtype.Timestamptz, words []string, f WordFilter) ([]database.ListWordTrendRow, error) {
+	if f.batchFiltered() {
+		rows, err := svc.q.ListWordTrend(ctx, database.ListWordTrendParams{
+			Bucket:     bucket,
+			Since:      since,
+			Words:      words,
+			Language:   textParam(f.Language),
+			Seniority:  textParam(f.Seniority),
+			Location:   textParam(f.Location),
+			WorkMode:   textParam(f.WorkMode),
+			Tag:        textParam(f.Tag),
+			Collection: textParam(f.Collection),
+			Excluded:   svc.filter.StopWords(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list word trend: %w", err)
+		}
+
+		return rows, nil
+	}
+
+	stats, err := svc.q.ListWordStatsTrend(ctx, database.ListWordStatsTrendParams{
+		Bucket:   bucket,
+		Since:    since,
+		Words:    words,
+		Language: textParam(f.Language),
+		Excluded: svc.filter.StopWords(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list word stats trend: %w", err)
+	}
+
+	rows := make([]database.ListWordTrendRow, 0, len(stats))
+	for _, row := range stats {
+		rows = append(rows, database.ListWordTrendRow(row))
+	}
+
+	return rows, nil
+}
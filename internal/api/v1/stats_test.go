@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/stretchr/testify/require"
+)
+
+// statsRecorder records whether rankings were read from summary tables.
+type statsRecorder struct {
+	*memory.Store
+
+	stats, live int
+}
+
+func (q *statsRecorder) ListWordRankings(ctx context.Context, arg database.ListWordRankingsParams) ([]database.ListWordRankingsRow, error) {
+	q.live++
+
+	return q.Store.ListWordRankings(ctx, arg)
+}
+
+func (q *statsRecorder) ListWordStatsRankings(ctx context.Context, arg database.ListWordStatsRankingsParams) ([]database.ListWordStatsRankingsRow, error) {
+	q.stats++
+
+	return q.Store.ListWordStatsRankings(ctx, arg)
+}
+
+func TestListWordRankingsUsesStats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		filter WordFilter
+		stats  bool
+	}{
+		{
+			desc: "unfiltered",
+
+			stats: true,
+		},
+		{
+			desc: "language",
+
+			filter: WordFilter{Language: "en"},
+			stats:  true,
+		},
+		{
+			desc: "seniority",
+
+			filter: WordFilter{Seniority: "senior"},
+		},
+		{
+			desc: "work_mode",
+
+			filter: WordFilter{WorkMode: "remote"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			q := &statsRecorder{Store: newTestStore(t)}
+			svc := NewService(q, nil, nil, nil, testLogger())
+
+			rows, err := svc.ListWordRankings(context.Background(), 10, 0, tC.filter)
+			require.NoError(t, err)
+			require.NotEmpty(t, rows)
+
+			if tC.stats {
+				require.Equal(t, 1, q.stats)
+				require.Zero(t, q.live)
+			} else {
+				require.Equal(t, 1, q.live)
+				require.Zero(t, q.stats)
+			}
+		})
+	}
+}
+
+func TestKeptPage(t *testing.T) {
+	t.Parallel()
+
+	filter, err := textproc.NewFilter(textproc.FilterOptions{MinLength: 3, DropNumbers: true})
+	require.NoError(t, err)
+
+	// Every second word is noise
+	values := make([]string, 0, 500)
+	for i := range 250 {
+		values = append(values, fmt.Sprintf("word%d", i), strconv.Itoa(i))
+	}
+	var fetches int
+	fetch := func(limit, offset int32) ([]database.ListWordRankingsRow, error) {
+		fetches++
+		rows := make([]database.ListWordRankingsRow, 0, limit)
+		for i := offset; i < min(offset+limit, int32(len(values))); i++ {
+			rows = append(rows, database.ListWordRankingsRow{Value: values[i], Ranking: int64(i) + 1})
+		}
+
+		return rows, nil
+	}
+	value := func(row database.ListWordRankingsRow) string { return row.Value }
+
+	rows, err := keptPage(10, 20, filter, fetch, value)
+	require.NoError(t, err)
+	rows = rerank(rows, 20)
+	require.Len(t, rows, 10)
+	require.Equal(t, database.ListWordRankingsRow{Value: "word20", Ranking: 21}, rows[0])
+	require.Equal(t, database.ListWordRankingsRow{Value: "word29", Ranking: 30}, rows[9])
+	require.Equal(t, 1, fetches)
+
+	// Last page is short only if words run out
+	rows, err = keptPage(100, 200, filter, fetch, value)
+	require.NoError(t, err)
+	require.Len(t, rows, 50)
+	require.Equal(t, "word249", rows[49].Value)
+
+	rows, err = keptPage(10, 0, nil, fetch, value)
+	require.NoError(t, err)
+	require.Len(t, rows, 10)
+	require.Equal(t, "0", rows[1].Value)
+}
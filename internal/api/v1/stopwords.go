@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+var ErrNoStopWordsFile = errors.New("stop words file is not configured")
+
+func (svc *service) FilterWords(words []textproc.Word) []textproc.Word {
+	return svc.filter.Words(words)
+}
+
+func (svc *service) ListStopWords(_ context.Context) ([]string, error) {
+	return svc.filter.StopWords(), nil
+}
+
+func (svc *service) AddStopWords(_ context.Context, words ...string) ([]string, error) {
+	if svc.stopWords == nil {
+		return nil, ErrNoStopWordsFile
+	}
+	all, err := svc.stopWords.Add(words...)
+	if err != nil {
+		return nil, fmt.Errorf("add stop words: %w", err)
+	}
+	svc.filter.SetStopWords(all)
+
+	return all, nil
+}
+
+func (svc *service) RemoveStopWords(_ context.Context, words ...string) ([]string, error) {
+	if svc.stopWords == nil {
+		return nil, ErrNoStopWordsFile
+	}
+	all, err := svc.stopWords.Remove(words...)
+	if err != nil {
+		return nil, fmt.Errorf("remove stop words: %w", err)
+	}
+	svc.filter.SetStopWords(all)
+
+	return all, nil
+}
+
+type stopWordsResponse struct {
+	StopWords []string `json:"stop_words"`
+}
+
+func listStopWordsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		words, err := svc.ListStopWords(r.Context())
+		if err != nil {
+			respondJSON(w, "Failed to list stop words", err, http.StatusInternalServerError)
+
+			return
+		}
+		l.Info("Got stop words", "total", len(words))
+
+		if err := encode(w, r, http.StatusOK, stopWordsResponse{StopWords: words}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
+
+// updateStopWordsHandler adds stop words from request body on POST and removes them on DELETE.
+func updateStopWordsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type request struct {
+		Words []string `json:"words"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decode[request](r)
+		if err != nil {
+			respondJSON(w, "Failed to decode request", err, http.StatusBadRequest)
+
+			return
+		}
+		if len(req.Words) == 0 {
+			respondJSON(w, "No words in request", nil, http.StatusBadRequest)
+
+			return
+		}
+
+		var words []string
+		switch r.Method {
+		case http.MethodDelete:
+			words, err = svc.RemoveStopWords(r.Context(), req.Words...)
+		default:
+			words, err = svc.AddStopWords(r.Context(), req.Words...)
+		}
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrNoStopWordsFile) {
+				status = http.StatusConflict
+			}
+			respondJSON(w, "Failed to update stop words", err, status)
+
+			return
+		}
+		l.Info("Updated stop words", "method", r.Method, "total", len(words))
+
+		if err := encode(w, r, http.StatusOK, stopWordsResponse{StopWords: words}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopWordsHandlers(t *testing.T) {
+	t.Parallel()
+
+	l := testLogger()
+
+	filter, err := textproc.NewFilter(textproc.FilterOptions{})
+	require.NoError(t, err)
+	file := textproc.NewStopWordsFile(filepath.Join(t.TempDir(), "stopwords.txt"))
+	svc := NewService(newTestStore(t), filter, file, nil, l)
+
+	do := func(t *testing.T, handler http.HandlerFunc, method, body string) (int, []string) {
+		t.Helper()
+
+		req := httptest.NewRequestWithContext(context.Background(), method, "/", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		var resp stopWordsResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+
+		return rr.Code, resp.StopWords
+	}
+
+	code, words := do(t, updateStopWordsHandler(svc, l), http.MethodPost, `{"words":["Apply","cookies"]}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"apply", "cookies"}, words)
+	require.False(t, filter.Keep("apply"))
+
+	code, words = do(t, updateStopWordsHandler(svc, l), http.MethodDelete, `{"words":["apply"]}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"cookies"}, words)
+	require.True(t, filter.Keep("apply"))
+
+	code, words = do(t, listStopWordsHandler(svc, l), http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"cookies"}, words)
+
+	stored, err := file.Read()
+	require.NoError(t, err)
+	require.Equal(t, []string{"cookies"}, stored)
+}
+
+func TestUpdateStopWordsWithoutFile(t *testing.T) {
+	t.Parallel()
+
+	l := testLogger()
+	svc := NewService(newTestStore(t), nil, nil, nil, l)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/", strings.NewReader(`{"words":["apply"]}`))
+	rr := httptest.NewRecorder()
+	updateStopWordsHandler(svc, l)(rr, req)
+
+	require.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
+
+// testPostingText is raw text of posting 1 and text of its document.
+const testPostingText = "Senior Go Developer\nKubernetes, PostgreSQL\n20 000 - 25 000 PLN B2B"
+
+// englishWords returns values as English words.
+func englishWords(values ...string) []database.IngestedWord {
+	words := make([]database.IngestedWord, 0, len(values))
+	for _, v := range values {
+		words = append(words, database.IngestedWord{Value: v, Language: "en"})
+	}
+
+	return words
+}
+
+// repeatedWords returns English words of counts, each value repeated by its count.
+func repeatedWords(counts map[string]int) []database.IngestedWord {
+	var words []database.IngestedWord
+	for v, n := range counts {
+		for range n {
+			words = append(words, englishWords(v)...)
+		}
+	}
+
+	return words
+}
+
+func ingest(t *testing.T, q *memory.Store, in database.Ingestion) database.IngestResult {
+	t.Helper()
+
+	res, err := q.Ingest(context.Background(), in)
+	require.NoError(t, err)
+
+	return res
+}
+
+func createPhrases(t *testing.T, q *memory.Store, name string, phrases ...string) int64 {
+	t.Helper()
+
+	languages := make([]string, len(phrases))
+	for i := range languages {
+		languages[i] = "en"
+	}
+	row, err := q.CreatePhrasesBatch(context.Background(), database.CreatePhrasesBatchParams{
+		Name:      name,
+		Phrases:   phrases,
+		Languages: languages,
+	})
+	require.NoError(t, err)
+
+	return row.BatchID.Int64
+}
+
+// newTestStore returns a memory store with rows which handlers are tested against:
+//   - word batch 1 test_batch of a senior remote posting in Warszawa, with word go
+//     occurring twice and Polish word zdalnie,
+//   - phrase batch 1 test_phrases with phrase Remote work and phrase batch 2 taken,
+//   - posting 1 Go Developer at Acme with word batch 2 and phrase batch 3 posting_1,
+//     a senior B2B salary and a document recognized by tesseract without stored image,
+//   - posting 2 with deleted word batch 3, which was replaced by word batch 4,
+//   - deleted posting 3 and deleted phrase batch 4,
+//   - tag test with posting 1 and word batch 1, collection test with phrase batch 1,
+//     tag and collection taken.
+//
+// Rows with ID 99 don't exist.
+func newTestStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	ctx := context.Background()
+	q := memory.New()
+
+	ingest(t, q, database.Ingestion{
+		Name:      "test_batch",
+		Seniority: "senior",
+		Location:  "Warszawa",
+		WorkMode:  "remote",
+		Words:     append(englishWords("go", "go"), database.IngestedWord{Value: "zdalnie", Language: "pl"}),
+	})
+	createPhrases(t, q, "test_phrases", "Remote work")
+	createPhrases(t, q, "taken", "Office in Warsaw")
+
+	ingest(t, q, database.Ingestion{
+		Seniority: "senior",
+		Posting: &database.CreatePostingParams{
+			Title:     "Go Developer",
+			Company:   "Acme",
+			SourceUrl: "https://jobs.example.com/1",
+			RawText:   testPostingText,
+		},
+		Document: &database.IngestedDocument{Document: database.CreateOcrDocumentParams{
+			Text:      testPostingText,
+			Engine:    "tesseract",
+			Languages: []string{"eng"},
+			Settings:  []byte(`{"languages":["eng"],"variables":{},"trim":true}`),
+		}},
+		Words:   englishWords("golang", "kubernetes", "postgresql"),
+		Phrases: []database.IngestedPhrase{{Value: "Senior Go Developer", Language: "en"}},
+		Salaries: []database.CreateSalaryParams{{
+			MinAmount:  20000,
+			MaxAmount:  25000,
+			Currency:   "PLN",
+			Period:     textproc.PeriodMonth,
+			MonthlyMin: 20000,
+			MonthlyMax: 25000,
+			Contract:   "b2b",
+			Seniority:  "senior",
+			Raw:        "20 000 - 25 000 PLN B2B",
+		}},
+	})
+
+	res := ingest(t, q, database.Ingestion{
+		Posting: &database.CreatePostingParams{Title: "PHP Developer"},
+		Words:   englishWords("php"),
+	})
+	_, err := q.DeleteWordBatch(ctx, res.WordBatchID.Int64)
+	require.NoError(t, err)
+	ingest(t, q, database.Ingestion{
+		Name:      database.PostingBatchName(res.PostingID.Int64),
+		PostingID: res.PostingID,
+		Words:     englishWords("php", "laravel"),
+	})
+
+	posting, err := q.CreatePosting(ctx, database.CreatePostingParams{Title: "Java Developer"})
+	require.NoError(t, err)
+	_, err = q.DeletePosting(ctx, posting.ID)
+	require.NoError(t, err)
+	_, err = q.DeletePhraseBatch(ctx, createPhrases(t, q, "deleted_phrases", "Hybrid work"))
+	require.NoError(t, err)
+
+	_, err = q.AddTagMember(ctx, database.AddTagMemberParams{Name: "test", PostingID: pgtype.Int8{Int64: 1, Valid: true}})
+	require.NoError(t, err)
+	_, err = q.AddTagMember(ctx, database.AddTagMemberParams{Name: "test", WordBatchID: pgtype.Int8{Int64: 1, Valid: true}})
+	require.NoError(t, err)
+	_, err = q.CreateCollection(ctx, database.CreateCollectionParams{Name: "test", Description: "Test collection"})
+	require.NoError(t, err)
+	_, err = q.AddCollectionMember(ctx, database.AddCollectionMemberParams{Name: "test", PhraseBatchID: pgtype.Int8{Int64: 1, Valid: true}})
+	require.NoError(t, err)
+	_, err = q.CreateTag(ctx, "taken")
+	require.NoError(t, err)
+	_, err = q.CreateCollection(ctx, database.CreateCollectionParams{Name: "taken"})
+	require.NoError(t, err)
+
+	return q
+}
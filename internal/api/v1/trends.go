@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/kndrad/piccrack/pkg/trend"
+)
+
+// WordTrends returns series of words within window ending now. Aliases of skills are
+// merged under their canonical name. Empty words list returns series of every word.
+func (svc *service) WordTrends(ctx context.Context, words []string, w trend.Window, f WordFilter) ([]trend.Series, error) {
+	names := make([]string, 0, len(words))
+	for _, word := range words {
+		names = append(names, textproc.SkillAliases(word)...)
+	}
+
+	now := time.Now()
+	since := w.Since(now)
+	rows, err := svc.listWordTrend(ctx, string(w.Bucket), pgtype.Timestamptz{Time: since, Valid: true}, names, f)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]trend.Count, 0, len(rows))
+	for _, row := range rows {
+		if !svc.filter.Keep(row.Value) {
+			continue
+		}
+		counts = append(counts, trend.Count{
+			Bucket:         row.Bucket.Time,
+			Word:           row.Value,
+			Total:          row.Total,
+			Postings:       row.Postings,
+			BucketPostings: row.BucketPostings,
+		})
+	}
+
+	return trend.Build(counts, w.Bucket, since, now, textproc.CanonicalSkill), nil
+}
+
+// windowValue returns trend window from bucket, recent, baseline and threshold query values.
+// Missing values default to trend.DefaultWindow.
+func windowValue(values url.Values) (trend.Window, error) {
+	w := trend.DefaultWindow()
+
+	bucket, err := trend.ParseBucket(values.Get("bucket"))
+	if err != nil {
+		return w, fmt.Errorf("parse bucket: %w", err)
+	}
+	w.Bucket = bucket
+
+	for key, n := range map[string]*int{"recent": &w.Recent, "baseline": &w.Baseline} {
+		v := values.Get(key)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return w, fmt.Errorf("parse %s: %w", key, err)
+		}
+		if parsed == 0 {
+			return w, fmt.Errorf("%s must be positive", key)
+		}
+		*n = int(parsed)
+	}
+
+	if v := values.Get("threshold"); v != "" {
+		threshold, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return w, fmt.Errorf("parse threshold: %w", err)
+		}
+		if threshold < 0 {
+			return w, errors.New("threshold cannot be negative")
+		}
+		w.Threshold = threshold
+	}
+
+	return w, nil
+}
+
+// listValue returns items of repeated or comma separated key query values.
+func listValue(values url.Values, key string) []string {
+	items := make([]string, 0)
+	for _, v := range values[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+// wordTrendsHandler serves time series of words with their trend. Without word query
+// values every word is classified and limit words with the biggest change are returned.
+// Direction query value (rising, falling, stable) keeps only words trending that way.
+func wordTrendsHandler(svc Service, l *slog.Logger) http.HandlerFunc {
+	type response struct {
+		Bucket trend.Bucket   `json:"bucket"`
+		Trends []trend.Trend  `json:"trends"`
+		Series []trend.Series `json:"series"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		limit, err := limitValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get limit query value", err, http.StatusBadRequest)
+
+			return
+		}
+		window, err := windowValue(query)
+		if err != nil {
+			respondJSON(w, "Failed to get trend window query values", err, http.StatusBadRequest)
+
+			return
+		}
+		direction := trend.Direction(query.Get("direction"))
+		switch direction {
+		case "", trend.Rising, trend.Falling, trend.Stable:
+		default:
+			respondJSON(w, fmt.Sprintf("Unknown direction query value %q, use rising, falling or stable", direction), nil, http.StatusBadRequest)
+
+			return
+		}
+
+		series, err := svc.WordTrends(r.Context(), listValue(query, "word"), window, wordFilterValue(query))
+		if err != nil {
+			respondJSON(w, "Failed to get word trends", err, http.StatusInternalServerError)
+
+			return
+		}
+		trends := window.DetectAll(series)
+		if direction != "" {
+			trends = slices.DeleteFunc(trends, func(t trend.Trend) bool {
+				return t.Direction != direction
+			})
+		}
+		if len(trends) > int(limit) {
+			trends = trends[:limit]
+		}
+		series = slices.DeleteFunc(series, func(s trend.Series) bool {
+			return !slices.ContainsFunc(trends, func(t trend.Trend) bool {
+				return t.Word == s.Word
+			})
+		})
+		l.Info("Got word trends", "total", len(trends), "bucket", window.Bucket)
+
+		if err := encode(w, r, http.StatusOK, response{Bucket: window.Bucket, Trends: trends, Series: series}); err != nil {
+			respondJSON(w, "Failed to serve response", err, http.StatusInternalServerError)
+
+			return
+		}
+	}
+}
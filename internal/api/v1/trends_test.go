@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/pkg/trend"
+	"github.com/stretchr/testify/require"
+)
+
+// trendQueries adds counts of the first bucket of a trend window to counts of a memory
+// store, which has counts of the current bucket only. Go and php were asked for before.
+type trendQueries struct {
+	*memory.Store
+}
+
+func (q trendQueries) ListWordStatsTrend(ctx context.Context, arg database.ListWordStatsTrendParams) ([]database.ListWordStatsTrendRow, error) {
+	rows, err := q.Store.ListWordStatsTrend(ctx, arg)
+	past := []database.ListWordStatsTrendRow{
+		{Bucket: arg.Since, Value: "go", Total: 1, Postings: 1, BucketPostings: 4},
+		{Bucket: arg.Since, Value: "php", Total: 2, Postings: 2, BucketPostings: 4},
+	}
+	past = slices.DeleteFunc(past, func(row database.ListWordStatsTrendRow) bool {
+		return len(arg.Words) > 0 && !slices.Contains(arg.Words, row.Value)
+	})
+
+	return append(past, rows...), err
+}
+
+// newTrendStore returns trendQueries of a memory store with two batches asking for go,
+// one of them under its alias golang.
+func newTrendStore(t *testing.T) trendQueries {
+	t.Helper()
+
+	q := memory.New()
+	ingest(t, q, database.Ingestion{Name: "backend", Words: englishWords("go", "golang")})
+	ingest(t, q, database.Ingestion{Name: "platform", Words: englishWords("go")})
+
+	return trendQueries{Store: q}
+}
+
+func TestWordTrendsHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		query      string
+		statusCode int
+		words      []string
+	}{
+		{
+			desc: "all_words",
+
+			query:      "?bucket=month&recent=1&baseline=2",
+			statusCode: http.StatusOK,
+			words:      []string{"go", "php"},
+		},
+		{
+			desc: "skill_aliases_are_merged",
+
+			query:      "?word=golang&bucket=week",
+			statusCode: http.StatusOK,
+			words:      []string{"go"},
+		},
+		{
+			desc: "falling_words",
+
+			query:      "?direction=falling&bucket=day&recent=1&baseline=6",
+			statusCode: http.StatusOK,
+			words:      []string{"php"},
+		},
+		{
+			desc: "unknown_bucket",
+
+			query:      "?bucket=year",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "zero_recent",
+
+			query:      "?recent=0",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc: "unknown_direction",
+
+			query:      "?direction=up",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewService(newTrendStore(t), nil, nil, nil, testLogger())
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/"+tC.query, nil)
+			rr := httptest.NewRecorder()
+			wordTrendsHandler(svc, testLogger())(rr, req)
+
+			require.Equal(t, tC.statusCode, rr.Code)
+			if tC.statusCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Trends []trend.Trend  `json:"trends"`
+				Series []trend.Series `json:"series"`
+			}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+
+			words := make([]string, 0, len(body.Trends))
+			for _, tr := range body.Trends {
+				words = append(words, tr.Word)
+			}
+			require.Equal(t, tC.words, words)
+			require.Len(t, body.Series, len(tC.words))
+		})
+	}
+}
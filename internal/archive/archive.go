@@ -0,0 +1,116 @@
+// Package archive exports the dataset of the database to a zip archive and imports it
+// back, so that a corpus can be moved between machines or backed up without pg_dump.
+//
+// An archive holds a JSON lines file of each table and a manifest with the archive
+// format, the schema version of the exported database and checksums of the files.
+// Content of images may be added under images/. Rows reference each other by content
+// hashes of their fields which don't change after insert, instead of IDs, so importing
+// an archive again adds nothing.
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// FormatVersion is a version of the archive layout written by this package. Archives of
+// newer formats aren't imported.
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	imagesDir    = "images/"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported archive format")
+	ErrCorrupt           = errors.New("corrupt archive")
+	ErrSchemaMismatch    = errors.New("database schema isn't migrated to the latest version")
+)
+
+// Manifest describes an archive. It's written as the last file of an archive, after
+// all tables were streamed.
+type Manifest struct {
+	Format int `json:"format"`
+	// SchemaVersion is a version of the last migration of the exported database.
+	SchemaVersion uint      `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Tables        []File    `json:"tables"`
+	// Images is the number of image files in the archive.
+	Images int64 `json:"images"`
+}
+
+// File is a JSON lines file of a table.
+type File struct {
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// file returns file of table named name, false if the archive has none.
+func (m Manifest) file(table string) (File, bool) {
+	for _, f := range m.Tables {
+		if f.Table == table {
+			return f, true
+		}
+	}
+
+	return File{}, false
+}
+
+// Transactor runs functions in transactions, e.g. *database.Repository.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(tx pgx.Tx) error) error
+}
+
+// checkSchema fails if schema of database of tx isn't at the latest embedded migration,
+// as tables are read and written with its columns.
+func checkSchema(ctx context.Context, tx pgx.Tx) (uint, error) {
+	latest, err := database.LatestVersion()
+	if err != nil {
+		return 0, err
+	}
+	v, dirty, err := database.SchemaVersion(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if v > latest {
+		return 0, fmt.Errorf("%w: version %d, latest supported %d", database.ErrSchemaTooNew, v, latest)
+	}
+	if v < latest || dirty {
+		return 0, fmt.Errorf("%w: version %d (dirty %t), latest %d, run piccrack db migrate up", ErrSchemaMismatch, v, dirty, latest)
+	}
+
+	return v, nil
+}
+
+// digest returns a hex encoded SHA-256 hash of JSON encoding of parts.
+func digest(parts ...any) string {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		// Parts are strings, numbers and timestamps
+		panic(fmt.Sprintf("archive: marshal digest parts: %v", err))
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// stamp returns t in UTC, so that hashes don't depend on time zone of a connection.
+func stamp(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
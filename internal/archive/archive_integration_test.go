@@ -0,0 +1,105 @@
+//go:build integration
+
+package archive_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/archive"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// TestRoundTrip exports a database, imports the archive into an empty one twice
+// and exports it again.
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:17",
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpassword"),
+		postgres.WithDatabase("piccrack"),
+		postgres.BasicWaitStrategies(),
+		postgres.WithSQLDriver("pgx"),
+	)
+	require.NoError(t, err)
+	defer container.Terminate(ctx)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	conn, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	open := func(name string) *database.Repository {
+		_, err := conn.Exec(ctx, "CREATE DATABASE "+name)
+		require.NoError(t, err)
+
+		u, err := url.Parse(dsn)
+		require.NoError(t, err)
+		u.Path = "/" + name
+
+		m, err := database.NewMigrator(u.String())
+		require.NoError(t, err)
+		require.NoError(t, m.Up())
+		require.NoError(t, m.Close())
+
+		pool, err := database.Pool(ctx, config.DatabaseConfig{DSN: u.String()})
+		require.NoError(t, err)
+		t.Cleanup(pool.Close)
+
+		return database.NewRepository(pool)
+	}
+
+	src := open("source")
+	_, err = src.Ingest(ctx, database.Ingestion{
+		Name:     "golang_0",
+		Words:    []database.IngestedWord{{Value: "go"}, {Value: "docker"}, {Value: "go"}},
+		Phrases:  []database.IngestedPhrase{{Value: "Senior Go Developer"}},
+		Salaries: []database.CreateSalaryParams{{Currency: "PLN", Period: "month", Contract: "b2b"}},
+	})
+	require.NoError(t, err)
+
+	var first bytes.Buffer
+	m, err := archive.Export(ctx, src, &first, archive.ExportOptions{})
+	require.NoError(t, err)
+
+	r, err := zip.NewReader(bytes.NewReader(first.Bytes()), int64(first.Len()))
+	require.NoError(t, err)
+	read, err := archive.ReadManifest(r)
+	require.NoError(t, err)
+	require.Equal(t, m.SchemaVersion, read.SchemaVersion)
+	require.Len(t, read.Tables, len(m.Tables))
+
+	dst := open("target")
+	_, results, err := archive.Import(ctx, dst, r, archive.ImportOptions{})
+	require.NoError(t, err)
+	for _, res := range results {
+		require.Equal(t, res.Rows, res.Inserted, res.Table)
+		require.Zero(t, res.Skipped, res.Table)
+	}
+
+	// Rows of the archive are in the database already
+	_, results, err = archive.Import(ctx, dst, r, archive.ImportOptions{})
+	require.NoError(t, err)
+	for _, res := range results {
+		require.Zero(t, res.Inserted, res.Table)
+		require.Equal(t, res.Rows, res.Skipped, res.Table)
+	}
+
+	var second bytes.Buffer
+	m2, err := archive.Export(ctx, dst, &second, archive.ExportOptions{})
+	require.NoError(t, err)
+	require.Len(t, m2.Tables, len(m.Tables))
+	for i, f := range m.Tables {
+		require.Equal(t, f.Rows, m2.Tables[i].Rows, f.Table)
+	}
+}
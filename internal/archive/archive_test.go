@@ -0,0 +1,116 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+// zipOf returns a reader of an archive with files of names mapped to contents.
+func zipOf(t *testing.T, files map[string]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+
+	latest, err := database.LatestVersion()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   error
+	}{
+		{
+			name:  "no manifest",
+			files: map[string]string{"postings.jsonl": ""},
+			err:   ErrCorrupt,
+		},
+		{
+			name:  "malformed manifest",
+			files: map[string]string{manifestName: "{"},
+			err:   ErrCorrupt,
+		},
+		{
+			name:  "newer format",
+			files: map[string]string{manifestName: `{"format":2,"schema_version":1}`},
+			err:   ErrUnsupportedFormat,
+		},
+		{
+			name:  "newer schema",
+			files: map[string]string{manifestName: `{"format":1,"schema_version":99999999999999}`},
+			err:   database.ErrSchemaTooNew,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ReadManifest(zipOf(t, tt.files))
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("older schema", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := ReadManifest(zipOf(t, map[string]string{
+			manifestName: `{"format":1,"schema_version":1,"tables":[{"table":"postings","name":"postings.jsonl","rows":2}]}`,
+		}))
+		require.NoError(t, err)
+		require.Less(t, m.SchemaVersion, latest)
+
+		f, ok := m.file("postings")
+		require.True(t, ok)
+		require.Equal(t, int64(2), f.Rows)
+		_, ok = m.file("salaries")
+		require.False(t, ok)
+	})
+}
+
+func TestDigest(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, digest("a", int64(1)), digest("a", int64(1)))
+	require.NotEqual(t, digest("ab", "c"), digest("a", "bc"))
+
+	at := time.Date(2024, 11, 17, 9, 30, 0, 0, time.UTC)
+	warsaw := time.FixedZone("CET", 3600)
+	require.Equal(t,
+		stamp(pgtype.Timestamptz{Time: at, Valid: true}),
+		stamp(pgtype.Timestamptz{Time: at.In(warsaw), Valid: true}),
+	)
+	require.Empty(t, stamp(pgtype.Timestamptz{}))
+}
+
+func TestTableNames(t *testing.T) {
+	t.Parallel()
+
+	// Each table is written to a file of its own
+	seen := make(map[string]bool, len(tables))
+	for _, tb := range tables {
+		require.False(t, seen[tb.tableName()], tb.tableName())
+		seen[tb.tableName()] = true
+	}
+}
@@ -0,0 +1,145 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+)
+
+// ExportOptions select contents of an archive.
+type ExportOptions struct {
+	// Images adds stored content of images to the archive.
+	Images bool
+}
+
+// Export writes an archive of the database of db to w and returns its manifest. Tables
+// are read in a single read only transaction, so the archive is a consistent snapshot,
+// and written to w as they're read. Archive written until a failure is incomplete.
+func Export(ctx context.Context, db Transactor, w io.Writer, opts ExportOptions) (Manifest, error) {
+	m := Manifest{
+		Format:    FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Tables:    make([]File, 0, len(tables)),
+	}
+	zw := zip.NewWriter(w)
+
+	err := db.InTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"); err != nil {
+			return fmt.Errorf("set transaction: %w", err)
+		}
+		v, err := checkSchema(ctx, tx)
+		if err != nil {
+			return err
+		}
+		m.SchemaVersion = v
+
+		st := newState()
+		for _, t := range tables {
+			f, err := writeTable(ctx, tx, st, zw, t)
+			if err != nil {
+				return err
+			}
+			m.Tables = append(m.Tables, f)
+		}
+		if opts.Images {
+			if m.Images, err = writeImages(ctx, tx, zw); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return m, err
+	}
+
+	fw, err := zw.Create(manifestName)
+	if err != nil {
+		return m, fmt.Errorf("create manifest: %w", err)
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return m, fmt.Errorf("write manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return m, fmt.Errorf("close archive: %w", err)
+	}
+
+	return m, nil
+}
+
+// writeTable writes records of t to a new file of zw.
+func writeTable(ctx context.Context, tx pgx.Tx, st *state, zw *zip.Writer, t tabler) (File, error) {
+	f := File{Table: t.tableName(), Name: t.tableName() + ".jsonl"}
+
+	fw, err := zw.Create(f.Name)
+	if err != nil {
+		return f, fmt.Errorf("create %s: %w", f.Name, err)
+	}
+	h := sha256.New()
+	if f.Rows, err = t.write(ctx, tx, st, io.MultiWriter(fw, h)); err != nil {
+		return f, err
+	}
+	f.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	return f, nil
+}
+
+// writeImages writes stored content of images to zw and returns number of written files.
+// Images without content, or whose file is gone, are skipped.
+func writeImages(ctx context.Context, tx pgx.Tx, zw *zip.Writer) (int64, error) {
+	var (
+		hash, format string
+		content      []byte
+		path         pgtype.Text
+		n            int64
+	)
+	written := make(map[string]bool)
+
+	err := query(ctx, tx, "SELECT hash, format, content, path FROM images ORDER BY id",
+		[]any{&hash, &format, &content, &path},
+		func() error {
+			name := imageFile(hash, format)
+			if written[name] {
+				return nil
+			}
+			data, err := imgstore.Load(imgstore.Stored{Content: content, Path: path.String})
+			if errors.Is(err, imgstore.ErrNotStored) || errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			// Image formats are compressed already
+			fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+			if err != nil {
+				return fmt.Errorf("create %s: %w", name, err)
+			}
+			if _, err := fw.Write(data); err != nil {
+				return fmt.Errorf("write %s: %w", name, err)
+			}
+			written[name] = true
+			n++
+
+			return nil
+		},
+	)
+	if err != nil {
+		return n, fmt.Errorf("read images: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,142 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+)
+
+// ImportOptions of an import.
+type ImportOptions struct {
+	// Images keeps content of imported images. Nil store keeps only their metadata.
+	Images *imgstore.Store
+}
+
+// ImportResult counts rows of a table of an archive.
+type ImportResult struct {
+	Table    string `json:"table"`
+	Rows     int64  `json:"rows"`
+	Inserted int64  `json:"inserted"`
+	// Skipped rows were in the database already.
+	Skipped int64 `json:"skipped"`
+}
+
+// ReadManifest returns manifest of archive r. It fails with ErrUnsupportedFormat if r
+// was written in a newer format, and with database.ErrSchemaTooNew if it was exported
+// from a database migrated by a newer binary.
+func ReadManifest(r *zip.Reader) (Manifest, error) {
+	var m Manifest
+
+	f, err := r.Open(manifestName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, fmt.Errorf("%w: no %s", ErrCorrupt, manifestName)
+	}
+	if err != nil {
+		return m, fmt.Errorf("open manifest: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return m, fmt.Errorf("%w: decode manifest: %w", ErrCorrupt, err)
+	}
+	if m.Format < 1 || m.Format > FormatVersion {
+		return m, fmt.Errorf("%w: %d, supported %d", ErrUnsupportedFormat, m.Format, FormatVersion)
+	}
+	latest, err := database.LatestVersion()
+	if err != nil {
+		return m, err
+	}
+	if m.SchemaVersion > latest {
+		return m, fmt.Errorf("%w: archive of schema version %d, latest supported %d", database.ErrSchemaTooNew, m.SchemaVersion, latest)
+	}
+
+	return m, nil
+}
+
+// Import inserts rows of archive r which aren't in the database of db yet, with a single
+// transaction rolled back if any of them fails. Rows are matched by their hashes, so
+// importing an archive again inserts nothing, while rows changed since an earlier import
+// keep their values in the database.
+//
+// Archives exported from older schema versions are imported too. Their missing tables
+// are skipped and missing fields take defaults of the columns.
+func Import(ctx context.Context, db Transactor, r *zip.Reader, opts ImportOptions) (Manifest, []ImportResult, error) {
+	m, err := ReadManifest(r)
+	if err != nil {
+		return m, nil, err
+	}
+
+	st := newState()
+	st.images = opts.Images
+	st.files = make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		st.files[f.Name] = f
+	}
+
+	results := make([]ImportResult, 0, len(tables))
+	err = db.InTx(ctx, func(tx pgx.Tx) error {
+		if _, err := checkSchema(ctx, tx); err != nil {
+			return err
+		}
+
+		for _, t := range tables {
+			if err := t.load(ctx, tx, st); err != nil {
+				return err
+			}
+			f, ok := m.file(t.tableName())
+			if !ok {
+				continue
+			}
+			res, err := restoreTable(ctx, tx, st, t, f)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return m, nil, err
+	}
+
+	return m, results, nil
+}
+
+// restoreTable inserts records of file f into t and checks them against the manifest.
+func restoreTable(ctx context.Context, tx pgx.Tx, st *state, t tabler, f File) (ImportResult, error) {
+	res := ImportResult{Table: t.tableName(), Rows: f.Rows}
+
+	zf, ok := st.files[f.Name]
+	if !ok {
+		return res, fmt.Errorf("%w: no %s", ErrCorrupt, f.Name)
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return res, fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if res.Inserted, res.Skipped, err = t.restore(ctx, tx, st, io.TeeReader(rc, h)); err != nil {
+		return res, err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != f.SHA256 {
+		return res, fmt.Errorf("%w: checksum of %s is %s, manifest has %s", ErrCorrupt, f.Name, sum, f.SHA256)
+	}
+	if n := res.Inserted + res.Skipped; n != f.Rows {
+		return res, fmt.Errorf("%w: %s has %d rows, manifest has %d", ErrCorrupt, f.Name, n, f.Rows)
+	}
+
+	return res, nil
+}
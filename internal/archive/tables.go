@@ -0,0 +1,899 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/pkg/imgsniff"
+	"github.com/kndrad/piccrack/pkg/imgstore"
+)
+
+// index maps hashes of rows of a table to their IDs and back.
+type index struct {
+	ids    map[string]int64
+	hashes map[int64]string
+}
+
+// state of an export or an import.
+type state struct {
+	indexes map[string]*index
+	// Files of the imported archive by their names.
+	files map[string]*zip.File
+	// Keeps content of imported images.
+	images *imgstore.Store
+}
+
+func newState() *state {
+	return &state{indexes: make(map[string]*index)}
+}
+
+func (st *state) index(table string) *index {
+	x, ok := st.indexes[table]
+	if !ok {
+		x = &index{ids: make(map[string]int64), hashes: make(map[int64]string)}
+		st.indexes[table] = x
+	}
+
+	return x
+}
+
+// add indexes row id of table. Rows of equal hashes map to the first of them.
+func (st *state) add(table string, id int64, hash string) {
+	x := st.index(table)
+	if _, ok := x.ids[hash]; !ok {
+		x.ids[hash] = id
+	}
+	x.hashes[id] = hash
+}
+
+// hash returns hash of row id of table, empty if id is null.
+func (st *state) hash(table string, id pgtype.Int8) (string, error) {
+	if !id.Valid {
+		return "", nil
+	}
+	hash, ok := st.index(table).hashes[id.Int64]
+	if !ok {
+		return "", fmt.Errorf("%s row %d isn't indexed", table, id.Int64)
+	}
+
+	return hash, nil
+}
+
+// id returns ID of row of table with hash, null if hash is empty.
+func (st *state) id(table, hash string) (pgtype.Int8, error) {
+	if hash == "" {
+		return pgtype.Int8{}, nil
+	}
+	id, ok := st.index(table).ids[hash]
+	if !ok {
+		return pgtype.Int8{}, fmt.Errorf("%w: missing %s row %s", ErrCorrupt, table, hash)
+	}
+
+	return pgtype.Int8{Int64: id, Valid: true}, nil
+}
+
+// record is a row of a table, identified by its hash.
+type record interface {
+	key() string
+}
+
+// table reads rows of a database table as records and inserts records into it.
+type table[T record] struct {
+	name string
+	// Rows of parents are referenced by other tables, so they're indexed on export.
+	parent bool
+	// read calls fn with IDs and records of rows, deleted rows included, in order of IDs.
+	read func(ctx context.Context, tx pgx.Tx, st *state, fn func(id int64, rec T) error) error
+	// insert inserts rec and returns ID of the new row.
+	insert func(ctx context.Context, tx pgx.Tx, st *state, rec T) (int64, error)
+}
+
+// tabler is a table of any record.
+type tabler interface {
+	tableName() string
+	// write writes records of rows as JSON lines to w and returns their number.
+	write(ctx context.Context, tx pgx.Tx, st *state, w io.Writer) (int64, error)
+	// load indexes all rows of the table.
+	load(ctx context.Context, tx pgx.Tx, st *state) error
+	// restore inserts records of JSON lines of r which aren't indexed yet.
+	restore(ctx context.Context, tx pgx.Tx, st *state, r io.Reader) (inserted, skipped int64, err error)
+}
+
+func (t *table[T]) tableName() string {
+	return t.name
+}
+
+func (t *table[T]) write(ctx context.Context, tx pgx.Tx, st *state, w io.Writer) (int64, error) {
+	enc := json.NewEncoder(w)
+
+	var n int64
+	err := t.read(ctx, tx, st, func(id int64, rec T) error {
+		if t.parent {
+			st.add(t.name, id, rec.key())
+		}
+		n++
+
+		return enc.Encode(rec)
+	})
+	if err != nil {
+		return n, fmt.Errorf("read %s: %w", t.name, err)
+	}
+
+	return n, nil
+}
+
+func (t *table[T]) load(ctx context.Context, tx pgx.Tx, st *state) error {
+	err := t.read(ctx, tx, st, func(id int64, rec T) error {
+		st.add(t.name, id, rec.key())
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read %s: %w", t.name, err)
+	}
+
+	return nil
+}
+
+func (t *table[T]) restore(ctx context.Context, tx pgx.Tx, st *state, r io.Reader) (int64, int64, error) {
+	dec := json.NewDecoder(r)
+
+	var inserted, skipped int64
+	for {
+		var rec T
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return inserted, skipped, nil
+		}
+		if err != nil {
+			return inserted, skipped, fmt.Errorf("%w: decode %s: %w", ErrCorrupt, t.name, err)
+		}
+		if rec.key() == "" {
+			return inserted, skipped, fmt.Errorf("%w: %s row without hash", ErrCorrupt, t.name)
+		}
+
+		if _, ok := st.index(t.name).ids[rec.key()]; ok {
+			skipped++
+
+			continue
+		}
+		id, err := t.insert(ctx, tx, st, rec)
+		if err != nil {
+			return inserted, skipped, fmt.Errorf("insert %s row %s: %w", t.name, rec.key(), err)
+		}
+		st.add(t.name, id, rec.key())
+		inserted++
+	}
+}
+
+// tables in order of their references.
+var tables = []tabler{
+	images,
+	postings,
+	documents,
+	wordBatches,
+	vocabulary,
+	occurrences,
+	salaries,
+	phraseBatches,
+	phrases,
+	groups("tags"),
+	members("tag_members", "tags", "tag_id"),
+	groups("collections"),
+	members("collection_members", "collections", "collection_id"),
+}
+
+// query calls fn after each row of sql is scanned into dest.
+func query(ctx context.Context, tx pgx.Tx, sql string, dest []any, fn func() error) error {
+	rows, err := tx.Query(ctx, sql)
+	if err != nil {
+		return err
+	}
+	_, err = pgx.ForEachRow(rows, dest, fn)
+
+	return err
+}
+
+// insert runs sql returning ID of an inserted row.
+func insert(ctx context.Context, tx pgx.Tx, sql string, args ...any) (int64, error) {
+	var id int64
+	err := tx.QueryRow(ctx, sql, args...).Scan(&id)
+
+	return id, err
+}
+
+// Images are identified by hashes of their content.
+type image struct {
+	Hash      string             `json:"hash"`
+	Width     int32              `json:"width"`
+	Height    int32              `json:"height"`
+	Format    string             `json:"format"`
+	SizeBytes int64              `json:"size_bytes"`
+	Filename  string             `json:"filename"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r image) key() string {
+	return r.Hash
+}
+
+// imageFile returns name of file of content of an image in an archive.
+func imageFile(hash, format string) string {
+	if format == "" {
+		return imagesDir + hash
+	}
+
+	return imagesDir + hash + "." + format
+}
+
+var images = &table[image]{
+	name:   "images",
+	parent: true,
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, image) error) error {
+		var (
+			id  int64
+			rec image
+		)
+
+		return query(ctx, tx, `SELECT id, hash, width, height, format, size_bytes, filename, created_at, deleted_at
+FROM images ORDER BY id`,
+			[]any{&id, &rec.Hash, &rec.Width, &rec.Height, &rec.Format, &rec.SizeBytes, &rec.Filename, &rec.CreatedAt, &rec.DeletedAt},
+			func() error { return fn(id, rec) },
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec image) (int64, error) {
+		var stored imgstore.Stored
+		if f, ok := st.files[imageFile(rec.Hash, rec.Format)]; ok {
+			content, err := readFile(f)
+			if err != nil {
+				return 0, err
+			}
+			if imgsniff.Hash(content) != rec.Hash {
+				return 0, fmt.Errorf("%w: content of image %s doesn't match its hash", ErrCorrupt, rec.Hash)
+			}
+			if stored, err = st.images.Put(rec.Hash, rec.Format, content); err != nil {
+				return 0, fmt.Errorf("store image: %w", err)
+			}
+		}
+
+		return insert(ctx, tx, `INSERT INTO images (
+    hash, width, height, format, size_bytes, filename, content, path, created_at, deleted_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), COALESCE($9, CURRENT_TIMESTAMP), $10)
+RETURNING id`,
+			rec.Hash, rec.Width, rec.Height, rec.Format, rec.SizeBytes, rec.Filename,
+			stored.Content, stored.Path, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Postings are identified by their image, text and time of creation, as other fields
+// may be edited.
+type posting struct {
+	Hash       string             `json:"hash"`
+	Title      string             `json:"title"`
+	Company    string             `json:"company"`
+	SourceURL  string             `json:"source_url"`
+	CapturedAt pgtype.Timestamptz `json:"captured_at"`
+	ImageHash  pgtype.Text        `json:"image_hash"`
+	RawText    string             `json:"raw_text"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r posting) key() string {
+	return r.Hash
+}
+
+var postings = &table[posting]{
+	name:   "postings",
+	parent: true,
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, posting) error) error {
+		var (
+			id  int64
+			rec posting
+		)
+
+		return query(ctx, tx, `SELECT
+    id, title, company, source_url, captured_at, image_hash, raw_text, created_at, updated_at, deleted_at
+FROM postings ORDER BY id`,
+			[]any{&id, &rec.Title, &rec.Company, &rec.SourceURL, &rec.CapturedAt, &rec.ImageHash, &rec.RawText, &rec.CreatedAt, &rec.UpdatedAt, &rec.DeletedAt},
+			func() error {
+				rec.Hash = digest("postings", rec.ImageHash.String, rec.RawText, stamp(rec.CreatedAt))
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec posting) (int64, error) {
+		return insert(ctx, tx, `INSERT INTO postings (
+    title, company, source_url, captured_at, image_hash, raw_text, created_at, updated_at, deleted_at
+)
+VALUES (
+    $1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP), $5, $6,
+    COALESCE($7, CURRENT_TIMESTAMP), COALESCE($8, $7, CURRENT_TIMESTAMP), $9
+)
+RETURNING id`,
+			rec.Title, rec.Company, rec.SourceURL, rec.CapturedAt, rec.ImageHash, rec.RawText,
+			rec.CreatedAt, rec.UpdatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+type document struct {
+	Hash          string             `json:"hash"`
+	Posting       string             `json:"posting,omitempty"`
+	Image         string             `json:"image,omitempty"`
+	Text          string             `json:"text"`
+	Engine        string             `json:"engine"`
+	EngineVersion string             `json:"engine_version"`
+	Languages     []string           `json:"languages"`
+	Settings      json.RawMessage    `json:"settings"`
+	Confidence    pgtype.Float8      `json:"confidence"`
+	DurationMs    int64              `json:"duration_ms"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r document) key() string {
+	return r.Hash
+}
+
+var documents = &table[document]{
+	name: "ocr_documents",
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, document) error) error {
+		var (
+			id               int64
+			postingID, imgID pgtype.Int8
+			rec              document
+		)
+
+		return query(ctx, tx, `SELECT
+    id, posting_id, image_id, text, engine, engine_version, languages, settings, confidence,
+    duration_ms, created_at, deleted_at
+FROM ocr_documents ORDER BY id`,
+			[]any{
+				&id, &postingID, &imgID, &rec.Text, &rec.Engine, &rec.EngineVersion, &rec.Languages, &rec.Settings,
+				&rec.Confidence, &rec.DurationMs, &rec.CreatedAt, &rec.DeletedAt,
+			},
+			func() error {
+				var err error
+				if rec.Posting, err = st.hash("postings", postingID); err != nil {
+					return err
+				}
+				if rec.Image, err = st.hash("images", imgID); err != nil {
+					return err
+				}
+				rec.Hash = digest("ocr_documents", rec.Posting, rec.Image, rec.Text, rec.Engine, rec.EngineVersion, stamp(rec.CreatedAt))
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec document) (int64, error) {
+		postingID, err := st.id("postings", rec.Posting)
+		if err != nil {
+			return 0, err
+		}
+		imgID, err := st.id("images", rec.Image)
+		if err != nil {
+			return 0, err
+		}
+		if rec.Languages == nil {
+			rec.Languages = []string{}
+		}
+		if len(rec.Settings) == 0 {
+			rec.Settings = json.RawMessage("{}")
+		}
+
+		return insert(ctx, tx, `INSERT INTO ocr_documents (
+    posting_id, image_id, text, engine, engine_version, languages, settings, confidence,
+    duration_ms, created_at, deleted_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, CURRENT_TIMESTAMP), $11)
+RETURNING id`,
+			postingID, imgID, rec.Text, rec.Engine, rec.EngineVersion, rec.Languages, []byte(rec.Settings),
+			rec.Confidence, rec.DurationMs, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Batches are identified by their posting, time of creation and their order among
+// batches created with them, as they may be renamed.
+type wordBatch struct {
+	Hash      string             `json:"hash"`
+	Name      string             `json:"name"`
+	Seniority pgtype.Text        `json:"seniority"`
+	Location  pgtype.Text        `json:"location"`
+	WorkMode  pgtype.Text        `json:"work_mode"`
+	Posting   string             `json:"posting,omitempty"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r wordBatch) key() string {
+	return r.Hash
+}
+
+var wordBatches = &table[wordBatch]{
+	name:   "word_batches",
+	parent: true,
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, wordBatch) error) error {
+		var (
+			id, seq   int64
+			postingID pgtype.Int8
+			rec       wordBatch
+		)
+
+		return query(ctx, tx, `SELECT
+    id, name, seniority, location, work_mode, posting_id, created_at, deleted_at,
+    ROW_NUMBER() OVER (PARTITION BY posting_id, created_at ORDER BY id)
+FROM word_batches ORDER BY id`,
+			[]any{&id, &rec.Name, &rec.Seniority, &rec.Location, &rec.WorkMode, &postingID, &rec.CreatedAt, &rec.DeletedAt, &seq},
+			func() error {
+				var err error
+				if rec.Posting, err = st.hash("postings", postingID); err != nil {
+					return err
+				}
+				rec.Hash = digest("word_batches", rec.Posting, stamp(rec.CreatedAt), seq)
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec wordBatch) (int64, error) {
+		postingID, err := st.id("postings", rec.Posting)
+		if err != nil {
+			return 0, err
+		}
+
+		return insert(ctx, tx, `INSERT INTO word_batches (
+    name, seniority, location, work_mode, posting_id, created_at, deleted_at
+)
+VALUES ($1, $2, $3, $4, $5, COALESCE($6, CURRENT_TIMESTAMP), $7)
+RETURNING id`,
+			rec.Name, rec.Seniority, rec.Location, rec.WorkMode, postingID, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Terms are identified by their raw value and language like in the database.
+type term struct {
+	Hash       string             `json:"hash"`
+	Raw        string             `json:"raw"`
+	Normalized string             `json:"normalized"`
+	Lemma      string             `json:"lemma"`
+	Language   string             `json:"language"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r term) key() string {
+	return r.Hash
+}
+
+var vocabulary = &table[term]{
+	name:   "vocabulary",
+	parent: true,
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, term) error) error {
+		var (
+			id  int64
+			rec term
+		)
+
+		return query(ctx, tx, `SELECT id, raw, normalized, lemma, language, created_at, deleted_at
+FROM vocabulary ORDER BY id`,
+			[]any{&id, &rec.Raw, &rec.Normalized, &rec.Lemma, &rec.Language, &rec.CreatedAt, &rec.DeletedAt},
+			func() error {
+				rec.Hash = digest("vocabulary", rec.Raw, rec.Language)
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec term) (int64, error) {
+		return insert(ctx, tx, `INSERT INTO vocabulary (raw, normalized, lemma, language, created_at, deleted_at)
+VALUES ($1, COALESCE(NULLIF($2, ''), LOWER($1)), $3, $4, COALESCE($5, CURRENT_TIMESTAMP), $6)
+RETURNING id`,
+			rec.Raw, rec.Normalized, rec.Lemma, rec.Language, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Occurrences are identified by their term and batch like in the database, occurrences
+// without a batch by their term and day (UTC).
+type occurrence struct {
+	Hash      string             `json:"hash"`
+	Term      string             `json:"term"`
+	Batch     string             `json:"batch,omitempty"`
+	Count     int32              `json:"count"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r occurrence) key() string {
+	return r.Hash
+}
+
+var occurrences = &table[occurrence]{
+	name: "occurrences",
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, occurrence) error) error {
+		var (
+			id, termID int64
+			batchID    pgtype.Int8
+			rec        occurrence
+		)
+
+		return query(ctx, tx, `SELECT id, term_id, batch_id, count, created_at, deleted_at
+FROM occurrences ORDER BY id`,
+			[]any{&id, &termID, &batchID, &rec.Count, &rec.CreatedAt, &rec.DeletedAt},
+			func() error {
+				var err error
+				if rec.Term, err = st.hash("vocabulary", pgtype.Int8{Int64: termID, Valid: true}); err != nil {
+					return err
+				}
+				if rec.Batch, err = st.hash("word_batches", batchID); err != nil {
+					return err
+				}
+				rec.Hash = digest("occurrences", rec.Term, rec.Batch)
+				if !batchID.Valid {
+					rec.Hash = digest("occurrences", rec.Term, rec.CreatedAt.Time.UTC().Format(time.DateOnly))
+				}
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec occurrence) (int64, error) {
+		termID, err := st.id("vocabulary", rec.Term)
+		if err != nil {
+			return 0, err
+		}
+		batchID, err := st.id("word_batches", rec.Batch)
+		if err != nil {
+			return 0, err
+		}
+
+		return insert(ctx, tx, `INSERT INTO occurrences (term_id, batch_id, count, created_at, deleted_at)
+VALUES ($1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP), $5)
+RETURNING id`,
+			termID, batchID, rec.Count, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Salaries are identified by their batch, raw text, time of creation and their order
+// among equal salaries created with them.
+type salary struct {
+	Hash       string             `json:"hash"`
+	Batch      string             `json:"batch"`
+	MinAmount  float64            `json:"min_amount"`
+	MaxAmount  float64            `json:"max_amount"`
+	Currency   string             `json:"currency"`
+	Period     string             `json:"period"`
+	MonthlyMin float64            `json:"monthly_min"`
+	MonthlyMax float64            `json:"monthly_max"`
+	Contract   pgtype.Text        `json:"contract"`
+	Basis      pgtype.Text        `json:"basis"`
+	Seniority  pgtype.Text        `json:"seniority"`
+	Raw        string             `json:"raw"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r salary) key() string {
+	return r.Hash
+}
+
+var salaries = &table[salary]{
+	name: "salaries",
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, salary) error) error {
+		var (
+			id, batchID, seq int64
+			rec              salary
+		)
+
+		return query(ctx, tx, `SELECT
+    id, batch_id, min_amount, max_amount, currency, period, monthly_min, monthly_max,
+    contract, basis, seniority, raw, created_at, deleted_at,
+    ROW_NUMBER() OVER (PARTITION BY batch_id, created_at, raw ORDER BY id)
+FROM salaries ORDER BY id`,
+			[]any{
+				&id, &batchID, &rec.MinAmount, &rec.MaxAmount, &rec.Currency, &rec.Period, &rec.MonthlyMin, &rec.MonthlyMax,
+				&rec.Contract, &rec.Basis, &rec.Seniority, &rec.Raw, &rec.CreatedAt, &rec.DeletedAt, &seq,
+			},
+			func() error {
+				var err error
+				if rec.Batch, err = st.hash("word_batches", pgtype.Int8{Int64: batchID, Valid: true}); err != nil {
+					return err
+				}
+				rec.Hash = digest("salaries", rec.Batch, rec.Raw, stamp(rec.CreatedAt), seq)
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec salary) (int64, error) {
+		batchID, err := st.id("word_batches", rec.Batch)
+		if err != nil {
+			return 0, err
+		}
+		if !batchID.Valid {
+			return 0, fmt.Errorf("%w: salary %s without batch", ErrCorrupt, rec.Hash)
+		}
+
+		return insert(ctx, tx, `INSERT INTO salaries (
+    batch_id, min_amount, max_amount, currency, period, monthly_min, monthly_max,
+    contract, basis, seniority, raw, created_at, deleted_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, COALESCE($12, CURRENT_TIMESTAMP), $13)
+RETURNING id`,
+			batchID, rec.MinAmount, rec.MaxAmount, rec.Currency, rec.Period, rec.MonthlyMin, rec.MonthlyMax,
+			rec.Contract, rec.Basis, rec.Seniority, rec.Raw, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+type phraseBatch struct {
+	Hash      string             `json:"hash"`
+	Name      string             `json:"name"`
+	Posting   string             `json:"posting,omitempty"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r phraseBatch) key() string {
+	return r.Hash
+}
+
+var phraseBatches = &table[phraseBatch]{
+	name:   "phrase_batches",
+	parent: true,
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, phraseBatch) error) error {
+		var (
+			id, seq   int64
+			postingID pgtype.Int8
+			rec       phraseBatch
+		)
+
+		return query(ctx, tx, `SELECT
+    id, name, posting_id, created_at, deleted_at,
+    ROW_NUMBER() OVER (PARTITION BY posting_id, created_at ORDER BY id)
+FROM phrase_batches ORDER BY id`,
+			[]any{&id, &rec.Name, &postingID, &rec.CreatedAt, &rec.DeletedAt, &seq},
+			func() error {
+				var err error
+				if rec.Posting, err = st.hash("postings", postingID); err != nil {
+					return err
+				}
+				rec.Hash = digest("phrase_batches", rec.Posting, stamp(rec.CreatedAt), seq)
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec phraseBatch) (int64, error) {
+		postingID, err := st.id("postings", rec.Posting)
+		if err != nil {
+			return 0, err
+		}
+
+		return insert(ctx, tx, `INSERT INTO phrase_batches (name, posting_id, created_at, deleted_at)
+VALUES ($1, $2, COALESCE($3, CURRENT_TIMESTAMP), $4)
+RETURNING id`,
+			rec.Name, postingID, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Phrases are identified by their batch, value, time of creation and their order among
+// equal phrases created with them.
+type phrase struct {
+	Hash      string             `json:"hash"`
+	Batch     string             `json:"batch,omitempty"`
+	Value     string             `json:"value"`
+	Language  pgtype.Text        `json:"language"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (r phrase) key() string {
+	return r.Hash
+}
+
+var phrases = &table[phrase]{
+	name: "phrases",
+	read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, phrase) error) error {
+		var (
+			id, seq int64
+			batchID pgtype.Int8
+			rec     phrase
+		)
+
+		return query(ctx, tx, `SELECT
+    id, batch_id, value, language, created_at, deleted_at,
+    ROW_NUMBER() OVER (PARTITION BY batch_id, created_at, value ORDER BY id)
+FROM phrases ORDER BY id`,
+			[]any{&id, &batchID, &rec.Value, &rec.Language, &rec.CreatedAt, &rec.DeletedAt, &seq},
+			func() error {
+				var err error
+				if rec.Batch, err = st.hash("phrase_batches", batchID); err != nil {
+					return err
+				}
+				rec.Hash = digest("phrases", rec.Batch, rec.Value, stamp(rec.CreatedAt), seq)
+
+				return fn(id, rec)
+			},
+		)
+	},
+	insert: func(ctx context.Context, tx pgx.Tx, st *state, rec phrase) (int64, error) {
+		batchID, err := st.id("phrase_batches", rec.Batch)
+		if err != nil {
+			return 0, err
+		}
+
+		return insert(ctx, tx, `INSERT INTO phrases (batch_id, value, language, created_at, deleted_at)
+VALUES ($1, $2, $3, COALESCE($4, CURRENT_TIMESTAMP), $5)
+RETURNING id`,
+			batchID, rec.Value, rec.Language, rec.CreatedAt, rec.DeletedAt,
+		)
+	},
+}
+
+// Tags and collections are identified by their names like in the database. Tags have
+// no description.
+type group struct {
+	Hash        string             `json:"hash"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (r group) key() string {
+	return r.Hash
+}
+
+// groups returns table of tags or collections.
+func groups(name string) *table[group] {
+	description := "description"
+	if name == "tags" {
+		description = "''"
+	}
+
+	return &table[group]{
+		name:   name,
+		parent: true,
+		read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, group) error) error {
+			var (
+				id  int64
+				rec group
+			)
+
+			return query(ctx, tx, "SELECT id, name, "+description+", created_at FROM "+name+" ORDER BY id",
+				[]any{&id, &rec.Name, &rec.Description, &rec.CreatedAt},
+				func() error {
+					rec.Hash = digest(name, rec.Name)
+
+					return fn(id, rec)
+				},
+			)
+		},
+		insert: func(ctx context.Context, tx pgx.Tx, st *state, rec group) (int64, error) {
+			if name == "tags" {
+				return insert(ctx, tx, "INSERT INTO tags (name, created_at) VALUES ($1, COALESCE($2, CURRENT_TIMESTAMP)) RETURNING id",
+					rec.Name, rec.CreatedAt,
+				)
+			}
+
+			return insert(ctx, tx, "INSERT INTO "+name+" (name, description, created_at) VALUES ($1, $2, COALESCE($3, CURRENT_TIMESTAMP)) RETURNING id",
+				rec.Name, rec.Description, rec.CreatedAt,
+			)
+		},
+	}
+}
+
+// Members are identified by their group and their posting or batch.
+type member struct {
+	Hash        string             `json:"hash"`
+	Group       string             `json:"group"`
+	Posting     string             `json:"posting,omitempty"`
+	WordBatch   string             `json:"word_batch,omitempty"`
+	PhraseBatch string             `json:"phrase_batch,omitempty"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (r member) key() string {
+	return r.Hash
+}
+
+// members returns table of members of groups of table parent, referenced by column
+// groupID. Members have no IDs, so rows are indexed by their order.
+func members(name, parent, groupID string) *table[member] {
+	return &table[member]{
+		name: name,
+		read: func(ctx context.Context, tx pgx.Tx, st *state, fn func(int64, member) error) error {
+			var (
+				id, gid                               int64
+				postingID, wordBatchID, phraseBatchID pgtype.Int8
+				rec                                   member
+			)
+
+			return query(ctx, tx, "SELECT "+groupID+", posting_id, word_batch_id, phrase_batch_id, created_at FROM "+name+
+				" ORDER BY "+groupID+", created_at, posting_id, word_batch_id, phrase_batch_id",
+				[]any{&gid, &postingID, &wordBatchID, &phraseBatchID, &rec.CreatedAt},
+				func() error {
+					var err error
+					if rec.Group, err = st.hash(parent, pgtype.Int8{Int64: gid, Valid: true}); err != nil {
+						return err
+					}
+					if rec.Posting, err = st.hash("postings", postingID); err != nil {
+						return err
+					}
+					if rec.WordBatch, err = st.hash("word_batches", wordBatchID); err != nil {
+						return err
+					}
+					if rec.PhraseBatch, err = st.hash("phrase_batches", phraseBatchID); err != nil {
+						return err
+					}
+					rec.Hash = digest(name, rec.Group, rec.Posting, rec.WordBatch, rec.PhraseBatch)
+					id++
+
+					return fn(id, rec)
+				},
+			)
+		},
+		insert: func(ctx context.Context, tx pgx.Tx, st *state, rec member) (int64, error) {
+			gid, err := st.id(parent, rec.Group)
+			if err != nil {
+				return 0, err
+			}
+			if !gid.Valid {
+				return 0, fmt.Errorf("%w: member %s without group", ErrCorrupt, rec.Hash)
+			}
+			postingID, err := st.id("postings", rec.Posting)
+			if err != nil {
+				return 0, err
+			}
+			wordBatchID, err := st.id("word_batches", rec.WordBatch)
+			if err != nil {
+				return 0, err
+			}
+			phraseBatchID, err := st.id("phrase_batches", rec.PhraseBatch)
+			if err != nil {
+				return 0, err
+			}
+			_, err = tx.Exec(ctx, "INSERT INTO "+name+" ("+groupID+", posting_id, word_batch_id, phrase_batch_id, created_at)"+
+				" VALUES ($1, $2, $3, $4, COALESCE($5, CURRENT_TIMESTAMP))",
+				gid, postingID, wordBatchID, phraseBatchID, rec.CreatedAt,
+			)
+
+			// Members aren't referenced, so they need no IDs
+			return 0, err
+		},
+	}
+}
+
+// readFile returns content of f.
+func readFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read %s: %w", ErrCorrupt, f.Name, err)
+	}
+
+	return content, nil
+}
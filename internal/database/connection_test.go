@@ -0,0 +1,63 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kndrad/piccrack/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolConfig(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		User:     "piccrack",
+		Password: "p@ss word",
+		Host:     "localhost",
+		Port:     "5432",
+		Name:     "piccrack",
+		TLS:      config.TLSConfig{Mode: "require"},
+		Pool: config.PoolConfig{
+			MaxConns:        10,
+			MinConns:        2,
+			MaxConnLifetime: 2 * time.Hour,
+			MaxConnIdleTime: time.Minute,
+			ConnectTimeout:  3 * time.Second,
+			DialerKeepAlive: time.Second,
+		},
+	}
+
+	pgcfg, err := poolConfig(cfg)
+	require.NoError(t, err)
+	require.Equal(t, int32(10), pgcfg.MaxConns)
+	require.Equal(t, int32(2), pgcfg.MinConns)
+	require.Equal(t, 2*time.Hour, pgcfg.MaxConnLifetime)
+	require.Equal(t, time.Minute, pgcfg.MaxConnIdleTime)
+	require.Equal(t, 3*time.Second, pgcfg.ConnConfig.ConnectTimeout)
+	require.Equal(t, "p@ss word", pgcfg.ConnConfig.Password)
+	require.NotNil(t, pgcfg.ConnConfig.TLSConfig)
+
+	// DSN takes precedence over connection settings.
+	cfg.DSN = "postgres://other:secret@db:5433/other?sslmode=disable"
+	pgcfg, err = poolConfig(cfg)
+	require.NoError(t, err)
+	require.Equal(t, "db", pgcfg.ConnConfig.Host)
+	require.Equal(t, "other", pgcfg.ConnConfig.Database)
+	require.Nil(t, pgcfg.ConnConfig.TLSConfig)
+	require.Equal(t, int32(10), pgcfg.MaxConns)
+}
+
+func TestDSN(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		User: "piccrack",
+		Host: "localhost",
+		Port: "5432",
+		Name: "piccrack",
+	}
+	require.Equal(t, "postgres://piccrack:@localhost:5432/piccrack?sslmode=disable", DSN(cfg))
+
+	cfg.TLS = config.TLSConfig{Mode: "verify-full", RootCert: "ca.pem", Cert: "client.pem", Key: "client.key"}
+	require.Equal(t,
+		"postgres://piccrack:@localhost:5432/piccrack?sslcert=client.pem&sslkey=client.key&sslmode=verify-full&sslrootcert=ca.pem",
+		DSN(cfg),
+	)
+}
@@ -8,16 +8,16 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/kndrad/piccrack/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -61,7 +61,7 @@ func (s *DatabaseTestSuite) SetupSuite() {
 	s.connStr = connStr
 
 	// Apply migrations
-	err = applyMigrations(ctx, s.connStr)
+	err = applyMigrations(s.connStr)
 	require.NoError(s.T(), err)
 }
 
@@ -84,7 +84,7 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 
 		q := New(conn)
 		for _, w := range testWords(s.T()) {
-			row, err := q.CreateWord(ctx, w)
+			row, err := q.CreateWord(ctx, CreateWordParams{Value: w})
 			require.NoError(s.T(), err)
 			assert.Equal(s.T(), w, row.Value)
 		}
@@ -99,7 +99,10 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 		params := ListWordsParams{Limit: DefaultQueryLimit}
 		rows, err := query.ListWords(ctx, params)
 		require.NoError(s.T(), err)
-		require.Equal(s.T(), len(testWords(s.T())), len(rows))
+
+		// Repeated words are stored once in vocabulary
+		unique := slices.Compact(slices.Sorted(slices.Values(testWords(s.T()))))
+		require.Equal(s.T(), len(unique), len(rows))
 	})
 
 	s.Run("create_word", func() {
@@ -108,9 +111,10 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 		defer conn.Close(ctx)
 
 		q := New(conn)
-		row, err := q.CreateWord(ctx, "test1")
+		row, err := q.CreateWord(ctx, CreateWordParams{Value: "test1", Lemma: "test"})
 		require.NoError(s.T(), err)
 		require.Equal(s.T(), "test1", row.Value)
+		require.Equal(s.T(), "test", row.Lemma)
 	})
 
 	s.Run("list_word_frequencies", func() {
@@ -135,6 +139,178 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 		}
 	})
 
+	s.Run("list_lemma_frequencies_falls_back_to_value", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		params := ListLemmaFrequenciesParams{Limit: DefaultQueryLimit}
+		rows, err := q.ListLemmaFrequencies(ctx, params)
+		require.NoError(s.T(), err)
+
+		for _, row := range rows {
+			switch row.Lemma {
+			case "experience":
+				require.Equal(s.T(), int64(28), row.Total)
+			case "test":
+				require.Equal(s.T(), int64(1), row.Total)
+			}
+		}
+	})
+
+	s.Run("list_word_frequencies_filtered_by_language", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		_, err = q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "language_batch",
+			Words:     []string{"doświadczenie", "experience"},
+			Lemmas:    []string{"doświadczen", "experi"},
+			Languages: []string{"pl", "en"},
+		})
+		require.NoError(s.T(), err)
+
+		rows, err := q.ListWordFrequencies(ctx, ListWordFrequenciesParams{
+			Limit:    DefaultQueryLimit,
+			Language: pgtype.Text{String: "pl", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+		require.Equal(s.T(), "doświadczenie", rows[0].Value)
+	})
+
+	s.Run("list_word_frequencies_filtered_by_batch_attributes", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		_, err = q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "attributes_batch",
+			Words:     []string{"elixir"},
+			Lemmas:    []string{"elixir"},
+			Languages: []string{"en"},
+			Seniority: "junior",
+			WorkMode:  "remote",
+		})
+		require.NoError(s.T(), err)
+
+		row, err := q.UpdateWordBatchAttributes(ctx, UpdateWordBatchAttributesParams{
+			Name:      "attributes_batch",
+			Seniority: pgtype.Text{String: "senior", Valid: true},
+			Location:  pgtype.Text{String: "Kraków", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "senior", row.Seniority)
+		require.Equal(s.T(), "remote", row.WorkMode)
+
+		rows, err := q.ListWordFrequencies(ctx, ListWordFrequenciesParams{
+			Limit:     DefaultQueryLimit,
+			Seniority: pgtype.Text{String: "senior", Valid: true},
+			Location:  pgtype.Text{String: "kraków", Valid: true},
+			WorkMode:  pgtype.Text{String: "remote", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+		require.Equal(s.T(), "elixir", rows[0].Value)
+	})
+
+	s.Run("list_batch_word_sets", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		rows, err := q.ListBatchWordSets(ctx, ListBatchWordSetsParams{
+			Seniority: pgtype.Text{String: "senior", Valid: true},
+			Location:  pgtype.Text{String: "Kraków", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+		require.Equal(s.T(), []string{"elixir"}, rows[0].Words)
+	})
+
+	s.Run("list_word_trend", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		rows, err := q.ListWordTrend(ctx, ListWordTrendParams{
+			Bucket:    "week",
+			Since:     pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -7), Valid: true},
+			Words:     []string{"elixir", "erlang"},
+			Seniority: pgtype.Text{String: "senior", Valid: true},
+			Location:  pgtype.Text{String: "Kraków", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+		require.Equal(s.T(), "elixir", rows[0].Value)
+		require.Equal(s.T(), int64(1), rows[0].Postings)
+		require.Equal(s.T(), int64(1), rows[0].BucketPostings)
+	})
+
+	s.Run("list_word_trend_buckets_in_utc", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		// Days of the session time zone start 14 hours before days of UTC
+		_, err = conn.Exec(ctx, "SET TIME ZONE 'Pacific/Kiritimati'")
+		require.NoError(s.T(), err)
+
+		q := New(conn)
+		rows, err := q.ListWordTrend(ctx, ListWordTrendParams{
+			Bucket: "day",
+			Since:  pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -1), Valid: true},
+			Words:  []string{"elixir"},
+		})
+		require.NoError(s.T(), err)
+		require.NotEmpty(s.T(), rows)
+
+		now := time.Now().UTC()
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		require.True(s.T(), rows[len(rows)-1].Bucket.Time.Equal(day), rows[len(rows)-1].Bucket.Time)
+		require.Positive(s.T(), rows[len(rows)-1].BucketPostings)
+	})
+
+	s.Run("list_word_counts_in_set", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		rows, err := q.ListWordCountsInSet(ctx, ListWordCountsInSetParams{
+			Batches:   []string{"attributes_batch", "language_batch"},
+			CreatedTo: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		})
+		require.NoError(s.T(), err)
+
+		values := make([]string, 0, len(rows))
+		for _, row := range rows {
+			values = append(values, row.Value)
+			require.Equal(s.T(), int64(1), row.Postings)
+		}
+		require.Equal(s.T(), []string{"doświadczenie", "elixir", "experience"}, values)
+	})
+
+	s.Run("count_word_batches_in_set", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		total, err := q.CountWordBatchesInSet(ctx, CountWordBatchesInSetParams{
+			Batches:  []string{"attributes_batch", "language_batch"},
+			WorkMode: pgtype.Text{String: "remote", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), total)
+	})
+
 	s.Run("list_word_rankings", func() {
 		conn, err := pgx.Connect(ctx, s.connStr)
 		require.NoError(s.T(), err)
@@ -163,8 +339,11 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 		defer conn.Close(ctx)
 
 		q := New(conn)
-		params := ListWordBatchesParams{Limit: DefaultQueryLimit}
-		batchRows, err := q.ListWordBatches(ctx, params)
+		params := ListBatchesParams{
+			Limit: DefaultQueryLimit,
+			Kind:  pgtype.Text{String: "words", Valid: true},
+		}
+		batchRows, err := q.ListBatches(ctx, params)
 		require.NoError(s.T(), err)
 
 		for _, row := range batchRows {
@@ -184,6 +363,212 @@ func (s *DatabaseTestSuite) TestDatabaseQueries() {
 	})
 }
 
+func (s *DatabaseTestSuite) TestSalaryQueries() {
+	ctx := context.Background()
+
+	s.Run("salary_medians_by_skill_and_seniority", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		for i, monthly := range []float64{10000, 20000, 30000} {
+			batch, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+				Name:      fmt.Sprintf("salary_batch_%d", i),
+				Words:     []string{"kotlin", "kotlin"},
+				Lemmas:    []string{"kotlin", "kotlin"},
+				Languages: []string{"en", "en"},
+			})
+			require.NoError(s.T(), err)
+
+			_, err = q.CreateSalary(ctx, CreateSalaryParams{
+				BatchID:    batch.BatchID.Int64,
+				MinAmount:  monthly,
+				MaxAmount:  monthly,
+				Currency:   "PLN",
+				Period:     "month",
+				MonthlyMin: monthly,
+				MonthlyMax: monthly,
+				Contract:   "b2b",
+				Seniority:  "senior",
+				Raw:        fmt.Sprintf("%.0f PLN", monthly),
+			})
+			require.NoError(s.T(), err)
+		}
+
+		salaries, err := q.ListSalariesByBatchName(ctx, "salary_batch_0")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), salaries, 1)
+		require.Equal(s.T(), "b2b", salaries[0].Contract)
+
+		bySkill, err := q.ListSalaryMediansBySkill(ctx, ListSalaryMediansBySkillParams{
+			Limit: 10,
+			Skill: pgtype.Text{String: "kotlin", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), bySkill, 1)
+		require.Equal(s.T(), int64(3), bySkill[0].Total)
+		require.InDelta(s.T(), 20000.0, bySkill[0].Median, 0.001)
+
+		bySeniority, err := q.ListSalaryMediansBySeniority(ctx, pgtype.Text{String: "b2b", Valid: true})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), bySeniority, 1)
+		require.Equal(s.T(), "senior", bySeniority[0].Seniority)
+	})
+}
+
+func (s *DatabaseTestSuite) TestPostingQueries() {
+	ctx := context.Background()
+
+	s.Run("posting_owns_word_and_phrase_batches", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		posting, err := q.CreatePosting(ctx, CreatePostingParams{
+			Title:     "Go Developer",
+			Company:   "Acme",
+			SourceUrl: "https://jobs.example.com/1",
+			ImageHash: "abc",
+			RawText:   "Go Developer at Acme",
+		})
+		require.NoError(s.T(), err)
+		require.True(s.T(), posting.CapturedAt.Valid)
+
+		postingID := pgtype.Int8{Int64: posting.ID, Valid: true}
+		words, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "posting_words",
+			Words:     []string{"go"},
+			Lemmas:    []string{"go"},
+			Languages: []string{"en"},
+			PostingID: postingID,
+		})
+		require.NoError(s.T(), err)
+		phrases, err := q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+			Name:      "posting_phrases",
+			Phrases:   []string{"Go Developer at Acme"},
+			Languages: []string{"en"},
+			PostingID: postingID,
+		})
+		require.NoError(s.T(), err)
+
+		row, err := q.GetPosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "abc", row.ImageHash)
+		require.Equal(s.T(), words.BatchID, row.WordBatchID)
+		require.Equal(s.T(), phrases.BatchID, row.PhraseBatchID)
+
+		rows, err := q.ListPostings(ctx, ListPostingsParams{
+			Limit:   10,
+			Company: pgtype.Text{String: "acme", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+
+		updated, err := q.UpdatePosting(ctx, UpdatePostingParams{
+			ID:    posting.ID,
+			Title: pgtype.Text{String: "Senior Go Developer", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "Senior Go Developer", updated.Title)
+		require.Equal(s.T(), "Acme", updated.Company)
+
+		id, err := q.DeletePosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), posting.ID, id)
+
+		_, err = q.GetPosting(ctx, posting.ID)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+		batchWords, err := q.ListWordsByBatchName(ctx, "posting_words")
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), batchWords)
+	})
+}
+
+func (s *DatabaseTestSuite) TestOcrDocumentQueries() {
+	ctx := context.Background()
+
+	s.Run("stores_documents_and_replaces_posting_batches", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		posting, err := q.CreatePosting(ctx, CreatePostingParams{
+			Title:   "Rust Developer",
+			RawText: "Rust Developer",
+		})
+		require.NoError(s.T(), err)
+
+		image, err := q.CreateImage(ctx, CreateImageParams{
+			Hash:      "ocr_documents_image",
+			Width:     800,
+			Height:    600,
+			Format:    "png",
+			SizeBytes: 3,
+			Filename:  "posting.png",
+			Content:   []byte{1, 2, 3},
+		})
+		require.NoError(s.T(), err)
+		// Storing the same image again returns the stored row
+		again, err := q.CreateImage(ctx, CreateImageParams{Hash: "ocr_documents_image", Format: "png"})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), image.ID, again.ID)
+		require.Equal(s.T(), int32(800), again.Width)
+
+		imageID := pgtype.Int8{Int64: image.ID, Valid: true}
+		postingID := pgtype.Int8{Int64: posting.ID, Valid: true}
+		for _, text := range []string{"Rust Develper", "Rust Developer"} {
+			_, err := q.CreateOcrDocument(ctx, CreateOcrDocumentParams{
+				PostingID:  postingID,
+				ImageID:    imageID,
+				Text:       text,
+				Engine:     "tesseract",
+				Languages:  []string{"eng"},
+				Settings:   []byte(`{"trim": true}`),
+				Confidence: pgtype.Float8{Float64: 91.5, Valid: true},
+				DurationMs: 120,
+			})
+			require.NoError(s.T(), err)
+		}
+
+		latest, err := q.GetLatestOcrDocument(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "Rust Developer", latest.Text)
+		require.Equal(s.T(), []byte{1, 2, 3}, latest.ImageContent)
+		require.False(s.T(), latest.ImagePath.Valid)
+
+		docs, err := q.ListOcrDocuments(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), docs, 2)
+		require.Equal(s.T(), []string{"eng"}, docs[0].Languages)
+		require.Equal(s.T(), "posting.png", docs[0].ImageFilename.String)
+
+		batch := func(name string) {
+			_, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+				Name:      name,
+				Words:     []string{"rust"},
+				Lemmas:    []string{"rust"},
+				Languages: []string{"en"},
+				PostingID: postingID,
+			})
+			require.NoError(s.T(), err)
+		}
+		batch("ocr_documents_first")
+		require.NoError(s.T(), q.DeletePostingBatches(ctx, posting.ID))
+		// Soft deleted batch doesn't block a new batch of the posting
+		batch("ocr_documents_second")
+
+		row, err := q.GetPosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "ocr_documents_second", row.WordBatchName.String)
+		words, err := q.ListWordsByBatchName(ctx, "ocr_documents_first")
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), words)
+	})
+}
+
 func (s *DatabaseTestSuite) TestCreatePhrasesBatchQuery() {
 	ctx := context.Background()
 
@@ -192,12 +577,751 @@ func (s *DatabaseTestSuite) TestCreatePhrasesBatchQuery() {
 		require.NoError(s.T(), err)
 		defer conn.Close(ctx)
 
-		row := conn.QueryRow(ctx, createPhrasesBatch, "test", loadTestPhrases(s.T()))
+		row := conn.QueryRow(ctx, createPhrasesBatch, "test", []string{}, loadTestPhrases(s.T()), pgtype.Int8{})
 		var i CreatePhrasesBatchRow
 		err = row.Scan(&i.ID, &i.BatchID)
 		require.NoError(s.T(), err)
 		s.T().Logf("Created phrase batch: %v", i.BatchID)
 	})
+
+	s.Run("list_phrase_counts_in_set", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		_, err = q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+			Name:      "compared_phrases",
+			Phrases:   []string{"Remote work", "remote work", "Office in Warsaw"},
+			Languages: []string{"en", "en", "en"},
+		})
+		require.NoError(s.T(), err)
+
+		rows, err := q.ListPhraseCountsInSet(ctx, ListPhraseCountsInSetParams{
+			Batches: []string{"compared_phrases"},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 2)
+		require.Equal(s.T(), "office in warsaw", rows[0].Value)
+		require.Equal(s.T(), "remote work", rows[1].Value)
+		require.Equal(s.T(), int64(2), rows[1].Total)
+	})
+}
+
+func (s *DatabaseTestSuite) TestWordStatsQueries() {
+	ctx := context.Background()
+
+	s.Run("triggers_keep_stats_current", func() {
+		conn, err := pgx.Connect(ctx, s.connStr)
+		require.NoError(s.T(), err)
+		defer conn.Close(ctx)
+
+		q := New(conn)
+		posting, err := q.CreatePosting(ctx, CreatePostingParams{
+			Title:   "Zig Developer",
+			RawText: "Zig Developer",
+		})
+		require.NoError(s.T(), err)
+
+		_, err = q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "word_stats",
+			Words:     []string{"zigstats", "zigstats", "comptimestats"},
+			Lemmas:    []string{"zigstats", "zigstats", "comptimestats"},
+			Languages: []string{"en", "en", "en"},
+			PostingID: pgtype.Int8{Int64: posting.ID, Valid: true},
+		})
+		require.NoError(s.T(), err)
+
+		totals := func() map[string]int64 {
+			rows, err := q.ListWordStatsFrequencies(ctx, ListWordStatsFrequenciesParams{Limit: 10000})
+			require.NoError(s.T(), err)
+
+			totals := make(map[string]int64)
+			for _, row := range rows {
+				totals[row.Value] = row.Total
+			}
+			return totals
+		}
+		got := totals()
+		require.Equal(s.T(), int64(2), got["zigstats"])
+		require.Equal(s.T(), int64(1), got["comptimestats"])
+
+		batches, err := q.ListBatches(ctx, ListBatchesParams{
+			Limit:  10000,
+			Kind:   pgtype.Text{String: "words", Valid: true},
+			Prefix: pgtype.Text{String: "word_stats", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		i := slices.IndexFunc(batches, func(row ListBatchesRow) bool {
+			return row.Name == "word_stats"
+		})
+		require.NotEqual(s.T(), -1, i)
+		require.Equal(s.T(), int64(2), batches[i].Items)
+		require.Equal(s.T(), int64(3), batches[i].Total)
+
+		trend, err := q.ListWordStatsTrend(ctx, ListWordStatsTrendParams{
+			Bucket: "day",
+			Since:  pgtype.Timestamptz{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+			Words:  []string{"zigstats"},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), trend, 1)
+		require.Equal(s.T(), int64(2), trend[0].Total)
+		require.Equal(s.T(), int64(1), trend[0].Postings)
+
+		// Soft deleted occurrences are subtracted from stats
+		require.NoError(s.T(), q.DeletePostingBatches(ctx, posting.ID))
+		got = totals()
+		require.Zero(s.T(), got["zigstats"])
+		require.Zero(s.T(), got["comptimestats"])
+
+		before := totals()
+		require.NoError(s.T(), q.RefreshWordStats(ctx))
+		require.Equal(s.T(), before, totals())
+	})
+}
+
+func (s *DatabaseTestSuite) TestStandaloneWordsDatedPerDay() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	q := New(conn)
+	row, err := q.CreateWord(ctx, CreateWordParams{Value: "zigdaily", Language: "en"})
+	require.NoError(s.T(), err)
+	_, err = q.CreateWord(ctx, CreateWordParams{Value: "zigdaily", Language: "en"})
+	require.NoError(s.T(), err)
+
+	// Words added on another day are counted in a new row
+	_, err = conn.Exec(ctx, `UPDATE occurrences
+SET created_at = created_at - INTERVAL '2 days'
+WHERE term_id = $1 AND batch_id IS NULL`, row.ID)
+	require.NoError(s.T(), err)
+	_, err = q.CreateWord(ctx, CreateWordParams{Value: "zigdaily", Language: "en"})
+	require.NoError(s.T(), err)
+
+	var rows, total int64
+	err = conn.QueryRow(ctx, `SELECT COUNT(*), SUM(count) FROM occurrences
+WHERE term_id = $1 AND batch_id IS NULL AND deleted_at IS NULL`, row.ID).Scan(&rows, &total)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), rows)
+	require.Equal(s.T(), int64(3), total)
+
+	trend, err := q.ListWordStatsTrend(ctx, ListWordStatsTrendParams{
+		Bucket: "day",
+		Since:  pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -3), Valid: true},
+		Words:  []string{"zigdaily"},
+	})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), trend, 2)
+	require.Equal(s.T(), int64(2), trend[0].Total)
+	require.Equal(s.T(), int64(1), trend[1].Total)
+
+	// Ingested words without a batch are counted in the row of the day
+	repo := NewRepository(conn)
+	_, err = repo.Ingest(ctx, Ingestion{Words: []IngestedWord{{Value: "zigdaily", Language: "en"}}})
+	require.NoError(s.T(), err)
+	err = conn.QueryRow(ctx, `SELECT COUNT(*), SUM(count) FROM occurrences
+WHERE term_id = $1 AND batch_id IS NULL AND deleted_at IS NULL`, row.ID).Scan(&rows, &total)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), rows)
+	require.Equal(s.T(), int64(4), total)
+}
+
+func (s *DatabaseTestSuite) TestSearchQueries() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	q := New(conn)
+	_, err = q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+		Name:      "searched_phrases",
+		Phrases:   []string{"Remote working from Gdansk", "Kubernetes clusters <b>on-call</b>"},
+		Languages: []string{"en", "en"},
+	})
+	require.NoError(s.T(), err)
+	_, err = q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+		Name:      "searched_words",
+		Words:     []string{"terraformsearch"},
+		Lemmas:    []string{"terraformsearch"},
+		Languages: []string{"en"},
+	})
+	require.NoError(s.T(), err)
+
+	s.Run("search_phrases_stemmed", func() {
+		rows, err := q.SearchPhrases(ctx, SearchPhrasesParams{
+			Limit:    10,
+			Query:    "remotely works",
+			Language: pgtype.Text{String: "en", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.NotEmpty(s.T(), rows)
+		require.Equal(s.T(), "Remote working from Gdansk", rows[0].Value)
+		require.Equal(s.T(), "searched_phrases", rows[0].BatchName)
+		require.Positive(s.T(), rows[0].Rank)
+		require.Contains(s.T(), rows[0].Snippet, "<mark>")
+	})
+
+	s.Run("search_phrases_fuzzy", func() {
+		rows, err := q.SearchPhrases(ctx, SearchPhrasesParams{Limit: 10, Query: "Kubernets clusters"})
+		require.NoError(s.T(), err)
+		require.NotEmpty(s.T(), rows)
+		require.Equal(s.T(), "Kubernetes clusters <b>on-call</b>", rows[0].Value)
+	})
+
+	s.Run("search_words_fuzzy", func() {
+		rows, err := q.SearchWords(ctx, SearchWordsParams{Limit: 10, Query: "TerraformSerch"})
+		require.NoError(s.T(), err)
+		require.NotEmpty(s.T(), rows)
+		require.Equal(s.T(), "terraformsearch", rows[0].Value)
+		require.Equal(s.T(), int64(1), rows[0].Total)
+	})
+}
+
+func (s *DatabaseTestSuite) TestRepositoryIngest() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	repo := NewRepository(conn)
+
+	s.Run("ingests_batches_in_transaction", func() {
+		res, err := repo.Ingest(ctx, Ingestion{
+			Name:     "ingested",
+			Location: "Warszawa",
+			Words: []IngestedWord{
+				{Value: "ingestgo", Lemma: "ingestgo", Language: "en"},
+				{Value: "ingestgo", Lemma: "ingestgo", Language: "en"},
+				{Value: "ingestsql", Language: "en"},
+			},
+			Phrases: []IngestedPhrase{{Value: "Ingested phrase", Language: "en"}},
+			Salaries: []CreateSalaryParams{{
+				MinAmount:  10000,
+				MaxAmount:  15000,
+				Currency:   "PLN",
+				Period:     "month",
+				MonthlyMin: 10000,
+				MonthlyMax: 15000,
+				Raw:        "10 000 - 15 000 PLN",
+			}},
+		})
+		require.NoError(s.T(), err)
+		require.True(s.T(), res.WordBatchID.Valid)
+		require.True(s.T(), res.PhraseBatchID.Valid)
+		require.Equal(s.T(), int64(3), res.Words)
+		require.Equal(s.T(), int64(2), res.Terms)
+		require.Equal(s.T(), int64(2), res.Occurrences)
+		require.Equal(s.T(), int64(1), res.Phrases)
+		require.Equal(s.T(), int64(1), res.Salaries)
+
+		words, err := repo.ListWordsByBatchName(ctx, "ingested")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), words, 2)
+		salaries, err := repo.ListSalariesByBatchName(ctx, "ingested")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), salaries, 1)
+	})
+
+	s.Run("copies_large_word_sets", func() {
+		words := make([]IngestedWord, 0, CopyThreshold+1)
+		for i := range CopyThreshold + 1 {
+			words = append(words, IngestedWord{Value: fmt.Sprintf("copied%d", i%100)})
+		}
+		res, err := repo.Ingest(ctx, Ingestion{Name: "copied", Words: words})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(CopyThreshold+1), res.Words)
+		require.Equal(s.T(), int64(100), res.Terms)
+
+		// Ingesting again adds no terms, but counts occurrences in a new batch
+		res, err = repo.Ingest(ctx, Ingestion{Name: "copied_again", Words: words})
+		require.NoError(s.T(), err)
+		require.Zero(s.T(), res.Terms)
+		require.Equal(s.T(), int64(100), res.Occurrences)
+	})
+
+	s.Run("ingests_words_without_batch", func() {
+		res, err := repo.Ingest(ctx, Ingestion{Words: []IngestedWord{{Value: "unbatched"}, {Value: "unbatched"}}})
+		require.NoError(s.T(), err)
+		require.False(s.T(), res.WordBatchID.Valid)
+		require.Equal(s.T(), int64(2), res.Words)
+		require.Equal(s.T(), int64(1), res.Occurrences)
+	})
+
+	s.Run("rolls_back_on_error", func() {
+		// Empty phrases violate a check constraint after words are inserted
+		_, err := repo.Ingest(ctx, Ingestion{
+			Name:    "rolled_back",
+			Words:   []IngestedWord{{Value: "rolledbackword"}},
+			Phrases: []IngestedPhrase{{Value: ""}},
+		})
+		require.Error(s.T(), err)
+
+		batches, err := repo.ListBatches(ctx, ListBatchesParams{Limit: 10000})
+		require.NoError(s.T(), err)
+		require.False(s.T(), slices.ContainsFunc(batches, func(row ListBatchesRow) bool {
+			return row.Name == "rolled_back"
+		}))
+		rows, err := repo.SearchWords(ctx, SearchWordsParams{Limit: 10, Query: "rolledbackword"})
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), rows)
+	})
+
+	s.Run("ingests_posting_with_document", func() {
+		res, err := repo.Ingest(ctx, Ingestion{
+			Posting: &CreatePostingParams{Title: "Ingested posting", RawText: "ingestedposting"},
+			Words:   []IngestedWord{{Value: "ingestedposting"}},
+			Phrases: []IngestedPhrase{{Value: "ingestedposting"}},
+			Document: &IngestedDocument{
+				Image:    &CreateImageParams{Hash: "ingestedpostinghash", Format: "png", Filename: "posting.png"},
+				Document: CreateOcrDocumentParams{Text: "ingestedposting", Engine: "tesseract", Settings: []byte("{}")},
+			},
+		})
+		require.NoError(s.T(), err)
+		require.True(s.T(), res.PostingID.Valid)
+		require.True(s.T(), res.DocumentID.Valid)
+
+		posting, err := repo.GetPosting(ctx, res.PostingID.Int64)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), PostingBatchName(posting.ID), posting.WordBatchName.String)
+		require.Equal(s.T(), PostingBatchName(posting.ID), posting.PhraseBatchName.String)
+		docs, err := repo.ListOcrDocuments(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), docs, 1)
+		require.True(s.T(), docs[0].ImageID.Valid)
+	})
+
+	s.Run("rolls_back_posting_on_document_error", func() {
+		// Settings of the document aren't JSON
+		_, err := repo.Ingest(ctx, Ingestion{
+			Posting:  &CreatePostingParams{Title: "Rolled back posting", Company: "rolledbackcompany"},
+			Words:    []IngestedWord{{Value: "rolledbackposting"}},
+			Document: &IngestedDocument{Document: CreateOcrDocumentParams{Engine: "text", Settings: []byte("{")}},
+		})
+		require.Error(s.T(), err)
+
+		postings, err := repo.ListPostings(ctx, ListPostingsParams{Limit: 10, Company: pgtype.Text{String: "rolledbackcompany", Valid: true}})
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), postings)
+	})
+
+	s.Run("rejects_document_without_posting", func() {
+		_, err := repo.Ingest(ctx, Ingestion{Document: &IngestedDocument{}})
+		require.ErrorIs(s.T(), err, ErrDocumentWithoutPosting)
+	})
+}
+
+func (s *DatabaseTestSuite) TestSoftDeleteQueries() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	q := New(conn)
+
+	s.Run("deletes_and_restores_word_batch", func() {
+		batch, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "soft_deleted",
+			Words:     []string{"softgo", "softsql"},
+			Lemmas:    []string{"", ""},
+			Languages: []string{"en", "en"},
+		})
+		require.NoError(s.T(), err)
+
+		id, err := q.DeleteWordBatch(ctx, batch.BatchID.Int64)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), batch.BatchID.Int64, id)
+		words, err := q.ListWordsByBatchName(ctx, "soft_deleted")
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), words)
+
+		_, err = q.DeleteWordBatch(ctx, batch.BatchID.Int64)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+
+		_, err = q.RestoreWordBatch(ctx, batch.BatchID.Int64)
+		require.NoError(s.T(), err)
+		words, err = q.ListWordsByBatchName(ctx, "soft_deleted")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), words, 2)
+	})
+
+	s.Run("deletes_and_restores_word", func() {
+		row, err := q.CreateWord(ctx, CreateWordParams{Value: "softword", Language: "en"})
+		require.NoError(s.T(), err)
+
+		_, err = q.DeleteWord(ctx, row.ID)
+		require.NoError(s.T(), err)
+		_, err = q.DeleteWord(ctx, row.ID)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+
+		_, err = q.RestoreWord(ctx, row.ID)
+		require.NoError(s.T(), err)
+		_, err = q.RestoreWord(ctx, row.ID)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+	})
+
+	s.Run("restores_posting_with_batches", func() {
+		posting, err := q.CreatePosting(ctx, CreatePostingParams{Title: "Soft Developer"})
+		require.NoError(s.T(), err)
+		postingID := pgtype.Int8{Int64: posting.ID, Valid: true}
+		batch, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      "soft_posting_words",
+			Words:     []string{"softposting"},
+			Lemmas:    []string{""},
+			Languages: []string{"en"},
+			PostingID: postingID,
+		})
+		require.NoError(s.T(), err)
+
+		_, err = q.DeletePosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+
+		// Batches of deleted postings are restored with their posting
+		_, err = q.RestoreWordBatch(ctx, batch.BatchID.Int64)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+
+		_, err = q.RestorePosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		row, err := q.GetPosting(ctx, posting.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), batch.BatchID, row.WordBatchID)
+		words, err := q.ListWordsByBatchName(ctx, "soft_posting_words")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), words, 1)
+	})
+
+	s.Run("purges_deleted_rows", func() {
+		batch, err := q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+			Name:      "soft_purged",
+			Phrases:   []string{"Purged phrase"},
+			Languages: []string{"en"},
+		})
+		require.NoError(s.T(), err)
+		_, err = q.DeletePhraseBatch(ctx, batch.BatchID.Int64)
+		require.NoError(s.T(), err)
+
+		row, err := q.PurgeDeleted(ctx, pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true})
+		require.NoError(s.T(), err)
+		require.Zero(s.T(), row.PhraseBatches)
+
+		row, err = q.PurgeDeleted(ctx, pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true})
+		require.NoError(s.T(), err)
+		require.GreaterOrEqual(s.T(), row.PhraseBatches, int64(1))
+		require.GreaterOrEqual(s.T(), row.Phrases, int64(1))
+
+		_, err = q.RestorePhraseBatch(ctx, batch.BatchID.Int64)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+	})
+}
+
+func (s *DatabaseTestSuite) TestBatchQueries() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	q := New(conn)
+
+	createWords := func(name string, words ...string) int64 {
+		lemmas := make([]string, len(words))
+		languages := make([]string, len(words))
+		row, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+			Name:      name,
+			Location:  "Warszawa",
+			Words:     words,
+			Lemmas:    lemmas,
+			Languages: languages,
+		})
+		require.NoError(s.T(), err)
+
+		return row.BatchID.Int64
+	}
+
+	s.Run("lists_and_gets_batches", func() {
+		id := createWords("managed_words", "managedgo", "managedgo", "managedsql")
+		phrases, err := q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+			Name:      "managed_phrases",
+			Phrases:   []string{"Managed phrase", "Managed phrase"},
+			Languages: []string{"en", "en"},
+		})
+		require.NoError(s.T(), err)
+
+		rows, err := q.ListBatches(ctx, ListBatchesParams{
+			Limit:  10,
+			Prefix: pgtype.Text{String: "managed_", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 2)
+
+		rows, err = q.ListBatches(ctx, ListBatchesParams{
+			Limit:     10,
+			Kind:      pgtype.Text{String: "phrases", Valid: true},
+			Prefix:    pgtype.Text{String: "managed_", Valid: true},
+			CreatedTo: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), rows)
+
+		batch, err := q.GetWordBatch(ctx, id)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(2), batch.Items)
+		require.Equal(s.T(), int64(3), batch.Total)
+		require.Equal(s.T(), "Warszawa", batch.Location)
+		words, err := q.ListBatchWords(ctx, id)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), words, 2)
+		require.Equal(s.T(), "managedgo", words[0].Value)
+
+		phraseBatch, err := q.GetPhraseBatch(ctx, phrases.BatchID.Int64)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), phraseBatch.Items)
+		require.Equal(s.T(), int64(2), phraseBatch.Total)
+	})
+
+	s.Run("renames_batches", func() {
+		id := createWords("unnamed_words", "renamedgo")
+		_, err := q.RenameWordBatch(ctx, RenameWordBatchParams{ID: id, Name: "renamed_words"})
+		require.NoError(s.T(), err)
+		batch, err := q.GetWordBatch(ctx, id)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "renamed_words", batch.Name)
+
+		a, err := q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{Name: "renamed_a", Phrases: []string{"A"}, Languages: []string{""}})
+		require.NoError(s.T(), err)
+		_, err = q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{Name: "renamed_b", Phrases: []string{"B"}, Languages: []string{""}})
+		require.NoError(s.T(), err)
+		_, err = q.RenamePhraseBatch(ctx, RenamePhraseBatchParams{ID: a.BatchID.Int64, Name: "renamed_b"})
+		require.Error(s.T(), err)
+	})
+
+	s.Run("merges_word_batches", func() {
+		target := createWords("merge_target", "mergego", "mergesql")
+		source := createWords("merge_source", "mergego", "mergego", "mergerust")
+
+		moved, err := q.MergeWordBatches(ctx, MergeWordBatchesParams{Target: target, Source: source})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(2), moved)
+
+		words, err := q.ListBatchWords(ctx, target)
+		require.NoError(s.T(), err)
+		counts := make(map[string]int32)
+		for _, w := range words {
+			counts[w.Value] = w.Count
+		}
+		require.Equal(s.T(), map[string]int32{"mergego": 3, "mergesql": 1, "mergerust": 1}, counts)
+		batch, err := q.GetWordBatch(ctx, target)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(5), batch.Total)
+
+		_, err = q.GetWordBatch(ctx, source)
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+		_, err = q.MergeWordBatches(ctx, MergeWordBatchesParams{Target: target, Source: source})
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+	})
+
+	s.Run("splits_batches", func() {
+		id := createWords("split_source", "splitgo", "SplitReact", "SplitReact")
+
+		row, err := q.SplitWordBatch(ctx, SplitWordBatchParams{ID: id, Name: "split_words", Values: []string{"splitreact"}})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), row.Moved)
+
+		batch, err := q.GetWordBatch(ctx, row.ID)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), "Warszawa", batch.Location)
+		require.Equal(s.T(), int64(2), batch.Total)
+		batch, err = q.GetWordBatch(ctx, id)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), batch.Total)
+
+		phrases, err := q.CreatePhrasesBatch(ctx, CreatePhrasesBatchParams{
+			Name:      "split_phrases_source",
+			Phrases:   []string{"Kept", "Moved"},
+			Languages: []string{"", ""},
+		})
+		require.NoError(s.T(), err)
+		split, err := q.SplitPhraseBatch(ctx, SplitPhraseBatchParams{
+			ID:     phrases.BatchID.Int64,
+			Name:   "split_phrases",
+			Values: []string{"Moved"},
+		})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), split.Moved)
+		moved, err := q.ListBatchPhrases(ctx, split.ID)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), moved, 1)
+		require.Equal(s.T(), "Moved", moved[0].Value)
+	})
+}
+
+func (s *DatabaseTestSuite) TestTagAndCollectionQueries() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	q := New(conn)
+
+	tagged, err := q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+		Name:      "tagged_words",
+		Words:     []string{"taggedgo", "taggedgo"},
+		Lemmas:    []string{"", ""},
+		Languages: []string{"", ""},
+	})
+	require.NoError(s.T(), err)
+	_, err = q.CreateWordsBatch(ctx, CreateWordsBatchParams{
+		Name:      "untagged_words",
+		Words:     []string{"untaggedgo"},
+		Lemmas:    []string{""},
+		Languages: []string{""},
+	})
+	require.NoError(s.T(), err)
+	batch := pgtype.Int8{Int64: tagged.BatchID.Int64, Valid: true}
+
+	s.Run("tags_are_created_on_first_use", func() {
+		_, err := q.AddTagMember(ctx, AddTagMemberParams{Name: "backend", WordBatchID: batch})
+		require.NoError(s.T(), err)
+		_, err = q.AddTagMember(ctx, AddTagMemberParams{Name: "backend", WordBatchID: batch})
+		require.NoError(s.T(), err)
+
+		tag, err := q.GetTag(ctx, "backend")
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), int64(1), tag.WordBatches)
+
+		members, err := q.ListTagMembers(ctx, "backend")
+		require.NoError(s.T(), err)
+		require.Len(s.T(), members, 1)
+		require.Equal(s.T(), "words", members[0].Type)
+		require.Equal(s.T(), "tagged_words", members[0].Name)
+	})
+
+	s.Run("collections_must_exist", func() {
+		_, err := q.AddCollectionMember(ctx, AddCollectionMemberParams{Name: "missing", WordBatchID: batch})
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+
+		_, err = q.CreateCollection(ctx, CreateCollectionParams{Name: "tracked", Description: "Tracked batches"})
+		require.NoError(s.T(), err)
+		_, err = q.AddCollectionMember(ctx, AddCollectionMemberParams{Name: "tracked", WordBatchID: batch})
+		require.NoError(s.T(), err)
+
+		rows, err := q.ListCollections(ctx, ListCollectionsParams{Limit: 10})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+		require.Equal(s.T(), "Tracked batches", rows[0].Description)
+	})
+
+	s.Run("filters_frequencies", func() {
+		rows, err := q.ListWordFrequencies(ctx, ListWordFrequenciesParams{
+			Limit: 10,
+			Tag:   pgtype.Text{String: "backend", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), []ListWordFrequenciesRow{{Value: "taggedgo", Total: 2}}, rows)
+
+		rows, err = q.ListWordFrequencies(ctx, ListWordFrequenciesParams{
+			Limit:      10,
+			Collection: pgtype.Text{String: "tracked", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), rows, 1)
+	})
+
+	s.Run("removes_members", func() {
+		_, err := q.RemoveTagMember(ctx, RemoveTagMemberParams{Name: "backend", WordBatchID: batch})
+		require.NoError(s.T(), err)
+		_, err = q.RemoveTagMember(ctx, RemoveTagMemberParams{Name: "backend", WordBatchID: batch})
+		require.ErrorIs(s.T(), err, pgx.ErrNoRows)
+
+		rows, err := q.ListWordFrequencies(ctx, ListWordFrequenciesParams{
+			Limit: 10,
+			Tag:   pgtype.Text{String: "backend", Valid: true},
+		})
+		require.NoError(s.T(), err)
+		require.Empty(s.T(), rows)
+
+		_, err = q.DeleteCollection(ctx, "tracked")
+		require.NoError(s.T(), err)
+	})
+}
+
+func (s *DatabaseTestSuite) TestMigrator() {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, s.connStr)
+	require.NoError(s.T(), err)
+	defer conn.Close(ctx)
+
+	// Migrations are run against a separate database to keep the suite schema intact.
+	_, err = conn.Exec(ctx, "CREATE DATABASE piccrack_migrations")
+	require.NoError(s.T(), err)
+	u, err := url.Parse(s.connStr)
+	require.NoError(s.T(), err)
+	u.Path = "/piccrack_migrations"
+
+	m, err := NewMigrator(u.String())
+	require.NoError(s.T(), err)
+	defer m.Close()
+
+	s.Run("applies_embedded_migrations", func() {
+		status, err := m.Status()
+		require.NoError(s.T(), err)
+		require.Zero(s.T(), status.Version)
+		require.Len(s.T(), status.Pending, int(status.Latest))
+
+		require.NoError(s.T(), m.Up())
+		require.NoError(s.T(), m.Up())
+
+		status, err = m.Status()
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), m.Latest(), status.Version)
+		require.False(s.T(), status.Dirty)
+		require.Empty(s.T(), status.Pending)
+	})
+
+	s.Run("reverts_migrations", func() {
+		require.NoError(s.T(), m.Down(2))
+		v, _, err := m.Version()
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), m.Latest()-2, v)
+
+		require.NoError(s.T(), m.Up())
+		v, _, err = m.Version()
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), m.Latest(), v)
+	})
+
+	s.Run("refuses_newer_schema", func() {
+		mconn, err := pgx.Connect(ctx, u.String())
+		require.NoError(s.T(), err)
+		defer mconn.Close(ctx)
+
+		_, err = mconn.Exec(ctx, "UPDATE schema_migrations SET version = version + 1")
+		require.NoError(s.T(), err)
+
+		require.ErrorIs(s.T(), m.Check(), ErrSchemaTooNew)
+		require.ErrorIs(s.T(), m.Up(), ErrSchemaTooNew)
+		require.ErrorIs(s.T(), m.Down(1), ErrSchemaTooNew)
+
+		_, err = mconn.Exec(ctx, "UPDATE schema_migrations SET version = version - 1")
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), m.Check())
+	})
+
+	s.Run("reverts_all_migrations", func() {
+		require.NoError(s.T(), m.Down(0))
+		status, err := m.Status()
+		require.NoError(s.T(), err)
+		require.Zero(s.T(), status.Version)
+		require.Len(s.T(), status.Pending, int(m.Latest()))
+	})
 }
 
 // Helper functions remain the same
@@ -238,20 +1362,14 @@ func testWords(t *testing.T) []string {
 	return values
 }
 
-func applyMigrations(ctx context.Context, url string) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	m, err := migrate.New("file://"+filepath.Join("testdata", "migrations"), url)
+func applyMigrations(dsn string) error {
+	m, err := NewMigrator(dsn)
 	if err != nil {
-		return fmt.Errorf("new migrate: %w", err)
+		return fmt.Errorf("new migrator: %w", err)
 	}
+	defer m.Close()
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("up migrations: %w", err)
-	}
-
-	return nil
+	return m.Up()
 }
 
 type testConfig struct {
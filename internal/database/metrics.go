@@ -0,0 +1,77 @@
+package database
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports statistics of a connection pool to Prometheus.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns         *prometheus.Desc
+	idleConns             *prometheus.Desc
+	constructingConns     *prometheus.Desc
+	totalConns            *prometheus.Desc
+	maxConns              *prometheus.Desc
+	acquiresTotal         *prometheus.Desc
+	canceledAcquiresTotal *prometheus.Desc
+	emptyAcquiresTotal    *prometheus.Desc
+	acquireSecondsTotal   *prometheus.Desc
+	newConnsTotal         *prometheus.Desc
+	lifetimeDestroysTotal *prometheus.Desc
+	idleDestroysTotal     *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*PoolCollector)(nil)
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("db_pool_"+name, help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool: pool,
+
+		acquiredConns:         desc("acquired_conns", "Number of currently acquired connections."),
+		idleConns:             desc("idle_conns", "Number of currently idle connections."),
+		constructingConns:     desc("constructing_conns", "Number of connections being constructed."),
+		totalConns:            desc("total_conns", "Number of all connections in the pool."),
+		maxConns:              desc("max_conns", "Maximum size of the pool."),
+		acquiresTotal:         desc("acquires_total", "Number of successful acquires from the pool."),
+		canceledAcquiresTotal: desc("canceled_acquires_total", "Number of acquires canceled by a context."),
+		emptyAcquiresTotal:    desc("empty_acquires_total", "Number of acquires which waited for a connection because the pool was empty."),
+		acquireSecondsTotal:   desc("acquire_seconds_total", "Total time spent on successful acquires."),
+		newConnsTotal:         desc("new_conns_total", "Number of new connections opened."),
+		lifetimeDestroysTotal: desc("max_lifetime_destroys_total", "Number of connections closed because they exceeded max lifetime."),
+		idleDestroysTotal:     desc("max_idle_destroys_total", "Number of connections closed because they exceeded max idle time."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	gauge := func(d *prometheus.Desc, v int32) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, float64(v))
+	}
+	counter := func(d *prometheus.Desc, v int64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v))
+	}
+
+	gauge(c.acquiredConns, s.AcquiredConns())
+	gauge(c.idleConns, s.IdleConns())
+	gauge(c.constructingConns, s.ConstructingConns())
+	gauge(c.totalConns, s.TotalConns())
+	gauge(c.maxConns, s.MaxConns())
+	counter(c.acquiresTotal, s.AcquireCount())
+	counter(c.canceledAcquiresTotal, s.CanceledAcquireCount())
+	counter(c.emptyAcquiresTotal, s.EmptyAcquireCount())
+	ch <- prometheus.MustNewConstMetric(c.acquireSecondsTotal, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	counter(c.newConnsTotal, s.NewConnsCount())
+	counter(c.lifetimeDestroysTotal, s.MaxLifetimeDestroyCount())
+	counter(c.idleDestroysTotal, s.MaxIdleDestroyCount())
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kndrad/piccrack/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolCollector(t *testing.T) {
+	pgcfg, err := poolConfig(config.DatabaseConfig{
+		User: "piccrack",
+		Host: "localhost",
+		Port: "5432",
+		Name: "piccrack",
+		Pool: config.PoolConfig{MaxConns: 7},
+	})
+	require.NoError(t, err)
+	// Pool without idle connections doesn't connect until a connection is acquired.
+	pool, err := pgxpool.NewWithConfig(context.Background(), pgcfg)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c := NewPoolCollector(pool)
+	require.Equal(t, 12, testutil.CollectAndCount(c))
+
+	expected := `
+# HELP db_pool_max_conns Maximum size of the pool.
+# TYPE db_pool_max_conns gauge
+db_pool_max_conns 7
+# HELP db_pool_acquired_conns Number of currently acquired connections.
+# TYPE db_pool_acquired_conns gauge
+db_pool_acquired_conns 0
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "db_pool_max_conns", "db_pool_acquired_conns"))
+}
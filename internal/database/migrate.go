@@ -0,0 +1,222 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // pgx5 database driver
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/kndrad/piccrack/db/migrations"
+)
+
+var ErrSchemaTooNew = errors.New("database schema is newer than supported by this binary")
+
+// MigrationStatus is a state of database schema compared to embedded migrations.
+type MigrationStatus struct {
+	// Version of the last applied migration, 0 if none were applied.
+	Version uint `json:"version"`
+	// Dirty is true if the last migration failed and has to be fixed by hand.
+	Dirty bool `json:"dirty"`
+	// Latest is a version of the last embedded migration.
+	Latest uint `json:"latest"`
+	// Pending migrations which aren't applied yet.
+	Pending []uint `json:"pending"`
+}
+
+// Migrator applies migrations embedded in the binary.
+type Migrator struct {
+	m        *migrate.Migrate
+	versions []uint
+}
+
+// NewMigrator returns migrator of database at dsn, a postgres connection string.
+// Migrator must be closed once no longer used.
+func NewMigrator(dsn string) (*Migrator, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse dsn: %w", err)
+	}
+	// Migrations are run with the pgx driver registered under pgx5 scheme.
+	u.Scheme = "pgx5"
+
+	return NewSourceMigrator(migrations.FS, u.String())
+}
+
+// NewSourceMigrator returns migrator applying migrations of fsys to database at
+// databaseURL, whose scheme selects a registered golang-migrate driver.
+func NewSourceMigrator(fsys fs.FS, databaseURL string) (*Migrator, error) {
+	versions, err := migrationVersions(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrations source: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("new migrate: %w", err)
+	}
+
+	return &Migrator{m: m, versions: versions}, nil
+}
+
+// migrationVersions returns versions of migrations in fsys in ascending order.
+func migrationVersions(fsys fs.FS) ([]uint, error) {
+	src, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrations source: %w", err)
+	}
+	defer src.Close()
+
+	v, err := src.First()
+	if err != nil {
+		return nil, fmt.Errorf("first migration: %w", err)
+	}
+	versions := []uint{v}
+	for {
+		v, err = src.Next(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("next migration: %w", err)
+		}
+		versions = append(versions, v)
+	}
+}
+
+// LatestVersion returns version of the last embedded migration.
+func LatestVersion() (uint, error) {
+	versions, err := migrationVersions(migrations.FS)
+	if err != nil {
+		return 0, err
+	}
+
+	return versions[len(versions)-1], nil
+}
+
+// SchemaVersion returns version of the last migration applied to database of db and
+// whether it failed, read within the transaction or connection of db. Version is 0
+// if no migrations were applied.
+func SchemaVersion(ctx context.Context, db DBTX) (uint, bool, error) {
+	var exists bool
+	if err := db.QueryRow(ctx, "SELECT TO_REGCLASS('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, false, fmt.Errorf("find schema migrations: %w", err)
+	}
+	if !exists {
+		return 0, false, nil
+	}
+
+	var (
+		v     int64
+		dirty bool
+	)
+	err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("schema version: %w", err)
+	}
+
+	return uint(v), dirty, nil
+}
+
+// Latest returns version of the last embedded migration.
+func (m *Migrator) Latest() uint {
+	return m.versions[len(m.versions)-1]
+}
+
+// Version returns version of the last applied migration and whether it failed.
+// Version is 0 if no migrations were applied.
+func (m *Migrator) Version() (uint, bool, error) {
+	v, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("version: %w", err)
+	}
+
+	return v, dirty, nil
+}
+
+// Status returns state of database schema.
+func (m *Migrator) Status() (MigrationStatus, error) {
+	v, dirty, err := m.Version()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	status := MigrationStatus{
+		Version: v,
+		Dirty:   dirty,
+		Latest:  m.Latest(),
+		Pending: make([]uint, 0),
+	}
+	for _, mv := range m.versions {
+		if mv > v {
+			status.Pending = append(status.Pending, mv)
+		}
+	}
+
+	return status, nil
+}
+
+// Check returns ErrSchemaTooNew if database schema was migrated by a newer binary.
+func (m *Migrator) Check() error {
+	v, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if v > m.Latest() {
+		return fmt.Errorf("%w: version %d, latest supported %d", ErrSchemaTooNew, v, m.Latest())
+	}
+
+	return nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	if err := m.Check(); err != nil {
+		return err
+	}
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("up migrations: %w", err)
+	}
+
+	return nil
+}
+
+// Down reverts steps last applied migrations, or all of them if steps isn't positive.
+func (m *Migrator) Down(steps int) error {
+	if err := m.Check(); err != nil {
+		return err
+	}
+
+	var err error
+	if steps > 0 {
+		err = m.m.Steps(-steps)
+	} else {
+		err = m.m.Down()
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("down migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+
+	return errors.Join(srcErr, dbErr)
+}
@@ -8,12 +8,81 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type BatchStat struct {
+	BatchID   int64              `json:"batch_id"`
+	Terms     int64              `json:"terms"`
+	Total     int64              `json:"total"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Collection struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type CollectionMember struct {
+	CollectionID  int64              `json:"collection_id"`
+	PostingID     pgtype.Int8        `json:"posting_id"`
+	WordBatchID   pgtype.Int8        `json:"word_batch_id"`
+	PhraseBatchID pgtype.Int8        `json:"phrase_batch_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type DailyTermStat struct {
+	Day      pgtype.Date `json:"day"`
+	TermID   int64       `json:"term_id"`
+	Total    int64       `json:"total"`
+	Postings int64       `json:"postings"`
+}
+
+type Image struct {
+	ID        int64              `json:"id"`
+	Hash      string             `json:"hash"`
+	Width     int32              `json:"width"`
+	Height    int32              `json:"height"`
+	Format    string             `json:"format"`
+	SizeBytes int64              `json:"size_bytes"`
+	Filename  string             `json:"filename"`
+	Content   []byte             `json:"content"`
+	Path      pgtype.Text        `json:"path"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Occurrence struct {
+	ID        int64              `json:"id"`
+	TermID    int64              `json:"term_id"`
+	BatchID   pgtype.Int8        `json:"batch_id"`
+	Count     int32              `json:"count"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type OcrDocument struct {
+	ID            int64              `json:"id"`
+	PostingID     pgtype.Int8        `json:"posting_id"`
+	ImageID       pgtype.Int8        `json:"image_id"`
+	Text          string             `json:"text"`
+	Engine        string             `json:"engine"`
+	EngineVersion string             `json:"engine_version"`
+	Languages     []string           `json:"languages"`
+	Settings      []byte             `json:"settings"`
+	Confidence    pgtype.Float8      `json:"confidence"`
+	DurationMs    int64              `json:"duration_ms"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+}
+
 type Phrase struct {
 	ID        int64              `json:"id"`
 	Value     string             `json:"value"`
 	BatchID   pgtype.Int8        `json:"batch_id"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Language  pgtype.Text        `json:"language"`
+	Search    interface{}        `json:"search"`
 }
 
 type PhraseBatch struct {
@@ -21,14 +90,78 @@ type PhraseBatch struct {
 	Name      string             `json:"name"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	PostingID pgtype.Int8        `json:"posting_id"`
 }
 
-type Word struct {
+type PhraseBatchCollection struct {
+	BatchID    int64  `json:"batch_id"`
+	Collection string `json:"collection"`
+}
+
+type PhraseBatchTag struct {
+	BatchID int64  `json:"batch_id"`
+	Tag     string `json:"tag"`
+}
+
+type Posting struct {
+	ID         int64              `json:"id"`
+	Title      string             `json:"title"`
+	Company    string             `json:"company"`
+	SourceUrl  string             `json:"source_url"`
+	CapturedAt pgtype.Timestamptz `json:"captured_at"`
+	ImageHash  pgtype.Text        `json:"image_hash"`
+	RawText    string             `json:"raw_text"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Salary struct {
+	ID         int64              `json:"id"`
+	BatchID    int64              `json:"batch_id"`
+	MinAmount  float64            `json:"min_amount"`
+	MaxAmount  float64            `json:"max_amount"`
+	Currency   string             `json:"currency"`
+	Period     string             `json:"period"`
+	MonthlyMin float64            `json:"monthly_min"`
+	MonthlyMax float64            `json:"monthly_max"`
+	Contract   pgtype.Text        `json:"contract"`
+	Basis      pgtype.Text        `json:"basis"`
+	Seniority  pgtype.Text        `json:"seniority"`
+	Raw        string             `json:"raw"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type Tag struct {
 	ID        int64              `json:"id"`
-	Value     string             `json:"value"`
+	Name      string             `json:"name"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
-	BatchID   pgtype.Int8        `json:"batch_id"`
+}
+
+type TagMember struct {
+	TagID         int64              `json:"tag_id"`
+	PostingID     pgtype.Int8        `json:"posting_id"`
+	WordBatchID   pgtype.Int8        `json:"word_batch_id"`
+	PhraseBatchID pgtype.Int8        `json:"phrase_batch_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type TermStat struct {
+	TermID    int64              `json:"term_id"`
+	Total     int64              `json:"total"`
+	Postings  int64              `json:"postings"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Vocabulary struct {
+	ID         int64              `json:"id"`
+	Raw        string             `json:"raw"`
+	Normalized string             `json:"normalized"`
+	Lemma      string             `json:"lemma"`
+	Language   string             `json:"language"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
 }
 
 type WordBatch struct {
@@ -36,4 +169,18 @@ type WordBatch struct {
 	Name      string             `json:"name"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Seniority pgtype.Text        `json:"seniority"`
+	Location  pgtype.Text        `json:"location"`
+	WorkMode  pgtype.Text        `json:"work_mode"`
+	PostingID pgtype.Int8        `json:"posting_id"`
+}
+
+type WordBatchCollection struct {
+	BatchID    int64  `json:"batch_id"`
+	Collection string `json:"collection"`
+}
+
+type WordBatchTag struct {
+	BatchID int64  `json:"batch_id"`
+	Tag     string `json:"tag"`
 }
@@ -13,22 +13,25 @@ import (
 
 const createPhrasesBatch = `-- name: CreatePhrasesBatch :one
 WITH batch AS (
-    INSERT INTO phrase_batches (name)
-    VALUES ($1)
+    INSERT INTO phrase_batches (name, posting_id)
+    VALUES ($1, $4::bigint)
     RETURNING id
 )
 
-INSERT INTO phrases (value, batch_id)
+INSERT INTO phrases (value, language, batch_id)
 SELECT
-    phrase_value,
+    phrase.value,
+    NULLIF(($2::text [])[phrase.position], ''),
     (SELECT id FROM batch)
-FROM UNNEST($2::text []) AS phrase_value
+FROM UNNEST($3::text []) WITH ORDINALITY AS phrase (value, position)
 RETURNING id, batch_id
 `
 
 type CreatePhrasesBatchParams struct {
-	Name    string   `json:"name"`
-	Phrases []string `json:"phrases"`
+	Name      string      `json:"name"`
+	Languages []string    `json:"languages"`
+	Phrases   []string    `json:"phrases"`
+	PostingID pgtype.Int8 `json:"posting_id"`
 }
 
 type CreatePhrasesBatchRow struct {
@@ -37,8 +40,99 @@ type CreatePhrasesBatchRow struct {
 }
 
 func (q *Queries) CreatePhrasesBatch(ctx context.Context, arg CreatePhrasesBatchParams) (CreatePhrasesBatchRow, error) {
-	row := q.db.QueryRow(ctx, createPhrasesBatch, arg.Name, arg.Phrases)
+	row := q.db.QueryRow(ctx, createPhrasesBatch,
+		arg.Name,
+		arg.Languages,
+		arg.Phrases,
+		arg.PostingID,
+	)
 	var i CreatePhrasesBatchRow
 	err := row.Scan(&i.ID, &i.BatchID)
 	return i, err
 }
+
+const listPhraseCountsInSet = `-- name: ListPhraseCountsInSet :many
+SELECT
+    LOWER(phrases.value)::text AS value,
+    COUNT(*) AS total,
+    COUNT(DISTINCT phrases.batch_id) AS postings
+FROM phrases
+INNER JOIN phrase_batches AS pb ON phrases.batch_id = pb.id
+WHERE
+    phrases.deleted_at IS NULL
+    AND pb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY($1::text []), 0) = 0
+        OR pb.name = ANY($1::text [])
+    )
+    AND (
+        $2::timestamptz IS NULL
+        OR pb.created_at >= $2::timestamptz
+    )
+    AND (
+        $3::timestamptz IS NULL
+        OR pb.created_at < $3::timestamptz
+    )
+    AND (
+        $4::text IS NULL
+        OR phrases.language = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_tags.batch_id FROM phrase_batch_tags
+            WHERE phrase_batch_tags.tag = $5::text
+        )
+    )
+    AND (
+        $6::text IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_collections.batch_id FROM phrase_batch_collections
+            WHERE phrase_batch_collections.collection = $6::text
+        )
+    )
+GROUP BY LOWER(phrases.value)
+ORDER BY LOWER(phrases.value) ASC
+`
+
+type ListPhraseCountsInSetParams struct {
+	Batches     []string           `json:"batches"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	Language    pgtype.Text        `json:"language"`
+	Tag         pgtype.Text        `json:"tag"`
+	Collection  pgtype.Text        `json:"collection"`
+}
+
+type ListPhraseCountsInSetRow struct {
+	Value    string `json:"value"`
+	Total    int64  `json:"total"`
+	Postings int64  `json:"postings"`
+}
+
+func (q *Queries) ListPhraseCountsInSet(ctx context.Context, arg ListPhraseCountsInSetParams) ([]ListPhraseCountsInSetRow, error) {
+	rows, err := q.db.Query(ctx, listPhraseCountsInSet,
+		arg.Batches,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Language,
+		arg.Tag,
+		arg.Collection,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPhraseCountsInSetRow
+	for rows.Next() {
+		var i ListPhraseCountsInSetRow
+		if err := rows.Scan(&i.Value, &i.Total, &i.Postings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
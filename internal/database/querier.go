@@ -6,17 +6,95 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	// Adding a member again is a no-op.
+	AddCollectionMember(ctx context.Context, arg AddCollectionMemberParams) (int64, error)
+	// Tags are created on first use. Adding a member again is a no-op.
+	AddTagMember(ctx context.Context, arg AddTagMemberParams) (int64, error)
+	CountWordBatchesInSet(ctx context.Context, arg CountWordBatchesInSetParams) (int64, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (int64, error)
+	CreateImage(ctx context.Context, arg CreateImageParams) (CreateImageRow, error)
+	CreateOcrDocument(ctx context.Context, arg CreateOcrDocumentParams) (CreateOcrDocumentRow, error)
 	CreatePhrasesBatch(ctx context.Context, arg CreatePhrasesBatchParams) (CreatePhrasesBatchRow, error)
-	CreateWord(ctx context.Context, value string) (CreateWordRow, error)
+	CreatePosting(ctx context.Context, arg CreatePostingParams) (CreatePostingRow, error)
+	CreateSalary(ctx context.Context, arg CreateSalaryParams) (CreateSalaryRow, error)
+	CreateTag(ctx context.Context, name string) (int64, error)
+	CreateWord(ctx context.Context, arg CreateWordParams) (CreateWordRow, error)
+	CreateWordBatch(ctx context.Context, arg CreateWordBatchParams) (int64, error)
 	CreateWordsBatch(ctx context.Context, arg CreateWordsBatchParams) (CreateWordsBatchRow, error)
-	ListWordBatches(ctx context.Context, arg ListWordBatchesParams) ([]ListWordBatchesRow, error)
+	DeleteCollection(ctx context.Context, name string) (int64, error)
+	DeletePhraseBatch(ctx context.Context, id int64) (int64, error)
+	DeletePosting(ctx context.Context, id int64) (int64, error)
+	DeletePostingBatches(ctx context.Context, postingID int64) error
+	DeleteTag(ctx context.Context, name string) (int64, error)
+	DeleteWord(ctx context.Context, id int64) (int64, error)
+	DeleteWordBatch(ctx context.Context, id int64) (int64, error)
+	GetCollection(ctx context.Context, name string) (GetCollectionRow, error)
+	GetLatestOcrDocument(ctx context.Context, postingID int64) (GetLatestOcrDocumentRow, error)
+	GetPhraseBatch(ctx context.Context, id int64) (GetPhraseBatchRow, error)
+	GetPosting(ctx context.Context, id int64) (GetPostingRow, error)
+	GetTag(ctx context.Context, name string) (GetTagRow, error)
+	GetWordBatch(ctx context.Context, id int64) (GetWordBatchRow, error)
+	ListBatchPhrases(ctx context.Context, batchID int64) ([]ListBatchPhrasesRow, error)
+	ListBatchWordSets(ctx context.Context, arg ListBatchWordSetsParams) ([]ListBatchWordSetsRow, error)
+	ListBatchWords(ctx context.Context, batchID int64) ([]ListBatchWordsRow, error)
+	// Items are distinct words or phrases of a batch, total counts their occurrences.
+	ListBatches(ctx context.Context, arg ListBatchesParams) ([]ListBatchesRow, error)
+	// Members are postings, word batches (words) or phrase batches (phrases) which aren't deleted.
+	ListCollectionMembers(ctx context.Context, name string) ([]ListCollectionMembersRow, error)
+	ListCollections(ctx context.Context, arg ListCollectionsParams) ([]ListCollectionsRow, error)
+	ListLemmaFrequencies(ctx context.Context, arg ListLemmaFrequenciesParams) ([]ListLemmaFrequenciesRow, error)
+	ListOcrDocuments(ctx context.Context, postingID int64) ([]ListOcrDocumentsRow, error)
+	ListPhraseCountsInSet(ctx context.Context, arg ListPhraseCountsInSetParams) ([]ListPhraseCountsInSetRow, error)
+	ListPostings(ctx context.Context, arg ListPostingsParams) ([]ListPostingsRow, error)
+	ListSalariesByBatchName(ctx context.Context, name string) ([]ListSalariesByBatchNameRow, error)
+	ListSalaryMediansBySeniority(ctx context.Context, contract pgtype.Text) ([]ListSalaryMediansBySeniorityRow, error)
+	ListSalaryMediansBySkill(ctx context.Context, arg ListSalaryMediansBySkillParams) ([]ListSalaryMediansBySkillRow, error)
+	// Members are postings, word batches (words) or phrase batches (phrases) which aren't deleted.
+	ListTagMembers(ctx context.Context, name string) ([]ListTagMembersRow, error)
+	ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error)
+	ListWordCountsInSet(ctx context.Context, arg ListWordCountsInSetParams) ([]ListWordCountsInSetRow, error)
 	ListWordFrequencies(ctx context.Context, arg ListWordFrequenciesParams) ([]ListWordFrequenciesRow, error)
 	ListWordRankings(ctx context.Context, arg ListWordRankingsParams) ([]ListWordRankingsRow, error)
+	ListWordStatsFrequencies(ctx context.Context, arg ListWordStatsFrequenciesParams) ([]ListWordStatsFrequenciesRow, error)
+	ListWordStatsRankings(ctx context.Context, arg ListWordStatsRankingsParams) ([]ListWordStatsRankingsRow, error)
+	ListWordStatsTrend(ctx context.Context, arg ListWordStatsTrendParams) ([]ListWordStatsTrendRow, error)
+	ListWordTrend(ctx context.Context, arg ListWordTrendParams) ([]ListWordTrendRow, error)
 	ListWords(ctx context.Context, arg ListWordsParams) ([]ListWordsRow, error)
 	ListWordsByBatchName(ctx context.Context, name string) ([]ListWordsByBatchNameRow, error)
+	// Moves phrases of source batch into target batch and deletes source batch.
+	// Returns number of moved phrases.
+	MergePhraseBatches(ctx context.Context, arg MergePhraseBatchesParams) (int64, error)
+	// Moves occurrences and salaries of source batch into target batch and deletes source
+	// batch. Counts of terms in both batches are summed. Returns number of moved occurrences.
+	MergeWordBatches(ctx context.Context, arg MergeWordBatchesParams) (int64, error)
+	// Hard deletes rows soft deleted before time. Rows of purged batches and postings are
+	// deleted with them by foreign keys.
+	PurgeDeleted(ctx context.Context, deletedBefore pgtype.Timestamptz) (PurgeDeletedRow, error)
+	RefreshWordStats(ctx context.Context) error
+	RemoveCollectionMember(ctx context.Context, arg RemoveCollectionMemberParams) (int64, error)
+	RemoveTagMember(ctx context.Context, arg RemoveTagMemberParams) (int64, error)
+	RenamePhraseBatch(ctx context.Context, arg RenamePhraseBatchParams) (int64, error)
+	RenameWordBatch(ctx context.Context, arg RenameWordBatchParams) (int64, error)
+	// Batches of deleted postings are restored with their posting.
+	RestorePhraseBatch(ctx context.Context, id int64) (int64, error)
+	RestorePosting(ctx context.Context, id int64) (int64, error)
+	RestoreWord(ctx context.Context, id int64) (int64, error)
+	// Batches of deleted postings are restored with their posting.
+	RestoreWordBatch(ctx context.Context, id int64) (int64, error)
+	SearchPhrases(ctx context.Context, arg SearchPhrasesParams) ([]SearchPhrasesRow, error)
+	SearchWords(ctx context.Context, arg SearchWordsParams) ([]SearchWordsRow, error)
+	// Moves phrases equal to values into a new batch.
+	SplitPhraseBatch(ctx context.Context, arg SplitPhraseBatchParams) (SplitPhraseBatchRow, error)
+	// Moves occurrences of words normalized to values into a new batch with attributes of
+	// the batch. Salaries stay in the batch.
+	SplitWordBatch(ctx context.Context, arg SplitWordBatchParams) (SplitWordBatchRow, error)
+	UpdatePosting(ctx context.Context, arg UpdatePostingParams) (UpdatePostingRow, error)
+	UpdateWordBatchAttributes(ctx context.Context, arg UpdateWordBatchAttributesParams) (UpdateWordBatchAttributesRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,465 @@
+-- name: DeleteWordBatch :one
+WITH deleted AS (
+    UPDATE word_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE word_batches.id = $1 AND word_batches.deleted_at IS NULL
+    RETURNING word_batches.id
+),
+
+deleted_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        occurrences.batch_id IN (SELECT deleted.id FROM deleted)
+        AND occurrences.deleted_at IS NULL
+),
+
+deleted_salaries AS (
+    UPDATE salaries
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        salaries.batch_id IN (SELECT deleted.id FROM deleted)
+        AND salaries.deleted_at IS NULL
+)
+
+SELECT deleted.id FROM deleted;
+
+-- name: RestoreWordBatch :one
+-- Batches of deleted postings are restored with their posting.
+WITH batch AS (
+    SELECT
+        wb.id,
+        wb.deleted_at
+    FROM word_batches AS wb
+    LEFT JOIN postings ON wb.posting_id = postings.id
+    WHERE
+        wb.id = $1
+        AND wb.deleted_at IS NOT NULL
+        AND postings.deleted_at IS NULL
+),
+
+restored AS (
+    UPDATE word_batches
+    SET deleted_at = NULL
+    FROM batch
+    WHERE word_batches.id = batch.id
+    RETURNING word_batches.id
+),
+
+restored_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = NULL
+    FROM batch
+    WHERE
+        occurrences.batch_id = batch.id
+        AND occurrences.deleted_at = batch.deleted_at
+),
+
+restored_salaries AS (
+    UPDATE salaries
+    SET deleted_at = NULL
+    FROM batch
+    WHERE
+        salaries.batch_id = batch.id
+        AND salaries.deleted_at = batch.deleted_at
+)
+
+SELECT restored.id FROM restored;
+
+-- name: DeletePhraseBatch :one
+WITH deleted AS (
+    UPDATE phrase_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE phrase_batches.id = $1 AND phrase_batches.deleted_at IS NULL
+    RETURNING phrase_batches.id
+),
+
+deleted_phrases AS (
+    UPDATE phrases
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        phrases.batch_id IN (SELECT deleted.id FROM deleted)
+        AND phrases.deleted_at IS NULL
+)
+
+SELECT deleted.id FROM deleted;
+
+-- name: RestorePhraseBatch :one
+-- Batches of deleted postings are restored with their posting.
+WITH batch AS (
+    SELECT
+        pb.id,
+        pb.deleted_at
+    FROM phrase_batches AS pb
+    LEFT JOIN postings ON pb.posting_id = postings.id
+    WHERE
+        pb.id = $1
+        AND pb.deleted_at IS NOT NULL
+        AND postings.deleted_at IS NULL
+),
+
+restored AS (
+    UPDATE phrase_batches
+    SET deleted_at = NULL
+    FROM batch
+    WHERE phrase_batches.id = batch.id
+    RETURNING phrase_batches.id
+),
+
+restored_phrases AS (
+    UPDATE phrases
+    SET deleted_at = NULL
+    FROM batch
+    WHERE
+        phrases.batch_id = batch.id
+        AND phrases.deleted_at = batch.deleted_at
+)
+
+SELECT restored.id FROM restored;
+
+-- name: PurgeDeleted :one
+-- Hard deletes rows soft deleted before time. Rows of purged batches and postings are
+-- deleted with them by foreign keys.
+WITH purged_postings AS (
+    DELETE FROM postings
+    WHERE postings.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING postings.id
+),
+
+purged_word_batches AS (
+    DELETE FROM word_batches
+    WHERE word_batches.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING word_batches.id
+),
+
+purged_phrase_batches AS (
+    DELETE FROM phrase_batches
+    WHERE phrase_batches.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING phrase_batches.id
+),
+
+purged_words AS (
+    DELETE FROM vocabulary
+    WHERE vocabulary.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING vocabulary.id
+),
+
+purged_occurrences AS (
+    DELETE FROM occurrences
+    WHERE occurrences.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING occurrences.id
+),
+
+purged_phrases AS (
+    DELETE FROM phrases
+    WHERE phrases.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING phrases.id
+),
+
+purged_salaries AS (
+    DELETE FROM salaries
+    WHERE salaries.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING salaries.id
+),
+
+purged_documents AS (
+    DELETE FROM ocr_documents
+    WHERE ocr_documents.deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING ocr_documents.id
+)
+
+SELECT
+    (SELECT COUNT(*) FROM purged_postings) AS postings,
+    (SELECT COUNT(*) FROM purged_word_batches) AS word_batches,
+    (SELECT COUNT(*) FROM purged_phrase_batches) AS phrase_batches,
+    (SELECT COUNT(*) FROM purged_words) AS words,
+    (SELECT COUNT(*) FROM purged_occurrences) AS occurrences,
+    (SELECT COUNT(*) FROM purged_phrases) AS phrases,
+    (SELECT COUNT(*) FROM purged_salaries) AS salaries,
+    (SELECT COUNT(*) FROM purged_documents) AS documents;
+
+-- name: ListBatches :many
+-- Items are distinct words or phrases of a batch, total counts their occurrences.
+SELECT
+    batches.kind,
+    batches.id,
+    batches.name,
+    batches.seniority,
+    batches.location,
+    batches.work_mode,
+    batches.posting_id,
+    batches.items,
+    batches.total,
+    batches.created_at
+FROM (
+    SELECT
+        'words'::text AS kind,
+        wb.id,
+        wb.name,
+        COALESCE(wb.seniority, '')::text AS seniority,
+        COALESCE(wb.location, '')::text AS location,
+        COALESCE(wb.work_mode, '')::text AS work_mode,
+        wb.posting_id,
+        COALESCE(bs.terms, 0)::bigint AS items,
+        COALESCE(bs.total, 0)::bigint AS total,
+        wb.created_at
+    FROM word_batches AS wb
+    LEFT JOIN batch_stats AS bs ON wb.id = bs.batch_id
+    WHERE wb.deleted_at IS NULL
+    UNION ALL
+    SELECT
+        'phrases'::text AS kind,
+        pb.id,
+        pb.name,
+        ''::text AS seniority,
+        ''::text AS location,
+        ''::text AS work_mode,
+        pb.posting_id,
+        COUNT(DISTINCT phrases.value)::bigint AS items,
+        COUNT(phrases.id)::bigint AS total,
+        pb.created_at
+    FROM phrase_batches AS pb
+    LEFT JOIN phrases ON pb.id = phrases.batch_id AND phrases.deleted_at IS NULL
+    WHERE pb.deleted_at IS NULL
+    GROUP BY pb.id
+) AS batches
+WHERE
+    (
+        sqlc.narg(kind)::text IS NULL
+        OR batches.kind = sqlc.narg(kind)::text
+    )
+    AND (
+        sqlc.narg(created_from)::timestamptz IS NULL
+        OR batches.created_at >= sqlc.narg(created_from)::timestamptz
+    )
+    AND (
+        sqlc.narg(created_to)::timestamptz IS NULL
+        OR batches.created_at < sqlc.narg(created_to)::timestamptz
+    )
+    AND (
+        sqlc.narg(prefix)::text IS NULL
+        OR STARTS_WITH(batches.name, sqlc.narg(prefix)::text)
+    )
+ORDER BY batches.created_at ASC, batches.kind ASC, batches.id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: GetWordBatch :one
+SELECT
+    'words'::text AS kind,
+    wb.id,
+    wb.name,
+    COALESCE(wb.seniority, '')::text AS seniority,
+    COALESCE(wb.location, '')::text AS location,
+    COALESCE(wb.work_mode, '')::text AS work_mode,
+    wb.posting_id,
+    COALESCE(bs.terms, 0)::bigint AS items,
+    COALESCE(bs.total, 0)::bigint AS total,
+    wb.created_at
+FROM word_batches AS wb
+LEFT JOIN batch_stats AS bs ON wb.id = bs.batch_id
+WHERE wb.id = $1 AND wb.deleted_at IS NULL;
+
+-- name: GetPhraseBatch :one
+SELECT
+    'phrases'::text AS kind,
+    pb.id,
+    pb.name,
+    ''::text AS seniority,
+    ''::text AS location,
+    ''::text AS work_mode,
+    pb.posting_id,
+    COUNT(DISTINCT phrases.value)::bigint AS items,
+    COUNT(phrases.id)::bigint AS total,
+    pb.created_at
+FROM phrase_batches AS pb
+LEFT JOIN phrases ON pb.id = phrases.batch_id AND phrases.deleted_at IS NULL
+WHERE pb.id = $1 AND pb.deleted_at IS NULL
+GROUP BY pb.id;
+
+-- name: ListBatchWords :many
+SELECT
+    vocabulary.id,
+    vocabulary.raw AS value,
+    vocabulary.lemma,
+    vocabulary.language,
+    occurrences.count
+FROM occurrences
+INNER JOIN vocabulary ON occurrences.term_id = vocabulary.id
+WHERE
+    occurrences.batch_id = sqlc.arg(batch_id)::bigint
+    AND occurrences.deleted_at IS NULL
+    AND vocabulary.deleted_at IS NULL
+ORDER BY occurrences.count DESC, vocabulary.raw ASC;
+
+-- name: ListBatchPhrases :many
+SELECT
+    phrases.id,
+    phrases.value,
+    COALESCE(phrases.language, '')::text AS language
+FROM phrases
+WHERE phrases.batch_id = sqlc.arg(batch_id)::bigint AND phrases.deleted_at IS NULL
+ORDER BY phrases.id ASC;
+
+-- name: RenameWordBatch :one
+UPDATE word_batches
+SET name = sqlc.arg(name)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING id;
+
+-- name: RenamePhraseBatch :one
+UPDATE phrase_batches
+SET name = sqlc.arg(name)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING id;
+
+-- name: MergeWordBatches :one
+-- Moves occurrences and salaries of source batch into target batch and deletes source
+-- batch. Counts of terms in both batches are summed. Returns number of moved occurrences.
+WITH source AS (
+    UPDATE word_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        word_batches.id = sqlc.arg(source)
+        AND word_batches.id <> sqlc.arg(target)
+        AND word_batches.deleted_at IS NULL
+        AND EXISTS (
+            SELECT 1 FROM word_batches AS target
+            WHERE target.id = sqlc.arg(target) AND target.deleted_at IS NULL
+        )
+    RETURNING word_batches.id
+),
+
+moved AS (
+    DELETE FROM occurrences
+    USING source
+    WHERE occurrences.batch_id = source.id AND occurrences.deleted_at IS NULL
+    RETURNING occurrences.term_id, occurrences.count, occurrences.created_at
+),
+
+merged AS (
+    INSERT INTO occurrences (term_id, batch_id, count, created_at)
+    SELECT
+        moved.term_id,
+        sqlc.arg(target),
+        moved.count,
+        moved.created_at
+    FROM moved
+    ON CONFLICT (term_id, batch_id) WHERE batch_id IS NOT NULL DO UPDATE
+    SET
+        count = CASE
+            WHEN occurrences.deleted_at IS NULL
+                THEN occurrences.count + excluded.count
+            ELSE excluded.count
+        END,
+        deleted_at = NULL
+),
+
+moved_salaries AS (
+    UPDATE salaries
+    SET batch_id = sqlc.arg(target)
+    FROM source
+    WHERE salaries.batch_id = source.id AND salaries.deleted_at IS NULL
+)
+
+SELECT (SELECT COUNT(*) FROM moved) AS moved
+FROM source;
+
+-- name: MergePhraseBatches :one
+-- Moves phrases of source batch into target batch and deletes source batch.
+-- Returns number of moved phrases.
+WITH source AS (
+    UPDATE phrase_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        phrase_batches.id = sqlc.arg(source)
+        AND phrase_batches.id <> sqlc.arg(target)
+        AND phrase_batches.deleted_at IS NULL
+        AND EXISTS (
+            SELECT 1 FROM phrase_batches AS target
+            WHERE target.id = sqlc.arg(target) AND target.deleted_at IS NULL
+        )
+    RETURNING phrase_batches.id
+),
+
+moved AS (
+    UPDATE phrases
+    SET batch_id = sqlc.arg(target)
+    FROM source
+    WHERE phrases.batch_id = source.id AND phrases.deleted_at IS NULL
+    RETURNING phrases.id
+)
+
+SELECT (SELECT COUNT(*) FROM moved) AS moved
+FROM source;
+
+-- name: SplitWordBatch :one
+-- Moves occurrences of words normalized to values into a new batch with attributes of
+-- the batch. Salaries stay in the batch.
+WITH source AS (
+    SELECT
+        word_batches.id,
+        word_batches.seniority,
+        word_batches.location,
+        word_batches.work_mode
+    FROM word_batches
+    WHERE word_batches.id = sqlc.arg(id) AND word_batches.deleted_at IS NULL
+),
+
+created AS (
+    INSERT INTO word_batches (name, seniority, location, work_mode)
+    SELECT
+        sqlc.arg(name),
+        source.seniority,
+        source.location,
+        source.work_mode
+    FROM source
+    RETURNING word_batches.id
+),
+
+moved AS (
+    UPDATE occurrences
+    SET batch_id = created.id
+    FROM created, source, vocabulary
+    WHERE
+        occurrences.batch_id = source.id
+        AND occurrences.term_id = vocabulary.id
+        AND occurrences.deleted_at IS NULL
+        AND vocabulary.normalized = ANY(sqlc.arg(values)::text [])
+    RETURNING occurrences.id
+)
+
+SELECT
+    created.id,
+    (SELECT COUNT(*) FROM moved) AS moved
+FROM created;
+
+-- name: SplitPhraseBatch :one
+-- Moves phrases equal to values into a new batch.
+WITH source AS (
+    SELECT phrase_batches.id
+    FROM phrase_batches
+    WHERE phrase_batches.id = sqlc.arg(id) AND phrase_batches.deleted_at IS NULL
+),
+
+created AS (
+    INSERT INTO phrase_batches (name)
+    SELECT sqlc.arg(name)
+    FROM source
+    RETURNING phrase_batches.id
+),
+
+moved AS (
+    UPDATE phrases
+    SET batch_id = created.id
+    FROM created, source
+    WHERE
+        phrases.batch_id = source.id
+        AND phrases.deleted_at IS NULL
+        AND phrases.value = ANY(sqlc.arg(values)::text [])
+    RETURNING phrases.id
+)
+
+SELECT
+    created.id,
+    (SELECT COUNT(*) FROM moved) AS moved
+FROM created;
@@ -0,0 +1,88 @@
+-- name: CreateCollection :one
+INSERT INTO collections (name, description)
+VALUES (sqlc.arg(name), sqlc.arg(description))
+RETURNING id;
+
+-- name: ListCollections :many
+SELECT
+    g.id,
+    g.name,
+    g.description AS description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM collections AS g
+LEFT JOIN collection_members AS m ON g.id = m.collection_id
+GROUP BY g.id
+ORDER BY g.name ASC
+LIMIT $1 OFFSET $2;
+
+-- name: GetCollection :one
+SELECT
+    g.id,
+    g.name,
+    g.description AS description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM collections AS g
+LEFT JOIN collection_members AS m ON g.id = m.collection_id
+WHERE g.name = sqlc.arg(name)
+GROUP BY g.id;
+
+-- name: DeleteCollection :one
+DELETE FROM collections
+WHERE name = sqlc.arg(name)
+RETURNING id;
+
+-- name: ListCollectionMembers :many
+-- Members are postings, word batches (words) or phrase batches (phrases) which aren't deleted.
+SELECT
+    (CASE
+        WHEN m.posting_id IS NOT NULL THEN 'posting'
+        WHEN m.word_batch_id IS NOT NULL THEN 'words'
+        ELSE 'phrases'
+    END)::text AS type,
+    COALESCE(m.posting_id, m.word_batch_id, m.phrase_batch_id)::bigint AS id,
+    COALESCE(postings.title, word_batches.name, phrase_batches.name, '')::text AS name,
+    m.created_at
+FROM collection_members AS m
+INNER JOIN collections AS g ON m.collection_id = g.id
+LEFT JOIN postings ON m.posting_id = postings.id
+LEFT JOIN word_batches ON m.word_batch_id = word_batches.id
+LEFT JOIN phrase_batches ON m.phrase_batch_id = phrase_batches.id
+WHERE
+    g.name = sqlc.arg(name)
+    AND postings.deleted_at IS NULL
+    AND word_batches.deleted_at IS NULL
+    AND phrase_batches.deleted_at IS NULL
+ORDER BY m.created_at ASC;
+
+-- name: AddCollectionMember :one
+-- Adding a member again is a no-op.
+INSERT INTO collection_members (
+    collection_id, posting_id, word_batch_id, phrase_batch_id
+)
+SELECT
+    collections.id,
+    sqlc.narg(posting_id)::bigint,
+    sqlc.narg(word_batch_id)::bigint,
+    sqlc.narg(phrase_batch_id)::bigint
+FROM collections
+WHERE collections.name = sqlc.arg(name)
+ON CONFLICT ON CONSTRAINT collection_members_unique DO UPDATE
+SET created_at = collection_members.created_at
+RETURNING collection_members.collection_id;
+
+-- name: RemoveCollectionMember :one
+DELETE FROM collection_members AS m
+USING collections AS g
+WHERE
+    m.collection_id = g.id
+    AND g.name = sqlc.arg(name)
+    AND m.posting_id IS NOT DISTINCT FROM sqlc.narg(posting_id)::bigint
+    AND m.word_batch_id IS NOT DISTINCT FROM sqlc.narg(word_batch_id)::bigint
+    AND m.phrase_batch_id IS NOT DISTINCT FROM sqlc.narg(phrase_batch_id)::bigint
+RETURNING m.collection_id;
@@ -0,0 +1,156 @@
+-- name: CreateImage :one
+INSERT INTO images (
+    hash,
+    width,
+    height,
+    format,
+    size_bytes,
+    filename,
+    content,
+    path
+)
+VALUES (
+    sqlc.arg(hash),
+    sqlc.arg(width),
+    sqlc.arg(height),
+    sqlc.arg(format),
+    sqlc.arg(size_bytes),
+    sqlc.arg(filename),
+    sqlc.narg(content)::bytea,
+    NULLIF(sqlc.arg(path)::text, '')
+)
+ON CONFLICT (hash) WHERE deleted_at IS NULL DO UPDATE
+SET
+    content = COALESCE(images.content, excluded.content),
+    path = COALESCE(images.path, excluded.path)
+RETURNING
+    id,
+    hash,
+    width,
+    height,
+    format,
+    size_bytes,
+    filename,
+    created_at;
+
+-- name: CreateOcrDocument :one
+INSERT INTO ocr_documents (
+    posting_id,
+    image_id,
+    text,
+    engine,
+    engine_version,
+    languages,
+    settings,
+    confidence,
+    duration_ms
+)
+VALUES (
+    sqlc.narg(posting_id)::bigint,
+    sqlc.narg(image_id)::bigint,
+    sqlc.arg(text),
+    sqlc.arg(engine),
+    sqlc.arg(engine_version),
+    COALESCE(sqlc.arg(languages)::text [], '{}'),
+    sqlc.arg(settings)::jsonb,
+    sqlc.narg(confidence)::double precision,
+    sqlc.arg(duration_ms)
+)
+RETURNING
+    id,
+    posting_id,
+    image_id,
+    engine,
+    engine_version,
+    languages,
+    confidence,
+    duration_ms,
+    created_at;
+
+-- name: ListOcrDocuments :many
+SELECT
+    d.id,
+    d.posting_id,
+    d.image_id,
+    d.engine,
+    d.engine_version,
+    d.languages,
+    d.settings,
+    d.confidence,
+    d.duration_ms,
+    LENGTH(d.text)::integer AS text_length,
+    images.hash AS image_hash,
+    images.width AS image_width,
+    images.height AS image_height,
+    images.format AS image_format,
+    images.size_bytes AS image_size_bytes,
+    images.filename AS image_filename,
+    d.created_at
+FROM ocr_documents AS d
+LEFT JOIN images ON d.image_id = images.id AND images.deleted_at IS NULL
+WHERE d.posting_id = sqlc.arg(posting_id)::bigint AND d.deleted_at IS NULL
+ORDER BY d.created_at DESC, d.id DESC;
+
+-- name: GetLatestOcrDocument :one
+SELECT
+    d.id,
+    d.posting_id,
+    d.image_id,
+    d.text,
+    d.engine,
+    images.format AS image_format,
+    images.content AS image_content,
+    images.path AS image_path,
+    d.created_at
+FROM ocr_documents AS d
+LEFT JOIN images ON d.image_id = images.id AND images.deleted_at IS NULL
+WHERE d.posting_id = sqlc.arg(posting_id)::bigint AND d.deleted_at IS NULL
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT 1;
+
+-- name: DeletePostingBatches :exec
+WITH deleted_word_batches AS (
+    UPDATE word_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        word_batches.posting_id = sqlc.arg(posting_id)::bigint
+        AND word_batches.deleted_at IS NULL
+    RETURNING word_batches.id
+),
+
+deleted_phrase_batches AS (
+    UPDATE phrase_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        phrase_batches.posting_id = sqlc.arg(posting_id)::bigint
+        AND phrase_batches.deleted_at IS NULL
+    RETURNING phrase_batches.id
+),
+
+deleted_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        occurrences.batch_id IN (
+            SELECT deleted_word_batches.id FROM deleted_word_batches
+        )
+        AND occurrences.deleted_at IS NULL
+),
+
+deleted_salaries AS (
+    UPDATE salaries
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        salaries.batch_id IN (
+            SELECT deleted_word_batches.id FROM deleted_word_batches
+        )
+        AND salaries.deleted_at IS NULL
+)
+
+UPDATE phrases
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE
+    phrases.batch_id IN (
+        SELECT deleted_phrase_batches.id FROM deleted_phrase_batches
+    )
+    AND phrases.deleted_at IS NULL;
@@ -1,13 +1,57 @@
 -- name: CreatePhrasesBatch :one
 WITH batch AS (
-    INSERT INTO phrase_batches (name)
-    VALUES ($1)
+    INSERT INTO phrase_batches (name, posting_id)
+    VALUES ($1, sqlc.narg(posting_id)::bigint)
     RETURNING id
 )
 
-INSERT INTO phrases (value, batch_id)
+INSERT INTO phrases (value, language, batch_id)
 SELECT
-    phrase_value,
+    phrase.value,
+    NULLIF((sqlc.arg(languages)::text [])[phrase.position], ''),
     (SELECT id FROM batch)
-FROM UNNEST(sqlc.arg(phrases)::text []) AS phrase_value
+FROM UNNEST(sqlc.arg(phrases)::text []) WITH ORDINALITY AS phrase (value, position)
 RETURNING id, batch_id;
+
+-- name: ListPhraseCountsInSet :many
+SELECT
+    LOWER(phrases.value)::text AS value,
+    COUNT(*) AS total,
+    COUNT(DISTINCT phrases.batch_id) AS postings
+FROM phrases
+INNER JOIN phrase_batches AS pb ON phrases.batch_id = pb.id
+WHERE
+    phrases.deleted_at IS NULL
+    AND pb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY(sqlc.arg(batches)::text []), 0) = 0
+        OR pb.name = ANY(sqlc.arg(batches)::text [])
+    )
+    AND (
+        sqlc.narg(created_from)::timestamptz IS NULL
+        OR pb.created_at >= sqlc.narg(created_from)::timestamptz
+    )
+    AND (
+        sqlc.narg(created_to)::timestamptz IS NULL
+        OR pb.created_at < sqlc.narg(created_to)::timestamptz
+    )
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR phrases.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_tags.batch_id FROM phrase_batch_tags
+            WHERE phrase_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_collections.batch_id FROM phrase_batch_collections
+            WHERE phrase_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+GROUP BY LOWER(phrases.value)
+ORDER BY LOWER(phrases.value) ASC;
@@ -0,0 +1,237 @@
+-- name: CreatePosting :one
+INSERT INTO postings (
+    title,
+    company,
+    source_url,
+    captured_at,
+    image_hash,
+    raw_text
+)
+VALUES (
+    sqlc.arg(title),
+    sqlc.arg(company),
+    sqlc.arg(source_url),
+    COALESCE(sqlc.narg(captured_at)::timestamptz, CURRENT_TIMESTAMP),
+    NULLIF(sqlc.arg(image_hash)::text, ''),
+    sqlc.arg(raw_text)
+)
+RETURNING
+    id,
+    title,
+    company,
+    source_url,
+    captured_at,
+    COALESCE(image_hash, '')::text AS image_hash,
+    created_at;
+
+-- name: GetPosting :one
+SELECT
+    postings.id,
+    postings.title,
+    postings.company,
+    postings.source_url,
+    postings.captured_at,
+    COALESCE(postings.image_hash, '')::text AS image_hash,
+    postings.raw_text,
+    wb.id AS word_batch_id,
+    wb.name AS word_batch_name,
+    pb.id AS phrase_batch_id,
+    pb.name AS phrase_batch_name,
+    postings.created_at,
+    postings.updated_at
+FROM postings
+LEFT JOIN word_batches AS wb
+    ON postings.id = wb.posting_id AND wb.deleted_at IS NULL
+LEFT JOIN phrase_batches AS pb
+    ON postings.id = pb.posting_id AND pb.deleted_at IS NULL
+WHERE postings.id = $1 AND postings.deleted_at IS NULL;
+
+-- name: ListPostings :many
+SELECT
+    postings.id,
+    postings.title,
+    postings.company,
+    postings.source_url,
+    postings.captured_at,
+    COALESCE(postings.image_hash, '')::text AS image_hash,
+    wb.id AS word_batch_id,
+    pb.id AS phrase_batch_id,
+    postings.created_at
+FROM postings
+LEFT JOIN word_batches AS wb
+    ON postings.id = wb.posting_id AND wb.deleted_at IS NULL
+LEFT JOIN phrase_batches AS pb
+    ON postings.id = pb.posting_id AND pb.deleted_at IS NULL
+WHERE
+    postings.deleted_at IS NULL
+    AND (
+        sqlc.narg(company)::text IS NULL
+        OR LOWER(postings.company) = LOWER(sqlc.narg(company)::text)
+    )
+ORDER BY postings.captured_at DESC, postings.id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: UpdatePosting :one
+UPDATE postings
+SET
+    title = COALESCE(sqlc.narg(title)::text, title),
+    company = COALESCE(sqlc.narg(company)::text, company),
+    source_url = COALESCE(sqlc.narg(source_url)::text, source_url),
+    captured_at = COALESCE(sqlc.narg(captured_at)::timestamptz, captured_at),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING
+    id,
+    title,
+    company,
+    source_url,
+    captured_at,
+    COALESCE(image_hash, '')::text AS image_hash,
+    updated_at;
+
+-- name: DeletePosting :one
+WITH deleted AS (
+    UPDATE postings
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE postings.id = $1 AND postings.deleted_at IS NULL
+    RETURNING postings.id
+),
+
+deleted_word_batches AS (
+    UPDATE word_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        word_batches.posting_id IN (SELECT deleted.id FROM deleted)
+        AND word_batches.deleted_at IS NULL
+    RETURNING word_batches.id
+),
+
+deleted_phrase_batches AS (
+    UPDATE phrase_batches
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        phrase_batches.posting_id IN (SELECT deleted.id FROM deleted)
+        AND phrase_batches.deleted_at IS NULL
+    RETURNING phrase_batches.id
+),
+
+deleted_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        occurrences.batch_id IN (
+            SELECT deleted_word_batches.id FROM deleted_word_batches
+        )
+        AND occurrences.deleted_at IS NULL
+),
+
+deleted_salaries AS (
+    UPDATE salaries
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        salaries.batch_id IN (
+            SELECT deleted_word_batches.id FROM deleted_word_batches
+        )
+        AND salaries.deleted_at IS NULL
+),
+
+deleted_phrases AS (
+    UPDATE phrases
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        phrases.batch_id IN (
+            SELECT deleted_phrase_batches.id FROM deleted_phrase_batches
+        )
+        AND phrases.deleted_at IS NULL
+),
+
+deleted_documents AS (
+    UPDATE ocr_documents
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        ocr_documents.posting_id IN (SELECT deleted.id FROM deleted)
+        AND ocr_documents.deleted_at IS NULL
+)
+
+SELECT deleted.id FROM deleted;
+
+-- name: RestorePosting :one
+WITH posting AS (
+    SELECT
+        postings.id,
+        postings.deleted_at
+    FROM postings
+    WHERE postings.id = $1 AND postings.deleted_at IS NOT NULL
+),
+
+restored AS (
+    UPDATE postings
+    SET deleted_at = NULL
+    FROM posting
+    WHERE postings.id = posting.id
+    RETURNING postings.id
+),
+
+restored_word_batches AS (
+    UPDATE word_batches
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        word_batches.posting_id = posting.id
+        AND word_batches.deleted_at = posting.deleted_at
+    RETURNING word_batches.id
+),
+
+restored_phrase_batches AS (
+    UPDATE phrase_batches
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        phrase_batches.posting_id = posting.id
+        AND phrase_batches.deleted_at = posting.deleted_at
+    RETURNING phrase_batches.id
+),
+
+restored_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        occurrences.batch_id IN (
+            SELECT restored_word_batches.id FROM restored_word_batches
+        )
+        AND occurrences.deleted_at = posting.deleted_at
+),
+
+restored_salaries AS (
+    UPDATE salaries
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        salaries.batch_id IN (
+            SELECT restored_word_batches.id FROM restored_word_batches
+        )
+        AND salaries.deleted_at = posting.deleted_at
+),
+
+restored_phrases AS (
+    UPDATE phrases
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        phrases.batch_id IN (
+            SELECT restored_phrase_batches.id FROM restored_phrase_batches
+        )
+        AND phrases.deleted_at = posting.deleted_at
+),
+
+restored_documents AS (
+    UPDATE ocr_documents
+    SET deleted_at = NULL
+    FROM posting
+    WHERE
+        ocr_documents.posting_id = posting.id
+        AND ocr_documents.deleted_at = posting.deleted_at
+)
+
+SELECT restored.id FROM restored;
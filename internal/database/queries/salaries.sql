@@ -0,0 +1,121 @@
+-- name: CreateSalary :one
+INSERT INTO salaries (
+    batch_id,
+    min_amount,
+    max_amount,
+    currency,
+    period,
+    monthly_min,
+    monthly_max,
+    contract,
+    basis,
+    seniority,
+    raw
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    NULLIF(sqlc.arg(contract)::text, ''),
+    NULLIF(sqlc.arg(basis)::text, ''),
+    NULLIF(sqlc.arg(seniority)::text, ''),
+    sqlc.arg(raw)
+)
+RETURNING
+    id,
+    batch_id,
+    min_amount,
+    max_amount,
+    currency,
+    period,
+    contract,
+    basis,
+    seniority;
+
+-- name: ListSalariesByBatchName :many
+SELECT
+    salaries.id,
+    wb.name AS batch_name,
+    salaries.min_amount,
+    salaries.max_amount,
+    salaries.currency,
+    salaries.period,
+    salaries.monthly_min,
+    salaries.monthly_max,
+    COALESCE(salaries.contract, '')::text AS contract,
+    COALESCE(salaries.basis, '')::text AS basis,
+    COALESCE(salaries.seniority, '')::text AS seniority,
+    salaries.raw
+FROM salaries
+INNER JOIN word_batches AS wb ON salaries.batch_id = wb.id
+WHERE
+    wb.name = $1
+    AND wb.deleted_at IS NULL
+    AND salaries.deleted_at IS NULL
+ORDER BY salaries.id ASC;
+
+-- name: ListSalaryMediansBySkill :many
+SELECT
+    batch_words.value AS skill,
+    salaries.currency,
+    COUNT(*) AS total,
+    PERCENTILE_CONT(0.5) WITHIN GROUP (
+        ORDER BY (salaries.monthly_min + salaries.monthly_max) / 2
+    )::float8 AS median
+FROM salaries
+INNER JOIN (
+    SELECT DISTINCT
+        o.batch_id,
+        COALESCE(known.skill, v.raw) AS value
+    FROM occurrences AS o
+    INNER JOIN vocabulary AS v ON o.term_id = v.id
+    LEFT JOIN UNNEST(
+        sqlc.narg(skills)::text [], sqlc.narg(canonical_skills)::text []
+    ) AS known (name, skill) ON v.normalized = known.name
+    WHERE
+        o.deleted_at IS NULL
+        AND (
+            sqlc.narg(skills)::text [] IS NULL
+            OR known.skill IS NOT NULL
+        )
+) AS batch_words ON salaries.batch_id = batch_words.batch_id
+WHERE
+    salaries.deleted_at IS NULL
+    AND (
+        sqlc.narg(skill)::text IS NULL
+        OR batch_words.value = sqlc.narg(skill)::text
+    )
+    AND (
+        sqlc.narg(contract)::text IS NULL
+        OR salaries.contract = sqlc.narg(contract)::text
+    )
+    AND NOT LOWER(batch_words.value) = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY batch_words.value, salaries.currency
+ORDER BY total DESC, skill ASC
+LIMIT $1 OFFSET $2;
+
+-- name: ListSalaryMediansBySeniority :many
+SELECT
+    COALESCE(wb.seniority, salaries.seniority, '')::text AS seniority,
+    salaries.currency,
+    COUNT(*) AS total,
+    PERCENTILE_CONT(0.5) WITHIN GROUP (
+        ORDER BY (salaries.monthly_min + salaries.monthly_max) / 2
+    )::float8 AS median
+FROM salaries
+INNER JOIN word_batches AS wb ON salaries.batch_id = wb.id
+WHERE
+    salaries.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (
+        sqlc.narg(contract)::text IS NULL
+        OR salaries.contract = sqlc.narg(contract)::text
+    )
+GROUP BY COALESCE(wb.seniority, salaries.seniority, ''), salaries.currency
+ORDER BY COALESCE(wb.seniority, salaries.seniority, '') ASC, salaries.currency ASC;
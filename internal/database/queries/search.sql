@@ -0,0 +1,56 @@
+-- name: SearchPhrases :many
+WITH search AS (
+    SELECT
+        WEBSEARCH_TO_TSQUERY(
+            search_config(sqlc.narg(language)::text), sqlc.arg(query)::text
+        )
+        || WEBSEARCH_TO_TSQUERY('simple', sqlc.arg(query)::text) AS query
+)
+
+SELECT
+    p.id,
+    p.value,
+    COALESCE(p.language, '')::text AS language,
+    COALESCE(pb.name, '')::text AS batch_name,
+    TS_RANK_CD(p.search, search.query)::real AS rank,
+    SIMILARITY(p.value, sqlc.arg(query)::text)::real AS similarity,
+    TS_HEADLINE(
+        search_config(p.language),
+        p.value,
+        search.query,
+        'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'
+    )::text AS snippet
+FROM phrases AS p
+CROSS JOIN search
+LEFT JOIN phrase_batches AS pb ON p.batch_id = pb.id
+WHERE
+    p.deleted_at IS NULL
+    AND pb.deleted_at IS NULL
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR p.language = sqlc.narg(language)::text
+    )
+    AND (
+        p.search @@ search.query
+        OR p.value % sqlc.arg(query)::text
+    )
+ORDER BY rank DESC, similarity DESC, p.id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: SearchWords :many
+SELECT
+    v.raw AS value,
+    v.language,
+    s.total,
+    SIMILARITY(v.normalized, LOWER(sqlc.arg(query)::text))::real AS similarity
+FROM vocabulary AS v
+INNER JOIN term_stats AS s ON v.id = s.term_id
+WHERE
+    s.total > 0
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND v.normalized % LOWER(sqlc.arg(query)::text)
+ORDER BY similarity DESC, s.total DESC, v.raw ASC
+LIMIT $1 OFFSET $2;
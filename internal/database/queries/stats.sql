@@ -0,0 +1,90 @@
+-- name: RefreshWordStats :exec
+SELECT refresh_word_stats();
+
+-- name: ListWordStatsFrequencies :many
+SELECT
+    v.raw AS value,
+    SUM(s.total)::bigint AS total
+FROM term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+WHERE
+    s.total > 0
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY v.raw
+ORDER BY total ASC
+LIMIT $1 OFFSET $2;
+
+-- name: ListWordStatsRankings :many
+SELECT
+    v.raw AS value,
+    ROW_NUMBER() OVER (ORDER BY SUM(s.total) DESC) AS ranking
+FROM term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+WHERE
+    s.total > 0
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY v.raw
+ORDER BY ranking ASC
+LIMIT $1 OFFSET $2;
+
+-- name: ListWordStatsTrend :many
+WITH bucket_postings AS (
+    SELECT
+        DATE_TRUNC(
+            sqlc.arg(bucket)::text, word_batches.created_at AT TIME ZONE 'UTC'
+        ) AS bucket,
+        COUNT(*) AS postings
+    FROM word_batches
+    WHERE
+        word_batches.deleted_at IS NULL
+        AND word_batches.created_at >= sqlc.arg(since)::timestamptz
+    GROUP BY
+        DATE_TRUNC(
+            sqlc.arg(bucket)::text, word_batches.created_at AT TIME ZONE 'UTC'
+        )
+)
+
+SELECT
+    (
+        DATE_TRUNC(sqlc.arg(bucket)::text, s.day::timestamp) AT TIME ZONE 'UTC'
+    )::timestamptz AS bucket,
+    v.normalized AS value,
+    SUM(s.total)::bigint AS total,
+    SUM(s.postings)::bigint AS postings,
+    COALESCE(MAX(bp.postings), 0)::bigint AS bucket_postings
+FROM daily_term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+LEFT JOIN bucket_postings AS bp
+    ON DATE_TRUNC(sqlc.arg(bucket)::text, s.day::timestamp) = bp.bucket
+WHERE
+    s.total > 0
+    AND s.day >= (sqlc.arg(since)::timestamptz AT TIME ZONE 'UTC')::date
+    AND (
+        COALESCE(CARDINALITY(sqlc.arg(words)::text []), 0) = 0
+        OR v.normalized = ANY(sqlc.arg(words)::text [])
+    )
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY
+    DATE_TRUNC(sqlc.arg(bucket)::text, s.day::timestamp),
+    v.normalized
+ORDER BY
+    DATE_TRUNC(sqlc.arg(bucket)::text, s.day::timestamp) ASC,
+    v.normalized ASC;
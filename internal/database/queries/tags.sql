@@ -0,0 +1,93 @@
+-- name: CreateTag :one
+INSERT INTO tags (name)
+VALUES (sqlc.arg(name))
+RETURNING id;
+
+-- name: ListTags :many
+SELECT
+    g.id,
+    g.name,
+    ''::text AS description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM tags AS g
+LEFT JOIN tag_members AS m ON g.id = m.tag_id
+GROUP BY g.id
+ORDER BY g.name ASC
+LIMIT $1 OFFSET $2;
+
+-- name: GetTag :one
+SELECT
+    g.id,
+    g.name,
+    ''::text AS description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM tags AS g
+LEFT JOIN tag_members AS m ON g.id = m.tag_id
+WHERE g.name = sqlc.arg(name)
+GROUP BY g.id;
+
+-- name: DeleteTag :one
+DELETE FROM tags
+WHERE name = sqlc.arg(name)
+RETURNING id;
+
+-- name: ListTagMembers :many
+-- Members are postings, word batches (words) or phrase batches (phrases) which aren't deleted.
+SELECT
+    (CASE
+        WHEN m.posting_id IS NOT NULL THEN 'posting'
+        WHEN m.word_batch_id IS NOT NULL THEN 'words'
+        ELSE 'phrases'
+    END)::text AS type,
+    COALESCE(m.posting_id, m.word_batch_id, m.phrase_batch_id)::bigint AS id,
+    COALESCE(postings.title, word_batches.name, phrase_batches.name, '')::text AS name,
+    m.created_at
+FROM tag_members AS m
+INNER JOIN tags AS g ON m.tag_id = g.id
+LEFT JOIN postings ON m.posting_id = postings.id
+LEFT JOIN word_batches ON m.word_batch_id = word_batches.id
+LEFT JOIN phrase_batches ON m.phrase_batch_id = phrase_batches.id
+WHERE
+    g.name = sqlc.arg(name)
+    AND postings.deleted_at IS NULL
+    AND word_batches.deleted_at IS NULL
+    AND phrase_batches.deleted_at IS NULL
+ORDER BY m.created_at ASC;
+
+-- name: AddTagMember :one
+-- Tags are created on first use. Adding a member again is a no-op.
+WITH tag AS (
+    INSERT INTO tags (name)
+    VALUES (sqlc.arg(name))
+    ON CONFLICT ON CONSTRAINT tags_name_unique DO UPDATE
+    SET name = excluded.name
+    RETURNING tags.id
+)
+
+INSERT INTO tag_members (tag_id, posting_id, word_batch_id, phrase_batch_id)
+SELECT
+    tag.id,
+    sqlc.narg(posting_id)::bigint,
+    sqlc.narg(word_batch_id)::bigint,
+    sqlc.narg(phrase_batch_id)::bigint
+FROM tag
+ON CONFLICT ON CONSTRAINT tag_members_unique DO UPDATE
+SET created_at = tag_members.created_at
+RETURNING tag_members.tag_id;
+
+-- name: RemoveTagMember :one
+DELETE FROM tag_members AS m
+USING tags AS g
+WHERE
+    m.tag_id = g.id
+    AND g.name = sqlc.arg(name)
+    AND m.posting_id IS NOT DISTINCT FROM sqlc.narg(posting_id)::bigint
+    AND m.word_batch_id IS NOT DISTINCT FROM sqlc.narg(word_batch_id)::bigint
+    AND m.phrase_batch_id IS NOT DISTINCT FROM sqlc.narg(phrase_batch_id)::bigint
+RETURNING m.tag_id;
@@ -1,67 +1,584 @@
 -- name: ListWords :many
 SELECT
     id,
-    value,
+    raw AS value,
     created_at
-FROM words
+FROM vocabulary
 WHERE deleted_at IS NULL
-ORDER BY value ASC
+ORDER BY raw ASC
 LIMIT $1 OFFSET $2;
 
 -- name: CreateWord :one
-INSERT INTO words (value, created_at)
-VALUES ($1, CURRENT_TIMESTAMP)
-RETURNING id, value, created_at;
+WITH term AS (
+    INSERT INTO vocabulary (raw, normalized, lemma, language)
+    VALUES (
+        sqlc.arg(value)::text,
+        LOWER(sqlc.arg(value)::text),
+        sqlc.arg(lemma)::text,
+        sqlc.arg(language)::text
+    )
+    ON CONFLICT (raw, language) DO UPDATE
+    SET
+        lemma = COALESCE(NULLIF(vocabulary.lemma, ''), excluded.lemma),
+        deleted_at = NULL
+    RETURNING id, raw, lemma, language, created_at
+),
+
+occurrence AS (
+    INSERT INTO occurrences (term_id, count)
+    SELECT
+        term.id,
+        1
+    FROM term
+    ON CONFLICT (
+        term_id, ((created_at AT TIME ZONE 'UTC')::date)
+    ) WHERE batch_id IS NULL DO UPDATE
+    SET
+        count = CASE
+            WHEN occurrences.deleted_at IS NULL THEN occurrences.count + 1
+            ELSE 1
+        END,
+        deleted_at = NULL
+)
+
+SELECT
+    term.id,
+    term.raw AS value,
+    term.lemma,
+    term.language,
+    term.created_at
+FROM term;
+
+-- name: DeleteWord :one
+WITH deleted AS (
+    UPDATE vocabulary
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE vocabulary.id = $1 AND vocabulary.deleted_at IS NULL
+    RETURNING vocabulary.id
+),
+
+deleted_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        occurrences.term_id IN (SELECT deleted.id FROM deleted)
+        AND occurrences.deleted_at IS NULL
+)
+
+SELECT deleted.id FROM deleted;
+
+-- name: RestoreWord :one
+WITH term AS (
+    SELECT
+        vocabulary.id,
+        vocabulary.deleted_at
+    FROM vocabulary
+    WHERE vocabulary.id = $1 AND vocabulary.deleted_at IS NOT NULL
+),
+
+restored AS (
+    UPDATE vocabulary
+    SET deleted_at = NULL
+    FROM term
+    WHERE vocabulary.id = term.id
+    RETURNING vocabulary.id
+),
+
+restored_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = NULL
+    FROM term
+    WHERE
+        occurrences.term_id = term.id
+        AND occurrences.deleted_at = term.deleted_at
+)
+
+SELECT restored.id FROM restored;
 
 -- name: ListWordFrequencies :many
 SELECT
-    words.value,
-    COUNT(*) AS total
-FROM words
-WHERE words.deleted_at IS NULL
-GROUP BY words.value
+    v.raw AS value,
+    SUM(o.count)::bigint AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY v.raw
 ORDER BY total ASC
 LIMIT $1 OFFSET $2;
 
--- name: ListWordRankings :many
+-- name: ListLemmaFrequencies :many
 SELECT
-    words.value,
-    ROW_NUMBER() OVER (ORDER BY COUNT(*) DESC) AS ranking
-FROM words
-WHERE words.deleted_at IS NULL
-GROUP BY words.value
-ORDER BY ranking ASC
+    COALESCE(NULLIF(v.lemma, ''), v.raw)::text AS lemma,
+    SUM(o.count)::bigint AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY COALESCE(NULLIF(v.lemma, ''), v.raw)
+ORDER BY total ASC
 LIMIT $1 OFFSET $2;
 
--- name: ListWordBatches :many
+-- name: ListWordRankings :many
 SELECT
-    id,
-    name,
-    created_at
-FROM word_batches
-WHERE deleted_at IS NULL
-ORDER BY created_at ASC
+    v.raw AS value,
+    ROW_NUMBER() OVER (ORDER BY SUM(o.count) DESC) AS ranking
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY v.raw
+ORDER BY ranking ASC
 LIMIT $1 OFFSET $2;
 
+-- name: CreateWordBatch :one
+INSERT INTO word_batches (name, seniority, location, work_mode, posting_id)
+VALUES (
+    $1,
+    NULLIF(sqlc.arg(seniority)::text, ''),
+    NULLIF(sqlc.arg(location)::text, ''),
+    NULLIF(sqlc.arg(work_mode)::text, ''),
+    sqlc.narg(posting_id)::bigint
+)
+RETURNING id;
+
 -- name: CreateWordsBatch :one
 WITH new_batch AS (
-    INSERT INTO word_batches (name)
-    VALUES ($1)
+    INSERT INTO word_batches (name, seniority, location, work_mode, posting_id)
+    VALUES (
+        $1,
+        NULLIF(sqlc.arg(seniority)::text, ''),
+        NULLIF(sqlc.arg(location)::text, ''),
+        NULLIF(sqlc.arg(work_mode)::text, ''),
+        sqlc.narg(posting_id)::bigint
+    )
     RETURNING id
+),
+
+word AS (
+    SELECT
+        word.value,
+        COALESCE((sqlc.arg(lemmas)::text [])[word.position], '') AS lemma,
+        COALESCE((sqlc.arg(languages)::text [])[word.position], '') AS language
+    FROM
+        UNNEST(sqlc.arg(words)::text []) WITH ORDINALITY AS word (value, position)
+),
+
+terms AS (
+    INSERT INTO vocabulary (raw, normalized, lemma, language)
+    SELECT DISTINCT ON (word.value, word.language)
+        word.value,
+        LOWER(word.value),
+        word.lemma,
+        word.language
+    FROM word
+    ORDER BY word.value, word.language
+    ON CONFLICT (raw, language) DO UPDATE
+    SET
+        lemma = COALESCE(NULLIF(vocabulary.lemma, ''), excluded.lemma),
+        deleted_at = NULL
+    RETURNING id, raw, language
 )
 
-INSERT INTO words (value, batch_id)
+INSERT INTO occurrences (term_id, batch_id, count)
 SELECT
-    word_value,
-    (SELECT id FROM new_batch)
-FROM UNNEST($2::text []) AS word_value
-RETURNING id, value, batch_id;
+    terms.id,
+    (SELECT new_batch.id FROM new_batch),
+    COUNT(*)
+FROM word
+INNER JOIN terms ON word.value = terms.raw AND word.language = terms.language
+GROUP BY terms.id
+RETURNING term_id, batch_id;
 
 -- name: ListWordsByBatchName :many
 SELECT
     wb.name AS batch_name,
-    w.value AS word_value
+    v.raw AS word_value,
+    o.count
+FROM word_batches AS wb
+INNER JOIN occurrences AS o ON wb.id = o.batch_id
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+WHERE wb.name = $1 AND wb.deleted_at IS NULL AND o.deleted_at IS NULL
+ORDER BY wb.created_at DESC, v.raw ASC;
+
+-- name: UpdateWordBatchAttributes :one
+UPDATE word_batches
+SET
+    seniority = CASE
+        WHEN sqlc.narg(seniority)::text IS NULL THEN seniority
+        ELSE NULLIF(sqlc.narg(seniority)::text, '')
+    END,
+    location = CASE
+        WHEN sqlc.narg(location)::text IS NULL THEN location
+        ELSE NULLIF(sqlc.narg(location)::text, '')
+    END,
+    work_mode = CASE
+        WHEN sqlc.narg(work_mode)::text IS NULL THEN work_mode
+        ELSE NULLIF(sqlc.narg(work_mode)::text, '')
+    END
+WHERE name = sqlc.arg(name) AND deleted_at IS NULL
+RETURNING
+    id,
+    name,
+    COALESCE(seniority, '')::text AS seniority,
+    COALESCE(location, '')::text AS location,
+    COALESCE(work_mode, '')::text AS work_mode;
+
+-- name: ListBatchWordSets :many
+SELECT
+    wb.id AS batch_id,
+    ARRAY_AGG(DISTINCT v.normalized)::text [] AS words
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+    AND (
+        sqlc.narg(skills)::text [] IS NULL
+        OR v.normalized = ANY(sqlc.narg(skills)::text [])
+    )
+GROUP BY wb.id
+ORDER BY wb.id ASC;
+
+-- name: ListWordTrend :many
+WITH bucket_postings AS (
+    SELECT
+        DATE_TRUNC(
+            sqlc.arg(bucket)::text, word_batches.created_at AT TIME ZONE 'UTC'
+        ) AS bucket,
+        COUNT(*) AS postings
+    FROM word_batches
+    WHERE
+        word_batches.deleted_at IS NULL
+        AND word_batches.created_at >= sqlc.arg(since)::timestamptz
+        AND (
+            sqlc.narg(seniority)::text IS NULL
+            OR word_batches.seniority = sqlc.narg(seniority)::text
+        )
+        AND (
+            sqlc.narg(location)::text IS NULL
+            OR LOWER(word_batches.location)
+            = LOWER(sqlc.narg(location)::text)
+        )
+        AND (
+            sqlc.narg(work_mode)::text IS NULL
+            OR word_batches.work_mode = sqlc.narg(work_mode)::text
+        )
+        AND (
+            sqlc.narg(tag)::text IS NULL
+            OR word_batches.id IN (
+                SELECT word_batch_tags.batch_id FROM word_batch_tags
+                WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+            )
+        )
+        AND (
+            sqlc.narg(collection)::text IS NULL
+            OR word_batches.id IN (
+                SELECT word_batch_collections.batch_id FROM word_batch_collections
+                WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+            )
+        )
+    GROUP BY
+        DATE_TRUNC(
+            sqlc.arg(bucket)::text, word_batches.created_at AT TIME ZONE 'UTC'
+        )
+)
+
+SELECT
+    (
+        DATE_TRUNC(sqlc.arg(bucket)::text, o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+    )::timestamptz AS bucket,
+    v.normalized AS value,
+    SUM(o.count)::bigint AS total,
+    COUNT(DISTINCT o.batch_id) AS postings,
+    COALESCE(MAX(bp.postings), 0)::bigint AS bucket_postings
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+LEFT JOIN bucket_postings AS bp
+    ON DATE_TRUNC(sqlc.arg(bucket)::text, o.created_at AT TIME ZONE 'UTC') = bp.bucket
+WHERE
+    o.deleted_at IS NULL
+    AND o.created_at >= sqlc.arg(since)::timestamptz
+    AND (
+        COALESCE(CARDINALITY(sqlc.arg(words)::text []), 0) = 0
+        OR v.normalized = ANY(sqlc.arg(words)::text [])
+    )
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY
+    DATE_TRUNC(sqlc.arg(bucket)::text, o.created_at AT TIME ZONE 'UTC'),
+    v.normalized
+ORDER BY
+    DATE_TRUNC(sqlc.arg(bucket)::text, o.created_at AT TIME ZONE 'UTC') ASC,
+    v.normalized ASC;
+
+-- name: ListWordCountsInSet :many
+SELECT
+    v.normalized AS value,
+    SUM(o.count)::bigint AS total,
+    COUNT(DISTINCT o.batch_id) AS postings
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY(sqlc.arg(batches)::text []), 0) = 0
+        OR wb.name = ANY(sqlc.arg(batches)::text [])
+    )
+    AND (
+        sqlc.narg(created_from)::timestamptz IS NULL
+        OR wb.created_at >= sqlc.narg(created_from)::timestamptz
+    )
+    AND (
+        sqlc.narg(created_to)::timestamptz IS NULL
+        OR wb.created_at < sqlc.narg(created_to)::timestamptz
+    )
+    AND (
+        sqlc.narg(language)::text IS NULL
+        OR v.language = sqlc.narg(language)::text
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE(sqlc.arg(excluded)::text [], '{}')
+    )
+GROUP BY v.normalized
+ORDER BY v.normalized ASC;
+
+-- name: CountWordBatchesInSet :one
+SELECT COUNT(*) AS total
 FROM word_batches AS wb
-INNER JOIN words AS w ON wb.id = w.id
-WHERE wb.name = $1 AND wb.deleted_at IS NULL
-ORDER BY wb.created_at DESC;
+WHERE
+    wb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY(sqlc.arg(batches)::text []), 0) = 0
+        OR wb.name = ANY(sqlc.arg(batches)::text [])
+    )
+    AND (
+        sqlc.narg(created_from)::timestamptz IS NULL
+        OR wb.created_at >= sqlc.narg(created_from)::timestamptz
+    )
+    AND (
+        sqlc.narg(created_to)::timestamptz IS NULL
+        OR wb.created_at < sqlc.narg(created_to)::timestamptz
+    )
+    AND (
+        sqlc.narg(seniority)::text IS NULL
+        OR wb.seniority = sqlc.narg(seniority)::text
+    )
+    AND (
+        sqlc.narg(location)::text IS NULL
+        OR LOWER(wb.location) = LOWER(sqlc.narg(location)::text)
+    )
+    AND (
+        sqlc.narg(work_mode)::text IS NULL
+        OR wb.work_mode = sqlc.narg(work_mode)::text
+    )
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(collection)::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = sqlc.narg(collection)::text
+        )
+    );
@@ -0,0 +1,370 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CopyThreshold is the number of words from which ingested words are sent
+// with COPY protocol instead of a single insert statement.
+const CopyThreshold = 500
+
+var (
+	ErrNoBatchName            = errors.New("ingested phrases or salaries require a batch name")
+	ErrSalariesWithWords      = errors.New("ingested salaries require words")
+	ErrDocumentWithoutPosting = errors.New("ingested document requires a posting")
+)
+
+// TxBeginner is a database connection which begins transactions, e.g. *pgx.Conn
+// or *pgxpool.Pool.
+type TxBeginner interface {
+	DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Store runs queries and ingests whole batches in transactions.
+type Store interface {
+	Querier
+	Ingest(ctx context.Context, in Ingestion) (IngestResult, error)
+}
+
+// Repository is a Store on pgx. Queries run outside of transactions.
+type Repository struct {
+	*Queries
+
+	db TxBeginner
+}
+
+var _ Store = (*Repository)(nil)
+
+func NewRepository(db TxBeginner) *Repository {
+	return &Repository{
+		Queries: New(db),
+		db:      db,
+	}
+}
+
+// IngestedWord is a word with its lemma and language, empty if unknown.
+type IngestedWord struct {
+	Value    string
+	Lemma    string
+	Language string
+}
+
+// IngestedPhrase is a phrase with its language, empty if unknown.
+type IngestedPhrase struct {
+	Value    string
+	Language string
+}
+
+// IngestedDocument is a document recognized from a captured posting. Image is nil if
+// the capture isn't an image. Posting and image IDs of Document are set on ingestion.
+type IngestedDocument struct {
+	Image    *CreateImageParams
+	Document CreateOcrDocumentParams
+}
+
+// Ingestion is a batch stored all or nothing. Words are stored in a word batch and
+// phrases in a phrase batch named Name, salaries are stored in the word batch.
+// Words of ingestion without name are added without a batch.
+//
+// Ingestion with Posting creates the posting first and its batches are owned by it,
+// named posting_<id> if Name is empty.
+type Ingestion struct {
+	Name      string
+	Seniority string
+	Location  string
+	WorkMode  string
+	PostingID pgtype.Int8
+	// Posting created with the batches, PostingID is ignored if it's set.
+	Posting *CreatePostingParams
+	// Document of the posting, stored after batches.
+	Document *IngestedDocument
+	// Batches of posting with PostingID are deleted before new ones are stored, so that
+	// reprocessed postings keep their batches if ingestion fails.
+	ReplaceBatches bool
+
+	Words   []IngestedWord
+	Phrases []IngestedPhrase
+	// Salaries of the word batch, BatchID is set on ingestion.
+	Salaries []CreateSalaryParams
+}
+
+// PostingBatchName returns name of batches of posting with id.
+func PostingBatchName(id int64) string {
+	return fmt.Sprintf("posting_%d", id)
+}
+
+// IngestResult counts rows stored by ingestion. Batch IDs are null if batches weren't created,
+// posting and document IDs are null if ingestion had none.
+type IngestResult struct {
+	PostingID     pgtype.Int8 `json:"posting_id"`
+	DocumentID    pgtype.Int8 `json:"document_id"`
+	WordBatchID   pgtype.Int8 `json:"word_batch_id"`
+	PhraseBatchID pgtype.Int8 `json:"phrase_batch_id"`
+	// Ingested words, repeated words included.
+	Words int64 `json:"words"`
+	// Terms added to vocabulary, or restored if they were deleted.
+	Terms int64 `json:"terms"`
+	// Occurrences of terms inserted or incremented.
+	Occurrences int64 `json:"occurrences"`
+	Phrases     int64 `json:"phrases"`
+	Salaries    int64 `json:"salaries"`
+}
+
+// Ingest stores batches of in with a single transaction, which is rolled back if any
+// of them fails. Word sets of at least CopyThreshold words are sent with COPY protocol.
+func (r *Repository) Ingest(ctx context.Context, in Ingestion) (IngestResult, error) {
+	var res IngestResult
+
+	if err := in.Validate(); err != nil {
+		return res, err
+	}
+
+	err := r.InTx(ctx, func(tx pgx.Tx) error {
+		q := r.WithTx(tx)
+
+		if in.Posting != nil {
+			row, err := q.CreatePosting(ctx, *in.Posting)
+			if err != nil {
+				return fmt.Errorf("create posting: %w", err)
+			}
+			res.PostingID = pgtype.Int8{Int64: row.ID, Valid: true}
+			in.PostingID = res.PostingID
+			if in.Name == "" {
+				in.Name = PostingBatchName(row.ID)
+			}
+		}
+
+		if in.ReplaceBatches && in.PostingID.Valid {
+			if err := q.DeletePostingBatches(ctx, in.PostingID.Int64); err != nil {
+				return fmt.Errorf("delete posting batches: %w", err)
+			}
+		}
+
+		if len(in.Words) > 0 {
+			if in.Name != "" {
+				id, err := q.CreateWordBatch(ctx, CreateWordBatchParams{
+					Name:      in.Name,
+					Seniority: in.Seniority,
+					Location:  in.Location,
+					WorkMode:  in.WorkMode,
+					PostingID: in.PostingID,
+				})
+				if err != nil {
+					return fmt.Errorf("create word batch: %w", err)
+				}
+				res.WordBatchID = pgtype.Int8{Int64: id, Valid: true}
+			}
+			if err := ingestWords(ctx, tx, res.WordBatchID, in.Words, &res); err != nil {
+				return err
+			}
+		}
+
+		for _, s := range in.Salaries {
+			s.BatchID = res.WordBatchID.Int64
+			if _, err := q.CreateSalary(ctx, s); err != nil {
+				return fmt.Errorf("create salary: %w", err)
+			}
+			res.Salaries++
+		}
+
+		if len(in.Phrases) > 0 {
+			params := CreatePhrasesBatchParams{
+				Name:      in.Name,
+				PostingID: in.PostingID,
+				Phrases:   make([]string, 0, len(in.Phrases)),
+				Languages: make([]string, 0, len(in.Phrases)),
+			}
+			for _, p := range in.Phrases {
+				params.Phrases = append(params.Phrases, p.Value)
+				params.Languages = append(params.Languages, p.Language)
+			}
+			row, err := q.CreatePhrasesBatch(ctx, params)
+			if err != nil {
+				return fmt.Errorf("create phrases batch: %w", err)
+			}
+			res.PhraseBatchID = row.BatchID
+			// Every phrase is inserted or the statement fails
+			res.Phrases = int64(len(in.Phrases))
+		}
+
+		if in.Document != nil {
+			doc := in.Document.Document
+			doc.PostingID = in.PostingID
+			if in.Document.Image != nil {
+				row, err := q.CreateImage(ctx, *in.Document.Image)
+				if err != nil {
+					return fmt.Errorf("create image: %w", err)
+				}
+				doc.ImageID = pgtype.Int8{Int64: row.ID, Valid: true}
+			}
+			row, err := q.CreateOcrDocument(ctx, doc)
+			if err != nil {
+				return fmt.Errorf("create ocr document: %w", err)
+			}
+			res.DocumentID = pgtype.Int8{Int64: row.ID, Valid: true}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return IngestResult{}, err
+	}
+
+	return res, nil
+}
+
+// Validate checks that batches of in can be named and owned and its document has a posting.
+func (in Ingestion) Validate() error {
+	if in.Name == "" && in.Posting == nil && (len(in.Phrases) > 0 || len(in.Salaries) > 0) {
+		return ErrNoBatchName
+	}
+	if len(in.Words) == 0 && len(in.Salaries) > 0 {
+		return ErrSalariesWithWords
+	}
+	if in.Document != nil && in.Posting == nil && !in.PostingID.Valid {
+		return ErrDocumentWithoutPosting
+	}
+
+	return nil
+}
+
+// InTx runs fn in a transaction, which is committed if fn returns nil and rolled back otherwise.
+func (r *Repository) InTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback transaction: %w", rbErr))
+		}
+
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Words are staged in a temporary table dropped on commit, then merged into vocabulary
+// and occurrences with set based statements.
+const (
+	createIngestedWords = `CREATE TEMPORARY TABLE ingested_words (
+    value TEXT NOT NULL,
+    lemma TEXT NOT NULL,
+    language TEXT NOT NULL
+) ON COMMIT DROP`
+
+	insertIngestedWords = `INSERT INTO ingested_words (value, lemma, language)
+SELECT * FROM UNNEST($1::text [], $2::text [], $3::text [])`
+
+	insertIngestedTerms = `INSERT INTO vocabulary (raw, normalized, lemma, language)
+SELECT DISTINCT ON (value, language)
+    value,
+    LOWER(value),
+    lemma,
+    language
+FROM ingested_words
+ORDER BY value, language, lemma DESC
+ON CONFLICT (raw, language) DO UPDATE
+SET deleted_at = NULL
+WHERE vocabulary.deleted_at IS NOT NULL`
+
+	updateIngestedLemmas = `UPDATE vocabulary
+SET lemma = w.lemma
+FROM ingested_words AS w
+WHERE
+    vocabulary.raw = w.value
+    AND vocabulary.language = w.language
+    AND vocabulary.lemma = ''
+    AND w.lemma <> ''`
+
+	// Occurrences of a batch are unique by term, occurrences without a batch by term
+	// and day, which is formatted into the statement as its conflict target.
+	insertIngestedOccurrences = `INSERT INTO occurrences (term_id, batch_id, count)
+SELECT
+    v.id,
+    $1::bigint,
+    COUNT(*)
+FROM ingested_words AS w
+INNER JOIN vocabulary AS v ON w.value = v.raw AND w.language = v.language
+GROUP BY v.id
+ON CONFLICT %s DO UPDATE
+SET
+    count = CASE
+        WHEN occurrences.deleted_at IS NULL
+            THEN occurrences.count + excluded.count
+        ELSE excluded.count
+    END,
+    deleted_at = NULL`
+
+	batchOccurrenceConflict = `(term_id, batch_id) WHERE batch_id IS NOT NULL`
+	dayOccurrenceConflict   = `(term_id, ((created_at AT TIME ZONE 'UTC')::date)) WHERE batch_id IS NULL`
+)
+
+// ingestWords stores words as occurrences in batch, or without a batch in occurrences of
+// the current day if batchID is null, and adds counts of stored rows to res.
+func ingestWords(ctx context.Context, tx pgx.Tx, batchID pgtype.Int8, words []IngestedWord, res *IngestResult) error {
+	if _, err := tx.Exec(ctx, createIngestedWords); err != nil {
+		return fmt.Errorf("create ingested words: %w", err)
+	}
+
+	if len(words) >= CopyThreshold {
+		n, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"ingested_words"},
+			[]string{"value", "lemma", "language"},
+			pgx.CopyFromSlice(len(words), func(i int) ([]any, error) {
+				return []any{words[i].Value, words[i].Lemma, words[i].Language}, nil
+			}),
+		)
+		if err != nil {
+			return fmt.Errorf("copy ingested words: %w", err)
+		}
+		res.Words = n
+	} else {
+		values := make([]string, 0, len(words))
+		lemmas := make([]string, 0, len(words))
+		languages := make([]string, 0, len(words))
+		for _, w := range words {
+			values = append(values, w.Value)
+			lemmas = append(lemmas, w.Lemma)
+			languages = append(languages, w.Language)
+		}
+		tag, err := tx.Exec(ctx, insertIngestedWords, values, lemmas, languages)
+		if err != nil {
+			return fmt.Errorf("insert ingested words: %w", err)
+		}
+		res.Words = tag.RowsAffected()
+	}
+
+	tag, err := tx.Exec(ctx, insertIngestedTerms)
+	if err != nil {
+		return fmt.Errorf("insert ingested terms: %w", err)
+	}
+	res.Terms = tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, updateIngestedLemmas); err != nil {
+		return fmt.Errorf("update ingested lemmas: %w", err)
+	}
+
+	conflict := batchOccurrenceConflict
+	if !batchID.Valid {
+		conflict = dayOccurrenceConflict
+	}
+	tag, err = tx.Exec(ctx, fmt.Sprintf(insertIngestedOccurrences, conflict), batchID)
+	if err != nil {
+		return fmt.Errorf("insert ingested occurrences: %w", err)
+	}
+	res.Occurrences = tag.RowsAffected()
+
+	return nil
+}
@@ -11,90 +11,576 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countWordBatchesInSet = `-- name: CountWordBatchesInSet :one
+SELECT COUNT(*) AS total
+FROM word_batches AS wb
+WHERE
+    wb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY($1::text []), 0) = 0
+        OR wb.name = ANY($1::text [])
+    )
+    AND (
+        $2::timestamptz IS NULL
+        OR wb.created_at >= $2::timestamptz
+    )
+    AND (
+        $3::timestamptz IS NULL
+        OR wb.created_at < $3::timestamptz
+    )
+    AND (
+        $4::text IS NULL
+        OR wb.seniority = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR LOWER(wb.location) = LOWER($5::text)
+    )
+    AND (
+        $6::text IS NULL
+        OR wb.work_mode = $6::text
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $7::text
+        )
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $8::text
+        )
+    )
+`
+
+type CountWordBatchesInSetParams struct {
+	Batches     []string           `json:"batches"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	Seniority   pgtype.Text        `json:"seniority"`
+	Location    pgtype.Text        `json:"location"`
+	WorkMode    pgtype.Text        `json:"work_mode"`
+	Tag         pgtype.Text        `json:"tag"`
+	Collection  pgtype.Text        `json:"collection"`
+}
+
+func (q *Queries) CountWordBatchesInSet(ctx context.Context, arg CountWordBatchesInSetParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countWordBatchesInSet,
+		arg.Batches,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+	)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createWord = `-- name: CreateWord :one
-INSERT INTO words (value, created_at)
-VALUES ($1, CURRENT_TIMESTAMP)
-RETURNING id, value, created_at
+WITH term AS (
+    INSERT INTO vocabulary (raw, normalized, lemma, language)
+    VALUES (
+        $1::text,
+        LOWER($1::text),
+        $2::text,
+        $3::text
+    )
+    ON CONFLICT (raw, language) DO UPDATE
+    SET
+        lemma = COALESCE(NULLIF(vocabulary.lemma, ''), excluded.lemma),
+        deleted_at = NULL
+    RETURNING id, raw, lemma, language, created_at
+),
+
+occurrence AS (
+    INSERT INTO occurrences (term_id, count)
+    SELECT
+        term.id,
+        1
+    FROM term
+    ON CONFLICT (
+        term_id, ((created_at AT TIME ZONE 'UTC')::date)
+    ) WHERE batch_id IS NULL DO UPDATE
+    SET
+        count = CASE
+            WHEN occurrences.deleted_at IS NULL THEN occurrences.count + 1
+            ELSE 1
+        END,
+        deleted_at = NULL
+)
+
+SELECT
+    term.id,
+    term.raw AS value,
+    term.lemma,
+    term.language,
+    term.created_at
+FROM term
 `
 
+type CreateWordParams struct {
+	Value    string `json:"value"`
+	Lemma    string `json:"lemma"`
+	Language string `json:"language"`
+}
+
 type CreateWordRow struct {
 	ID        int64              `json:"id"`
 	Value     string             `json:"value"`
+	Lemma     string             `json:"lemma"`
+	Language  string             `json:"language"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
-func (q *Queries) CreateWord(ctx context.Context, value string) (CreateWordRow, error) {
-	row := q.db.QueryRow(ctx, createWord, value)
+func (q *Queries) CreateWord(ctx context.Context, arg CreateWordParams) (CreateWordRow, error) {
+	row := q.db.QueryRow(ctx, createWord, arg.Value, arg.Lemma, arg.Language)
 	var i CreateWordRow
-	err := row.Scan(&i.ID, &i.Value, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.Value,
+		&i.Lemma,
+		&i.Language,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
+const createWordBatch = `-- name: CreateWordBatch :one
+INSERT INTO word_batches (name, seniority, location, work_mode, posting_id)
+VALUES (
+    $1,
+    NULLIF($2::text, ''),
+    NULLIF($3::text, ''),
+    NULLIF($4::text, ''),
+    $5::bigint
+)
+RETURNING id
+`
+
+type CreateWordBatchParams struct {
+	Name      string      `json:"name"`
+	Seniority string      `json:"seniority"`
+	Location  string      `json:"location"`
+	WorkMode  string      `json:"work_mode"`
+	PostingID pgtype.Int8 `json:"posting_id"`
+}
+
+func (q *Queries) CreateWordBatch(ctx context.Context, arg CreateWordBatchParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createWordBatch,
+		arg.Name,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.PostingID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const createWordsBatch = `-- name: CreateWordsBatch :one
 WITH new_batch AS (
-    INSERT INTO word_batches (name)
-    VALUES ($1)
+    INSERT INTO word_batches (name, seniority, location, work_mode, posting_id)
+    VALUES (
+        $1,
+        NULLIF($2::text, ''),
+        NULLIF($3::text, ''),
+        NULLIF($4::text, ''),
+        $5::bigint
+    )
     RETURNING id
+),
+
+word AS (
+    SELECT
+        word.value,
+        COALESCE(($6::text [])[word.position], '') AS lemma,
+        COALESCE(($7::text [])[word.position], '') AS language
+    FROM
+        UNNEST($8::text []) WITH ORDINALITY AS word (value, position)
+),
+
+terms AS (
+    INSERT INTO vocabulary (raw, normalized, lemma, language)
+    SELECT DISTINCT ON (word.value, word.language)
+        word.value,
+        LOWER(word.value),
+        word.lemma,
+        word.language
+    FROM word
+    ORDER BY word.value, word.language
+    ON CONFLICT (raw, language) DO UPDATE
+    SET
+        lemma = COALESCE(NULLIF(vocabulary.lemma, ''), excluded.lemma),
+        deleted_at = NULL
+    RETURNING id, raw, language
 )
 
-INSERT INTO words (value, batch_id)
+INSERT INTO occurrences (term_id, batch_id, count)
 SELECT
-    word_value,
-    (SELECT id FROM new_batch)
-FROM UNNEST($2::text []) AS word_value
-RETURNING id, value, batch_id
+    terms.id,
+    (SELECT new_batch.id FROM new_batch),
+    COUNT(*)
+FROM word
+INNER JOIN terms ON word.value = terms.raw AND word.language = terms.language
+GROUP BY terms.id
+RETURNING term_id, batch_id
 `
 
 type CreateWordsBatchParams struct {
-	Name    string   `json:"name"`
-	Column2 []string `json:"column_2"`
+	Name      string      `json:"name"`
+	Seniority string      `json:"seniority"`
+	Location  string      `json:"location"`
+	WorkMode  string      `json:"work_mode"`
+	PostingID pgtype.Int8 `json:"posting_id"`
+	Lemmas    []string    `json:"lemmas"`
+	Languages []string    `json:"languages"`
+	Words     []string    `json:"words"`
 }
 
 type CreateWordsBatchRow struct {
-	ID      int64       `json:"id"`
-	Value   string      `json:"value"`
+	TermID  int64       `json:"term_id"`
 	BatchID pgtype.Int8 `json:"batch_id"`
 }
 
 func (q *Queries) CreateWordsBatch(ctx context.Context, arg CreateWordsBatchParams) (CreateWordsBatchRow, error) {
-	row := q.db.QueryRow(ctx, createWordsBatch, arg.Name, arg.Column2)
+	row := q.db.QueryRow(ctx, createWordsBatch,
+		arg.Name,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.PostingID,
+		arg.Lemmas,
+		arg.Languages,
+		arg.Words,
+	)
 	var i CreateWordsBatchRow
-	err := row.Scan(&i.ID, &i.Value, &i.BatchID)
+	err := row.Scan(&i.TermID, &i.BatchID)
 	return i, err
 }
 
-const listWordBatches = `-- name: ListWordBatches :many
+const deleteWord = `-- name: DeleteWord :one
+WITH deleted AS (
+    UPDATE vocabulary
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE vocabulary.id = $1 AND vocabulary.deleted_at IS NULL
+    RETURNING vocabulary.id
+),
+
+deleted_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = CURRENT_TIMESTAMP
+    WHERE
+        occurrences.term_id IN (SELECT deleted.id FROM deleted)
+        AND occurrences.deleted_at IS NULL
+)
+
+SELECT deleted.id FROM deleted
+`
+
+func (q *Queries) DeleteWord(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteWord, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listBatchWordSets = `-- name: ListBatchWordSets :many
 SELECT
-    id,
-    name,
-    created_at
-FROM word_batches
-WHERE deleted_at IS NULL
-ORDER BY created_at ASC
+    wb.id AS batch_id,
+    ARRAY_AGG(DISTINCT v.normalized)::text [] AS words
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (
+        $1::text IS NULL
+        OR v.language = $1::text
+    )
+    AND (
+        $2::text IS NULL
+        OR wb.seniority = $2::text
+    )
+    AND (
+        $3::text IS NULL
+        OR LOWER(wb.location) = LOWER($3::text)
+    )
+    AND (
+        $4::text IS NULL
+        OR wb.work_mode = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $5::text
+        )
+    )
+    AND (
+        $6::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $6::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($7::text [], '{}')
+    )
+    AND (
+        $8::text [] IS NULL
+        OR v.normalized = ANY($8::text [])
+    )
+GROUP BY wb.id
+ORDER BY wb.id ASC
+`
+
+type ListBatchWordSetsParams struct {
+	Language   pgtype.Text `json:"language"`
+	Seniority  pgtype.Text `json:"seniority"`
+	Location   pgtype.Text `json:"location"`
+	WorkMode   pgtype.Text `json:"work_mode"`
+	Tag        pgtype.Text `json:"tag"`
+	Collection pgtype.Text `json:"collection"`
+	Excluded   []string    `json:"excluded"`
+	Skills     []string    `json:"skills"`
+}
+
+type ListBatchWordSetsRow struct {
+	BatchID int64    `json:"batch_id"`
+	Words   []string `json:"words"`
+}
+
+func (q *Queries) ListBatchWordSets(ctx context.Context, arg ListBatchWordSetsParams) ([]ListBatchWordSetsRow, error) {
+	rows, err := q.db.Query(ctx, listBatchWordSets,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+		arg.Skills,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBatchWordSetsRow
+	for rows.Next() {
+		var i ListBatchWordSetsRow
+		if err := rows.Scan(&i.BatchID, &i.Words); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLemmaFrequencies = `-- name: ListLemmaFrequencies :many
+SELECT
+    COALESCE(NULLIF(v.lemma, ''), v.raw)::text AS lemma,
+    SUM(o.count)::bigint AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        $3::text IS NULL
+        OR v.language = $3::text
+    )
+    AND (
+        $4::text IS NULL
+        OR wb.seniority = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR LOWER(wb.location) = LOWER($5::text)
+    )
+    AND (
+        $6::text IS NULL
+        OR wb.work_mode = $6::text
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $7::text
+        )
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $8::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($9::text [], '{}')
+    )
+GROUP BY COALESCE(NULLIF(v.lemma, ''), v.raw)
+ORDER BY total ASC
 LIMIT $1 OFFSET $2
 `
 
-type ListWordBatchesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListLemmaFrequenciesParams struct {
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	Language   pgtype.Text `json:"language"`
+	Seniority  pgtype.Text `json:"seniority"`
+	Location   pgtype.Text `json:"location"`
+	WorkMode   pgtype.Text `json:"work_mode"`
+	Tag        pgtype.Text `json:"tag"`
+	Collection pgtype.Text `json:"collection"`
+	Excluded   []string    `json:"excluded"`
 }
 
-type ListWordBatchesRow struct {
-	ID        int64              `json:"id"`
-	Name      string             `json:"name"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+type ListLemmaFrequenciesRow struct {
+	Lemma string `json:"lemma"`
+	Total int64  `json:"total"`
+}
+
+func (q *Queries) ListLemmaFrequencies(ctx context.Context, arg ListLemmaFrequenciesParams) ([]ListLemmaFrequenciesRow, error) {
+	rows, err := q.db.Query(ctx, listLemmaFrequencies,
+		arg.Limit,
+		arg.Offset,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLemmaFrequenciesRow
+	for rows.Next() {
+		var i ListLemmaFrequenciesRow
+		if err := rows.Scan(&i.Lemma, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWordCountsInSet = `-- name: ListWordCountsInSet :many
+SELECT
+    v.normalized AS value,
+    SUM(o.count)::bigint AS total,
+    COUNT(DISTINCT o.batch_id) AS postings
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (
+        COALESCE(CARDINALITY($1::text []), 0) = 0
+        OR wb.name = ANY($1::text [])
+    )
+    AND (
+        $2::timestamptz IS NULL
+        OR wb.created_at >= $2::timestamptz
+    )
+    AND (
+        $3::timestamptz IS NULL
+        OR wb.created_at < $3::timestamptz
+    )
+    AND (
+        $4::text IS NULL
+        OR v.language = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR wb.seniority = $5::text
+    )
+    AND (
+        $6::text IS NULL
+        OR LOWER(wb.location) = LOWER($6::text)
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.work_mode = $7::text
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $8::text
+        )
+    )
+    AND (
+        $9::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $9::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($10::text [], '{}')
+    )
+GROUP BY v.normalized
+ORDER BY v.normalized ASC
+`
+
+type ListWordCountsInSetParams struct {
+	Batches     []string           `json:"batches"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	Language    pgtype.Text        `json:"language"`
+	Seniority   pgtype.Text        `json:"seniority"`
+	Location    pgtype.Text        `json:"location"`
+	WorkMode    pgtype.Text        `json:"work_mode"`
+	Tag         pgtype.Text        `json:"tag"`
+	Collection  pgtype.Text        `json:"collection"`
+	Excluded    []string           `json:"excluded"`
 }
 
-func (q *Queries) ListWordBatches(ctx context.Context, arg ListWordBatchesParams) ([]ListWordBatchesRow, error) {
-	rows, err := q.db.Query(ctx, listWordBatches, arg.Limit, arg.Offset)
+type ListWordCountsInSetRow struct {
+	Value    string `json:"value"`
+	Total    int64  `json:"total"`
+	Postings int64  `json:"postings"`
+}
+
+func (q *Queries) ListWordCountsInSet(ctx context.Context, arg ListWordCountsInSetParams) ([]ListWordCountsInSetRow, error) {
+	rows, err := q.db.Query(ctx, listWordCountsInSet,
+		arg.Batches,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListWordBatchesRow
+	var items []ListWordCountsInSetRow
 	for rows.Next() {
-		var i ListWordBatchesRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+		var i ListWordCountsInSetRow
+		if err := rows.Scan(&i.Value, &i.Total, &i.Postings); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -107,18 +593,61 @@ func (q *Queries) ListWordBatches(ctx context.Context, arg ListWordBatchesParams
 
 const listWordFrequencies = `-- name: ListWordFrequencies :many
 SELECT
-    words.value,
-    COUNT(*) AS total
-FROM words
-WHERE words.deleted_at IS NULL
-GROUP BY words.value
+    v.raw AS value,
+    SUM(o.count)::bigint AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        $3::text IS NULL
+        OR v.language = $3::text
+    )
+    AND (
+        $4::text IS NULL
+        OR wb.seniority = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR LOWER(wb.location) = LOWER($5::text)
+    )
+    AND (
+        $6::text IS NULL
+        OR wb.work_mode = $6::text
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $7::text
+        )
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $8::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($9::text [], '{}')
+    )
+GROUP BY v.raw
 ORDER BY total ASC
 LIMIT $1 OFFSET $2
 `
 
 type ListWordFrequenciesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	Language   pgtype.Text `json:"language"`
+	Seniority  pgtype.Text `json:"seniority"`
+	Location   pgtype.Text `json:"location"`
+	WorkMode   pgtype.Text `json:"work_mode"`
+	Tag        pgtype.Text `json:"tag"`
+	Collection pgtype.Text `json:"collection"`
+	Excluded   []string    `json:"excluded"`
 }
 
 type ListWordFrequenciesRow struct {
@@ -127,7 +656,17 @@ type ListWordFrequenciesRow struct {
 }
 
 func (q *Queries) ListWordFrequencies(ctx context.Context, arg ListWordFrequenciesParams) ([]ListWordFrequenciesRow, error) {
-	rows, err := q.db.Query(ctx, listWordFrequencies, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listWordFrequencies,
+		arg.Limit,
+		arg.Offset,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -148,18 +687,61 @@ func (q *Queries) ListWordFrequencies(ctx context.Context, arg ListWordFrequenci
 
 const listWordRankings = `-- name: ListWordRankings :many
 SELECT
-    words.value,
-    ROW_NUMBER() OVER (ORDER BY COUNT(*) DESC) AS ranking
-FROM words
-WHERE words.deleted_at IS NULL
-GROUP BY words.value
+    v.raw AS value,
+    ROW_NUMBER() OVER (ORDER BY SUM(o.count) DESC) AS ranking
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND (
+        $3::text IS NULL
+        OR v.language = $3::text
+    )
+    AND (
+        $4::text IS NULL
+        OR wb.seniority = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR LOWER(wb.location) = LOWER($5::text)
+    )
+    AND (
+        $6::text IS NULL
+        OR wb.work_mode = $6::text
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $7::text
+        )
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $8::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($9::text [], '{}')
+    )
+GROUP BY v.raw
 ORDER BY ranking ASC
 LIMIT $1 OFFSET $2
 `
 
 type ListWordRankingsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	Language   pgtype.Text `json:"language"`
+	Seniority  pgtype.Text `json:"seniority"`
+	Location   pgtype.Text `json:"location"`
+	WorkMode   pgtype.Text `json:"work_mode"`
+	Tag        pgtype.Text `json:"tag"`
+	Collection pgtype.Text `json:"collection"`
+	Excluded   []string    `json:"excluded"`
 }
 
 type ListWordRankingsRow struct {
@@ -168,7 +750,17 @@ type ListWordRankingsRow struct {
 }
 
 func (q *Queries) ListWordRankings(ctx context.Context, arg ListWordRankingsParams) ([]ListWordRankingsRow, error) {
-	rows, err := q.db.Query(ctx, listWordRankings, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listWordRankings,
+		arg.Limit,
+		arg.Offset,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -187,14 +779,177 @@ func (q *Queries) ListWordRankings(ctx context.Context, arg ListWordRankingsPara
 	return items, nil
 }
 
+const listWordTrend = `-- name: ListWordTrend :many
+WITH bucket_postings AS (
+    SELECT
+        DATE_TRUNC(
+            $1::text, word_batches.created_at AT TIME ZONE 'UTC'
+        ) AS bucket,
+        COUNT(*) AS postings
+    FROM word_batches
+    WHERE
+        word_batches.deleted_at IS NULL
+        AND word_batches.created_at >= $2::timestamptz
+        AND (
+            $5::text IS NULL
+            OR word_batches.seniority = $5::text
+        )
+        AND (
+            $6::text IS NULL
+            OR LOWER(word_batches.location)
+            = LOWER($6::text)
+        )
+        AND (
+            $7::text IS NULL
+            OR word_batches.work_mode = $7::text
+        )
+        AND (
+            $8::text IS NULL
+            OR word_batches.id IN (
+                SELECT word_batch_tags.batch_id FROM word_batch_tags
+                WHERE word_batch_tags.tag = $8::text
+            )
+        )
+        AND (
+            $9::text IS NULL
+            OR word_batches.id IN (
+                SELECT word_batch_collections.batch_id FROM word_batch_collections
+                WHERE word_batch_collections.collection = $9::text
+            )
+        )
+    GROUP BY
+        DATE_TRUNC(
+            $1::text, word_batches.created_at AT TIME ZONE 'UTC'
+        )
+)
+
+SELECT
+    (
+        DATE_TRUNC($1::text, o.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+    )::timestamptz AS bucket,
+    v.normalized AS value,
+    SUM(o.count)::bigint AS total,
+    COUNT(DISTINCT o.batch_id) AS postings,
+    COALESCE(MAX(bp.postings), 0)::bigint AS bucket_postings
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+LEFT JOIN bucket_postings AS bp
+    ON DATE_TRUNC($1::text, o.created_at AT TIME ZONE 'UTC') = bp.bucket
+WHERE
+    o.deleted_at IS NULL
+    AND o.created_at >= $2::timestamptz
+    AND (
+        COALESCE(CARDINALITY($3::text []), 0) = 0
+        OR v.normalized = ANY($3::text [])
+    )
+    AND (
+        $4::text IS NULL
+        OR v.language = $4::text
+    )
+    AND (
+        $5::text IS NULL
+        OR wb.seniority = $5::text
+    )
+    AND (
+        $6::text IS NULL
+        OR LOWER(wb.location) = LOWER($6::text)
+    )
+    AND (
+        $7::text IS NULL
+        OR wb.work_mode = $7::text
+    )
+    AND (
+        $8::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = $8::text
+        )
+    )
+    AND (
+        $9::text IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = $9::text
+        )
+    )
+    AND NOT v.normalized = ANY(
+        COALESCE($10::text [], '{}')
+    )
+GROUP BY
+    DATE_TRUNC($1::text, o.created_at AT TIME ZONE 'UTC'),
+    v.normalized
+ORDER BY
+    DATE_TRUNC($1::text, o.created_at AT TIME ZONE 'UTC') ASC,
+    v.normalized ASC
+`
+
+type ListWordTrendParams struct {
+	Bucket     string             `json:"bucket"`
+	Since      pgtype.Timestamptz `json:"since"`
+	Words      []string           `json:"words"`
+	Language   pgtype.Text        `json:"language"`
+	Seniority  pgtype.Text        `json:"seniority"`
+	Location   pgtype.Text        `json:"location"`
+	WorkMode   pgtype.Text        `json:"work_mode"`
+	Tag        pgtype.Text        `json:"tag"`
+	Collection pgtype.Text        `json:"collection"`
+	Excluded   []string           `json:"excluded"`
+}
+
+type ListWordTrendRow struct {
+	Bucket         pgtype.Timestamptz `json:"bucket"`
+	Value          string             `json:"value"`
+	Total          int64              `json:"total"`
+	Postings       int64              `json:"postings"`
+	BucketPostings int64              `json:"bucket_postings"`
+}
+
+func (q *Queries) ListWordTrend(ctx context.Context, arg ListWordTrendParams) ([]ListWordTrendRow, error) {
+	rows, err := q.db.Query(ctx, listWordTrend,
+		arg.Bucket,
+		arg.Since,
+		arg.Words,
+		arg.Language,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Tag,
+		arg.Collection,
+		arg.Excluded,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWordTrendRow
+	for rows.Next() {
+		var i ListWordTrendRow
+		if err := rows.Scan(
+			&i.Bucket,
+			&i.Value,
+			&i.Total,
+			&i.Postings,
+			&i.BucketPostings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listWords = `-- name: ListWords :many
 SELECT
     id,
-    value,
+    raw AS value,
     created_at
-FROM words
+FROM vocabulary
 WHERE deleted_at IS NULL
-ORDER BY value ASC
+ORDER BY raw ASC
 LIMIT $1 OFFSET $2
 `
 
@@ -232,16 +987,19 @@ func (q *Queries) ListWords(ctx context.Context, arg ListWordsParams) ([]ListWor
 const listWordsByBatchName = `-- name: ListWordsByBatchName :many
 SELECT
     wb.name AS batch_name,
-    w.value AS word_value
+    v.raw AS word_value,
+    o.count
 FROM word_batches AS wb
-INNER JOIN words AS w ON wb.id = w.id
-WHERE wb.name = $1 AND wb.deleted_at IS NULL
-ORDER BY wb.created_at DESC
+INNER JOIN occurrences AS o ON wb.id = o.batch_id
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+WHERE wb.name = $1 AND wb.deleted_at IS NULL AND o.deleted_at IS NULL
+ORDER BY wb.created_at DESC, v.raw ASC
 `
 
 type ListWordsByBatchNameRow struct {
 	BatchName string `json:"batch_name"`
 	WordValue string `json:"word_value"`
+	Count     int32  `json:"count"`
 }
 
 func (q *Queries) ListWordsByBatchName(ctx context.Context, name string) ([]ListWordsByBatchNameRow, error) {
@@ -253,7 +1011,7 @@ func (q *Queries) ListWordsByBatchName(ctx context.Context, name string) ([]List
 	var items []ListWordsByBatchNameRow
 	for rows.Next() {
 		var i ListWordsByBatchNameRow
-		if err := rows.Scan(&i.BatchName, &i.WordValue); err != nil {
+		if err := rows.Scan(&i.BatchName, &i.WordValue, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -263,3 +1021,95 @@ func (q *Queries) ListWordsByBatchName(ctx context.Context, name string) ([]List
 	}
 	return items, nil
 }
+
+const restoreWord = `-- name: RestoreWord :one
+WITH term AS (
+    SELECT
+        vocabulary.id,
+        vocabulary.deleted_at
+    FROM vocabulary
+    WHERE vocabulary.id = $1 AND vocabulary.deleted_at IS NOT NULL
+),
+
+restored AS (
+    UPDATE vocabulary
+    SET deleted_at = NULL
+    FROM term
+    WHERE vocabulary.id = term.id
+    RETURNING vocabulary.id
+),
+
+restored_occurrences AS (
+    UPDATE occurrences
+    SET deleted_at = NULL
+    FROM term
+    WHERE
+        occurrences.term_id = term.id
+        AND occurrences.deleted_at = term.deleted_at
+)
+
+SELECT restored.id FROM restored
+`
+
+func (q *Queries) RestoreWord(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, restoreWord, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateWordBatchAttributes = `-- name: UpdateWordBatchAttributes :one
+UPDATE word_batches
+SET
+    seniority = CASE
+        WHEN $1::text IS NULL THEN seniority
+        ELSE NULLIF($1::text, '')
+    END,
+    location = CASE
+        WHEN $2::text IS NULL THEN location
+        ELSE NULLIF($2::text, '')
+    END,
+    work_mode = CASE
+        WHEN $3::text IS NULL THEN work_mode
+        ELSE NULLIF($3::text, '')
+    END
+WHERE name = $4 AND deleted_at IS NULL
+RETURNING
+    id,
+    name,
+    COALESCE(seniority, '')::text AS seniority,
+    COALESCE(location, '')::text AS location,
+    COALESCE(work_mode, '')::text AS work_mode
+`
+
+type UpdateWordBatchAttributesParams struct {
+	Seniority pgtype.Text `json:"seniority"`
+	Location  pgtype.Text `json:"location"`
+	WorkMode  pgtype.Text `json:"work_mode"`
+	Name      string      `json:"name"`
+}
+
+type UpdateWordBatchAttributesRow struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Seniority string `json:"seniority"`
+	Location  string `json:"location"`
+	WorkMode  string `json:"work_mode"`
+}
+
+func (q *Queries) UpdateWordBatchAttributes(ctx context.Context, arg UpdateWordBatchAttributesParams) (UpdateWordBatchAttributesRow, error) {
+	row := q.db.QueryRow(ctx, updateWordBatchAttributes,
+		arg.Seniority,
+		arg.Location,
+		arg.WorkMode,
+		arg.Name,
+	)
+	var i UpdateWordBatchAttributesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Seniority,
+		&i.Location,
+		&i.WorkMode,
+	)
+	return i, err
+}
@@ -0,0 +1,617 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+func byID(a, b *wordBatch) int {
+	return cmp.Compare(a.id, b.id)
+}
+
+// checkPhraseBatchName fails if a phrase batch other than id, deleted or not, is named name.
+func (s *Store) checkPhraseBatchName(name string, id int64) error {
+	if name == "" {
+		return checkViolation("phrase_batches", "phrase_batches_name_check")
+	}
+	for _, b := range s.phraseBatches {
+		if b.name == name && b.id != id {
+			return uniqueViolation("phrase_batches_name_unique")
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) createPhraseBatch(name string, postingID int64, now time.Time) *phraseBatch {
+	s.phraseBatchSeq++
+	b := &phraseBatch{id: s.phraseBatchSeq, name: name, postingID: postingID, createdAt: now}
+	s.phraseBatches[b.id] = b
+
+	return b
+}
+
+func (s *Store) CreatePhrasesBatch(ctx context.Context, arg database.CreatePhrasesBatchParams) (database.CreatePhrasesBatchRow, error) {
+	if slices.Contains(arg.Phrases, "") {
+		return database.CreatePhrasesBatchRow{}, checkViolation("phrases", "phrases_value_check")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkPhraseBatchPosting(arg.PostingID.Int64); err != nil {
+		return database.CreatePhrasesBatchRow{}, err
+	}
+	if err := s.checkPhraseBatchName(arg.Name, 0); err != nil {
+		return database.CreatePhrasesBatchRow{}, err
+	}
+	if len(arg.Phrases) == 0 {
+		return database.CreatePhrasesBatchRow{}, pgx.ErrNoRows
+	}
+
+	return s.createPhrases(arg.Name, arg.PostingID.Int64, arg.Phrases, arg.Languages, now()), nil
+}
+
+// createPhrases creates a batch of phrases with their languages and returns the first phrase.
+func (s *Store) createPhrases(name string, postingID int64, phrases, languages []string, now time.Time) database.CreatePhrasesBatchRow {
+	b := s.createPhraseBatch(name, postingID, now)
+
+	var first database.CreatePhrasesBatchRow
+	for i, value := range phrases {
+		s.phraseSeq++
+		p := &phrase{id: s.phraseSeq, value: value, batchID: b.id, createdAt: now}
+		if i < len(languages) {
+			p.language = languages[i]
+		}
+		s.phrases[p.id] = p
+		if i == 0 {
+			first = database.CreatePhrasesBatchRow{ID: p.id, BatchID: nullInt8(b.id)}
+		}
+	}
+
+	return first
+}
+
+// deleteWordBatch deletes b with its occurrences and salaries at time at.
+func (s *Store) deleteWordBatch(b *wordBatch, at time.Time) {
+	b.deletedAt = at
+	for _, o := range s.occurrences {
+		if o.batchID == b.id && o.deletedAt.IsZero() {
+			o.deletedAt = at
+		}
+	}
+	for _, sal := range s.salaries {
+		if sal.batchID == b.id && sal.deletedAt.IsZero() {
+			sal.deletedAt = at
+		}
+	}
+}
+
+// restoreWordBatch restores b with occurrences and salaries deleted with it.
+func (s *Store) restoreWordBatch(b *wordBatch) {
+	for _, o := range s.occurrences {
+		if o.batchID == b.id && o.deletedAt.Equal(b.deletedAt) {
+			o.deletedAt = time.Time{}
+		}
+	}
+	for _, sal := range s.salaries {
+		if sal.batchID == b.id && sal.deletedAt.Equal(b.deletedAt) {
+			sal.deletedAt = time.Time{}
+		}
+	}
+	b.deletedAt = time.Time{}
+}
+
+// deletePhraseBatch deletes b with its phrases at time at.
+func (s *Store) deletePhraseBatch(b *phraseBatch, at time.Time) {
+	b.deletedAt = at
+	for _, p := range s.phrases {
+		if p.batchID == b.id && p.deletedAt.IsZero() {
+			p.deletedAt = at
+		}
+	}
+}
+
+// restorePhraseBatch restores b with phrases deleted with it.
+func (s *Store) restorePhraseBatch(b *phraseBatch) {
+	for _, p := range s.phrases {
+		if p.batchID == b.id && p.deletedAt.Equal(b.deletedAt) {
+			p.deletedAt = time.Time{}
+		}
+	}
+	b.deletedAt = time.Time{}
+}
+
+// deletedPosting reports if batch of posting with id is deleted with its posting.
+func (s *Store) deletedPosting(id int64) bool {
+	p, ok := s.postings[id]
+
+	return ok && !p.deletedAt.IsZero()
+}
+
+func (s *Store) DeleteWordBatch(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.wordBatches[id]
+	if !ok || !b.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	s.deleteWordBatch(b, now())
+
+	return id, nil
+}
+
+// RestoreWordBatch restores a batch of a deleted posting only with its posting. Posting
+// of the batch can't have another batch which isn't deleted.
+func (s *Store) RestoreWordBatch(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.wordBatches[id]
+	if !ok || b.deletedAt.IsZero() || s.deletedPosting(b.postingID) {
+		return 0, pgx.ErrNoRows
+	}
+	if b.postingID != 0 && s.postingWordBatch(b.postingID) != nil {
+		return 0, uniqueViolation("idx_word_batches_posting_id")
+	}
+	s.restoreWordBatch(b)
+
+	return id, nil
+}
+
+func (s *Store) DeletePhraseBatch(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.phraseBatches[id]
+	if !ok || !b.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	s.deletePhraseBatch(b, now())
+
+	return id, nil
+}
+
+// RestorePhraseBatch restores a batch of a deleted posting only with its posting. Posting
+// of the batch can't have another batch which isn't deleted.
+func (s *Store) RestorePhraseBatch(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.phraseBatches[id]
+	if !ok || b.deletedAt.IsZero() || s.deletedPosting(b.postingID) {
+		return 0, pgx.ErrNoRows
+	}
+	if b.postingID != 0 && s.postingPhraseBatch(b.postingID) != nil {
+		return 0, uniqueViolation("idx_phrase_batches_posting_id")
+	}
+	s.restorePhraseBatch(b)
+
+	return id, nil
+}
+
+// purged reports if t is a time of soft delete before before.
+func purged(t, before time.Time) bool {
+	return !t.IsZero() && t.Before(before)
+}
+
+// PurgeDeleted counts rows deleted before deletedBefore, like a single statement does,
+// then deletes them with rows referencing them. Batches and documents of purged postings
+// are deleted even if they aren't soft deleted, like with cascading foreign keys.
+func (s *Store) PurgeDeleted(ctx context.Context, deletedBefore pgtype.Timestamptz) (database.PurgeDeletedRow, error) {
+	var row database.PurgeDeletedRow
+	if !deletedBefore.Valid {
+		return row, nil
+	}
+	before := deletedBefore.Time
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purgedPostings := make(map[int64]bool)
+	for id, p := range s.postings {
+		if purged(p.deletedAt, before) {
+			purgedPostings[id] = true
+			row.Postings++
+		}
+	}
+	purgedWordBatches := make(map[int64]bool)
+	for id, b := range s.wordBatches {
+		gone := purged(b.deletedAt, before)
+		if gone {
+			row.WordBatches++
+		}
+		if gone || purgedPostings[b.postingID] {
+			purgedWordBatches[id] = true
+		}
+	}
+	purgedPhraseBatches := make(map[int64]bool)
+	for id, b := range s.phraseBatches {
+		gone := purged(b.deletedAt, before)
+		if gone {
+			row.PhraseBatches++
+		}
+		if gone || purgedPostings[b.postingID] {
+			purgedPhraseBatches[id] = true
+		}
+	}
+	purgedTerms := make(map[int64]bool)
+	for id, t := range s.terms {
+		if purged(t.deletedAt, before) {
+			purgedTerms[id] = true
+			row.Words++
+		}
+	}
+
+	for _, o := range s.occurrences {
+		gone := purged(o.deletedAt, before)
+		if gone {
+			row.Occurrences++
+		}
+		if gone || purgedTerms[o.termID] || purgedWordBatches[o.batchID] {
+			s.deleteOccurrence(o)
+		}
+	}
+	for id, p := range s.phrases {
+		gone := purged(p.deletedAt, before)
+		if gone {
+			row.Phrases++
+		}
+		if gone || purgedPhraseBatches[p.batchID] {
+			delete(s.phrases, id)
+		}
+	}
+	for id, sal := range s.salaries {
+		gone := purged(sal.deletedAt, before)
+		if gone {
+			row.Salaries++
+		}
+		if gone || purgedWordBatches[sal.batchID] {
+			delete(s.salaries, id)
+		}
+	}
+	for id, d := range s.documents {
+		gone := purged(d.deletedAt, before)
+		if gone {
+			row.Documents++
+		}
+		if gone || purgedPostings[d.postingID] {
+			delete(s.documents, id)
+		}
+	}
+	for id := range purgedTerms {
+		delete(s.termKeys, termKey{raw: s.terms[id].raw, language: s.terms[id].language})
+		delete(s.terms, id)
+	}
+	for id := range purgedWordBatches {
+		delete(s.wordBatches, id)
+	}
+	for id := range purgedPhraseBatches {
+		delete(s.phraseBatches, id)
+	}
+	for id := range purgedPostings {
+		delete(s.postings, id)
+	}
+	for _, g := range []*groups{s.tags, s.collections} {
+		g.members = slices.DeleteFunc(g.members, func(m *member) bool {
+			return purgedPostings[m.postingID] || purgedWordBatches[m.wordBatchID] || purgedPhraseBatches[m.phraseBatchID]
+		})
+	}
+
+	return row, nil
+}
+
+// wordBatchRow returns b with counts of its terms and their occurrences.
+func (s *Store) wordBatchRow(b *wordBatch) database.ListBatchesRow {
+	row := database.ListBatchesRow{
+		Kind:      "words",
+		ID:        b.id,
+		Name:      b.name,
+		Seniority: b.seniority,
+		Location:  b.location,
+		WorkMode:  b.workMode,
+		PostingID: nullInt8(b.postingID),
+		CreatedAt: timestamptz(b.createdAt),
+	}
+	for _, o := range s.occurrences {
+		if o.batchID == b.id && o.deletedAt.IsZero() {
+			row.Items++
+			row.Total += int64(o.count)
+		}
+	}
+
+	return row
+}
+
+// phraseBatchRow returns b with counts of its distinct phrases and all of its phrases.
+func (s *Store) phraseBatchRow(b *phraseBatch) database.ListBatchesRow {
+	row := database.ListBatchesRow{
+		Kind:      "phrases",
+		ID:        b.id,
+		Name:      b.name,
+		PostingID: nullInt8(b.postingID),
+		CreatedAt: timestamptz(b.createdAt),
+	}
+	values := make(map[string]bool)
+	for _, p := range s.phrases {
+		if p.batchID == b.id && p.deletedAt.IsZero() {
+			values[p.value] = true
+			row.Total++
+		}
+	}
+	row.Items = int64(len(values))
+
+	return row
+}
+
+func (s *Store) ListBatches(ctx context.Context, arg database.ListBatchesParams) ([]database.ListBatchesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	match := func(kind, name string, created time.Time) bool {
+		return (!arg.Kind.Valid || kind == arg.Kind.String) &&
+			(!arg.CreatedFrom.Valid || !created.Before(arg.CreatedFrom.Time)) &&
+			(!arg.CreatedTo.Valid || created.Before(arg.CreatedTo.Time)) &&
+			(!arg.Prefix.Valid || strings.HasPrefix(name, arg.Prefix.String))
+	}
+
+	rows := make([]database.ListBatchesRow, 0)
+	for _, b := range s.wordBatches {
+		if b.deletedAt.IsZero() && match("words", b.name, b.createdAt) {
+			rows = append(rows, s.wordBatchRow(b))
+		}
+	}
+	for _, b := range s.phraseBatches {
+		if b.deletedAt.IsZero() && match("phrases", b.name, b.createdAt) {
+			rows = append(rows, s.phraseBatchRow(b))
+		}
+	}
+	slices.SortFunc(rows, func(a, b database.ListBatchesRow) int {
+		return cmp.Or(
+			a.CreatedAt.Time.Compare(b.CreatedAt.Time),
+			strings.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) GetWordBatch(ctx context.Context, id int64) (database.GetWordBatchRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.wordBatches[id]
+	if !ok || !b.deletedAt.IsZero() {
+		return database.GetWordBatchRow{}, pgx.ErrNoRows
+	}
+
+	return database.GetWordBatchRow(s.wordBatchRow(b)), nil
+}
+
+func (s *Store) GetPhraseBatch(ctx context.Context, id int64) (database.GetPhraseBatchRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.phraseBatches[id]
+	if !ok || !b.deletedAt.IsZero() {
+		return database.GetPhraseBatchRow{}, pgx.ErrNoRows
+	}
+
+	return database.GetPhraseBatchRow(s.phraseBatchRow(b)), nil
+}
+
+func (s *Store) ListBatchWords(ctx context.Context, batchID int64) ([]database.ListBatchWordsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []database.ListBatchWordsRow
+	for _, o := range s.occurrences {
+		t := s.terms[o.termID]
+		if o.batchID == batchID && o.deletedAt.IsZero() && t.deletedAt.IsZero() {
+			items = append(items, database.ListBatchWordsRow{
+				ID:       t.id,
+				Value:    t.raw,
+				Lemma:    t.lemma,
+				Language: t.language,
+				Count:    o.count,
+			})
+		}
+	}
+	slices.SortFunc(items, func(a, b database.ListBatchWordsRow) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+	})
+
+	return items, nil
+}
+
+func (s *Store) ListBatchPhrases(ctx context.Context, batchID int64) ([]database.ListBatchPhrasesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []database.ListBatchPhrasesRow
+	for _, p := range s.phrases {
+		if p.batchID == batchID && p.deletedAt.IsZero() {
+			items = append(items, database.ListBatchPhrasesRow{ID: p.id, Value: p.value, Language: p.language})
+		}
+	}
+	slices.SortFunc(items, func(a, b database.ListBatchPhrasesRow) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return items, nil
+}
+
+func (s *Store) RenameWordBatch(ctx context.Context, arg database.RenameWordBatchParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.wordBatches[arg.ID]
+	if !ok || !b.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	b.name = arg.Name
+
+	return b.id, nil
+}
+
+func (s *Store) RenamePhraseBatch(ctx context.Context, arg database.RenamePhraseBatchParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.phraseBatches[arg.ID]
+	if !ok || !b.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	if err := s.checkPhraseBatchName(arg.Name, b.id); err != nil {
+		return 0, err
+	}
+	b.name = arg.Name
+
+	return b.id, nil
+}
+
+func (s *Store) MergeWordBatches(ctx context.Context, arg database.MergeWordBatchesParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, target := s.wordBatches[arg.Source], s.wordBatches[arg.Target]
+	if source == nil || target == nil || source == target || !source.deletedAt.IsZero() || !target.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	source.deletedAt = now()
+
+	var moved int64
+	for _, o := range sorted(s.occurrences, func(a, b *occurrence) int { return cmp.Compare(a.id, b.id) }) {
+		if o.batchID != source.id || !o.deletedAt.IsZero() {
+			continue
+		}
+		s.deleteOccurrence(o)
+		s.upsertOccurrence(o.termID, target.id, o.count, o.createdAt)
+		moved++
+	}
+	for _, sal := range s.salaries {
+		if sal.batchID == source.id && sal.deletedAt.IsZero() {
+			sal.batchID = target.id
+		}
+	}
+
+	return moved, nil
+}
+
+func (s *Store) MergePhraseBatches(ctx context.Context, arg database.MergePhraseBatchesParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, target := s.phraseBatches[arg.Source], s.phraseBatches[arg.Target]
+	if source == nil || target == nil || source == target || !source.deletedAt.IsZero() || !target.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	source.deletedAt = now()
+
+	var moved int64
+	for _, p := range s.phrases {
+		if p.batchID == source.id && p.deletedAt.IsZero() {
+			p.batchID = target.id
+			moved++
+		}
+	}
+
+	return moved, nil
+}
+
+func (s *Store) SplitWordBatch(ctx context.Context, arg database.SplitWordBatchParams) (database.SplitWordBatchRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.wordBatches[arg.ID]
+	if !ok || !source.deletedAt.IsZero() {
+		return database.SplitWordBatchRow{}, pgx.ErrNoRows
+	}
+	created := s.createWordBatch(database.CreateWordBatchParams{
+		Name:      arg.Name,
+		Seniority: source.seniority,
+		Location:  source.location,
+		WorkMode:  source.workMode,
+	}, now())
+
+	row := database.SplitWordBatchRow{ID: created.id}
+	for _, o := range s.occurrences {
+		if o.batchID != source.id || !o.deletedAt.IsZero() || !slices.Contains(arg.Values, s.terms[o.termID].normalized) {
+			continue
+		}
+		delete(s.occurrenceIDs, newOccurrenceKey(o.termID, o.batchID, o.createdAt))
+		o.batchID = created.id
+		s.occurrenceIDs[newOccurrenceKey(o.termID, o.batchID, o.createdAt)] = o.id
+		row.Moved++
+	}
+
+	return row, nil
+}
+
+func (s *Store) SplitPhraseBatch(ctx context.Context, arg database.SplitPhraseBatchParams) (database.SplitPhraseBatchRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.phraseBatches[arg.ID]
+	if !ok || !source.deletedAt.IsZero() {
+		return database.SplitPhraseBatchRow{}, pgx.ErrNoRows
+	}
+	if err := s.checkPhraseBatchName(arg.Name, 0); err != nil {
+		return database.SplitPhraseBatchRow{}, err
+	}
+	created := s.createPhraseBatch(arg.Name, 0, now())
+
+	row := database.SplitPhraseBatchRow{ID: created.id}
+	for _, p := range s.phrases {
+		if p.batchID == source.id && p.deletedAt.IsZero() && slices.Contains(arg.Values, p.value) {
+			p.batchID = created.id
+			row.Moved++
+		}
+	}
+
+	return row, nil
+}
+
+func (s *Store) ListPhraseCountsInSet(ctx context.Context, arg database.ListPhraseCountsInSetParams) ([]database.ListPhraseCountsInSetRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := set{arg.Batches, arg.CreatedFrom, arg.CreatedTo}
+	groups := make(map[string]*trendRow)
+	for _, p := range s.phrases {
+		b := s.phraseBatches[p.batchID]
+		if b == nil || !p.deletedAt.IsZero() || !b.deletedAt.IsZero() || !st.has(b.name, b.createdAt) ||
+			!equal(p.language, arg.Language) ||
+			(arg.Tag.Valid && !s.tags.has(arg.Tag.String, member{postingID: b.postingID, phraseBatchID: b.id})) ||
+			(arg.Collection.Valid && !s.collections.has(arg.Collection.String, member{postingID: b.postingID, phraseBatchID: b.id})) {
+			continue
+		}
+		value := strings.ToLower(p.value)
+		g, ok := groups[value]
+		if !ok {
+			g = &trendRow{postings: make(map[int64]bool)}
+			groups[value] = g
+		}
+		g.total++
+		g.postings[b.id] = true
+	}
+
+	var items []database.ListPhraseCountsInSetRow
+	for value, g := range groups {
+		items = append(items, database.ListPhraseCountsInSetRow{Value: value, Total: g.total, Postings: int64(len(g.postings))})
+	}
+	slices.SortFunc(items, func(a, b database.ListPhraseCountsInSetRow) int {
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	return items, nil
+}
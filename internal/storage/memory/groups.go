@@ -0,0 +1,386 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// group is a tag or a collection, tags have no description.
+type group struct {
+	id          int64
+	name        string
+	description string
+	createdAt   time.Time
+}
+
+// member is a posting, a word batch or a phrase batch of a group, other IDs are zero.
+type member struct {
+	groupID       int64
+	postingID     int64
+	wordBatchID   int64
+	phraseBatchID int64
+	createdAt     time.Time
+}
+
+func newMember(posting, wordBatch, phraseBatch pgtype.Int8) member {
+	return member{postingID: posting.Int64, wordBatchID: wordBatch.Int64, phraseBatchID: phraseBatch.Int64}
+}
+
+func (m member) same(o *member) bool {
+	return m.postingID == o.postingID && m.wordBatchID == o.wordBatchID && m.phraseBatchID == o.phraseBatchID
+}
+
+// groups are tags or collections with their members, in order of addition.
+type groups struct {
+	table   string
+	seq     int64
+	byName  map[string]*group
+	members []*member
+}
+
+func newGroups(table string) *groups {
+	return &groups{table: table, byName: make(map[string]*group)}
+}
+
+func (g *groups) create(name, description string, now time.Time) (*group, error) {
+	if name == "" {
+		return nil, checkViolation(g.table, g.table+"_name_check")
+	}
+	if _, ok := g.byName[name]; ok {
+		return nil, uniqueViolation(g.table + "_name_unique")
+	}
+	g.seq++
+	gr := &group{id: g.seq, name: name, description: description, createdAt: now}
+	g.byName[name] = gr
+
+	return gr, nil
+}
+
+// has reports if batch of m is a member of group named name, directly or with posting
+// of m which owns the batch.
+func (g *groups) has(name string, m member) bool {
+	gr, ok := g.byName[name]
+	if !ok {
+		return false
+	}
+
+	return slices.ContainsFunc(g.members, func(o *member) bool {
+		return o.groupID == gr.id &&
+			((m.postingID != 0 && o.postingID == m.postingID) ||
+				(m.wordBatchID != 0 && o.wordBatchID == m.wordBatchID) ||
+				(m.phraseBatchID != 0 && o.phraseBatchID == m.phraseBatchID))
+	})
+}
+
+// row returns gr with numbers of its members of each type.
+func (g *groups) row(gr *group) database.ListTagsRow {
+	row := database.ListTagsRow{
+		ID:          gr.id,
+		Name:        gr.name,
+		Description: gr.description,
+		CreatedAt:   timestamptz(gr.createdAt),
+	}
+	for _, m := range g.members {
+		if m.groupID != gr.id {
+			continue
+		}
+		switch {
+		case m.postingID != 0:
+			row.Postings++
+		case m.wordBatchID != 0:
+			row.WordBatches++
+		default:
+			row.PhraseBatches++
+		}
+	}
+
+	return row
+}
+
+func (g *groups) list(limit, offset int32) []database.ListTagsRow {
+	var rows []database.ListTagsRow
+	for _, gr := range page(sorted(g.byName, func(a, b *group) int { return strings.Compare(a.name, b.name) }), limit, offset) {
+		rows = append(rows, g.row(gr))
+	}
+
+	return rows
+}
+
+func (g *groups) get(name string) (database.ListTagsRow, error) {
+	gr, ok := g.byName[name]
+	if !ok {
+		return database.ListTagsRow{}, pgx.ErrNoRows
+	}
+
+	return g.row(gr), nil
+}
+
+// delete deletes group named name with its members.
+func (g *groups) delete(name string) (int64, error) {
+	gr, ok := g.byName[name]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	delete(g.byName, name)
+	g.members = slices.DeleteFunc(g.members, func(m *member) bool {
+		return m.groupID == gr.id
+	})
+
+	return gr.id, nil
+}
+
+// add adds m to gr, adding it again does nothing.
+func (g *groups) add(gr *group, m member, now time.Time) {
+	m.groupID = gr.id
+	if slices.ContainsFunc(g.members, func(o *member) bool { return o.groupID == gr.id && m.same(o) }) {
+		return
+	}
+	m.createdAt = now
+	g.members = append(g.members, &m)
+}
+
+func (g *groups) remove(name string, m member) (int64, error) {
+	gr, ok := g.byName[name]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	n := len(g.members)
+	g.members = slices.DeleteFunc(g.members, func(o *member) bool {
+		return o.groupID == gr.id && m.same(o)
+	})
+	if len(g.members) == n {
+		return 0, pgx.ErrNoRows
+	}
+
+	return gr.id, nil
+}
+
+// checkMember fails like constraints of member tables if m isn't exactly one of
+// existing postings and batches, deleted or not.
+func (s *Store) checkMember(g *groups, m member) error {
+	table := strings.TrimSuffix(g.table, "s") + "_members"
+
+	n := 0
+	for _, id := range []int64{m.postingID, m.wordBatchID, m.phraseBatchID} {
+		if id != 0 {
+			n++
+		}
+	}
+	if n != 1 {
+		return checkViolation(table, table+"_check")
+	}
+
+	switch {
+	case m.postingID != 0:
+		return s.checkPosting(table, m.postingID)
+	case m.wordBatchID != 0:
+		if _, ok := s.wordBatches[m.wordBatchID]; !ok {
+			return foreignKeyViolation(table, table+"_word_batch_id_fkey")
+		}
+	default:
+		if _, ok := s.phraseBatches[m.phraseBatchID]; !ok {
+			return foreignKeyViolation(table, table+"_phrase_batch_id_fkey")
+		}
+	}
+
+	return nil
+}
+
+// members returns members of group named name, except deleted postings and batches.
+func (s *Store) members(g *groups, name string) []database.ListTagMembersRow {
+	gr, ok := g.byName[name]
+	if !ok {
+		return nil
+	}
+
+	var rows []database.ListTagMembersRow
+	for _, m := range g.members {
+		if m.groupID != gr.id {
+			continue
+		}
+		row := database.ListTagMembersRow{CreatedAt: timestamptz(m.createdAt)}
+		switch {
+		case m.postingID != 0:
+			p := s.postings[m.postingID]
+			if !p.deletedAt.IsZero() {
+				continue
+			}
+			row.Type, row.ID, row.Name = "posting", p.id, p.title
+		case m.wordBatchID != 0:
+			b := s.wordBatches[m.wordBatchID]
+			if !b.deletedAt.IsZero() {
+				continue
+			}
+			row.Type, row.ID, row.Name = "words", b.id, b.name
+		default:
+			b := s.phraseBatches[m.phraseBatchID]
+			if !b.deletedAt.IsZero() {
+				continue
+			}
+			row.Type, row.ID, row.Name = "phrases", b.id, b.name
+		}
+		rows = append(rows, row)
+	}
+	slices.SortStableFunc(rows, func(a, b database.ListTagMembersRow) int {
+		return cmp.Compare(a.CreatedAt.Time.UnixMicro(), b.CreatedAt.Time.UnixMicro())
+	})
+
+	return rows
+}
+
+func (s *Store) CreateTag(ctx context.Context, name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gr, err := s.tags.create(name, "", now())
+	if err != nil {
+		return 0, err
+	}
+
+	return gr.id, nil
+}
+
+func (s *Store) ListTags(ctx context.Context, arg database.ListTagsParams) ([]database.ListTagsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tags.list(arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) GetTag(ctx context.Context, name string) (database.GetTagRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, err := s.tags.get(name)
+
+	return database.GetTagRow(row), err
+}
+
+func (s *Store) DeleteTag(ctx context.Context, name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tags.delete(name)
+}
+
+func (s *Store) ListTagMembers(ctx context.Context, name string) ([]database.ListTagMembersRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.members(s.tags, name), nil
+}
+
+// AddTagMember creates tag named arg.Name on first use.
+func (s *Store) AddTagMember(ctx context.Context, arg database.AddTagMemberParams) (int64, error) {
+	m := newMember(arg.PostingID, arg.WordBatchID, arg.PhraseBatchID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkMember(s.tags, m); err != nil {
+		return 0, err
+	}
+	now := now()
+	gr, ok := s.tags.byName[arg.Name]
+	if !ok {
+		var err error
+		if gr, err = s.tags.create(arg.Name, "", now); err != nil {
+			return 0, err
+		}
+	}
+	s.tags.add(gr, m, now)
+
+	return gr.id, nil
+}
+
+func (s *Store) RemoveTagMember(ctx context.Context, arg database.RemoveTagMemberParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tags.remove(arg.Name, newMember(arg.PostingID, arg.WordBatchID, arg.PhraseBatchID))
+}
+
+func (s *Store) CreateCollection(ctx context.Context, arg database.CreateCollectionParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gr, err := s.collections.create(arg.Name, arg.Description, now())
+	if err != nil {
+		return 0, err
+	}
+
+	return gr.id, nil
+}
+
+func (s *Store) ListCollections(ctx context.Context, arg database.ListCollectionsParams) ([]database.ListCollectionsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []database.ListCollectionsRow
+	for _, row := range s.collections.list(arg.Limit, arg.Offset) {
+		rows = append(rows, database.ListCollectionsRow(row))
+	}
+
+	return rows, nil
+}
+
+func (s *Store) GetCollection(ctx context.Context, name string) (database.GetCollectionRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, err := s.collections.get(name)
+
+	return database.GetCollectionRow(row), err
+}
+
+func (s *Store) DeleteCollection(ctx context.Context, name string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.collections.delete(name)
+}
+
+func (s *Store) ListCollectionMembers(ctx context.Context, name string) ([]database.ListCollectionMembersRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []database.ListCollectionMembersRow
+	for _, row := range s.members(s.collections, name) {
+		rows = append(rows, database.ListCollectionMembersRow(row))
+	}
+
+	return rows, nil
+}
+
+// AddCollectionMember adds a member of an existing collection only.
+func (s *Store) AddCollectionMember(ctx context.Context, arg database.AddCollectionMemberParams) (int64, error) {
+	m := newMember(arg.PostingID, arg.WordBatchID, arg.PhraseBatchID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gr, ok := s.collections.byName[arg.Name]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	if err := s.checkMember(s.collections, m); err != nil {
+		return 0, err
+	}
+	s.collections.add(gr, m, now())
+
+	return gr.id, nil
+}
+
+func (s *Store) RemoveCollectionMember(ctx context.Context, arg database.RemoveCollectionMemberParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.collections.remove(arg.Name, newMember(arg.PostingID, arg.WordBatchID, arg.PhraseBatchID))
+}
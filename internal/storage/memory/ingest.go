@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// Ingest stores batches of in all or nothing, counts of result match the Postgres repository.
+// Constraints are checked before anything is stored, so a failed ingestion changes nothing.
+func (s *Store) Ingest(ctx context.Context, in database.Ingestion) (database.IngestResult, error) {
+	var res database.IngestResult
+
+	if err := in.Validate(); err != nil {
+		return res, err
+	}
+	if slices.ContainsFunc(in.Words, func(w database.IngestedWord) bool { return w.Value == "" }) {
+		return res, checkViolation("vocabulary", "vocabulary_raw_check")
+	}
+	if slices.ContainsFunc(in.Phrases, func(p database.IngestedPhrase) bool { return p.Value == "" }) {
+		return res, checkViolation("phrases", "phrases_value_check")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Posting created by ingestion takes the next ID and it has no batches yet.
+	postingID := in.PostingID.Int64
+	if in.Posting != nil {
+		postingID = s.postingSeq + 1
+		if in.Name == "" {
+			in.Name = database.PostingBatchName(postingID)
+		}
+	} else if in.ReplaceBatches {
+		// Batches of posting are deleted before new ones are stored
+		if len(in.Words) > 0 && in.Name != "" {
+			if err := s.checkPosting("word_batches", postingID); err != nil {
+				return res, err
+			}
+		}
+		if len(in.Phrases) > 0 {
+			if err := s.checkPosting("phrase_batches", postingID); err != nil {
+				return res, err
+			}
+		}
+	} else {
+		if len(in.Words) > 0 && in.Name != "" {
+			if err := s.checkWordBatchPosting(postingID); err != nil {
+				return res, err
+			}
+		}
+		if len(in.Phrases) > 0 {
+			if err := s.checkPhraseBatchPosting(postingID); err != nil {
+				return res, err
+			}
+		}
+	}
+	if len(in.Phrases) > 0 {
+		if err := s.checkPhraseBatchName(in.Name, 0); err != nil {
+			return res, err
+		}
+	}
+	if in.Document != nil {
+		// Posting and image created by ingestion don't exist yet, so they aren't checked.
+		doc := in.Document.Document
+		doc.PostingID = in.PostingID
+		if in.Posting != nil {
+			doc.PostingID = pgtype.Int8{}
+		}
+		if in.Document.Image != nil {
+			doc.ImageID = pgtype.Int8{}
+		}
+		if err := s.checkOcrDocument(doc); err != nil {
+			return res, err
+		}
+	}
+	now := now()
+
+	if in.Posting != nil {
+		s.createPosting(*in.Posting, now)
+		res.PostingID = nullInt8(postingID)
+	}
+	if in.ReplaceBatches && in.PostingID.Valid {
+		s.deletePostingBatches(postingID, now)
+	}
+
+	if len(in.Words) > 0 {
+		var batchID int64
+		if in.Name != "" {
+			batchID = s.createWordBatch(database.CreateWordBatchParams{
+				Name:      in.Name,
+				Seniority: in.Seniority,
+				Location:  in.Location,
+				WorkMode:  in.WorkMode,
+				PostingID: nullInt8(postingID),
+			}, now).id
+			res.WordBatchID = nullInt8(batchID)
+		}
+		res.Words = int64(len(in.Words))
+
+		// Terms in order of their first occurrence, with the greatest lemma of their words.
+		keys := make([]termKey, 0)
+		lemmas := make(map[termKey]string)
+		counts := make(map[termKey]int32)
+		for _, w := range in.Words {
+			key := termKey{raw: w.Value, language: w.Language}
+			if _, ok := counts[key]; !ok {
+				keys = append(keys, key)
+			}
+			counts[key]++
+			lemmas[key] = max(lemmas[key], w.Lemma)
+		}
+		for _, key := range keys {
+			t, stored := s.upsertTerm(key.raw, lemmas[key], key.language, now)
+			if stored {
+				res.Terms++
+			}
+			s.upsertOccurrence(t.id, batchID, counts[key], now)
+			res.Occurrences++
+		}
+	}
+
+	for _, sal := range in.Salaries {
+		sal.BatchID = res.WordBatchID.Int64
+		s.createSalary(sal, now)
+		res.Salaries++
+	}
+
+	if len(in.Phrases) > 0 {
+		phrases := make([]string, 0, len(in.Phrases))
+		languages := make([]string, 0, len(in.Phrases))
+		for _, p := range in.Phrases {
+			phrases = append(phrases, p.Value)
+			languages = append(languages, p.Language)
+		}
+		row := s.createPhrases(in.Name, postingID, phrases, languages, now)
+		res.PhraseBatchID = row.BatchID
+		res.Phrases = int64(len(in.Phrases))
+	}
+
+	if in.Document != nil {
+		doc := in.Document.Document
+		doc.PostingID = nullInt8(postingID)
+		if in.Document.Image != nil {
+			doc.ImageID = nullInt8(s.createImage(*in.Document.Image, now).id)
+		}
+		res.DocumentID = nullInt8(s.createOcrDocument(doc, now).id)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,293 @@
+// Package memory is an in-memory database.Store for tests and demos which don't need a
+// Postgres server. Queries match semantics of their Postgres counterparts, including
+// soft deletes, constraint errors and ordering, except that text is ordered by bytes
+// like with C collation and times are truncated to days, weeks and months in UTC.
+//
+// Full text search of phrases is approximated like described in package textsearch.
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+var _ database.Store = (*Store)(nil)
+
+type termKey struct {
+	raw      string
+	language string
+}
+
+// occurrenceKey is a term and a batch, zero batch if occurrence has no batch. Occurrences
+// without a batch are counted per day (UTC) of their creation.
+type occurrenceKey struct {
+	termID  int64
+	batchID int64
+	day     string
+}
+
+func newOccurrenceKey(termID, batchID int64, created time.Time) occurrenceKey {
+	key := occurrenceKey{termID: termID, batchID: batchID}
+	if batchID == 0 {
+		key.day = created.UTC().Format(time.DateOnly)
+	}
+
+	return key
+}
+
+// Rows are soft deleted if their deletedAt isn't zero.
+type (
+	term struct {
+		id         int64
+		raw        string
+		normalized string
+		lemma      string
+		language   string
+		createdAt  time.Time
+		deletedAt  time.Time
+	}
+
+	occurrence struct {
+		id        int64
+		termID    int64
+		batchID   int64
+		count     int32
+		createdAt time.Time
+		deletedAt time.Time
+	}
+
+	// Attributes of word batches are empty and posting is zero if they're null.
+	wordBatch struct {
+		id        int64
+		name      string
+		seniority string
+		location  string
+		workMode  string
+		postingID int64
+		createdAt time.Time
+		deletedAt time.Time
+	}
+
+	phraseBatch struct {
+		id        int64
+		name      string
+		postingID int64
+		createdAt time.Time
+		deletedAt time.Time
+	}
+
+	// Language of phrases is empty if it's null.
+	phrase struct {
+		id        int64
+		value     string
+		language  string
+		batchID   int64
+		createdAt time.Time
+		deletedAt time.Time
+	}
+
+	// Image hash of postings is empty if it's null.
+	posting struct {
+		id         int64
+		title      string
+		company    string
+		sourceURL  string
+		capturedAt time.Time
+		imageHash  string
+		rawText    string
+		createdAt  time.Time
+		updatedAt  time.Time
+		deletedAt  time.Time
+	}
+
+	// Content of images is nil and path is empty if they're null.
+	image struct {
+		id        int64
+		hash      string
+		width     int32
+		height    int32
+		format    string
+		sizeBytes int64
+		filename  string
+		content   []byte
+		path      string
+		createdAt time.Time
+		deletedAt time.Time
+	}
+
+	// Posting and image of documents are zero if they're null.
+	ocrDocument struct {
+		id            int64
+		postingID     int64
+		imageID       int64
+		text          string
+		engine        string
+		engineVersion string
+		languages     []string
+		settings      []byte
+		confidence    pgtype.Float8
+		durationMs    int64
+		createdAt     time.Time
+		deletedAt     time.Time
+	}
+
+	// Contract, basis and seniority of salaries are empty if they're null.
+	salary struct {
+		id         int64
+		batchID    int64
+		minAmount  float64
+		maxAmount  float64
+		currency   string
+		period     string
+		monthlyMin float64
+		monthlyMax float64
+		contract   string
+		basis      string
+		seniority  string
+		raw        string
+		createdAt  time.Time
+		deletedAt  time.Time
+	}
+)
+
+// Store is a database.Store of maps guarded by a mutex, so it's safe for concurrent use.
+// Every query runs under the lock, which makes it atomic like a Postgres statement.
+type Store struct {
+	mu sync.RWMutex
+
+	terms         map[int64]*term
+	termKeys      map[termKey]int64
+	occurrences   map[int64]*occurrence
+	occurrenceIDs map[occurrenceKey]int64
+	wordBatches   map[int64]*wordBatch
+	phraseBatches map[int64]*phraseBatch
+	phrases       map[int64]*phrase
+	postings      map[int64]*posting
+	images        map[int64]*image
+	documents     map[int64]*ocrDocument
+	salaries      map[int64]*salary
+	tags          *groups
+	collections   *groups
+
+	// Last IDs of sequences.
+	termSeq, occurrenceSeq, wordBatchSeq, phraseBatchSeq, phraseSeq int64
+	postingSeq, imageSeq, documentSeq, salarySeq                    int64
+}
+
+func New() *Store {
+	return &Store{
+		terms:         make(map[int64]*term),
+		termKeys:      make(map[termKey]int64),
+		occurrences:   make(map[int64]*occurrence),
+		occurrenceIDs: make(map[occurrenceKey]int64),
+		wordBatches:   make(map[int64]*wordBatch),
+		phraseBatches: make(map[int64]*phraseBatch),
+		phrases:       make(map[int64]*phrase),
+		postings:      make(map[int64]*posting),
+		images:        make(map[int64]*image),
+		documents:     make(map[int64]*ocrDocument),
+		salaries:      make(map[int64]*salary),
+		tags:          newGroups("tags"),
+		collections:   newGroups("collections"),
+	}
+}
+
+// Close does nothing, data of Store is kept until it's garbage collected.
+func (s *Store) Close() error {
+	return nil
+}
+
+// now returns current time with precision of Postgres timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+func nullInt8(id int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: id, Valid: id != 0}
+}
+
+func nullText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+// Errors of violated constraints are errors of Postgres, so callers handle them the same way.
+func uniqueViolation(constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23505",
+		Message:        fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		ConstraintName: constraint,
+	}
+}
+
+func foreignKeyViolation(table, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23503",
+		Message:        fmt.Sprintf("insert or update on table %q violates foreign key constraint %q", table, constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+func notNullViolation(table, column string) error {
+	return &pgconn.PgError{
+		Severity:   "ERROR",
+		Code:       "23502",
+		Message:    fmt.Sprintf("null value in column %q of relation %q violates not-null constraint", column, table),
+		TableName:  table,
+		ColumnName: column,
+	}
+}
+
+func invalidJSON() error {
+	return &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "22P02",
+		Message:  "invalid input syntax for type json",
+	}
+}
+
+func checkViolation(table, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23514",
+		Message:        fmt.Sprintf("new row for relation %q violates check constraint %q", table, constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+// page returns items of LIMIT and OFFSET, nil if there are none.
+func page[T any](items []T, limit, offset int32) []T {
+	if int(offset) >= len(items) || limit <= 0 {
+		return nil
+	}
+	items = items[offset:]
+	if int(limit) < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// sorted returns values of m sorted by cmp.
+func sorted[K comparable, V any](m map[K]V, cmp func(a, b V) int) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	slices.SortFunc(values, cmp)
+
+	return values
+}
@@ -0,0 +1,60 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/internal/storage/memory"
+	"github.com/kndrad/piccrack/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		t.Helper()
+
+		return memory.New()
+	})
+	storagetest.RunStore(t, func(t *testing.T) database.Store {
+		t.Helper()
+
+		return memory.New()
+	})
+}
+
+func TestConcurrentIngest(t *testing.T) {
+	t.Parallel()
+
+	s := memory.New()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range 10 {
+				_, err := s.Ingest(ctx, database.Ingestion{
+					Name:  fmt.Sprintf("batch-%d-%d", i, j),
+					Words: []database.IngestedWord{{Value: "go"}, {Value: fmt.Sprintf("word-%d", j)}},
+				})
+				require.NoError(t, err)
+				_, err = s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10})
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rows, err := s.ListWordCountsInSet(ctx, database.ListWordCountsInSetParams{Batches: nil})
+	require.NoError(t, err)
+	require.Len(t, rows, 11)
+	require.Equal(t, database.ListWordCountsInSetRow{Value: "go", Total: 80, Postings: 80}, rows[0])
+}
@@ -0,0 +1,504 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// checkPosting fails like the foreign key of table if id isn't zero and there's no
+// posting with id, deleted or not.
+func (s *Store) checkPosting(table string, id int64) error {
+	if id == 0 {
+		return nil
+	}
+	if _, ok := s.postings[id]; !ok {
+		return foreignKeyViolation(table, table+"_posting_id_fkey")
+	}
+
+	return nil
+}
+
+// postingWordBatch returns word batch of posting with id which isn't deleted, nil if
+// there's none. Posting has at most one such batch.
+func (s *Store) postingWordBatch(id int64) *wordBatch {
+	for _, b := range s.wordBatches {
+		if b.postingID == id && b.deletedAt.IsZero() {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// postingPhraseBatch returns phrase batch of posting with id which isn't deleted, nil if
+// there's none. Posting has at most one such batch.
+func (s *Store) postingPhraseBatch(id int64) *phraseBatch {
+	for _, b := range s.phraseBatches {
+		if b.postingID == id && b.deletedAt.IsZero() {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// checkWordBatchPosting fails like constraints of word batches if a new batch of posting
+// with id can't be stored.
+func (s *Store) checkWordBatchPosting(id int64) error {
+	if err := s.checkPosting("word_batches", id); err != nil {
+		return err
+	}
+	if id != 0 && s.postingWordBatch(id) != nil {
+		return uniqueViolation("idx_word_batches_posting_id")
+	}
+
+	return nil
+}
+
+// checkPhraseBatchPosting fails like constraints of phrase batches if a new batch of
+// posting with id can't be stored.
+func (s *Store) checkPhraseBatchPosting(id int64) error {
+	if err := s.checkPosting("phrase_batches", id); err != nil {
+		return err
+	}
+	if id != 0 && s.postingPhraseBatch(id) != nil {
+		return uniqueViolation("idx_phrase_batches_posting_id")
+	}
+
+	return nil
+}
+
+func (s *Store) createPosting(arg database.CreatePostingParams, now time.Time) *posting {
+	s.postingSeq++
+	p := &posting{
+		id:         s.postingSeq,
+		title:      arg.Title,
+		company:    arg.Company,
+		sourceURL:  arg.SourceUrl,
+		capturedAt: now,
+		imageHash:  arg.ImageHash,
+		rawText:    arg.RawText,
+		createdAt:  now,
+		updatedAt:  now,
+	}
+	if arg.CapturedAt.Valid {
+		p.capturedAt = arg.CapturedAt.Time
+	}
+	s.postings[p.id] = p
+
+	return p
+}
+
+func (s *Store) CreatePosting(ctx context.Context, arg database.CreatePostingParams) (database.CreatePostingRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.createPosting(arg, now())
+
+	return database.CreatePostingRow{
+		ID:         p.id,
+		Title:      p.title,
+		Company:    p.company,
+		SourceUrl:  p.sourceURL,
+		CapturedAt: timestamptz(p.capturedAt),
+		ImageHash:  p.imageHash,
+		CreatedAt:  timestamptz(p.createdAt),
+	}, nil
+}
+
+func (s *Store) GetPosting(ctx context.Context, id int64) (database.GetPostingRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.postings[id]
+	if !ok || !p.deletedAt.IsZero() {
+		return database.GetPostingRow{}, pgx.ErrNoRows
+	}
+	row := database.GetPostingRow{
+		ID:         p.id,
+		Title:      p.title,
+		Company:    p.company,
+		SourceUrl:  p.sourceURL,
+		CapturedAt: timestamptz(p.capturedAt),
+		ImageHash:  p.imageHash,
+		RawText:    p.rawText,
+		CreatedAt:  timestamptz(p.createdAt),
+		UpdatedAt:  timestamptz(p.updatedAt),
+	}
+	if b := s.postingWordBatch(p.id); b != nil {
+		row.WordBatchID = nullInt8(b.id)
+		row.WordBatchName = pgtype.Text{String: b.name, Valid: true}
+	}
+	if b := s.postingPhraseBatch(p.id); b != nil {
+		row.PhraseBatchID = nullInt8(b.id)
+		row.PhraseBatchName = pgtype.Text{String: b.name, Valid: true}
+	}
+
+	return row, nil
+}
+
+func (s *Store) ListPostings(ctx context.Context, arg database.ListPostingsParams) ([]database.ListPostingsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	postings := make([]*posting, 0, len(s.postings))
+	for _, p := range s.postings {
+		if p.deletedAt.IsZero() && (!arg.Company.Valid || strings.EqualFold(p.company, arg.Company.String)) {
+			postings = append(postings, p)
+		}
+	}
+	slices.SortFunc(postings, func(a, b *posting) int {
+		return cmp.Or(b.capturedAt.Compare(a.capturedAt), cmp.Compare(b.id, a.id))
+	})
+
+	var items []database.ListPostingsRow
+	for _, p := range page(postings, arg.Limit, arg.Offset) {
+		row := database.ListPostingsRow{
+			ID:         p.id,
+			Title:      p.title,
+			Company:    p.company,
+			SourceUrl:  p.sourceURL,
+			CapturedAt: timestamptz(p.capturedAt),
+			ImageHash:  p.imageHash,
+			CreatedAt:  timestamptz(p.createdAt),
+		}
+		if b := s.postingWordBatch(p.id); b != nil {
+			row.WordBatchID = nullInt8(b.id)
+		}
+		if b := s.postingPhraseBatch(p.id); b != nil {
+			row.PhraseBatchID = nullInt8(b.id)
+		}
+		items = append(items, row)
+	}
+
+	return items, nil
+}
+
+func (s *Store) UpdatePosting(ctx context.Context, arg database.UpdatePostingParams) (database.UpdatePostingRow, error) {
+	set := func(v *string, x pgtype.Text) {
+		if x.Valid {
+			*v = x.String
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.postings[arg.ID]
+	if !ok || !p.deletedAt.IsZero() {
+		return database.UpdatePostingRow{}, pgx.ErrNoRows
+	}
+	set(&p.title, arg.Title)
+	set(&p.company, arg.Company)
+	set(&p.sourceURL, arg.SourceUrl)
+	if arg.CapturedAt.Valid {
+		p.capturedAt = arg.CapturedAt.Time
+	}
+	p.updatedAt = now()
+
+	return database.UpdatePostingRow{
+		ID:         p.id,
+		Title:      p.title,
+		Company:    p.company,
+		SourceUrl:  p.sourceURL,
+		CapturedAt: timestamptz(p.capturedAt),
+		ImageHash:  p.imageHash,
+		UpdatedAt:  timestamptz(p.updatedAt),
+	}, nil
+}
+
+// deletePostingBatches deletes batches of posting with id which aren't deleted, with
+// their occurrences, salaries and phrases.
+func (s *Store) deletePostingBatches(id int64, at time.Time) {
+	for _, b := range s.wordBatches {
+		if b.postingID == id && b.deletedAt.IsZero() {
+			s.deleteWordBatch(b, at)
+		}
+	}
+	for _, b := range s.phraseBatches {
+		if b.postingID == id && b.deletedAt.IsZero() {
+			s.deletePhraseBatch(b, at)
+		}
+	}
+}
+
+// DeletePosting deletes posting with its batches and documents.
+func (s *Store) DeletePosting(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.postings[id]
+	if !ok || !p.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	p.deletedAt = now()
+	s.deletePostingBatches(id, p.deletedAt)
+	for _, d := range s.documents {
+		if d.postingID == id && d.deletedAt.IsZero() {
+			d.deletedAt = p.deletedAt
+		}
+	}
+
+	return id, nil
+}
+
+// RestorePosting restores posting with batches and documents deleted with it.
+func (s *Store) RestorePosting(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.postings[id]
+	if !ok || p.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+
+	var (
+		wordBatches   []*wordBatch
+		phraseBatches []*phraseBatch
+	)
+	for _, b := range s.wordBatches {
+		if b.postingID == id && b.deletedAt.Equal(p.deletedAt) {
+			wordBatches = append(wordBatches, b)
+		}
+	}
+	for _, b := range s.phraseBatches {
+		if b.postingID == id && b.deletedAt.Equal(p.deletedAt) {
+			phraseBatches = append(phraseBatches, b)
+		}
+	}
+	if len(wordBatches) > 0 && s.postingWordBatch(id) != nil {
+		return 0, uniqueViolation("idx_word_batches_posting_id")
+	}
+	if len(phraseBatches) > 0 && s.postingPhraseBatch(id) != nil {
+		return 0, uniqueViolation("idx_phrase_batches_posting_id")
+	}
+
+	for _, b := range wordBatches {
+		s.restoreWordBatch(b)
+	}
+	for _, b := range phraseBatches {
+		s.restorePhraseBatch(b)
+	}
+	for _, d := range s.documents {
+		if d.postingID == id && d.deletedAt.Equal(p.deletedAt) {
+			d.deletedAt = time.Time{}
+		}
+	}
+	p.deletedAt = time.Time{}
+
+	return id, nil
+}
+
+// DeletePostingBatches deletes batches of a posting, which is kept with its documents.
+func (s *Store) DeletePostingBatches(ctx context.Context, postingID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deletePostingBatches(postingID, now())
+
+	return nil
+}
+
+// liveImage returns image with id which isn't deleted, nil if there's none.
+func (s *Store) liveImage(id int64) *image {
+	if img, ok := s.images[id]; ok && img.deletedAt.IsZero() {
+		return img
+	}
+
+	return nil
+}
+
+// createImage stores an image or returns the image with the same hash, which is given
+// content and path if it had none.
+func (s *Store) createImage(arg database.CreateImageParams, now time.Time) *image {
+	for _, img := range s.images {
+		if img.hash != arg.Hash || !img.deletedAt.IsZero() {
+			continue
+		}
+		if img.content == nil {
+			img.content = slices.Clone(arg.Content)
+		}
+		if img.path == "" {
+			img.path = arg.Path
+		}
+
+		return img
+	}
+
+	s.imageSeq++
+	img := &image{
+		id:        s.imageSeq,
+		hash:      arg.Hash,
+		width:     arg.Width,
+		height:    arg.Height,
+		format:    arg.Format,
+		sizeBytes: arg.SizeBytes,
+		filename:  arg.Filename,
+		content:   slices.Clone(arg.Content),
+		path:      arg.Path,
+		createdAt: now,
+	}
+	s.images[img.id] = img
+
+	return img
+}
+
+func (s *Store) CreateImage(ctx context.Context, arg database.CreateImageParams) (database.CreateImageRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	img := s.createImage(arg, now())
+
+	return database.CreateImageRow{
+		ID:        img.id,
+		Hash:      img.hash,
+		Width:     img.width,
+		Height:    img.height,
+		Format:    img.format,
+		SizeBytes: img.sizeBytes,
+		Filename:  img.filename,
+		CreatedAt: timestamptz(img.createdAt),
+	}, nil
+}
+
+// checkOcrDocument fails like constraints of documents if a document of arg can't be stored.
+func (s *Store) checkOcrDocument(arg database.CreateOcrDocumentParams) error {
+	if err := s.checkPosting("ocr_documents", arg.PostingID.Int64); err != nil {
+		return err
+	}
+	if _, ok := s.images[arg.ImageID.Int64]; arg.ImageID.Valid && !ok {
+		return foreignKeyViolation("ocr_documents", "ocr_documents_image_id_fkey")
+	}
+	if arg.Settings == nil {
+		return notNullViolation("ocr_documents", "settings")
+	}
+	if !json.Valid(arg.Settings) {
+		return invalidJSON()
+	}
+
+	return nil
+}
+
+func (s *Store) createOcrDocument(arg database.CreateOcrDocumentParams, now time.Time) *ocrDocument {
+	s.documentSeq++
+	d := &ocrDocument{
+		id:            s.documentSeq,
+		postingID:     arg.PostingID.Int64,
+		imageID:       arg.ImageID.Int64,
+		text:          arg.Text,
+		engine:        arg.Engine,
+		engineVersion: arg.EngineVersion,
+		languages:     append([]string{}, arg.Languages...),
+		settings:      slices.Clone(arg.Settings),
+		confidence:    arg.Confidence,
+		durationMs:    arg.DurationMs,
+		createdAt:     now,
+	}
+	s.documents[d.id] = d
+
+	return d
+}
+
+func (s *Store) CreateOcrDocument(ctx context.Context, arg database.CreateOcrDocumentParams) (database.CreateOcrDocumentRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkOcrDocument(arg); err != nil {
+		return database.CreateOcrDocumentRow{}, err
+	}
+	d := s.createOcrDocument(arg, now())
+
+	return database.CreateOcrDocumentRow{
+		ID:            d.id,
+		PostingID:     nullInt8(d.postingID),
+		ImageID:       nullInt8(d.imageID),
+		Engine:        d.engine,
+		EngineVersion: d.engineVersion,
+		Languages:     slices.Clone(d.languages),
+		Confidence:    d.confidence,
+		DurationMs:    d.durationMs,
+		CreatedAt:     timestamptz(d.createdAt),
+	}, nil
+}
+
+// postingDocuments returns documents of posting with id which aren't deleted, latest first.
+func (s *Store) postingDocuments(id int64) []*ocrDocument {
+	var docs []*ocrDocument
+	for _, d := range s.documents {
+		if d.postingID == id && d.deletedAt.IsZero() {
+			docs = append(docs, d)
+		}
+	}
+	slices.SortFunc(docs, func(a, b *ocrDocument) int {
+		return cmp.Or(b.createdAt.Compare(a.createdAt), cmp.Compare(b.id, a.id))
+	})
+
+	return docs
+}
+
+func (s *Store) GetLatestOcrDocument(ctx context.Context, postingID int64) (database.GetLatestOcrDocumentRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := s.postingDocuments(postingID)
+	if len(docs) == 0 {
+		return database.GetLatestOcrDocumentRow{}, pgx.ErrNoRows
+	}
+	d := docs[0]
+	row := database.GetLatestOcrDocumentRow{
+		ID:        d.id,
+		PostingID: nullInt8(d.postingID),
+		ImageID:   nullInt8(d.imageID),
+		Text:      d.text,
+		Engine:    d.engine,
+		CreatedAt: timestamptz(d.createdAt),
+	}
+	if img := s.liveImage(d.imageID); img != nil {
+		row.ImageFormat = pgtype.Text{String: img.format, Valid: true}
+		row.ImageContent = slices.Clone(img.content)
+		row.ImagePath = nullText(img.path)
+	}
+
+	return row, nil
+}
+
+func (s *Store) ListOcrDocuments(ctx context.Context, postingID int64) ([]database.ListOcrDocumentsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []database.ListOcrDocumentsRow
+	for _, d := range s.postingDocuments(postingID) {
+		row := database.ListOcrDocumentsRow{
+			ID:            d.id,
+			PostingID:     nullInt8(d.postingID),
+			ImageID:       nullInt8(d.imageID),
+			Engine:        d.engine,
+			EngineVersion: d.engineVersion,
+			Languages:     slices.Clone(d.languages),
+			Settings:      slices.Clone(d.settings),
+			Confidence:    d.confidence,
+			DurationMs:    d.durationMs,
+			TextLength:    int32(len([]rune(d.text))),
+			CreatedAt:     timestamptz(d.createdAt),
+		}
+		if img := s.liveImage(d.imageID); img != nil {
+			row.ImageHash = pgtype.Text{String: img.hash, Valid: true}
+			row.ImageWidth = pgtype.Int4{Int32: img.width, Valid: true}
+			row.ImageHeight = pgtype.Int4{Int32: img.height, Valid: true}
+			row.ImageFormat = pgtype.Text{String: img.format, Valid: true}
+			row.ImageSizeBytes = pgtype.Int8{Int64: img.sizeBytes, Valid: true}
+			row.ImageFilename = pgtype.Text{String: img.filename, Valid: true}
+		}
+		items = append(items, row)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+func (s *Store) checkSalary(arg database.CreateSalaryParams) error {
+	if _, ok := s.wordBatches[arg.BatchID]; !ok {
+		return foreignKeyViolation("salaries", "salaries_batch_fkey")
+	}
+
+	return nil
+}
+
+func (s *Store) createSalary(arg database.CreateSalaryParams, now time.Time) *salary {
+	s.salarySeq++
+	sal := &salary{
+		id:         s.salarySeq,
+		batchID:    arg.BatchID,
+		minAmount:  arg.MinAmount,
+		maxAmount:  arg.MaxAmount,
+		currency:   arg.Currency,
+		period:     arg.Period,
+		monthlyMin: arg.MonthlyMin,
+		monthlyMax: arg.MonthlyMax,
+		contract:   arg.Contract,
+		basis:      arg.Basis,
+		seniority:  arg.Seniority,
+		raw:        arg.Raw,
+		createdAt:  now,
+	}
+	s.salaries[sal.id] = sal
+
+	return sal
+}
+
+func (s *Store) CreateSalary(ctx context.Context, arg database.CreateSalaryParams) (database.CreateSalaryRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkSalary(arg); err != nil {
+		return database.CreateSalaryRow{}, err
+	}
+	sal := s.createSalary(arg, now())
+
+	return database.CreateSalaryRow{
+		ID:        sal.id,
+		BatchID:   sal.batchID,
+		MinAmount: sal.minAmount,
+		MaxAmount: sal.maxAmount,
+		Currency:  sal.currency,
+		Period:    sal.period,
+		Contract:  nullText(sal.contract),
+		Basis:     nullText(sal.basis),
+		Seniority: nullText(sal.seniority),
+	}, nil
+}
+
+func (s *Store) ListSalariesByBatchName(ctx context.Context, name string) ([]database.ListSalariesByBatchNameRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []database.ListSalariesByBatchNameRow
+	for _, sal := range sorted(s.salaries, bySalaryID) {
+		b := s.wordBatches[sal.batchID]
+		if b.name != name || !b.deletedAt.IsZero() || !sal.deletedAt.IsZero() {
+			continue
+		}
+		items = append(items, database.ListSalariesByBatchNameRow{
+			ID:         sal.id,
+			BatchName:  b.name,
+			MinAmount:  sal.minAmount,
+			MaxAmount:  sal.maxAmount,
+			Currency:   sal.currency,
+			Period:     sal.period,
+			MonthlyMin: sal.monthlyMin,
+			MonthlyMax: sal.monthlyMax,
+			Contract:   sal.contract,
+			Basis:      sal.basis,
+			Seniority:  sal.seniority,
+			Raw:        sal.raw,
+		})
+	}
+
+	return items, nil
+}
+
+func bySalaryID(a, b *salary) int {
+	return cmp.Compare(a.id, b.id)
+}
+
+// median is PERCENTILE_CONT(0.5) of Postgres, values between two middle values are
+// interpolated.
+func median(values []float64) float64 {
+	slices.Sort(values)
+	pos := float64(len(values)-1) / 2
+	lower := int(pos)
+	if lower+1 >= len(values) {
+		return values[lower]
+	}
+
+	return values[lower] + (pos-float64(lower))*(values[lower+1]-values[lower])
+}
+
+type medianKey struct {
+	value    string
+	currency string
+}
+
+// medians returns median of monthly ranges of salaries grouped by key.
+func medians(groups map[medianKey][]*salary) map[medianKey]float64 {
+	res := make(map[medianKey]float64, len(groups))
+	for key, salaries := range groups {
+		values := make([]float64, 0, len(salaries))
+		for _, sal := range salaries {
+			values = append(values, (sal.monthlyMin+sal.monthlyMax)/2)
+		}
+		res[key] = median(values)
+	}
+
+	return res
+}
+
+// ListSalaryMediansBySkill groups salaries by words of their batches. Words of skills
+// are grouped under their canonical names and other words are skipped if skills are given.
+// Batches and words which are deleted aren't filtered, like in Postgres, only their occurrences are.
+func (s *Store) ListSalaryMediansBySkill(ctx context.Context, arg database.ListSalaryMediansBySkillParams) ([]database.ListSalaryMediansBySkillRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	canonical := make(map[string]string, len(arg.Skills))
+	for i, name := range arg.Skills {
+		if i < len(arg.CanonicalSkills) {
+			canonical[name] = arg.CanonicalSkills[i]
+		}
+	}
+
+	words := make(map[int64][]string)
+	for _, o := range s.occurrences {
+		if o.batchID == 0 || !o.deletedAt.IsZero() {
+			continue
+		}
+		t := s.terms[o.termID]
+		word, known := canonical[t.normalized]
+		if !known {
+			if arg.Skills != nil {
+				continue
+			}
+			word = t.raw
+		}
+		if !slices.Contains(words[o.batchID], word) {
+			words[o.batchID] = append(words[o.batchID], word)
+		}
+	}
+
+	groups := make(map[medianKey][]*salary)
+	for _, sal := range s.salaries {
+		if !sal.deletedAt.IsZero() || !equal(sal.contract, arg.Contract) {
+			continue
+		}
+		for _, word := range words[sal.batchID] {
+			if (arg.Skill.Valid && word != arg.Skill.String) || slices.Contains(arg.Excluded, strings.ToLower(word)) {
+				continue
+			}
+			key := medianKey{value: word, currency: sal.currency}
+			groups[key] = append(groups[key], sal)
+		}
+	}
+
+	items := make([]database.ListSalaryMediansBySkillRow, 0, len(groups))
+	for key, median := range medians(groups) {
+		items = append(items, database.ListSalaryMediansBySkillRow{
+			Skill:    key.value,
+			Currency: key.currency,
+			Total:    int64(len(groups[key])),
+			Median:   median,
+		})
+	}
+	slices.SortFunc(items, func(a, b database.ListSalaryMediansBySkillRow) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), strings.Compare(a.Skill, b.Skill), strings.Compare(a.Currency, b.Currency))
+	})
+
+	return page(items, arg.Limit, arg.Offset), nil
+}
+
+// ListSalaryMediansBySeniority groups salaries by seniority of their batches, or their
+// own seniority if batches have none.
+func (s *Store) ListSalaryMediansBySeniority(ctx context.Context, contract pgtype.Text) ([]database.ListSalaryMediansBySeniorityRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := make(map[medianKey][]*salary)
+	for _, sal := range s.salaries {
+		b := s.wordBatches[sal.batchID]
+		if !sal.deletedAt.IsZero() || !b.deletedAt.IsZero() || !equal(sal.contract, contract) {
+			continue
+		}
+		key := medianKey{value: cmp.Or(b.seniority, sal.seniority), currency: sal.currency}
+		groups[key] = append(groups[key], sal)
+	}
+
+	var items []database.ListSalaryMediansBySeniorityRow
+	for key, median := range medians(groups) {
+		items = append(items, database.ListSalaryMediansBySeniorityRow{
+			Seniority: key.value,
+			Currency:  key.currency,
+			Total:     int64(len(groups[key])),
+			Median:    median,
+		})
+	}
+	slices.SortFunc(items, func(a, b database.ListSalaryMediansBySeniorityRow) int {
+		return cmp.Or(strings.Compare(a.Seniority, b.Seniority), strings.Compare(a.Currency, b.Currency))
+	})
+
+	return items, nil
+}
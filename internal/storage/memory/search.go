@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/textsearch"
+)
+
+func (s *Store) SearchWords(ctx context.Context, arg database.SearchWordsParams) ([]database.SearchWordsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := make(map[int64]int64)
+	for _, r := range s.rows(filter{language: arg.Language}) {
+		totals[r.term.id] += int64(r.count)
+	}
+	query := strings.ToLower(arg.Query)
+
+	rows := make([]database.SearchWordsRow, 0)
+	for id, total := range totals {
+		t := s.terms[id]
+		if sim := textsearch.Similarity(t.normalized, query); sim >= textsearch.SimilarityThreshold {
+			rows = append(rows, database.SearchWordsRow{Value: t.raw, Language: t.language, Total: total, Similarity: sim})
+		}
+	}
+	slices.SortFunc(rows, func(a, b database.SearchWordsRow) int {
+		return cmp.Or(cmp.Compare(b.Similarity, a.Similarity), cmp.Compare(b.Total, a.Total), strings.Compare(a.Value, b.Value))
+	})
+
+	return page(rows, arg.Limit, arg.Offset), nil
+}
+
+// SearchPhrases matches phrases containing every word of query or similar to it, see
+// package textsearch.
+func (s *Store) SearchPhrases(ctx context.Context, arg database.SearchPhrasesParams) ([]database.SearchPhrasesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := textsearch.Words(arg.Query)
+
+	rows := make([]database.SearchPhrasesRow, 0)
+	for _, p := range s.phrases {
+		b := s.phraseBatches[p.batchID]
+		if !p.deletedAt.IsZero() || !b.deletedAt.IsZero() || !equal(p.language, arg.Language) {
+			continue
+		}
+		r, sim := textsearch.Rank(textsearch.Words(p.value), query), textsearch.Similarity(p.value, arg.Query)
+		if r == 0 && sim < textsearch.SimilarityThreshold {
+			continue
+		}
+		rows = append(rows, database.SearchPhrasesRow{
+			ID:         p.id,
+			Value:      p.value,
+			Language:   p.language,
+			BatchName:  b.name,
+			Rank:       r,
+			Similarity: sim,
+			Snippet:    textsearch.Headline(p.value, query),
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.SearchPhrasesRow) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), cmp.Compare(b.Similarity, a.Similarity), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(rows, arg.Limit, arg.Offset), nil
+}
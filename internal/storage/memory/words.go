@@ -0,0 +1,693 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// filter is a filter of word queries, its null fields match all rows.
+type filter struct {
+	language   pgtype.Text
+	seniority  pgtype.Text
+	location   pgtype.Text
+	workMode   pgtype.Text
+	tag        pgtype.Text
+	collection pgtype.Text
+	excluded   []string
+}
+
+// equal reports if attribute v equals x. Empty attributes are null and equal nothing.
+func equal(v string, x pgtype.Text) bool {
+	return !x.Valid || (v != "" && v == x.String)
+}
+
+func (f filter) term(t *term) bool {
+	return (!f.language.Valid || t.language == f.language.String) &&
+		!slices.Contains(f.excluded, t.normalized)
+}
+
+// batch reports if batch b matches f, b is nil if occurrence has no batch.
+func (s *Store) batch(b *wordBatch, f filter) bool {
+	if b == nil {
+		return !f.seniority.Valid && !f.location.Valid && !f.workMode.Valid && !f.tag.Valid && !f.collection.Valid
+	}
+
+	return equal(b.seniority, f.seniority) &&
+		(!f.location.Valid || (b.location != "" && strings.ToLower(b.location) == strings.ToLower(f.location.String))) &&
+		equal(b.workMode, f.workMode) &&
+		(!f.tag.Valid || s.tags.has(f.tag.String, member{postingID: b.postingID, wordBatchID: b.id})) &&
+		(!f.collection.Valid || s.collections.has(f.collection.String, member{postingID: b.postingID, wordBatchID: b.id}))
+}
+
+// row is an occurrence joined with its term and batch, which is nil if there's no batch.
+type row struct {
+	*occurrence
+
+	term  *term
+	batch *wordBatch
+}
+
+// rows returns occurrences which aren't deleted, of terms and batches matching f.
+func (s *Store) rows(f filter) []row {
+	rows := make([]row, 0, len(s.occurrences))
+	for _, o := range s.occurrences {
+		if !o.deletedAt.IsZero() {
+			continue
+		}
+		r := row{occurrence: o, term: s.terms[o.termID], batch: s.wordBatches[o.batchID]}
+		if f.term(r.term) && s.batch(r.batch, f) {
+			rows = append(rows, r)
+		}
+	}
+
+	return rows
+}
+
+type total struct {
+	value string
+	total int64
+}
+
+// totals sums counts of rows by key, in ascending order of sums and keys.
+func totals(rows []row, key func(r row) string) []total {
+	sums := make(map[string]int64)
+	for _, r := range rows {
+		sums[key(r)] += int64(r.count)
+	}
+	totals := make([]total, 0, len(sums))
+	for k, v := range sums {
+		totals = append(totals, total{value: k, total: v})
+	}
+	slices.SortFunc(totals, func(a, b total) int {
+		return cmp.Or(cmp.Compare(a.total, b.total), strings.Compare(a.value, b.value))
+	})
+
+	return totals
+}
+
+// rankings ranks totals from the greatest, totals equal to each other are ranked by key.
+func rankings(totals []total) []database.ListWordRankingsRow {
+	slices.SortStableFunc(totals, func(a, b total) int {
+		return cmp.Compare(b.total, a.total)
+	})
+	rows := make([]database.ListWordRankingsRow, 0, len(totals))
+	for i, t := range totals {
+		rows = append(rows, database.ListWordRankingsRow{Value: t.value, Ranking: int64(i + 1)})
+	}
+
+	return rows
+}
+
+func raw(r row) string {
+	return r.term.raw
+}
+
+func (s *Store) ListWords(ctx context.Context, arg database.ListWordsParams) ([]database.ListWordsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	terms := make([]*term, 0, len(s.terms))
+	for _, t := range s.terms {
+		if t.deletedAt.IsZero() {
+			terms = append(terms, t)
+		}
+	}
+	slices.SortFunc(terms, func(a, b *term) int {
+		return cmp.Or(strings.Compare(a.raw, b.raw), cmp.Compare(a.id, b.id))
+	})
+
+	var items []database.ListWordsRow
+	for _, t := range page(terms, arg.Limit, arg.Offset) {
+		items = append(items, database.ListWordsRow{ID: t.id, Value: t.raw, CreatedAt: timestamptz(t.createdAt)})
+	}
+
+	return items, nil
+}
+
+// upsertTerm adds a term or restores it if it's deleted, lemma is set if term had none.
+// It reports if term was added or restored.
+func (s *Store) upsertTerm(value, lemma, language string, now time.Time) (*term, bool) {
+	key := termKey{raw: value, language: language}
+	if id, ok := s.termKeys[key]; ok {
+		t := s.terms[id]
+		if t.lemma == "" {
+			t.lemma = lemma
+		}
+		restored := !t.deletedAt.IsZero()
+		t.deletedAt = time.Time{}
+
+		return t, restored
+	}
+
+	s.termSeq++
+	t := &term{
+		id:         s.termSeq,
+		raw:        value,
+		normalized: strings.ToLower(value),
+		lemma:      lemma,
+		language:   language,
+		createdAt:  now,
+	}
+	s.terms[t.id] = t
+	s.termKeys[key] = t.id
+
+	return t, true
+}
+
+// upsertOccurrence adds count to occurrence of term in batch, or to occurrence of term
+// without a batch created on the same day. Deleted occurrences are restored with count.
+func (s *Store) upsertOccurrence(termID, batchID int64, count int32, created time.Time) *occurrence {
+	key := newOccurrenceKey(termID, batchID, created)
+	if id, ok := s.occurrenceIDs[key]; ok {
+		o := s.occurrences[id]
+		if o.deletedAt.IsZero() {
+			o.count += count
+		} else {
+			o.count = count
+		}
+		o.deletedAt = time.Time{}
+
+		return o
+	}
+
+	s.occurrenceSeq++
+	o := &occurrence{id: s.occurrenceSeq, termID: termID, batchID: batchID, count: count, createdAt: created}
+	s.occurrences[o.id] = o
+	s.occurrenceIDs[key] = o.id
+
+	return o
+}
+
+func (s *Store) deleteOccurrence(o *occurrence) {
+	delete(s.occurrences, o.id)
+	delete(s.occurrenceIDs, newOccurrenceKey(o.termID, o.batchID, o.createdAt))
+}
+
+func (s *Store) CreateWord(ctx context.Context, arg database.CreateWordParams) (database.CreateWordRow, error) {
+	if arg.Value == "" {
+		return database.CreateWordRow{}, checkViolation("vocabulary", "vocabulary_raw_check")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := now()
+	t, _ := s.upsertTerm(arg.Value, arg.Lemma, arg.Language, now)
+	s.upsertOccurrence(t.id, 0, 1, now)
+
+	return database.CreateWordRow{
+		ID:        t.id,
+		Value:     t.raw,
+		Lemma:     t.lemma,
+		Language:  t.language,
+		CreatedAt: timestamptz(t.createdAt),
+	}, nil
+}
+
+func (s *Store) DeleteWord(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.terms[id]
+	if !ok || !t.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	t.deletedAt = now()
+	for _, o := range s.occurrences {
+		if o.termID == id && o.deletedAt.IsZero() {
+			o.deletedAt = t.deletedAt
+		}
+	}
+
+	return id, nil
+}
+
+func (s *Store) RestoreWord(ctx context.Context, id int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.terms[id]
+	if !ok || t.deletedAt.IsZero() {
+		return 0, pgx.ErrNoRows
+	}
+	for _, o := range s.occurrences {
+		if o.termID == id && o.deletedAt.Equal(t.deletedAt) {
+			o.deletedAt = time.Time{}
+		}
+	}
+	t.deletedAt = time.Time{}
+
+	return id, nil
+}
+
+func (s *Store) createWordBatch(arg database.CreateWordBatchParams, now time.Time) *wordBatch {
+	s.wordBatchSeq++
+	b := &wordBatch{
+		id:        s.wordBatchSeq,
+		name:      arg.Name,
+		seniority: arg.Seniority,
+		location:  arg.Location,
+		workMode:  arg.WorkMode,
+		postingID: arg.PostingID.Int64,
+		createdAt: now,
+	}
+	s.wordBatches[b.id] = b
+
+	return b
+}
+
+func (s *Store) CreateWordBatch(ctx context.Context, arg database.CreateWordBatchParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkWordBatchPosting(arg.PostingID.Int64); err != nil {
+		return 0, err
+	}
+
+	return s.createWordBatch(arg, now()).id, nil
+}
+
+func (s *Store) CreateWordsBatch(ctx context.Context, arg database.CreateWordsBatchParams) (database.CreateWordsBatchRow, error) {
+	if slices.Contains(arg.Words, "") {
+		return database.CreateWordsBatchRow{}, checkViolation("vocabulary", "vocabulary_raw_check")
+	}
+
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return values[i]
+		}
+
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkWordBatchPosting(arg.PostingID.Int64); err != nil {
+		return database.CreateWordsBatchRow{}, err
+	}
+	now := now()
+	b := s.createWordBatch(database.CreateWordBatchParams{
+		Name:      arg.Name,
+		Seniority: arg.Seniority,
+		Location:  arg.Location,
+		WorkMode:  arg.WorkMode,
+		PostingID: arg.PostingID,
+	}, now)
+
+	// Terms in order of values and languages, counted in the batch.
+	keys := make([]termKey, 0, len(arg.Words))
+	lemmas := make(map[termKey]string)
+	counts := make(map[termKey]int32)
+	for i, value := range arg.Words {
+		key := termKey{raw: value, language: at(arg.Languages, i)}
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+			lemmas[key] = at(arg.Lemmas, i)
+		}
+		counts[key]++
+	}
+	if len(keys) == 0 {
+		return database.CreateWordsBatchRow{}, pgx.ErrNoRows
+	}
+	slices.SortFunc(keys, func(a, b termKey) int {
+		return cmp.Or(strings.Compare(a.raw, b.raw), strings.Compare(a.language, b.language))
+	})
+
+	var first database.CreateWordsBatchRow
+	for i, key := range keys {
+		t, _ := s.upsertTerm(key.raw, lemmas[key], key.language, now)
+		s.upsertOccurrence(t.id, b.id, counts[key], now)
+		if i == 0 {
+			first = database.CreateWordsBatchRow{TermID: t.id, BatchID: nullInt8(b.id)}
+		}
+	}
+
+	return first, nil
+}
+
+func (s *Store) ListWordsByBatchName(ctx context.Context, name string) ([]database.ListWordsByBatchNameRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make([]row, 0)
+	for _, o := range s.occurrences {
+		b := s.wordBatches[o.batchID]
+		if b != nil && b.name == name && b.deletedAt.IsZero() && o.deletedAt.IsZero() {
+			rows = append(rows, row{occurrence: o, term: s.terms[o.termID], batch: b})
+		}
+	}
+	slices.SortFunc(rows, func(a, b row) int {
+		return cmp.Or(
+			b.batch.createdAt.Compare(a.batch.createdAt),
+			cmp.Compare(b.batch.id, a.batch.id),
+			strings.Compare(a.term.raw, b.term.raw),
+		)
+	})
+
+	var items []database.ListWordsByBatchNameRow
+	for _, r := range rows {
+		items = append(items, database.ListWordsByBatchNameRow{BatchName: r.batch.name, WordValue: r.term.raw, Count: r.count})
+	}
+
+	return items, nil
+}
+
+func (s *Store) ListWordFrequencies(ctx context.Context, arg database.ListWordFrequenciesParams) ([]database.ListWordFrequenciesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.rows(filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded})
+
+	var items []database.ListWordFrequenciesRow
+	for _, t := range page(totals(rows, raw), arg.Limit, arg.Offset) {
+		items = append(items, database.ListWordFrequenciesRow{Value: t.value, Total: t.total})
+	}
+
+	return items, nil
+}
+
+func (s *Store) ListLemmaFrequencies(ctx context.Context, arg database.ListLemmaFrequenciesParams) ([]database.ListLemmaFrequenciesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.rows(filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded})
+	lemma := func(r row) string {
+		return cmp.Or(r.term.lemma, r.term.raw)
+	}
+
+	var items []database.ListLemmaFrequenciesRow
+	for _, t := range page(totals(rows, lemma), arg.Limit, arg.Offset) {
+		items = append(items, database.ListLemmaFrequenciesRow{Lemma: t.value, Total: t.total})
+	}
+
+	return items, nil
+}
+
+func (s *Store) ListWordRankings(ctx context.Context, arg database.ListWordRankingsParams) ([]database.ListWordRankingsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.rows(filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded})
+
+	return page(rankings(totals(rows, raw)), arg.Limit, arg.Offset), nil
+}
+
+// ListWordStatsFrequencies sums the same occurrences as word stats tables of Postgres,
+// which are kept current by triggers, so there's nothing to refresh.
+func (s *Store) ListWordStatsFrequencies(ctx context.Context, arg database.ListWordStatsFrequenciesParams) ([]database.ListWordStatsFrequenciesRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.rows(filter{language: arg.Language, excluded: arg.Excluded})
+
+	var items []database.ListWordStatsFrequenciesRow
+	for _, t := range page(totals(rows, raw), arg.Limit, arg.Offset) {
+		items = append(items, database.ListWordStatsFrequenciesRow{Value: t.value, Total: t.total})
+	}
+
+	return items, nil
+}
+
+func (s *Store) ListWordStatsRankings(ctx context.Context, arg database.ListWordStatsRankingsParams) ([]database.ListWordStatsRankingsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.rows(filter{language: arg.Language, excluded: arg.Excluded})
+
+	var items []database.ListWordStatsRankingsRow
+	for _, r := range page(rankings(totals(rows, raw)), arg.Limit, arg.Offset) {
+		items = append(items, database.ListWordStatsRankingsRow(r))
+	}
+
+	return items, nil
+}
+
+func (s *Store) RefreshWordStats(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) ListBatchWordSets(ctx context.Context, arg database.ListBatchWordSetsParams) ([]database.ListBatchWordSetsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sets := make(map[int64][]string)
+	for _, r := range s.rows(filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded}) {
+		if r.batch == nil || !r.batch.deletedAt.IsZero() {
+			continue
+		}
+		if arg.Skills != nil && !slices.Contains(arg.Skills, r.term.normalized) {
+			continue
+		}
+		if !slices.Contains(sets[r.batch.id], r.term.normalized) {
+			sets[r.batch.id] = append(sets[r.batch.id], r.term.normalized)
+		}
+	}
+
+	var items []database.ListBatchWordSetsRow
+	for id, words := range sets {
+		slices.Sort(words)
+		items = append(items, database.ListBatchWordSetsRow{BatchID: id, Words: words})
+	}
+	slices.SortFunc(items, func(a, b database.ListBatchWordSetsRow) int {
+		return cmp.Compare(a.BatchID, b.BatchID)
+	})
+
+	return items, nil
+}
+
+func (s *Store) UpdateWordBatchAttributes(ctx context.Context, arg database.UpdateWordBatchAttributesParams) (database.UpdateWordBatchAttributesRow, error) {
+	set := func(v *string, x pgtype.Text) {
+		if x.Valid {
+			*v = x.String
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var updated *wordBatch
+	for _, b := range sorted(s.wordBatches, byID) {
+		if b.name != arg.Name || !b.deletedAt.IsZero() {
+			continue
+		}
+		set(&b.seniority, arg.Seniority)
+		set(&b.location, arg.Location)
+		set(&b.workMode, arg.WorkMode)
+		if updated == nil {
+			updated = b
+		}
+	}
+	if updated == nil {
+		return database.UpdateWordBatchAttributesRow{}, pgx.ErrNoRows
+	}
+
+	return database.UpdateWordBatchAttributesRow{
+		ID:        updated.id,
+		Name:      updated.name,
+		Seniority: updated.seniority,
+		Location:  updated.location,
+		WorkMode:  updated.workMode,
+	}, nil
+}
+
+var ErrUnknownBucket = errors.New("unknown bucket")
+
+// truncate truncates t to start of its bucket in UTC, weeks start on Monday like with
+// DATE_TRUNC of Postgres.
+func truncate(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case "week":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+func checkBucket(bucket string) error {
+	switch bucket {
+	case "day", "week", "month":
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownBucket, bucket)
+	}
+}
+
+type trendKey struct {
+	bucket time.Time
+	value  string
+}
+
+type trendRow struct {
+	total    int64
+	postings map[int64]bool
+	// Rows with a batch, which are postings of word stats.
+	batched int64
+}
+
+// trend groups rows by bucket and normalized word. Postings are distinct batches of rows,
+// or rows with a batch if batched is true.
+func trend(rows []row, bucket string, bucketPostings map[time.Time]int64, batched bool) []database.ListWordTrendRow {
+	groups := make(map[trendKey]*trendRow)
+	for _, r := range rows {
+		key := trendKey{bucket: truncate(r.createdAt, bucket), value: r.term.normalized}
+		g, ok := groups[key]
+		if !ok {
+			g = &trendRow{postings: make(map[int64]bool)}
+			groups[key] = g
+		}
+		g.total += int64(r.count)
+		if r.batchID != 0 {
+			g.postings[r.batchID] = true
+			g.batched++
+		}
+	}
+
+	var items []database.ListWordTrendRow
+	for key, g := range groups {
+		postings := int64(len(g.postings))
+		if batched {
+			postings = g.batched
+		}
+		items = append(items, database.ListWordTrendRow{
+			Bucket:         timestamptz(key.bucket),
+			Value:          key.value,
+			Total:          g.total,
+			Postings:       postings,
+			BucketPostings: bucketPostings[key.bucket],
+		})
+	}
+	slices.SortFunc(items, func(a, b database.ListWordTrendRow) int {
+		return cmp.Or(a.Bucket.Time.Compare(b.Bucket.Time), strings.Compare(a.Value, b.Value))
+	})
+
+	return items
+}
+
+// inWords reports if words is empty or it contains word.
+func inWords(words []string, word string) bool {
+	return len(words) == 0 || slices.Contains(words, word)
+}
+
+func (s *Store) ListWordTrend(ctx context.Context, arg database.ListWordTrendParams) ([]database.ListWordTrendRow, error) {
+	if err := checkBucket(arg.Bucket); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f := filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded}
+	bucketPostings := make(map[time.Time]int64)
+	for _, b := range s.wordBatches {
+		if b.deletedAt.IsZero() && !b.createdAt.Before(arg.Since.Time) && s.batch(b, f) {
+			bucketPostings[truncate(b.createdAt, arg.Bucket)]++
+		}
+	}
+	rows := slices.DeleteFunc(s.rows(f), func(r row) bool {
+		return r.createdAt.Before(arg.Since.Time) || !inWords(arg.Words, r.term.normalized)
+	})
+
+	return trend(rows, arg.Bucket, bucketPostings, false), nil
+}
+
+// ListWordStatsTrend counts occurrences by days of their creation, like daily word stats
+// of Postgres, and batches by buckets of their creation.
+func (s *Store) ListWordStatsTrend(ctx context.Context, arg database.ListWordStatsTrendParams) ([]database.ListWordStatsTrendRow, error) {
+	if err := checkBucket(arg.Bucket); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bucketPostings := make(map[time.Time]int64)
+	for _, b := range s.wordBatches {
+		if b.deletedAt.IsZero() && !b.createdAt.Before(arg.Since.Time) {
+			bucketPostings[truncate(b.createdAt, arg.Bucket)]++
+		}
+	}
+	since := truncate(arg.Since.Time, "day")
+	rows := slices.DeleteFunc(s.rows(filter{language: arg.Language, excluded: arg.Excluded}), func(r row) bool {
+		return r.createdAt.Before(since) || !inWords(arg.Words, r.term.normalized)
+	})
+
+	var items []database.ListWordStatsTrendRow
+	for _, r := range trend(rows, arg.Bucket, bucketPostings, true) {
+		items = append(items, database.ListWordStatsTrendRow(r))
+	}
+
+	return items, nil
+}
+
+// set is a filter of batches of a set compared by compare queries.
+type set struct {
+	batches     []string
+	createdFrom pgtype.Timestamptz
+	createdTo   pgtype.Timestamptz
+}
+
+func (st set) has(name string, created time.Time) bool {
+	return inWords(st.batches, name) &&
+		(!st.createdFrom.Valid || !created.Before(st.createdFrom.Time)) &&
+		(!st.createdTo.Valid || created.Before(st.createdTo.Time))
+}
+
+func (s *Store) ListWordCountsInSet(ctx context.Context, arg database.ListWordCountsInSetParams) ([]database.ListWordCountsInSetRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := set{arg.Batches, arg.CreatedFrom, arg.CreatedTo}
+	groups := make(map[string]*trendRow)
+	for _, r := range s.rows(filter{arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded}) {
+		if r.batch == nil || !r.batch.deletedAt.IsZero() || !st.has(r.batch.name, r.batch.createdAt) {
+			continue
+		}
+		g, ok := groups[r.term.normalized]
+		if !ok {
+			g = &trendRow{postings: make(map[int64]bool)}
+			groups[r.term.normalized] = g
+		}
+		g.total += int64(r.count)
+		g.postings[r.batch.id] = true
+	}
+
+	var items []database.ListWordCountsInSetRow
+	for value, g := range groups {
+		items = append(items, database.ListWordCountsInSetRow{Value: value, Total: g.total, Postings: int64(len(g.postings))})
+	}
+	slices.SortFunc(items, func(a, b database.ListWordCountsInSetRow) int {
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	return items, nil
+}
+
+func (s *Store) CountWordBatchesInSet(ctx context.Context, arg database.CountWordBatchesInSetParams) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st := set{arg.Batches, arg.CreatedFrom, arg.CreatedTo}
+	f := filter{seniority: arg.Seniority, location: arg.Location, workMode: arg.WorkMode, tag: arg.Tag, collection: arg.Collection}
+	var n int64
+	for _, b := range s.wordBatches {
+		if b.deletedAt.IsZero() && st.has(b.name, b.createdAt) && s.batch(b, f) {
+			n++
+		}
+	}
+
+	return n, nil
+}
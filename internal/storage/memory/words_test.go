@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"testing"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWordCountsOccurrencesPerDay(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	ctx := context.Background()
+
+	row, err := s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	_, err = s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	require.Len(t, s.occurrences, 1)
+
+	// Occurrence of words added two days ago
+	for _, o := range s.occurrences {
+		delete(s.occurrenceIDs, newOccurrenceKey(o.termID, o.batchID, o.createdAt))
+		o.createdAt = o.createdAt.AddDate(0, 0, -2)
+		s.occurrenceIDs[newOccurrenceKey(o.termID, o.batchID, o.createdAt)] = o.id
+	}
+	_, err = s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	_, err = s.Ingest(ctx, database.Ingestion{Words: []database.IngestedWord{{Value: "go"}}})
+	require.NoError(t, err)
+
+	counts := make([]int32, 0, len(s.occurrences))
+	for _, o := range sorted(s.occurrences, func(a, b *occurrence) int { return cmp.Compare(a.id, b.id) }) {
+		require.Equal(t, row.ID, o.termID)
+		counts = append(counts, o.count)
+	}
+	require.Equal(t, []int32{2, 2}, counts)
+}
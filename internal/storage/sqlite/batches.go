@@ -0,0 +1,655 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// Batches of both kinds with their numbers of items and total counts.
+const (
+	wordBatchesQuery = `SELECT
+    'words' AS kind,
+    wb.id,
+    wb.name,
+    COALESCE(wb.seniority, '') AS seniority,
+    COALESCE(wb.location, '') AS location,
+    COALESCE(wb.work_mode, '') AS work_mode,
+    wb.posting_id,
+    COALESCE(bs.terms, 0) AS items,
+    COALESCE(bs.total, 0) AS total,
+    wb.created_at
+FROM word_batches AS wb
+LEFT JOIN batch_stats AS bs ON wb.id = bs.batch_id`
+
+	phraseBatchesQuery = `SELECT
+    'phrases' AS kind,
+    pb.id,
+    pb.name,
+    '' AS seniority,
+    '' AS location,
+    '' AS work_mode,
+    pb.posting_id,
+    COUNT(DISTINCT phrases.value) AS items,
+    COUNT(phrases.id) AS total,
+    pb.created_at
+FROM phrase_batches AS pb
+LEFT JOIN phrases ON pb.id = phrases.batch_id AND phrases.deleted_at IS NULL`
+)
+
+const listBatches = `SELECT *
+FROM (
+    ` + wordBatchesQuery + `
+    WHERE wb.deleted_at IS NULL
+    UNION ALL
+    ` + phraseBatchesQuery + `
+    WHERE pb.deleted_at IS NULL
+    GROUP BY pb.id
+) AS batches
+WHERE
+    (@kind IS NULL OR batches.kind = @kind)
+    AND (@created_from IS NULL OR batches.created_at >= @created_from)
+    AND (@created_to IS NULL OR batches.created_at < @created_to)
+    AND (
+        @prefix IS NULL
+        OR SUBSTR(batches.name, 1, LENGTH(@prefix)) = @prefix
+    )
+ORDER BY batches.created_at ASC, batches.kind ASC, batches.id ASC
+LIMIT @limit OFFSET @offset`
+
+// ListBatches lists batches of both kinds, items are distinct words or phrases of a
+// batch, total counts their occurrences.
+func (s *Store) ListBatches(ctx context.Context, arg database.ListBatchesParams) ([]database.ListBatchesRow, error) {
+	rows, err := s.db.QueryContext(ctx, listBatches,
+		sql.Named("kind", text(arg.Kind)),
+		sql.Named("created_from", optionalTime(arg.CreatedFrom)),
+		sql.Named("created_to", optionalTime(arg.CreatedTo)),
+		sql.Named("prefix", text(arg.Prefix)),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListBatchesRow
+	for rows.Next() {
+		var i database.ListBatchesRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.Name,
+			&i.Seniority,
+			&i.Location,
+			&i.WorkMode,
+			&i.PostingID,
+			&i.Items,
+			&i.Total,
+			timestamp{&i.CreatedAt},
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const getWordBatch = wordBatchesQuery + `
+WHERE wb.id = @id AND wb.deleted_at IS NULL`
+
+func (s *Store) GetWordBatch(ctx context.Context, id int64) (database.GetWordBatchRow, error) {
+	var i database.GetWordBatchRow
+	err := s.db.QueryRowContext(ctx, getWordBatch, sql.Named("id", id)).Scan(
+		&i.Kind,
+		&i.ID,
+		&i.Name,
+		&i.Seniority,
+		&i.Location,
+		&i.WorkMode,
+		&i.PostingID,
+		&i.Items,
+		&i.Total,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const getPhraseBatch = phraseBatchesQuery + `
+WHERE pb.id = @id AND pb.deleted_at IS NULL
+GROUP BY pb.id`
+
+func (s *Store) GetPhraseBatch(ctx context.Context, id int64) (database.GetPhraseBatchRow, error) {
+	var i database.GetPhraseBatchRow
+	err := s.db.QueryRowContext(ctx, getPhraseBatch, sql.Named("id", id)).Scan(
+		&i.Kind,
+		&i.ID,
+		&i.Name,
+		&i.Seniority,
+		&i.Location,
+		&i.WorkMode,
+		&i.PostingID,
+		&i.Items,
+		&i.Total,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const listBatchWords = `SELECT
+    v.id,
+    v.raw AS value,
+    v.lemma,
+    v.language,
+    o.count
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+WHERE
+    o.batch_id = @batch_id
+    AND o.deleted_at IS NULL
+    AND v.deleted_at IS NULL
+ORDER BY o.count DESC, v.raw ASC`
+
+func (s *Store) ListBatchWords(ctx context.Context, batchID int64) ([]database.ListBatchWordsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listBatchWords, sql.Named("batch_id", batchID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListBatchWordsRow
+	for rows.Next() {
+		var i database.ListBatchWordsRow
+		if err := rows.Scan(&i.ID, &i.Value, &i.Lemma, &i.Language, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listBatchPhrases = `SELECT
+    id,
+    value,
+    COALESCE(language, '') AS language
+FROM phrases
+WHERE batch_id = @batch_id AND deleted_at IS NULL
+ORDER BY id ASC`
+
+func (s *Store) ListBatchPhrases(ctx context.Context, batchID int64) ([]database.ListBatchPhrasesRow, error) {
+	rows, err := s.db.QueryContext(ctx, listBatchPhrases, sql.Named("batch_id", batchID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListBatchPhrasesRow
+	for rows.Next() {
+		var i database.ListBatchPhrasesRow
+		if err := rows.Scan(&i.ID, &i.Value, &i.Language); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listWordsByBatchName = `SELECT
+    wb.name AS batch_name,
+    v.raw AS word_value,
+    o.count
+FROM word_batches AS wb
+INNER JOIN occurrences AS o ON wb.id = o.batch_id
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+WHERE wb.name = @name AND wb.deleted_at IS NULL AND o.deleted_at IS NULL
+ORDER BY wb.created_at DESC, v.raw ASC`
+
+func (s *Store) ListWordsByBatchName(ctx context.Context, name string) ([]database.ListWordsByBatchNameRow, error) {
+	rows, err := s.db.QueryContext(ctx, listWordsByBatchName, sql.Named("name", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordsByBatchNameRow
+	for rows.Next() {
+		var i database.ListWordsByBatchNameRow
+		if err := rows.Scan(&i.BatchName, &i.WordValue, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *Store) CreateWordBatch(ctx context.Context, arg database.CreateWordBatchParams) (int64, error) {
+	return createWordBatchIn(ctx, s.db, arg)
+}
+
+func createWordBatchIn(ctx context.Context, db dbtx, arg database.CreateWordBatchParams) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, createWordBatch,
+		sql.Named("name", arg.Name),
+		sql.Named("seniority", arg.Seniority),
+		sql.Named("location", arg.Location),
+		sql.Named("work_mode", arg.WorkMode),
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+	).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const (
+	renameWordBatch = `UPDATE word_batches
+SET name = @name
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	renamePhraseBatch = `UPDATE phrase_batches
+SET name = @name
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+)
+
+func (s *Store) RenameWordBatch(ctx context.Context, arg database.RenameWordBatchParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, renameWordBatch, sql.Named("name", arg.Name), sql.Named("id", arg.ID)).Scan(&id)
+
+	return id, pgError(err)
+}
+
+func (s *Store) RenamePhraseBatch(ctx context.Context, arg database.RenamePhraseBatchParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, renamePhraseBatch, sql.Named("name", arg.Name), sql.Named("id", arg.ID)).Scan(&id)
+
+	return id, pgError(err)
+}
+
+// Soft deletes set deleted_at of a row and rows deleted with it to @now of a transaction,
+// so that restores bring back exactly the rows deleted together.
+const (
+	deleteWordBatch = `UPDATE word_batches
+SET deleted_at = @now
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	deleteWordBatchRows = `UPDATE occurrences
+SET deleted_at = @now
+WHERE batch_id = @id AND deleted_at IS NULL;
+
+UPDATE salaries
+SET deleted_at = @now
+WHERE batch_id = @id AND deleted_at IS NULL`
+
+	deletePhraseBatch = `UPDATE phrase_batches
+SET deleted_at = @now
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	deletePhraseBatchRows = `UPDATE phrases
+SET deleted_at = @now
+WHERE batch_id = @id AND deleted_at IS NULL`
+)
+
+// softDelete deletes a row with query returning its id, then rows deleted with it with
+// rows, in a transaction.
+func (s *Store) softDelete(ctx context.Context, query, rows string, id int64) (int64, error) {
+	args := []any{sql.Named("id", id), sql.Named("now", formatTime(time.Now()))}
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+			return pgError(err)
+		}
+		if _, err := tx.ExecContext(ctx, rows, args...); err != nil {
+			return fmt.Errorf("delete rows: %w", pgError(err))
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+// DeleteWordBatch soft deletes a batch with its occurrences and salaries.
+func (s *Store) DeleteWordBatch(ctx context.Context, id int64) (int64, error) {
+	return s.softDelete(ctx, deleteWordBatch, deleteWordBatchRows, id)
+}
+
+// DeletePhraseBatch soft deletes a batch with its phrases.
+func (s *Store) DeletePhraseBatch(ctx context.Context, id int64) (int64, error) {
+	return s.softDelete(ctx, deletePhraseBatch, deletePhraseBatchRows, id)
+}
+
+// Restores select deleted_at of a deleted row as @deleted_at, which rows deleted with it
+// share. Batches of deleted postings are restored with their posting.
+const (
+	deletedWordBatch = `SELECT wb.deleted_at
+FROM word_batches AS wb
+LEFT JOIN postings ON wb.posting_id = postings.id
+WHERE
+    wb.id = @id
+    AND wb.deleted_at IS NOT NULL
+    AND postings.deleted_at IS NULL`
+
+	restoreWordBatch = `UPDATE word_batches
+SET deleted_at = NULL
+WHERE id = @id;
+
+UPDATE occurrences
+SET deleted_at = NULL
+WHERE batch_id = @id AND deleted_at = @deleted_at;
+
+UPDATE salaries
+SET deleted_at = NULL
+WHERE batch_id = @id AND deleted_at = @deleted_at`
+
+	deletedPhraseBatch = `SELECT pb.deleted_at
+FROM phrase_batches AS pb
+LEFT JOIN postings ON pb.posting_id = postings.id
+WHERE
+    pb.id = @id
+    AND pb.deleted_at IS NOT NULL
+    AND postings.deleted_at IS NULL`
+
+	restorePhraseBatch = `UPDATE phrase_batches
+SET deleted_at = NULL
+WHERE id = @id;
+
+UPDATE phrases
+SET deleted_at = NULL
+WHERE batch_id = @id AND deleted_at = @deleted_at`
+)
+
+// restore restores a row selected by deleted, then rows deleted with it, with restore
+// in a transaction.
+func (s *Store) restore(ctx context.Context, deleted, restore string, id int64) (int64, error) {
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var deletedAt string
+		if err := tx.QueryRowContext(ctx, deleted, sql.Named("id", id)).Scan(&deletedAt); err != nil {
+			return pgError(err)
+		}
+		if _, err := tx.ExecContext(ctx, restore, sql.Named("id", id), sql.Named("deleted_at", deletedAt)); err != nil {
+			return fmt.Errorf("restore rows: %w", pgError(err))
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+// RestoreWordBatch restores a batch with occurrences and salaries deleted with it.
+func (s *Store) RestoreWordBatch(ctx context.Context, id int64) (int64, error) {
+	return s.restore(ctx, deletedWordBatch, restoreWordBatch, id)
+}
+
+// RestorePhraseBatch restores a batch with phrases deleted with it.
+func (s *Store) RestorePhraseBatch(ctx context.Context, id int64) (int64, error) {
+	return s.restore(ctx, deletedPhraseBatch, restorePhraseBatch, id)
+}
+
+const (
+	deleteMergedWordBatch = `UPDATE word_batches
+SET deleted_at = @now
+WHERE
+    id = @source
+    AND id <> @target
+    AND deleted_at IS NULL
+    AND EXISTS (
+        SELECT 1 FROM word_batches AS target
+        WHERE target.id = @target AND target.deleted_at IS NULL
+    )
+RETURNING id`
+
+	mergeOccurrences = `INSERT INTO occurrences (term_id, batch_id, count, created_at)
+SELECT
+    term_id,
+    @target,
+    count,
+    created_at
+FROM occurrences
+WHERE batch_id = @source AND deleted_at IS NULL
+ON CONFLICT (term_id, batch_id) WHERE batch_id IS NOT NULL` + updateOccurrence
+
+	deleteMergedOccurrences = `DELETE FROM occurrences
+WHERE batch_id = @source AND deleted_at IS NULL`
+
+	moveMergedSalaries = `UPDATE salaries
+SET batch_id = @target
+WHERE batch_id = @source AND deleted_at IS NULL`
+)
+
+// MergeWordBatches moves occurrences and salaries of source batch into target batch and
+// deletes source batch. Counts of terms in both batches are summed. Returns number of
+// moved occurrences.
+func (s *Store) MergeWordBatches(ctx context.Context, arg database.MergeWordBatchesParams) (int64, error) {
+	var moved int64
+	args := []any{
+		sql.Named("source", arg.Source),
+		sql.Named("target", arg.Target),
+		sql.Named("now", formatTime(time.Now())),
+	}
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var id int64
+		if err := tx.QueryRowContext(ctx, deleteMergedWordBatch, args...).Scan(&id); err != nil {
+			return pgError(err)
+		}
+		if _, err := tx.ExecContext(ctx, mergeOccurrences, args...); err != nil {
+			return fmt.Errorf("merge occurrences: %w", pgError(err))
+		}
+		r, err := tx.ExecContext(ctx, deleteMergedOccurrences, args...)
+		if err != nil {
+			return fmt.Errorf("delete merged occurrences: %w", pgError(err))
+		}
+		if moved, err = r.RowsAffected(); err != nil {
+			return fmt.Errorf("rows affected: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, moveMergedSalaries, args...); err != nil {
+			return fmt.Errorf("move salaries: %w", pgError(err))
+		}
+
+		return nil
+	})
+
+	return moved, err
+}
+
+const (
+	deleteMergedPhraseBatch = `UPDATE phrase_batches
+SET deleted_at = @now
+WHERE
+    id = @source
+    AND id <> @target
+    AND deleted_at IS NULL
+    AND EXISTS (
+        SELECT 1 FROM phrase_batches AS target
+        WHERE target.id = @target AND target.deleted_at IS NULL
+    )
+RETURNING id`
+
+	moveMergedPhrases = `UPDATE phrases
+SET batch_id = @target
+WHERE batch_id = @source AND deleted_at IS NULL`
+)
+
+// MergePhraseBatches moves phrases of source batch into target batch and deletes source
+// batch. Returns number of moved phrases.
+func (s *Store) MergePhraseBatches(ctx context.Context, arg database.MergePhraseBatchesParams) (int64, error) {
+	var moved int64
+	args := []any{
+		sql.Named("source", arg.Source),
+		sql.Named("target", arg.Target),
+		sql.Named("now", formatTime(time.Now())),
+	}
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var id int64
+		if err := tx.QueryRowContext(ctx, deleteMergedPhraseBatch, args...).Scan(&id); err != nil {
+			return pgError(err)
+		}
+		r, err := tx.ExecContext(ctx, moveMergedPhrases, args...)
+		if err != nil {
+			return fmt.Errorf("move phrases: %w", pgError(err))
+		}
+		moved, err = r.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("rows affected: %w", err)
+		}
+
+		return nil
+	})
+
+	return moved, err
+}
+
+const (
+	createSplitWordBatch = `INSERT INTO word_batches (name, seniority, location, work_mode)
+SELECT
+    @name,
+    seniority,
+    location,
+    work_mode
+FROM word_batches
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	moveSplitOccurrences = `UPDATE occurrences
+SET batch_id = @created
+WHERE
+    batch_id = @id
+    AND deleted_at IS NULL
+    AND term_id IN (
+        SELECT vocabulary.id FROM vocabulary
+        WHERE vocabulary.normalized IN (SELECT value FROM JSON_EACH(@values))
+    )`
+
+	createSplitPhraseBatch = `INSERT INTO phrase_batches (name)
+SELECT @name
+FROM phrase_batches
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	moveSplitPhrases = `UPDATE phrases
+SET batch_id = @created
+WHERE
+    batch_id = @id
+    AND deleted_at IS NULL
+    AND value IN (SELECT value FROM JSON_EACH(@values))`
+)
+
+// split creates a batch with create, then moves rows of values into it with move, in
+// a transaction.
+func (s *Store) split(ctx context.Context, create, move string, id int64, name string, values []string) (int64, int64, error) {
+	var created, moved int64
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, create, sql.Named("name", name), sql.Named("id", id)).Scan(&created)
+		if err != nil {
+			return pgError(err)
+		}
+		r, err := tx.ExecContext(ctx, move,
+			sql.Named("created", created),
+			sql.Named("id", id),
+			sql.Named("values", list(values)),
+		)
+		if err != nil {
+			return fmt.Errorf("move rows: %w", pgError(err))
+		}
+		moved, err = r.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("rows affected: %w", err)
+		}
+
+		return nil
+	})
+
+	return created, moved, err
+}
+
+// SplitWordBatch moves occurrences of words normalized to values into a new batch with
+// attributes of the batch. Salaries stay in the batch.
+func (s *Store) SplitWordBatch(ctx context.Context, arg database.SplitWordBatchParams) (database.SplitWordBatchRow, error) {
+	id, moved, err := s.split(ctx, createSplitWordBatch, moveSplitOccurrences, arg.ID, arg.Name, arg.Values)
+
+	return database.SplitWordBatchRow{ID: id, Moved: moved}, err
+}
+
+// SplitPhraseBatch moves phrases equal to values into a new batch.
+func (s *Store) SplitPhraseBatch(ctx context.Context, arg database.SplitPhraseBatchParams) (database.SplitPhraseBatchRow, error) {
+	id, moved, err := s.split(ctx, createSplitPhraseBatch, moveSplitPhrases, arg.ID, arg.Name, arg.Values)
+
+	return database.SplitPhraseBatchRow{ID: id, Moved: moved}, err
+}
+
+// Rows soft deleted before @before are counted, then deleted. Rows of purged batches and
+// postings are deleted with them by foreign keys, without being counted.
+const (
+	countPurged = `SELECT
+    (SELECT COUNT(*) FROM postings WHERE deleted_at < @before) AS postings,
+    (SELECT COUNT(*) FROM word_batches WHERE deleted_at < @before) AS word_batches,
+    (SELECT COUNT(*) FROM phrase_batches WHERE deleted_at < @before) AS phrase_batches,
+    (SELECT COUNT(*) FROM vocabulary WHERE deleted_at < @before) AS words,
+    (SELECT COUNT(*) FROM occurrences WHERE deleted_at < @before) AS occurrences,
+    (SELECT COUNT(*) FROM phrases WHERE deleted_at < @before) AS phrases,
+    (SELECT COUNT(*) FROM salaries WHERE deleted_at < @before) AS salaries,
+    (SELECT COUNT(*) FROM ocr_documents WHERE deleted_at < @before) AS documents`
+
+	purgeDeleted = `DELETE FROM occurrences WHERE deleted_at < @before;
+
+DELETE FROM salaries WHERE deleted_at < @before;
+
+DELETE FROM phrases WHERE deleted_at < @before;
+
+DELETE FROM ocr_documents WHERE deleted_at < @before;
+
+DELETE FROM word_batches WHERE deleted_at < @before;
+
+DELETE FROM phrase_batches WHERE deleted_at < @before;
+
+DELETE FROM vocabulary WHERE deleted_at < @before;
+
+DELETE FROM postings WHERE deleted_at < @before`
+)
+
+// PurgeDeleted hard deletes rows soft deleted before time.
+func (s *Store) PurgeDeleted(ctx context.Context, deletedBefore pgtype.Timestamptz) (database.PurgeDeletedRow, error) {
+	var i database.PurgeDeletedRow
+	before := sql.Named("before", optionalTime(deletedBefore))
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, countPurged, before).Scan(
+			&i.Postings,
+			&i.WordBatches,
+			&i.PhraseBatches,
+			&i.Words,
+			&i.Occurrences,
+			&i.Phrases,
+			&i.Salaries,
+			&i.Documents,
+		)
+		if err != nil {
+			return fmt.Errorf("count purged: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, purgeDeleted, before); err != nil {
+			return fmt.Errorf("purge deleted: %w", pgError(err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return database.PurgeDeletedRow{}, err
+	}
+
+	return i, nil
+}
@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createCollection = `INSERT INTO collections (name, description)
+VALUES (@name, @description)
+RETURNING id`
+
+func (s *Store) CreateCollection(ctx context.Context, arg database.CreateCollectionParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, createCollection,
+		sql.Named("name", arg.Name),
+		sql.Named("description", arg.Description),
+	).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const deleteCollection = `DELETE FROM collections
+WHERE name = @name
+RETURNING id`
+
+func (s *Store) DeleteCollection(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, deleteCollection, sql.Named("name", name)).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const (
+	collectionsQuery = `SELECT
+    g.id,
+    g.name,
+    g.description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM collections AS g
+LEFT JOIN collection_members AS m ON g.id = m.collection_id`
+
+	getCollection = collectionsQuery + `
+WHERE g.name = @name
+GROUP BY g.id`
+
+	listCollections = collectionsQuery + `
+GROUP BY g.id
+ORDER BY g.name ASC
+LIMIT @limit OFFSET @offset`
+)
+
+func (s *Store) GetCollection(ctx context.Context, name string) (database.GetCollectionRow, error) {
+	var i database.GetCollectionRow
+	err := s.db.QueryRowContext(ctx, getCollection, sql.Named("name", name)).Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Postings,
+		&i.WordBatches,
+		&i.PhraseBatches,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+func (s *Store) ListCollections(ctx context.Context, arg database.ListCollectionsParams) ([]database.ListCollectionsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listCollections,
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListCollectionsRow
+	for rows.Next() {
+		var i database.ListCollectionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Postings,
+			&i.WordBatches,
+			&i.PhraseBatches,
+			timestamp{&i.CreatedAt},
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// The SELECT has a WHERE clause, which SQLite requires of an upsert selecting rows.
+const addCollectionMember = `INSERT INTO collection_members (
+    collection_id, posting_id, word_batch_id, phrase_batch_id
+)
+SELECT
+    collections.id,
+    @posting_id,
+    @word_batch_id,
+    @phrase_batch_id
+FROM collections
+WHERE collections.name = @name
+ON CONFLICT (
+    collection_id,
+    IFNULL(posting_id, 0),
+    IFNULL(word_batch_id, 0),
+    IFNULL(phrase_batch_id, 0)
+) DO UPDATE
+SET created_at = collection_members.created_at
+RETURNING collection_id`
+
+// AddCollectionMember adds a member of a collection. Adding a member again is a no-op.
+func (s *Store) AddCollectionMember(ctx context.Context, arg database.AddCollectionMemberParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, addCollectionMember,
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+		sql.Named("word_batch_id", nullInt8(arg.WordBatchID)),
+		sql.Named("phrase_batch_id", nullInt8(arg.PhraseBatchID)),
+		sql.Named("name", arg.Name),
+	).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const removeCollectionMember = `DELETE FROM collection_members
+WHERE
+    collection_id IN (SELECT id FROM collections WHERE name = @name)
+    AND posting_id IS @posting_id
+    AND word_batch_id IS @word_batch_id
+    AND phrase_batch_id IS @phrase_batch_id
+RETURNING collection_id`
+
+func (s *Store) RemoveCollectionMember(ctx context.Context, arg database.RemoveCollectionMemberParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, removeCollectionMember,
+		sql.Named("name", arg.Name),
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+		sql.Named("word_batch_id", nullInt8(arg.WordBatchID)),
+		sql.Named("phrase_batch_id", nullInt8(arg.PhraseBatchID)),
+	).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const listCollectionMembers = `SELECT
+    CASE
+        WHEN m.posting_id IS NOT NULL THEN 'posting'
+        WHEN m.word_batch_id IS NOT NULL THEN 'words'
+        ELSE 'phrases'
+    END AS type,
+    COALESCE(m.posting_id, m.word_batch_id, m.phrase_batch_id) AS id,
+    COALESCE(postings.title, word_batches.name, phrase_batches.name, '') AS name,
+    m.created_at
+FROM collection_members AS m
+INNER JOIN collections AS g ON m.collection_id = g.id
+LEFT JOIN postings ON m.posting_id = postings.id
+LEFT JOIN word_batches ON m.word_batch_id = word_batches.id
+LEFT JOIN phrase_batches ON m.phrase_batch_id = phrase_batches.id
+WHERE
+    g.name = @name
+    AND postings.deleted_at IS NULL
+    AND word_batches.deleted_at IS NULL
+    AND phrase_batches.deleted_at IS NULL
+ORDER BY m.created_at ASC`
+
+// ListCollectionMembers lists postings, word batches (words) and phrase batches
+// (phrases) of a collection which aren't deleted.
+func (s *Store) ListCollectionMembers(ctx context.Context, name string) ([]database.ListCollectionMembersRow, error) {
+	rows, err := s.db.QueryContext(ctx, listCollectionMembers, sql.Named("name", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListCollectionMembersRow
+	for rows.Next() {
+		var i database.ListCollectionMembersRow
+		if err := rows.Scan(&i.Type, &i.ID, &i.Name, timestamp{&i.CreatedAt}); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
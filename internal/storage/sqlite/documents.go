@@ -0,0 +1,259 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createImage = `INSERT INTO images (
+    hash,
+    width,
+    height,
+    format,
+    size_bytes,
+    filename,
+    content,
+    path
+)
+VALUES (
+    @hash,
+    @width,
+    @height,
+    @format,
+    @size_bytes,
+    @filename,
+    @content,
+    NULLIF(@path, '')
+)
+ON CONFLICT (hash) WHERE deleted_at IS NULL DO UPDATE
+SET
+    content = COALESCE(images.content, excluded.content),
+    path = COALESCE(images.path, excluded.path)
+RETURNING
+    id,
+    hash,
+    width,
+    height,
+    format,
+    size_bytes,
+    filename,
+    created_at`
+
+// CreateImage adds an image or returns the image of the same hash, storing its content
+// and path if they weren't stored.
+func (s *Store) CreateImage(ctx context.Context, arg database.CreateImageParams) (database.CreateImageRow, error) {
+	return createImageIn(ctx, s.db, arg)
+}
+
+func createImageIn(ctx context.Context, db dbtx, arg database.CreateImageParams) (database.CreateImageRow, error) {
+	var content any
+	if arg.Content != nil {
+		content = arg.Content
+	}
+
+	var i database.CreateImageRow
+	err := db.QueryRowContext(ctx, createImage,
+		sql.Named("hash", arg.Hash),
+		sql.Named("width", arg.Width),
+		sql.Named("height", arg.Height),
+		sql.Named("format", arg.Format),
+		sql.Named("size_bytes", arg.SizeBytes),
+		sql.Named("filename", arg.Filename),
+		sql.Named("content", content),
+		sql.Named("path", arg.Path),
+	).Scan(
+		&i.ID,
+		&i.Hash,
+		&i.Width,
+		&i.Height,
+		&i.Format,
+		&i.SizeBytes,
+		&i.Filename,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const createOcrDocument = `INSERT INTO ocr_documents (
+    posting_id,
+    image_id,
+    text,
+    engine,
+    engine_version,
+    languages,
+    settings,
+    confidence,
+    duration_ms
+)
+VALUES (
+    @posting_id,
+    @image_id,
+    @text,
+    @engine,
+    @engine_version,
+    @languages,
+    @settings,
+    @confidence,
+    @duration_ms
+)
+RETURNING
+    id,
+    posting_id,
+    image_id,
+    engine,
+    engine_version,
+    languages,
+    confidence,
+    duration_ms,
+    created_at`
+
+// errInvalidSettings is the error of Postgres for settings which aren't JSON.
+var errInvalidSettings = &pgconn.PgError{
+	Severity: "ERROR",
+	Code:     "22P02",
+	Message:  "invalid input syntax for type json",
+}
+
+// CreateOcrDocument adds a document. Settings must be JSON, as SQLite doesn't check
+// them like jsonb of Postgres does.
+func (s *Store) CreateOcrDocument(ctx context.Context, arg database.CreateOcrDocumentParams) (database.CreateOcrDocumentRow, error) {
+	return createOcrDocumentIn(ctx, s.db, arg)
+}
+
+func createOcrDocumentIn(ctx context.Context, db dbtx, arg database.CreateOcrDocumentParams) (database.CreateOcrDocumentRow, error) {
+	var i database.CreateOcrDocumentRow
+
+	var settings any
+	if arg.Settings != nil {
+		if !json.Valid(arg.Settings) {
+			return i, errInvalidSettings
+		}
+		settings = string(arg.Settings)
+	}
+	var confidence any
+	if arg.Confidence.Valid {
+		confidence = arg.Confidence.Float64
+	}
+
+	err := db.QueryRowContext(ctx, createOcrDocument,
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+		sql.Named("image_id", nullInt8(arg.ImageID)),
+		sql.Named("text", arg.Text),
+		sql.Named("engine", arg.Engine),
+		sql.Named("engine_version", arg.EngineVersion),
+		sql.Named("languages", list(arg.Languages)),
+		sql.Named("settings", settings),
+		sql.Named("confidence", confidence),
+		sql.Named("duration_ms", arg.DurationMs),
+	).Scan(
+		&i.ID,
+		&i.PostingID,
+		&i.ImageID,
+		&i.Engine,
+		&i.EngineVersion,
+		jsonList{&i.Languages},
+		&i.Confidence,
+		&i.DurationMs,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const getLatestOcrDocument = `SELECT
+    d.id,
+    d.posting_id,
+    d.image_id,
+    d.text,
+    d.engine,
+    images.format AS image_format,
+    images.content AS image_content,
+    images.path AS image_path,
+    d.created_at
+FROM ocr_documents AS d
+LEFT JOIN images ON d.image_id = images.id AND images.deleted_at IS NULL
+WHERE d.posting_id = @posting_id AND d.deleted_at IS NULL
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT 1`
+
+func (s *Store) GetLatestOcrDocument(ctx context.Context, postingID int64) (database.GetLatestOcrDocumentRow, error) {
+	var i database.GetLatestOcrDocumentRow
+	err := s.db.QueryRowContext(ctx, getLatestOcrDocument, sql.Named("posting_id", postingID)).Scan(
+		&i.ID,
+		&i.PostingID,
+		&i.ImageID,
+		&i.Text,
+		&i.Engine,
+		&i.ImageFormat,
+		&i.ImageContent,
+		&i.ImagePath,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const listOcrDocuments = `SELECT
+    d.id,
+    d.posting_id,
+    d.image_id,
+    d.engine,
+    d.engine_version,
+    d.languages,
+    d.settings,
+    d.confidence,
+    d.duration_ms,
+    LENGTH(d.text) AS text_length,
+    images.hash AS image_hash,
+    images.width AS image_width,
+    images.height AS image_height,
+    images.format AS image_format,
+    images.size_bytes AS image_size_bytes,
+    images.filename AS image_filename,
+    d.created_at
+FROM ocr_documents AS d
+LEFT JOIN images ON d.image_id = images.id AND images.deleted_at IS NULL
+WHERE d.posting_id = @posting_id AND d.deleted_at IS NULL
+ORDER BY d.created_at DESC, d.id DESC`
+
+func (s *Store) ListOcrDocuments(ctx context.Context, postingID int64) ([]database.ListOcrDocumentsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listOcrDocuments, sql.Named("posting_id", postingID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListOcrDocumentsRow
+	for rows.Next() {
+		var i database.ListOcrDocumentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostingID,
+			&i.ImageID,
+			&i.Engine,
+			&i.EngineVersion,
+			jsonList{&i.Languages},
+			&i.Settings,
+			&i.Confidence,
+			&i.DurationMs,
+			&i.TextLength,
+			&i.ImageHash,
+			&i.ImageWidth,
+			&i.ImageHeight,
+			&i.ImageFormat,
+			&i.ImageSizeBytes,
+			&i.ImageFilename,
+			timestamp{&i.CreatedAt},
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
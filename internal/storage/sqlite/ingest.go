@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createWordBatch = `INSERT INTO word_batches (name, seniority, location, work_mode, posting_id)
+VALUES (
+    @name,
+    NULLIF(@seniority, ''),
+    NULLIF(@location, ''),
+    NULLIF(@work_mode, ''),
+    @posting_id
+)
+RETURNING id`
+
+// insertTerm adds a term or restores it if it's deleted, otherwise it changes no rows.
+const insertTerm = `INSERT INTO vocabulary (raw, normalized, lemma, language)
+VALUES (@value, @normalized, @lemma, @language)
+ON CONFLICT (raw, language) DO UPDATE
+SET deleted_at = NULL
+WHERE vocabulary.deleted_at IS NOT NULL`
+
+const updateTermLemma = `UPDATE vocabulary
+SET lemma = @lemma
+WHERE
+    raw = @value
+    AND language = @language
+    AND lemma = ''
+    AND @lemma <> ''`
+
+const getTermID = `SELECT id FROM vocabulary WHERE raw = @value AND language = @language`
+
+// ingestedTerm is an ingested word counted by its value and language.
+type ingestedTerm struct {
+	database.IngestedWord
+
+	count int
+}
+
+// countTerms returns terms of words in order of their first occurrence, with the
+// greatest lemma of their words.
+func countTerms(words []database.IngestedWord) []*ingestedTerm {
+	terms := make([]*ingestedTerm, 0)
+	seen := make(map[[2]string]*ingestedTerm)
+	for _, w := range words {
+		key := [2]string{w.Value, w.Language}
+		t, ok := seen[key]
+		if !ok {
+			t = &ingestedTerm{IngestedWord: w}
+			seen[key] = t
+			terms = append(terms, t)
+		}
+		t.count++
+		if w.Lemma > t.Lemma {
+			t.Lemma = w.Lemma
+		}
+	}
+
+	return terms
+}
+
+// Ingest stores a posting, its batches, salaries and document with a single
+// transaction, which is rolled back if it fails. Counts of result match the Postgres
+// repository.
+func (s *Store) Ingest(ctx context.Context, in database.Ingestion) (database.IngestResult, error) {
+	var res database.IngestResult
+
+	if err := in.Validate(); err != nil {
+		return res, err
+	}
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if in.Posting != nil {
+			row, err := createPostingIn(ctx, tx, *in.Posting)
+			if err != nil {
+				return fmt.Errorf("create posting: %w", err)
+			}
+			res.PostingID = pgtype.Int8{Int64: row.ID, Valid: true}
+			in.PostingID = res.PostingID
+			if in.Name == "" {
+				in.Name = database.PostingBatchName(row.ID)
+			}
+		}
+
+		if in.ReplaceBatches && in.PostingID.Valid {
+			if err := deletePostingBatchesIn(ctx, tx, in.PostingID.Int64); err != nil {
+				return err
+			}
+		}
+
+		if len(in.Words) > 0 {
+			if in.Name != "" {
+				id, err := createWordBatchIn(ctx, tx, database.CreateWordBatchParams{
+					Name:      in.Name,
+					Seniority: in.Seniority,
+					Location:  in.Location,
+					WorkMode:  in.WorkMode,
+					PostingID: in.PostingID,
+				})
+				if err != nil {
+					return fmt.Errorf("create word batch: %w", err)
+				}
+				res.WordBatchID = pgtype.Int8{Int64: id, Valid: true}
+			}
+			if err := ingestWords(ctx, tx, res.WordBatchID, in.Words, &res); err != nil {
+				return err
+			}
+		}
+
+		for _, salary := range in.Salaries {
+			salary.BatchID = res.WordBatchID.Int64
+			if _, err := createSalaryIn(ctx, tx, salary); err != nil {
+				return fmt.Errorf("create salary: %w", err)
+			}
+			res.Salaries++
+		}
+
+		if len(in.Phrases) > 0 {
+			params := database.CreatePhrasesBatchParams{
+				Name:      in.Name,
+				PostingID: in.PostingID,
+				Phrases:   make([]string, 0, len(in.Phrases)),
+				Languages: make([]string, 0, len(in.Phrases)),
+			}
+			for _, p := range in.Phrases {
+				params.Phrases = append(params.Phrases, p.Value)
+				params.Languages = append(params.Languages, p.Language)
+			}
+			row, err := createPhrasesBatchIn(ctx, tx, params)
+			if err != nil {
+				return fmt.Errorf("create phrases batch: %w", err)
+			}
+			res.PhraseBatchID = row.BatchID
+			res.Phrases = int64(len(in.Phrases))
+		}
+
+		if in.Document != nil {
+			doc := in.Document.Document
+			doc.PostingID = in.PostingID
+			if in.Document.Image != nil {
+				row, err := createImageIn(ctx, tx, *in.Document.Image)
+				if err != nil {
+					return fmt.Errorf("create image: %w", err)
+				}
+				doc.ImageID = pgtype.Int8{Int64: row.ID, Valid: true}
+			}
+			row, err := createOcrDocumentIn(ctx, tx, doc)
+			if err != nil {
+				return fmt.Errorf("create ocr document: %w", err)
+			}
+			res.DocumentID = pgtype.Int8{Int64: row.ID, Valid: true}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return database.IngestResult{}, err
+	}
+
+	return res, nil
+}
+
+// ingestWords counts words in batch with batchID, or in days if it's null.
+func ingestWords(ctx context.Context, tx *sql.Tx, batchID pgtype.Int8, words []database.IngestedWord, res *database.IngestResult) error {
+	res.Words = int64(len(words))
+
+	for _, t := range countTerms(words) {
+		value, language := sql.Named("value", t.Value), sql.Named("language", t.Language)
+
+		r, err := tx.ExecContext(ctx, insertTerm, value, language,
+			sql.Named("normalized", strings.ToLower(t.Value)),
+			sql.Named("lemma", t.Lemma),
+		)
+		if err != nil {
+			return fmt.Errorf("insert ingested term: %w", err)
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("rows affected: %w", err)
+		}
+		res.Terms += n
+
+		if _, err := tx.ExecContext(ctx, updateTermLemma, value, language, sql.Named("lemma", t.Lemma)); err != nil {
+			return fmt.Errorf("update ingested lemma: %w", err)
+		}
+
+		var id int64
+		if err := tx.QueryRowContext(ctx, getTermID, value, language).Scan(&id); err != nil {
+			return fmt.Errorf("get ingested term: %w", err)
+		}
+		upsert := upsertBatchOccurrence
+		if !batchID.Valid {
+			upsert = upsertDayOccurrence
+		}
+		_, err = tx.ExecContext(ctx, upsert,
+			sql.Named("term_id", id),
+			sql.Named("batch_id", nullInt8(batchID)),
+			sql.Named("count", t.count),
+		)
+		if err != nil {
+			return fmt.Errorf("insert ingested occurrence: %w", pgError(err))
+		}
+		res.Occurrences++
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+DROP VIEW IF EXISTS word_batch_collections;
+DROP VIEW IF EXISTS word_batch_tags;
+DROP TABLE IF EXISTS collection_members;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS tag_members;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS occurrences;
+DROP TABLE IF EXISTS vocabulary;
+DROP TABLE IF EXISTS word_batches;
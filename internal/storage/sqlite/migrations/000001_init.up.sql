@@ -0,0 +1,90 @@
+-- Words schema of Postgres migrations reduced to what SQLite storage queries. Times
+-- are UTC text of the same width, so that they compare as strings.
+CREATE TABLE IF NOT EXISTS word_batches (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    seniority TEXT,
+    location TEXT,
+    work_mode TEXT,
+    posting_id INTEGER,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id)
+WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS vocabulary (
+    id INTEGER PRIMARY KEY,
+    raw TEXT NOT NULL CHECK (LENGTH(raw) > 0),
+    normalized TEXT NOT NULL,
+    lemma TEXT NOT NULL DEFAULT '',
+    language TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT,
+    CONSTRAINT vocabulary_raw_language_unique UNIQUE (raw, language)
+);
+
+CREATE INDEX idx_vocabulary_normalized ON vocabulary (normalized);
+
+CREATE TABLE IF NOT EXISTS occurrences (
+    id INTEGER PRIMARY KEY,
+    term_id INTEGER NOT NULL REFERENCES vocabulary (id) ON DELETE CASCADE,
+    batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    count INTEGER NOT NULL CHECK (count > 0),
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+-- Terms added without a batch are counted in a single row, like with NULLS NOT
+-- DISTINCT constraint of Postgres.
+CREATE UNIQUE INDEX occurrences_term_batch_unique ON occurrences (
+    term_id, IFNULL(batch_id, 0)
+);
+
+CREATE INDEX idx_occurrences_batch_id ON occurrences (batch_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS tag_members (
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    posting_id INTEGER,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS collection_members (
+    collection_id INTEGER NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    posting_id INTEGER,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE VIEW word_batch_tags AS
+SELECT
+    word_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE VIEW word_batch_collections AS
+SELECT
+    word_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
@@ -0,0 +1,36 @@
+DROP INDEX IF EXISTS occurrences_term_day_unique;
+DROP INDEX IF EXISTS occurrences_term_batch_unique;
+
+-- Occurrences of a term without a batch are merged into its first row.
+UPDATE occurrences
+SET
+    count = (
+        SELECT SUM(o.count)
+        FROM occurrences AS o
+        WHERE
+            o.term_id = occurrences.term_id
+            AND o.batch_id IS NULL
+            AND o.deleted_at IS NULL
+    ),
+    deleted_at = NULL
+WHERE id IN (
+    SELECT MIN(id)
+    FROM occurrences
+    WHERE batch_id IS NULL
+    GROUP BY term_id
+    HAVING COUNT(*) > 1 AND SUM(IIF(deleted_at IS NULL, count, 0)) > 0
+);
+
+DELETE FROM occurrences
+WHERE
+    batch_id IS NULL
+    AND id NOT IN (
+        SELECT MIN(id)
+        FROM occurrences
+        WHERE batch_id IS NULL
+        GROUP BY term_id
+    );
+
+CREATE UNIQUE INDEX occurrences_term_batch_unique ON occurrences (
+    term_id, IFNULL(batch_id, 0)
+);
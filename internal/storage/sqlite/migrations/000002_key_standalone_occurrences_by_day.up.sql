@@ -0,0 +1,12 @@
+-- Terms added without a batch are counted in a row per day (UTC), like in Postgres.
+DROP INDEX IF EXISTS occurrences_term_batch_unique;
+
+CREATE UNIQUE INDEX occurrences_term_batch_unique ON occurrences (
+    term_id, batch_id
+)
+WHERE batch_id IS NOT NULL;
+
+CREATE UNIQUE INDEX occurrences_term_day_unique ON occurrences (
+    term_id, SUBSTR(created_at, 1, 10)
+)
+WHERE batch_id IS NULL;
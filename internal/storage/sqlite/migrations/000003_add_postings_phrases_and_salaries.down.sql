@@ -0,0 +1,127 @@
+DROP VIEW IF EXISTS daily_term_stats;
+
+DROP VIEW IF EXISTS batch_stats;
+
+DROP VIEW IF EXISTS term_stats;
+
+DROP VIEW IF EXISTS phrase_batch_collections;
+
+DROP VIEW IF EXISTS word_batch_collections;
+
+DROP VIEW IF EXISTS phrase_batch_tags;
+
+DROP VIEW IF EXISTS word_batch_tags;
+
+-- Members of postings and phrase batches are dropped with them.
+CREATE TABLE new_collection_members (
+    collection_id INTEGER NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    posting_id INTEGER,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO new_collection_members (collection_id, word_batch_id, created_at)
+SELECT collection_id, word_batch_id, created_at
+FROM collection_members
+WHERE word_batch_id IS NOT NULL;
+
+DROP TABLE collection_members;
+
+ALTER TABLE new_collection_members RENAME TO collection_members;
+
+CREATE TABLE new_tag_members (
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    posting_id INTEGER,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO new_tag_members (tag_id, word_batch_id, created_at)
+SELECT tag_id, word_batch_id, created_at
+FROM tag_members
+WHERE word_batch_id IS NOT NULL;
+
+DROP TABLE tag_members;
+
+ALTER TABLE new_tag_members RENAME TO tag_members;
+
+CREATE TABLE new_collections (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO new_collections (id, name, description, created_at)
+SELECT id, name, description, created_at FROM collections;
+
+DROP TABLE collections;
+
+ALTER TABLE new_collections RENAME TO collections;
+
+CREATE TABLE new_tags (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+INSERT INTO new_tags (id, name, created_at)
+SELECT id, name, created_at FROM tags;
+
+DROP TABLE tags;
+
+ALTER TABLE new_tags RENAME TO tags;
+
+DROP TABLE IF EXISTS ocr_documents;
+
+DROP TABLE IF EXISTS images;
+
+DROP TABLE IF EXISTS salaries;
+
+DROP TABLE IF EXISTS phrases;
+
+DROP TABLE IF EXISTS phrase_batches;
+
+CREATE TABLE new_word_batches (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    seniority TEXT,
+    location TEXT,
+    work_mode TEXT,
+    posting_id INTEGER,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+INSERT INTO new_word_batches (
+    id, name, seniority, location, work_mode, posting_id, created_at, deleted_at
+)
+SELECT id, name, seniority, location, work_mode, posting_id, created_at, deleted_at
+FROM word_batches;
+
+DROP TABLE word_batches;
+
+ALTER TABLE new_word_batches RENAME TO word_batches;
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id)
+WHERE deleted_at IS NULL;
+
+DROP TABLE IF EXISTS postings;
+
+CREATE VIEW word_batch_tags AS
+SELECT
+    word_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE VIEW word_batch_collections AS
+SELECT
+    word_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
@@ -0,0 +1,296 @@
+-- Rest of the Postgres schema: postings with their documents, phrases, salaries and
+-- summaries of occurrences. SQLite can't add foreign keys or checks to existing tables,
+-- so tables referencing postings and phrase batches are rebuilt. Postings weren't
+-- stored before, so posting IDs of batches and members are dropped.
+DROP VIEW IF EXISTS word_batch_tags;
+
+DROP VIEW IF EXISTS word_batch_collections;
+
+CREATE TABLE IF NOT EXISTS postings (
+    id INTEGER PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    company TEXT NOT NULL DEFAULT '',
+    source_url TEXT NOT NULL DEFAULT '',
+    captured_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    image_hash TEXT,
+    raw_text TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE INDEX idx_postings_captured_at ON postings (captured_at)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_postings_image_hash ON postings (image_hash)
+WHERE deleted_at IS NULL;
+
+CREATE TABLE new_word_batches (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    seniority TEXT,
+    location TEXT,
+    work_mode TEXT,
+    posting_id INTEGER REFERENCES postings (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+INSERT INTO new_word_batches (
+    id, name, seniority, location, work_mode, created_at, deleted_at
+)
+SELECT id, name, seniority, location, work_mode, created_at, deleted_at
+FROM word_batches;
+
+DROP TABLE word_batches;
+
+ALTER TABLE new_word_batches RENAME TO word_batches;
+
+CREATE UNIQUE INDEX idx_word_batches_posting_id ON word_batches (posting_id)
+WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS phrase_batches (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL CHECK (LENGTH(name) > 0),
+    posting_id INTEGER REFERENCES postings (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT,
+    CONSTRAINT phrase_batches_name_unique UNIQUE (name)
+);
+
+CREATE UNIQUE INDEX idx_phrase_batches_posting_id ON phrase_batches (posting_id)
+WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS phrases (
+    id INTEGER PRIMARY KEY,
+    value TEXT NOT NULL CHECK (LENGTH(value) > 0),
+    language TEXT,
+    batch_id INTEGER REFERENCES phrase_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE INDEX idx_phrase_batch_id ON phrases (batch_id);
+
+CREATE TABLE IF NOT EXISTS salaries (
+    id INTEGER PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES word_batches (id) ON DELETE CASCADE,
+    min_amount REAL NOT NULL,
+    max_amount REAL NOT NULL,
+    currency TEXT NOT NULL,
+    period TEXT NOT NULL,
+    monthly_min REAL NOT NULL,
+    monthly_max REAL NOT NULL,
+    contract TEXT,
+    basis TEXT,
+    seniority TEXT,
+    raw TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE INDEX idx_salaries_batch_id ON salaries (batch_id);
+
+CREATE TABLE IF NOT EXISTS images (
+    id INTEGER PRIMARY KEY,
+    hash TEXT NOT NULL,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    format TEXT NOT NULL DEFAULT '',
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    filename TEXT NOT NULL DEFAULT '',
+    content BLOB,
+    path TEXT,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE UNIQUE INDEX idx_images_hash ON images (hash)
+WHERE deleted_at IS NULL;
+
+-- Languages are a JSON array, settings a JSON object.
+CREATE TABLE IF NOT EXISTS ocr_documents (
+    id INTEGER PRIMARY KEY,
+    posting_id INTEGER REFERENCES postings (id) ON DELETE CASCADE,
+    image_id INTEGER REFERENCES images (id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    engine TEXT NOT NULL,
+    engine_version TEXT NOT NULL DEFAULT '',
+    languages TEXT NOT NULL DEFAULT '[]',
+    settings TEXT NOT NULL DEFAULT '{}',
+    confidence REAL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at TEXT
+);
+
+CREATE INDEX idx_ocr_documents_posting_id ON ocr_documents (
+    posting_id, created_at
+);
+
+CREATE TABLE new_tags (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL CHECK (LENGTH(name) > 0),
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CONSTRAINT tags_name_unique UNIQUE (name)
+);
+
+INSERT INTO new_tags (id, name, created_at)
+SELECT id, name, created_at FROM tags;
+
+DROP TABLE tags;
+
+ALTER TABLE new_tags RENAME TO tags;
+
+CREATE TABLE new_collections (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL CHECK (LENGTH(name) > 0),
+    description TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CONSTRAINT collections_name_unique UNIQUE (name)
+);
+
+INSERT INTO new_collections (id, name, description, created_at)
+SELECT id, name, description, created_at FROM collections;
+
+DROP TABLE collections;
+
+ALTER TABLE new_collections RENAME TO collections;
+
+-- Members are exactly one of a posting, a word batch or a phrase batch. Members are
+-- unique like with NULLS NOT DISTINCT constraint of Postgres.
+CREATE TABLE new_tag_members (
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    posting_id INTEGER REFERENCES postings (id) ON DELETE CASCADE,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    phrase_batch_id INTEGER REFERENCES phrase_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CHECK (
+        (posting_id IS NOT NULL)
+        + (word_batch_id IS NOT NULL)
+        + (phrase_batch_id IS NOT NULL) = 1
+    )
+);
+
+INSERT INTO new_tag_members (tag_id, word_batch_id, created_at)
+SELECT tag_id, word_batch_id, MIN(created_at)
+FROM tag_members
+WHERE word_batch_id IS NOT NULL
+GROUP BY tag_id, word_batch_id;
+
+DROP TABLE tag_members;
+
+ALTER TABLE new_tag_members RENAME TO tag_members;
+
+CREATE UNIQUE INDEX tag_members_unique ON tag_members (
+    tag_id,
+    IFNULL(posting_id, 0),
+    IFNULL(word_batch_id, 0),
+    IFNULL(phrase_batch_id, 0)
+);
+
+CREATE INDEX idx_tag_members_posting_id ON tag_members (posting_id);
+
+CREATE TABLE new_collection_members (
+    collection_id INTEGER NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    posting_id INTEGER REFERENCES postings (id) ON DELETE CASCADE,
+    word_batch_id INTEGER REFERENCES word_batches (id) ON DELETE CASCADE,
+    phrase_batch_id INTEGER REFERENCES phrase_batches (id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CHECK (
+        (posting_id IS NOT NULL)
+        + (word_batch_id IS NOT NULL)
+        + (phrase_batch_id IS NOT NULL) = 1
+    )
+);
+
+INSERT INTO new_collection_members (collection_id, word_batch_id, created_at)
+SELECT collection_id, word_batch_id, MIN(created_at)
+FROM collection_members
+WHERE word_batch_id IS NOT NULL
+GROUP BY collection_id, word_batch_id;
+
+DROP TABLE collection_members;
+
+ALTER TABLE new_collection_members RENAME TO collection_members;
+
+CREATE UNIQUE INDEX collection_members_unique ON collection_members (
+    collection_id,
+    IFNULL(posting_id, 0),
+    IFNULL(word_batch_id, 0),
+    IFNULL(phrase_batch_id, 0)
+);
+
+CREATE INDEX idx_collection_members_posting_id ON collection_members (
+    posting_id
+);
+
+CREATE VIEW word_batch_tags AS
+SELECT
+    word_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE VIEW phrase_batch_tags AS
+SELECT
+    phrase_batches.id AS batch_id,
+    tags.name AS tag
+FROM tag_members AS m
+INNER JOIN tags ON m.tag_id = tags.id
+INNER JOIN phrase_batches
+    ON
+        m.phrase_batch_id = phrase_batches.id
+        OR m.posting_id = phrase_batches.posting_id;
+
+CREATE VIEW word_batch_collections AS
+SELECT
+    word_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN word_batches
+    ON m.word_batch_id = word_batches.id OR m.posting_id = word_batches.posting_id;
+
+CREATE VIEW phrase_batch_collections AS
+SELECT
+    phrase_batches.id AS batch_id,
+    collections.name AS collection
+FROM collection_members AS m
+INNER JOIN collections ON m.collection_id = collections.id
+INNER JOIN phrase_batches
+    ON
+        m.phrase_batch_id = phrase_batches.id
+        OR m.posting_id = phrase_batches.posting_id;
+
+-- Summaries of occurrences which aren't deleted, which Postgres keeps in tables updated
+-- by triggers. Views are always current, so they aren't refreshed.
+CREATE VIEW term_stats AS
+SELECT
+    term_id,
+    SUM(count) AS total,
+    COUNT(batch_id) AS postings
+FROM occurrences
+WHERE deleted_at IS NULL
+GROUP BY term_id;
+
+CREATE VIEW batch_stats AS
+SELECT
+    batch_id,
+    COUNT(*) AS terms,
+    SUM(count) AS total
+FROM occurrences
+WHERE deleted_at IS NULL AND batch_id IS NOT NULL
+GROUP BY batch_id;
+
+CREATE VIEW daily_term_stats AS
+SELECT
+    SUBSTR(created_at, 1, 10) AS day,
+    term_id,
+    SUM(count) AS total,
+    COUNT(batch_id) AS postings
+FROM occurrences
+WHERE deleted_at IS NULL
+GROUP BY SUBSTR(created_at, 1, 10), term_id;
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const (
+	createPhraseBatch = `INSERT INTO phrase_batches (name, posting_id)
+VALUES (@name, @posting_id)
+RETURNING id`
+
+	// Languages are paired with phrases by their positions.
+	insertPhrases = `INSERT INTO phrases (value, language, batch_id)
+SELECT
+    phrase.value,
+    NULLIF(language.value, ''),
+    @batch_id
+FROM JSON_EACH(@phrases) AS phrase
+LEFT JOIN JSON_EACH(@languages) AS language ON phrase.key = language.key
+ORDER BY phrase.key ASC`
+
+	firstPhrase = `SELECT MIN(id) FROM phrases WHERE batch_id = @batch_id`
+)
+
+// CreatePhrasesBatch adds a batch with its phrases and returns the first phrase. Like in
+// Postgres the batch is kept without phrases, though pgx.ErrNoRows is returned then.
+func (s *Store) CreatePhrasesBatch(ctx context.Context, arg database.CreatePhrasesBatchParams) (database.CreatePhrasesBatchRow, error) {
+	var i database.CreatePhrasesBatchRow
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		i, err = createPhrasesBatchIn(ctx, tx, arg)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	})
+	if err == nil && i.ID == 0 {
+		err = pgx.ErrNoRows
+	}
+
+	return i, err
+}
+
+func createPhrasesBatchIn(ctx context.Context, db dbtx, arg database.CreatePhrasesBatchParams) (database.CreatePhrasesBatchRow, error) {
+	var i database.CreatePhrasesBatchRow
+
+	var batchID int64
+	err := db.QueryRowContext(ctx, createPhraseBatch,
+		sql.Named("name", arg.Name),
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+	).Scan(&batchID)
+	if err != nil {
+		return i, pgError(err)
+	}
+	i.BatchID = pgtype.Int8{Int64: batchID, Valid: true}
+
+	_, err = db.ExecContext(ctx, insertPhrases,
+		sql.Named("batch_id", batchID),
+		sql.Named("phrases", list(arg.Phrases)),
+		sql.Named("languages", list(arg.Languages)),
+	)
+	if err != nil {
+		return i, fmt.Errorf("insert phrases: %w", pgError(err))
+	}
+
+	var id sql.NullInt64
+	if err := db.QueryRowContext(ctx, firstPhrase, sql.Named("batch_id", batchID)).Scan(&id); err != nil {
+		return i, err
+	}
+	if !id.Valid {
+		return i, pgx.ErrNoRows
+	}
+	i.ID = id.Int64
+
+	return i, nil
+}
+
+const listPhraseCountsInSet = `SELECT
+    UNICODE_LOWER(phrases.value) AS value,
+    COUNT(*) AS total,
+    COUNT(DISTINCT phrases.batch_id) AS postings
+FROM phrases
+INNER JOIN phrase_batches AS pb ON phrases.batch_id = pb.id
+WHERE
+    phrases.deleted_at IS NULL
+    AND pb.deleted_at IS NULL
+    AND (
+        JSON_ARRAY_LENGTH(@batches) = 0
+        OR pb.name IN (SELECT value FROM JSON_EACH(@batches))
+    )
+    AND (@created_from IS NULL OR pb.created_at >= @created_from)
+    AND (@created_to IS NULL OR pb.created_at < @created_to)
+    AND (@language IS NULL OR phrases.language = @language)
+    AND (
+        @tag IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_tags.batch_id FROM phrase_batch_tags
+            WHERE phrase_batch_tags.tag = @tag
+        )
+    )
+    AND (
+        @collection IS NULL
+        OR pb.id IN (
+            SELECT phrase_batch_collections.batch_id FROM phrase_batch_collections
+            WHERE phrase_batch_collections.collection = @collection
+        )
+    )
+GROUP BY UNICODE_LOWER(phrases.value)
+ORDER BY UNICODE_LOWER(phrases.value) ASC`
+
+func (s *Store) ListPhraseCountsInSet(ctx context.Context, arg database.ListPhraseCountsInSetParams) ([]database.ListPhraseCountsInSetRow, error) {
+	rows, err := s.db.QueryContext(ctx, listPhraseCountsInSet,
+		sql.Named("batches", list(arg.Batches)),
+		sql.Named("created_from", optionalTime(arg.CreatedFrom)),
+		sql.Named("created_to", optionalTime(arg.CreatedTo)),
+		sql.Named("language", text(arg.Language)),
+		sql.Named("tag", text(arg.Tag)),
+		sql.Named("collection", text(arg.Collection)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListPhraseCountsInSetRow
+	for rows.Next() {
+		var i database.ListPhraseCountsInSetRow
+		if err := rows.Scan(&i.Value, &i.Total, &i.Postings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
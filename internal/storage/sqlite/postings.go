@@ -0,0 +1,324 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createPosting = `INSERT INTO postings (
+    title,
+    company,
+    source_url,
+    captured_at,
+    image_hash,
+    raw_text
+)
+VALUES (
+    @title,
+    @company,
+    @source_url,
+    COALESCE(@captured_at, STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    NULLIF(@image_hash, ''),
+    @raw_text
+)
+RETURNING
+    id,
+    title,
+    company,
+    source_url,
+    captured_at,
+    COALESCE(image_hash, '') AS image_hash,
+    created_at`
+
+func (s *Store) CreatePosting(ctx context.Context, arg database.CreatePostingParams) (database.CreatePostingRow, error) {
+	return createPostingIn(ctx, s.db, arg)
+}
+
+func createPostingIn(ctx context.Context, db dbtx, arg database.CreatePostingParams) (database.CreatePostingRow, error) {
+	var i database.CreatePostingRow
+	err := db.QueryRowContext(ctx, createPosting,
+		sql.Named("title", arg.Title),
+		sql.Named("company", arg.Company),
+		sql.Named("source_url", arg.SourceUrl),
+		sql.Named("captured_at", optionalTime(arg.CapturedAt)),
+		sql.Named("image_hash", arg.ImageHash),
+		sql.Named("raw_text", arg.RawText),
+	).Scan(
+		&i.ID,
+		&i.Title,
+		&i.Company,
+		&i.SourceUrl,
+		timestamp{&i.CapturedAt},
+		&i.ImageHash,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const getPosting = `SELECT
+    postings.id,
+    postings.title,
+    postings.company,
+    postings.source_url,
+    postings.captured_at,
+    COALESCE(postings.image_hash, '') AS image_hash,
+    postings.raw_text,
+    wb.id AS word_batch_id,
+    wb.name AS word_batch_name,
+    pb.id AS phrase_batch_id,
+    pb.name AS phrase_batch_name,
+    postings.created_at,
+    postings.updated_at
+FROM postings
+LEFT JOIN word_batches AS wb
+    ON postings.id = wb.posting_id AND wb.deleted_at IS NULL
+LEFT JOIN phrase_batches AS pb
+    ON postings.id = pb.posting_id AND pb.deleted_at IS NULL
+WHERE postings.id = @id AND postings.deleted_at IS NULL`
+
+func (s *Store) GetPosting(ctx context.Context, id int64) (database.GetPostingRow, error) {
+	var i database.GetPostingRow
+	err := s.db.QueryRowContext(ctx, getPosting, sql.Named("id", id)).Scan(
+		&i.ID,
+		&i.Title,
+		&i.Company,
+		&i.SourceUrl,
+		timestamp{&i.CapturedAt},
+		&i.ImageHash,
+		&i.RawText,
+		&i.WordBatchID,
+		&i.WordBatchName,
+		&i.PhraseBatchID,
+		&i.PhraseBatchName,
+		timestamp{&i.CreatedAt},
+		timestamp{&i.UpdatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const listPostings = `SELECT
+    postings.id,
+    postings.title,
+    postings.company,
+    postings.source_url,
+    postings.captured_at,
+    COALESCE(postings.image_hash, '') AS image_hash,
+    wb.id AS word_batch_id,
+    pb.id AS phrase_batch_id,
+    postings.created_at
+FROM postings
+LEFT JOIN word_batches AS wb
+    ON postings.id = wb.posting_id AND wb.deleted_at IS NULL
+LEFT JOIN phrase_batches AS pb
+    ON postings.id = pb.posting_id AND pb.deleted_at IS NULL
+WHERE
+    postings.deleted_at IS NULL
+    AND (
+        @company IS NULL
+        OR UNICODE_LOWER(postings.company) = UNICODE_LOWER(@company)
+    )
+ORDER BY postings.captured_at DESC, postings.id DESC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListPostings(ctx context.Context, arg database.ListPostingsParams) ([]database.ListPostingsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listPostings,
+		sql.Named("company", text(arg.Company)),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListPostingsRow
+	for rows.Next() {
+		var i database.ListPostingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Company,
+			&i.SourceUrl,
+			timestamp{&i.CapturedAt},
+			&i.ImageHash,
+			&i.WordBatchID,
+			&i.PhraseBatchID,
+			timestamp{&i.CreatedAt},
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const updatePosting = `UPDATE postings
+SET
+    title = COALESCE(@title, title),
+    company = COALESCE(@company, company),
+    source_url = COALESCE(@source_url, source_url),
+    captured_at = COALESCE(@captured_at, captured_at),
+    updated_at = STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')
+WHERE id = @id AND deleted_at IS NULL
+RETURNING
+    id,
+    title,
+    company,
+    source_url,
+    captured_at,
+    COALESCE(image_hash, '') AS image_hash,
+    updated_at`
+
+func (s *Store) UpdatePosting(ctx context.Context, arg database.UpdatePostingParams) (database.UpdatePostingRow, error) {
+	var i database.UpdatePostingRow
+	err := s.db.QueryRowContext(ctx, updatePosting,
+		sql.Named("title", text(arg.Title)),
+		sql.Named("company", text(arg.Company)),
+		sql.Named("source_url", text(arg.SourceUrl)),
+		sql.Named("captured_at", optionalTime(arg.CapturedAt)),
+		sql.Named("id", arg.ID),
+	).Scan(
+		&i.ID,
+		&i.Title,
+		&i.Company,
+		&i.SourceUrl,
+		timestamp{&i.CapturedAt},
+		&i.ImageHash,
+		timestamp{&i.UpdatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+const (
+	deletePosting = `UPDATE postings
+SET deleted_at = @now
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	// Rows of batches are deleted before the batches, which they're selected by.
+	deletePostingBatches = `UPDATE occurrences
+SET deleted_at = @now
+WHERE
+    deleted_at IS NULL
+    AND batch_id IN (
+        SELECT id FROM word_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE salaries
+SET deleted_at = @now
+WHERE
+    deleted_at IS NULL
+    AND batch_id IN (
+        SELECT id FROM word_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE phrases
+SET deleted_at = @now
+WHERE
+    deleted_at IS NULL
+    AND batch_id IN (
+        SELECT id FROM phrase_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE word_batches
+SET deleted_at = @now
+WHERE posting_id = @id AND deleted_at IS NULL;
+
+UPDATE phrase_batches
+SET deleted_at = @now
+WHERE posting_id = @id AND deleted_at IS NULL`
+
+	deletePostingRows = deletePostingBatches + `;
+
+UPDATE ocr_documents
+SET deleted_at = @now
+WHERE posting_id = @id AND deleted_at IS NULL`
+)
+
+// DeletePosting soft deletes a posting with its batches and documents.
+func (s *Store) DeletePosting(ctx context.Context, id int64) (int64, error) {
+	return s.softDelete(ctx, deletePosting, deletePostingRows, id)
+}
+
+// DeletePostingBatches soft deletes batches of a posting with their rows.
+func (s *Store) DeletePostingBatches(ctx context.Context, postingID int64) error {
+	return deletePostingBatchesIn(ctx, s.db, postingID)
+}
+
+func deletePostingBatchesIn(ctx context.Context, db dbtx, postingID int64) error {
+	_, err := db.ExecContext(ctx, deletePostingBatches,
+		sql.Named("id", postingID),
+		sql.Named("now", formatTime(time.Now())),
+	)
+	if err != nil {
+		return fmt.Errorf("delete posting batches: %w", pgError(err))
+	}
+
+	return nil
+}
+
+const (
+	deletedPosting = `SELECT deleted_at
+FROM postings
+WHERE id = @id AND deleted_at IS NOT NULL`
+
+	// Rows of batches are restored after the batches, which they're selected by.
+	restorePosting = `UPDATE postings
+SET deleted_at = NULL
+WHERE id = @id;
+
+UPDATE word_batches
+SET deleted_at = NULL
+WHERE posting_id = @id AND deleted_at = @deleted_at;
+
+UPDATE phrase_batches
+SET deleted_at = NULL
+WHERE posting_id = @id AND deleted_at = @deleted_at;
+
+UPDATE occurrences
+SET deleted_at = NULL
+WHERE
+    deleted_at = @deleted_at
+    AND batch_id IN (
+        SELECT id FROM word_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE salaries
+SET deleted_at = NULL
+WHERE
+    deleted_at = @deleted_at
+    AND batch_id IN (
+        SELECT id FROM word_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE phrases
+SET deleted_at = NULL
+WHERE
+    deleted_at = @deleted_at
+    AND batch_id IN (
+        SELECT id FROM phrase_batches
+        WHERE posting_id = @id AND deleted_at IS NULL
+    );
+
+UPDATE ocr_documents
+SET deleted_at = NULL
+WHERE posting_id = @id AND deleted_at = @deleted_at`
+)
+
+// RestorePosting restores a posting with batches and documents deleted with it.
+func (s *Store) RestorePosting(ctx context.Context, id int64) (int64, error) {
+	return s.restore(ctx, deletedPosting, restorePosting, id)
+}
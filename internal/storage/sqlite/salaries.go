@@ -0,0 +1,226 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createSalary = `INSERT INTO salaries (
+    batch_id,
+    min_amount,
+    max_amount,
+    currency,
+    period,
+    monthly_min,
+    monthly_max,
+    contract,
+    basis,
+    seniority,
+    raw
+)
+VALUES (
+    @batch_id,
+    @min_amount,
+    @max_amount,
+    @currency,
+    @period,
+    @monthly_min,
+    @monthly_max,
+    NULLIF(@contract, ''),
+    NULLIF(@basis, ''),
+    NULLIF(@seniority, ''),
+    @raw
+)
+RETURNING
+    id,
+    batch_id,
+    min_amount,
+    max_amount,
+    currency,
+    period,
+    contract,
+    basis,
+    seniority`
+
+func (s *Store) CreateSalary(ctx context.Context, arg database.CreateSalaryParams) (database.CreateSalaryRow, error) {
+	return createSalaryIn(ctx, s.db, arg)
+}
+
+func createSalaryIn(ctx context.Context, db dbtx, arg database.CreateSalaryParams) (database.CreateSalaryRow, error) {
+	var i database.CreateSalaryRow
+	err := db.QueryRowContext(ctx, createSalary,
+		sql.Named("batch_id", arg.BatchID),
+		sql.Named("min_amount", arg.MinAmount),
+		sql.Named("max_amount", arg.MaxAmount),
+		sql.Named("currency", arg.Currency),
+		sql.Named("period", arg.Period),
+		sql.Named("monthly_min", arg.MonthlyMin),
+		sql.Named("monthly_max", arg.MonthlyMax),
+		sql.Named("contract", arg.Contract),
+		sql.Named("basis", arg.Basis),
+		sql.Named("seniority", arg.Seniority),
+		sql.Named("raw", arg.Raw),
+	).Scan(
+		&i.ID,
+		&i.BatchID,
+		&i.MinAmount,
+		&i.MaxAmount,
+		&i.Currency,
+		&i.Period,
+		&i.Contract,
+		&i.Basis,
+		&i.Seniority,
+	)
+
+	return i, pgError(err)
+}
+
+const listSalariesByBatchName = `SELECT
+    salaries.id,
+    wb.name AS batch_name,
+    salaries.min_amount,
+    salaries.max_amount,
+    salaries.currency,
+    salaries.period,
+    salaries.monthly_min,
+    salaries.monthly_max,
+    COALESCE(salaries.contract, '') AS contract,
+    COALESCE(salaries.basis, '') AS basis,
+    COALESCE(salaries.seniority, '') AS seniority,
+    salaries.raw
+FROM salaries
+INNER JOIN word_batches AS wb ON salaries.batch_id = wb.id
+WHERE
+    wb.name = @name
+    AND wb.deleted_at IS NULL
+    AND salaries.deleted_at IS NULL
+ORDER BY salaries.id ASC`
+
+func (s *Store) ListSalariesByBatchName(ctx context.Context, name string) ([]database.ListSalariesByBatchNameRow, error) {
+	rows, err := s.db.QueryContext(ctx, listSalariesByBatchName, sql.Named("name", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListSalariesByBatchNameRow
+	for rows.Next() {
+		var i database.ListSalariesByBatchNameRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BatchName,
+			&i.MinAmount,
+			&i.MaxAmount,
+			&i.Currency,
+			&i.Period,
+			&i.MonthlyMin,
+			&i.MonthlyMax,
+			&i.Contract,
+			&i.Basis,
+			&i.Seniority,
+			&i.Raw,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listSalaryMediansBySeniority = `SELECT
+    COALESCE(wb.seniority, salaries.seniority, '') AS seniority,
+    salaries.currency,
+    COUNT(*) AS total,
+    MEDIAN((salaries.monthly_min + salaries.monthly_max) / 2) AS median
+FROM salaries
+INNER JOIN word_batches AS wb ON salaries.batch_id = wb.id
+WHERE
+    salaries.deleted_at IS NULL
+    AND wb.deleted_at IS NULL
+    AND (@contract IS NULL OR salaries.contract = @contract)
+GROUP BY COALESCE(wb.seniority, salaries.seniority, ''), salaries.currency
+ORDER BY COALESCE(wb.seniority, salaries.seniority, '') ASC, salaries.currency ASC`
+
+func (s *Store) ListSalaryMediansBySeniority(ctx context.Context, contract pgtype.Text) ([]database.ListSalaryMediansBySeniorityRow, error) {
+	rows, err := s.db.QueryContext(ctx, listSalaryMediansBySeniority, sql.Named("contract", text(contract)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListSalaryMediansBySeniorityRow
+	for rows.Next() {
+		var i database.ListSalaryMediansBySeniorityRow
+		if err := rows.Scan(&i.Seniority, &i.Currency, &i.Total, &i.Median); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// Known skills pair names of @skills with canonical names of @canonical_skills by
+// their positions.
+const listSalaryMediansBySkill = `SELECT
+    batch_words.value AS skill,
+    salaries.currency,
+    COUNT(*) AS total,
+    MEDIAN((salaries.monthly_min + salaries.monthly_max) / 2) AS median
+FROM salaries
+INNER JOIN (
+    SELECT DISTINCT
+        o.batch_id,
+        COALESCE(known.skill, v.raw) AS value
+    FROM occurrences AS o
+    INNER JOIN vocabulary AS v ON o.term_id = v.id
+    LEFT JOIN (
+        SELECT
+            name.value AS name,
+            skill.value AS skill
+        FROM JSON_EACH(@skills) AS name
+        INNER JOIN JSON_EACH(@canonical_skills) AS skill ON name.key = skill.key
+    ) AS known ON v.normalized = known.name
+    WHERE
+        o.deleted_at IS NULL
+        AND (@skills IS NULL OR known.skill IS NOT NULL)
+) AS batch_words ON salaries.batch_id = batch_words.batch_id
+WHERE
+    salaries.deleted_at IS NULL
+    AND (@skill IS NULL OR batch_words.value = @skill)
+    AND (@contract IS NULL OR salaries.contract = @contract)
+    AND UNICODE_LOWER(batch_words.value) NOT IN (SELECT value FROM JSON_EACH(@excluded))
+GROUP BY batch_words.value, salaries.currency
+ORDER BY total DESC, skill ASC, salaries.currency ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListSalaryMediansBySkill(ctx context.Context, arg database.ListSalaryMediansBySkillParams) ([]database.ListSalaryMediansBySkillRow, error) {
+	rows, err := s.db.QueryContext(ctx, listSalaryMediansBySkill,
+		sql.Named("skills", optionalList(arg.Skills)),
+		sql.Named("canonical_skills", list(arg.CanonicalSkills)),
+		sql.Named("skill", text(arg.Skill)),
+		sql.Named("contract", text(arg.Contract)),
+		sql.Named("excluded", list(arg.Excluded)),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListSalaryMediansBySkillRow
+	for rows.Next() {
+		var i database.ListSalaryMediansBySkillRow
+		if err := rows.Scan(&i.Skill, &i.Currency, &i.Total, &i.Median); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
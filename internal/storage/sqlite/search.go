@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/textsearch"
+)
+
+const searchWords = `SELECT
+    v.raw AS value,
+    v.language,
+    s.total,
+    SIMILARITY(v.normalized, UNICODE_LOWER(@query)) AS similarity
+FROM vocabulary AS v
+INNER JOIN term_stats AS s ON v.id = s.term_id
+WHERE
+    s.total > 0
+    AND (@language IS NULL OR v.language = @language)
+    AND SIMILARITY(v.normalized, UNICODE_LOWER(@query)) >= @threshold
+ORDER BY similarity DESC, s.total DESC, v.raw ASC
+LIMIT @limit OFFSET @offset`
+
+// SearchWords lists words similar to a query, most similar first.
+func (s *Store) SearchWords(ctx context.Context, arg database.SearchWordsParams) ([]database.SearchWordsRow, error) {
+	rows, err := s.db.QueryContext(ctx, searchWords,
+		sql.Named("query", arg.Query),
+		sql.Named("language", text(arg.Language)),
+		sql.Named("threshold", textsearch.SimilarityThreshold),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.SearchWordsRow
+	for rows.Next() {
+		var i database.SearchWordsRow
+		if err := rows.Scan(&i.Value, &i.Language, &i.Total, &i.Similarity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const searchPhrases = `SELECT
+    p.id,
+    p.value,
+    COALESCE(p.language, '') AS language,
+    COALESCE(pb.name, '') AS batch_name,
+    TEXT_RANK(p.value, @query) AS rank,
+    SIMILARITY(p.value, @query) AS similarity,
+    TEXT_HEADLINE(p.value, @query) AS snippet
+FROM phrases AS p
+LEFT JOIN phrase_batches AS pb ON p.batch_id = pb.id
+WHERE
+    p.deleted_at IS NULL
+    AND pb.deleted_at IS NULL
+    AND (@language IS NULL OR p.language = @language)
+    AND (
+        TEXT_RANK(p.value, @query) > 0
+        OR SIMILARITY(p.value, @query) >= @threshold
+    )
+ORDER BY rank DESC, similarity DESC, p.id ASC
+LIMIT @limit OFFSET @offset`
+
+// SearchPhrases lists phrases matching words of a query or similar to it, best
+// matches first. Snippets mark matched words with <mark>.
+func (s *Store) SearchPhrases(ctx context.Context, arg database.SearchPhrasesParams) ([]database.SearchPhrasesRow, error) {
+	rows, err := s.db.QueryContext(ctx, searchPhrases,
+		sql.Named("query", arg.Query),
+		sql.Named("language", text(arg.Language)),
+		sql.Named("threshold", textsearch.SimilarityThreshold),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.SearchPhrasesRow
+	for rows.Next() {
+		var i database.SearchPhrasesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Value,
+			&i.Language,
+			&i.BatchName,
+			&i.Rank,
+			&i.Similarity,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
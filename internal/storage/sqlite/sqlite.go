@@ -0,0 +1,337 @@
+// Package sqlite is a storage in a local SQLite file, for use of CLI without a Postgres
+// server. Queries match semantics of their Postgres counterparts, including soft deletes
+// and constraint errors, which are returned as *pgconn.PgError of the same codes.
+//
+// The schema copies the Postgres schema with its own migrations. Summaries of
+// occurrences are views, so RefreshWordStats does nothing. Trigram similarity and full
+// text search are approximated like described in package textsearch, and medians are
+// computed with MEDIAN aggregate registered by this package. Changes of the Postgres
+// schema must be mirrored in migrations of this package; the storagetest suites run
+// against both storages keep them in step.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite" // sqlite migrate driver
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/textsearch"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// migrations holds SQLite schema migrations.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// timeLayout is a layout of times stored by STRFTIME('%Y-%m-%dT%H:%M:%fZ').
+const timeLayout = "2006-01-02T15:04:05.000Z"
+
+func init() {
+	// LOWER of SQLite folds ASCII letters only, unlike LOWER of Postgres.
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			s, ok := args[0].(string)
+			if !ok {
+				return args[0], nil
+			}
+
+			return strings.ToLower(s), nil
+		},
+	)
+	// SIMILARITY, % operator and full text search of Postgres, see package textsearch.
+	sqlite.MustRegisterDeterministicScalarFunction("similarity", 2,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			a, _ := args[0].(string)
+			b, _ := args[1].(string)
+
+			return float64(textsearch.Similarity(a, b)), nil
+		},
+	)
+	sqlite.MustRegisterDeterministicScalarFunction("text_rank", 2,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			value, _ := args[0].(string)
+			query, _ := args[1].(string)
+
+			return float64(textsearch.Rank(textsearch.Words(value), textsearch.Words(query))), nil
+		},
+	)
+	sqlite.MustRegisterDeterministicScalarFunction("text_headline", 2,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			value, _ := args[0].(string)
+			query, _ := args[1].(string)
+
+			return textsearch.Headline(value, textsearch.Words(query)), nil
+		},
+	)
+	// PERCENTILE_CONT(0.5) of Postgres.
+	sqlite.MustRegisterFunction("median", &sqlite.FunctionImpl{
+		NArgs:         1,
+		Deterministic: true,
+		MakeAggregate: func(ctx sqlite.FunctionContext) (sqlite.AggregateFunction, error) {
+			return &median{}, nil
+		},
+	})
+}
+
+// median is an aggregate of the median of its values, interpolated between the two
+// middle values of an even number of them. Values which aren't numbers are skipped.
+type median struct {
+	values []float64
+}
+
+func (m *median) Step(ctx *sqlite.FunctionContext, args []driver.Value) error {
+	switch v := args[0].(type) {
+	case int64:
+		m.values = append(m.values, float64(v))
+	case float64:
+		m.values = append(m.values, v)
+	}
+
+	return nil
+}
+
+var errMedianWindow = errors.New("median isn't a window function")
+
+func (m *median) WindowInverse(ctx *sqlite.FunctionContext, args []driver.Value) error {
+	return errMedianWindow
+}
+
+func (m *median) WindowValue(ctx *sqlite.FunctionContext) (driver.Value, error) {
+	if len(m.values) == 0 {
+		return nil, nil
+	}
+	slices.Sort(m.values)
+	mid := len(m.values) / 2
+	if len(m.values)%2 == 1 {
+		return m.values[mid], nil
+	}
+
+	return (m.values[mid-1] + m.values[mid]) / 2, nil
+}
+
+func (m *median) Final(ctx *sqlite.FunctionContext) {}
+
+// Store is a database.Store in a SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+var _ database.Store = (*Store)(nil)
+
+// Open opens SQLite database at path, creating it if it doesn't exist, and applies
+// pending migrations. Store must be closed once no longer used.
+func Open(ctx context.Context, path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create database dir: %w", err)
+	}
+	if err := migrate(path); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"}}
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	// Writes of SQLite are serialized anyway, a single connection avoids busy errors.
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func migrate(path string) error {
+	src, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("migrations dir: %w", err)
+	}
+	m, err := database.NewSourceMigrator(src, "sqlite://"+path)
+	if err != nil {
+		return fmt.Errorf("new migrator: %w", err)
+	}
+	defer m.Close()
+
+	return m.Up()
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// inTx runs fn in a transaction, which is committed if fn returns nil and rolled back otherwise.
+func (s *Store) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback transaction: %w", rbErr))
+		}
+
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// text returns t as a query argument, nil if it's null.
+func text(t pgtype.Text) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return t.String
+}
+
+// nullInt8 returns id as a query argument, nil if it's null.
+func nullInt8(id pgtype.Int8) any {
+	if !id.Valid {
+		return nil
+	}
+
+	return id.Int64
+}
+
+// list returns values as a JSON array argument read with JSON_EACH.
+func list(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	data, _ := json.Marshal(values)
+
+	return string(data)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (pgtype.Timestamptz, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return pgtype.Timestamptz{}, fmt.Errorf("parse time: %w", err)
+	}
+
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// dbtx runs queries either on the database or in a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Codes of Postgres errors of SQLite constraint errors.
+var constraintCodes = map[int]string{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     "23505",
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: "23505",
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: "23503",
+	sqlite3.SQLITE_CONSTRAINT_CHECK:      "23514",
+	sqlite3.SQLITE_CONSTRAINT_NOTNULL:    "23502",
+}
+
+// pgError returns errors callers of Postgres queries expect: pgx.ErrNoRows for
+// sql.ErrNoRows and *pgconn.PgError of the same code for constraint errors.
+func pgError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		if code, ok := constraintCodes[sqliteErr.Code()]; ok {
+			return &pgconn.PgError{Severity: "ERROR", Code: code, Message: sqliteErr.Error()}
+		}
+	}
+
+	return err
+}
+
+// timestamp scans text of a time into t, which is null if the text is.
+type timestamp struct {
+	t *pgtype.Timestamptz
+}
+
+func (s timestamp) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*s.t = pgtype.Timestamptz{}
+
+		return nil
+	case string:
+		t, err := parseTime(v)
+		*s.t = t
+
+		return err
+	default:
+		return fmt.Errorf("scan time: unexpected %T", src)
+	}
+}
+
+// jsonList scans a JSON array of strings into l.
+type jsonList struct {
+	l *[]string
+}
+
+func (s jsonList) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("scan list: unexpected %T", src)
+	}
+	if err := json.Unmarshal(data, s.l); err != nil {
+		return fmt.Errorf("unmarshal list: %w", err)
+	}
+
+	return nil
+}
+
+// optionalTime returns t as a query argument, nil if it's null.
+func optionalTime(t pgtype.Timestamptz) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return formatTime(t.Time)
+}
+
+// optionalList returns values as a JSON array argument, nil if values are nil like
+// a null array of Postgres.
+func optionalList(values []string) any {
+	if values == nil {
+		return nil
+	}
+
+	return list(values)
+}
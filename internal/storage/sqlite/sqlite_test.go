@@ -0,0 +1,129 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/internal/storage/sqlite"
+	"github.com/kndrad/piccrack/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func open(t *testing.T) *sqlite.Store {
+	t.Helper()
+
+	s, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "data", "piccrack.db"))
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		t.Helper()
+
+		return open(t)
+	})
+}
+
+func TestStoreConformance(t *testing.T) {
+	t.Parallel()
+
+	storagetest.RunStore(t, func(t *testing.T) database.Store {
+		t.Helper()
+
+		s := open(t)
+		t.Cleanup(func() { s.Close() })
+
+		return s
+	})
+}
+
+func TestOpenMigrated(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "piccrack.db")
+	s, err := sqlite.Open(context.Background(), path)
+	require.NoError(t, err)
+	_, err = s.CreateWord(context.Background(), database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// Reopened file keeps words
+	s, err = sqlite.Open(context.Background(), path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	rows, err := s.ListWords(context.Background(), database.ListWordsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
+
+func TestIngestPostingWithPhrases(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := open(t)
+	defer s.Close()
+
+	res, err := s.Ingest(ctx, database.Ingestion{
+		Posting: &database.CreatePostingParams{Title: "Go developer", Company: "Acme"},
+		Words:   []database.IngestedWord{{Value: "go"}},
+		Phrases: []database.IngestedPhrase{{Value: "Go developer"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.Phrases)
+
+	posting, err := s.GetPosting(ctx, res.PostingID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, database.PostingBatchName(res.PostingID.Int64), posting.WordBatchName.String)
+	require.Equal(t, res.PhraseBatchID, posting.PhraseBatchID)
+}
+
+func TestCreateWordCountsOccurrencesPerDay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "piccrack.db")
+	s, err := sqlite.Open(ctx, path)
+	require.NoError(t, err)
+	_, err = s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	_, err = s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// Occurrence of words added two days ago
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE occurrences
+SET created_at = STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now', '-2 days')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err = sqlite.Open(ctx, path)
+	require.NoError(t, err)
+	defer s.Close()
+	_, err = s.CreateWord(ctx, database.CreateWordParams{Value: "go"})
+	require.NoError(t, err)
+	_, err = s.Ingest(ctx, database.Ingestion{Words: []database.IngestedWord{{Value: "go"}}})
+	require.NoError(t, err)
+
+	rows, err := s.ListWordTrend(ctx, database.ListWordTrendParams{
+		Bucket: "day",
+		Since:  pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, -3), Valid: true},
+		Words:  []string{"go"},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, int64(2), rows[0].Total)
+	require.Equal(t, int64(2), rows[1].Total)
+}
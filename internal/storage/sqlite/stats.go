@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const listWordStatsFrequencies = `SELECT
+    v.raw AS value,
+    SUM(s.total) AS total
+FROM term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+WHERE
+    s.total > 0` + termFilters + `
+GROUP BY v.raw
+ORDER BY total ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListWordStatsFrequencies(ctx context.Context, arg database.ListWordStatsFrequenciesParams) ([]database.ListWordStatsFrequenciesRow, error) {
+	rows, err := s.db.QueryContext(ctx, listWordStatsFrequencies,
+		sql.Named("language", text(arg.Language)),
+		sql.Named("excluded", list(arg.Excluded)),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordStatsFrequenciesRow
+	for rows.Next() {
+		var i database.ListWordStatsFrequenciesRow
+		if err := rows.Scan(&i.Value, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listWordStatsRankings = `SELECT
+    v.raw AS value,
+    ROW_NUMBER() OVER (ORDER BY SUM(s.total) DESC) AS ranking
+FROM term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+WHERE
+    s.total > 0` + termFilters + `
+GROUP BY v.raw
+ORDER BY ranking ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListWordStatsRankings(ctx context.Context, arg database.ListWordStatsRankingsParams) ([]database.ListWordStatsRankingsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listWordStatsRankings,
+		sql.Named("language", text(arg.Language)),
+		sql.Named("excluded", list(arg.Excluded)),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordStatsRankingsRow
+	for rows.Next() {
+		var i database.ListWordStatsRankingsRow
+		if err := rows.Scan(&i.Value, &i.Ranking); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// Days of daily_term_stats are dates, which bucket truncates like times.
+var listWordStatsTrend = `WITH bucket_postings AS (
+    SELECT
+        ` + bucket("wb.created_at") + ` AS bucket,
+        COUNT(*) AS postings
+    FROM word_batches AS wb
+    WHERE
+        wb.deleted_at IS NULL
+        AND wb.created_at >= @since
+    GROUP BY 1
+)
+
+SELECT
+    ` + bucket("s.day") + ` AS bucket,
+    v.normalized AS value,
+    SUM(s.total) AS total,
+    SUM(s.postings) AS postings,
+    COALESCE(MAX(bp.postings), 0) AS bucket_postings
+FROM daily_term_stats AS s
+INNER JOIN vocabulary AS v ON s.term_id = v.id
+LEFT JOIN bucket_postings AS bp ON ` + bucket("s.day") + ` = bp.bucket
+WHERE
+    s.total > 0
+    AND s.day >= SUBSTR(@since, 1, 10)
+    AND (
+        JSON_ARRAY_LENGTH(@words) = 0
+        OR v.normalized IN (SELECT value FROM JSON_EACH(@words))
+    )` + termFilters + `
+GROUP BY 1, v.normalized
+ORDER BY 1 ASC, v.normalized ASC`
+
+func (s *Store) ListWordStatsTrend(ctx context.Context, arg database.ListWordStatsTrendParams) ([]database.ListWordStatsTrendRow, error) {
+	switch arg.Bucket {
+	case "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBucket, arg.Bucket)
+	}
+
+	rows, err := s.db.QueryContext(ctx, listWordStatsTrend,
+		sql.Named("bucket", arg.Bucket),
+		sql.Named("since", formatTime(arg.Since.Time)),
+		sql.Named("words", list(arg.Words)),
+		sql.Named("language", text(arg.Language)),
+		sql.Named("excluded", list(arg.Excluded)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordStatsTrendRow
+	for rows.Next() {
+		var i database.ListWordStatsTrendRow
+		if err := rows.Scan(timestamp{&i.Bucket}, &i.Value, &i.Total, &i.Postings, &i.BucketPostings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// RefreshWordStats does nothing, summaries of occurrences are views which are always
+// current.
+func (s *Store) RefreshWordStats(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+const createTag = `INSERT INTO tags (name)
+VALUES (@name)
+RETURNING id`
+
+func (s *Store) CreateTag(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, createTag, sql.Named("name", name)).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const deleteTag = `DELETE FROM tags
+WHERE name = @name
+RETURNING id`
+
+func (s *Store) DeleteTag(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, deleteTag, sql.Named("name", name)).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const (
+	tagsQuery = `SELECT
+    g.id,
+    g.name,
+    '' AS description,
+    COUNT(m.posting_id) AS postings,
+    COUNT(m.word_batch_id) AS word_batches,
+    COUNT(m.phrase_batch_id) AS phrase_batches,
+    g.created_at
+FROM tags AS g
+LEFT JOIN tag_members AS m ON g.id = m.tag_id`
+
+	getTag = tagsQuery + `
+WHERE g.name = @name
+GROUP BY g.id`
+
+	listTags = tagsQuery + `
+GROUP BY g.id
+ORDER BY g.name ASC
+LIMIT @limit OFFSET @offset`
+)
+
+func (s *Store) GetTag(ctx context.Context, name string) (database.GetTagRow, error) {
+	var i database.GetTagRow
+	err := s.db.QueryRowContext(ctx, getTag, sql.Named("name", name)).Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Postings,
+		&i.WordBatches,
+		&i.PhraseBatches,
+		timestamp{&i.CreatedAt},
+	)
+
+	return i, pgError(err)
+}
+
+func (s *Store) ListTags(ctx context.Context, arg database.ListTagsParams) ([]database.ListTagsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listTags,
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListTagsRow
+	for rows.Next() {
+		var i database.ListTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Postings,
+			&i.WordBatches,
+			&i.PhraseBatches,
+			timestamp{&i.CreatedAt},
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const (
+	ensureTag = `INSERT INTO tags (name)
+VALUES (@name)
+ON CONFLICT (name) DO NOTHING`
+
+	// The SELECT has a WHERE clause, which SQLite requires of an upsert selecting rows.
+	addTagMember = `INSERT INTO tag_members (tag_id, posting_id, word_batch_id, phrase_batch_id)
+SELECT
+    tags.id,
+    @posting_id,
+    @word_batch_id,
+    @phrase_batch_id
+FROM tags
+WHERE tags.name = @name
+ON CONFLICT (
+    tag_id,
+    IFNULL(posting_id, 0),
+    IFNULL(word_batch_id, 0),
+    IFNULL(phrase_batch_id, 0)
+) DO UPDATE
+SET created_at = tag_members.created_at
+RETURNING tag_id`
+)
+
+// AddTagMember adds a member of a tag, creating the tag on first use. Adding a member
+// again is a no-op.
+func (s *Store) AddTagMember(ctx context.Context, arg database.AddTagMemberParams) (int64, error) {
+	var id int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, ensureTag, sql.Named("name", arg.Name)); err != nil {
+			return pgError(err)
+		}
+
+		return pgError(tx.QueryRowContext(ctx, addTagMember,
+			sql.Named("posting_id", nullInt8(arg.PostingID)),
+			sql.Named("word_batch_id", nullInt8(arg.WordBatchID)),
+			sql.Named("phrase_batch_id", nullInt8(arg.PhraseBatchID)),
+			sql.Named("name", arg.Name),
+		).Scan(&id))
+	})
+
+	return id, err
+}
+
+const removeTagMember = `DELETE FROM tag_members
+WHERE
+    tag_id IN (SELECT id FROM tags WHERE name = @name)
+    AND posting_id IS @posting_id
+    AND word_batch_id IS @word_batch_id
+    AND phrase_batch_id IS @phrase_batch_id
+RETURNING tag_id`
+
+func (s *Store) RemoveTagMember(ctx context.Context, arg database.RemoveTagMemberParams) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, removeTagMember,
+		sql.Named("name", arg.Name),
+		sql.Named("posting_id", nullInt8(arg.PostingID)),
+		sql.Named("word_batch_id", nullInt8(arg.WordBatchID)),
+		sql.Named("phrase_batch_id", nullInt8(arg.PhraseBatchID)),
+	).Scan(&id)
+
+	return id, pgError(err)
+}
+
+const listTagMembers = `SELECT
+    CASE
+        WHEN m.posting_id IS NOT NULL THEN 'posting'
+        WHEN m.word_batch_id IS NOT NULL THEN 'words'
+        ELSE 'phrases'
+    END AS type,
+    COALESCE(m.posting_id, m.word_batch_id, m.phrase_batch_id) AS id,
+    COALESCE(postings.title, word_batches.name, phrase_batches.name, '') AS name,
+    m.created_at
+FROM tag_members AS m
+INNER JOIN tags AS g ON m.tag_id = g.id
+LEFT JOIN postings ON m.posting_id = postings.id
+LEFT JOIN word_batches ON m.word_batch_id = word_batches.id
+LEFT JOIN phrase_batches ON m.phrase_batch_id = phrase_batches.id
+WHERE
+    g.name = @name
+    AND postings.deleted_at IS NULL
+    AND word_batches.deleted_at IS NULL
+    AND phrase_batches.deleted_at IS NULL
+ORDER BY m.created_at ASC`
+
+// ListTagMembers lists postings, word batches (words) and phrase batches (phrases) of a
+// tag which aren't deleted.
+func (s *Store) ListTagMembers(ctx context.Context, name string) ([]database.ListTagMembersRow, error) {
+	rows, err := s.db.QueryContext(ctx, listTagMembers, sql.Named("name", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListTagMembersRow
+	for rows.Next() {
+		var i database.ListTagMembersRow
+		if err := rows.Scan(&i.Type, &i.ID, &i.Name, timestamp{&i.CreatedAt}); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
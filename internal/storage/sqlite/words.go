@@ -0,0 +1,603 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+)
+
+// Filters of word queries, occurrences o are joined with vocabulary v and word batches wb.
+const (
+	termFilters = `
+    AND (@language IS NULL OR v.language = @language)
+    AND v.normalized NOT IN (SELECT value FROM JSON_EACH(@excluded))`
+
+	batchFilters = `
+    AND (@seniority IS NULL OR wb.seniority = @seniority)
+    AND (
+        @location IS NULL
+        OR UNICODE_LOWER(wb.location) = UNICODE_LOWER(@location)
+    )
+    AND (@work_mode IS NULL OR wb.work_mode = @work_mode)
+    AND (
+        @tag IS NULL
+        OR wb.id IN (
+            SELECT word_batch_tags.batch_id FROM word_batch_tags
+            WHERE word_batch_tags.tag = @tag
+        )
+    )
+    AND (
+        @collection IS NULL
+        OR wb.id IN (
+            SELECT word_batch_collections.batch_id FROM word_batch_collections
+            WHERE word_batch_collections.collection = @collection
+        )
+    )`
+)
+
+// filterArgs returns named arguments of termFilters and batchFilters.
+func filterArgs(language, seniority, location, workMode, tag, collection pgtype.Text, excluded []string) []any {
+	return []any{
+		sql.Named("language", text(language)),
+		sql.Named("excluded", list(excluded)),
+		sql.Named("seniority", text(seniority)),
+		sql.Named("location", text(location)),
+		sql.Named("work_mode", text(workMode)),
+		sql.Named("tag", text(tag)),
+		sql.Named("collection", text(collection)),
+	}
+}
+
+const upsertTerm = `INSERT INTO vocabulary (raw, normalized, lemma, language)
+VALUES (@value, @normalized, @lemma, @language)
+ON CONFLICT (raw, language) DO UPDATE
+SET
+    lemma = COALESCE(NULLIF(vocabulary.lemma, ''), excluded.lemma),
+    deleted_at = NULL
+RETURNING id, raw, lemma, language, created_at`
+
+// Occurrences of a batch are unique by term, occurrences without a batch by term and
+// day, like in Postgres.
+const (
+	insertOccurrence = `INSERT INTO occurrences (term_id, batch_id, count)
+VALUES (@term_id, @batch_id, @count)
+ON CONFLICT `
+	updateOccurrence = ` DO UPDATE
+SET
+    count = CASE
+        WHEN occurrences.deleted_at IS NULL
+            THEN occurrences.count + excluded.count
+        ELSE excluded.count
+    END,
+    deleted_at = NULL`
+
+	upsertBatchOccurrence = insertOccurrence + `(term_id, batch_id) WHERE batch_id IS NOT NULL` + updateOccurrence
+	upsertDayOccurrence   = insertOccurrence + `(term_id, SUBSTR(created_at, 1, 10)) WHERE batch_id IS NULL` + updateOccurrence
+)
+
+// CreateWord adds a word without a batch, counting it again if it exists.
+func (s *Store) CreateWord(ctx context.Context, arg database.CreateWordParams) (database.CreateWordRow, error) {
+	var row database.CreateWordRow
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var createdAt string
+		err := tx.QueryRowContext(ctx, upsertTerm,
+			sql.Named("value", arg.Value),
+			sql.Named("normalized", strings.ToLower(arg.Value)),
+			sql.Named("lemma", arg.Lemma),
+			sql.Named("language", arg.Language),
+		).Scan(&row.ID, &row.Value, &row.Lemma, &row.Language, &createdAt)
+		if err != nil {
+			return fmt.Errorf("upsert term: %w", err)
+		}
+		if row.CreatedAt, err = parseTime(createdAt); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, upsertDayOccurrence,
+			sql.Named("term_id", row.ID),
+			sql.Named("batch_id", nil),
+			sql.Named("count", 1),
+		)
+		if err != nil {
+			return fmt.Errorf("upsert occurrence: %w", err)
+		}
+
+		return nil
+	})
+
+	return row, err
+}
+
+const listWords = `SELECT
+    id,
+    raw AS value,
+    created_at
+FROM vocabulary
+WHERE deleted_at IS NULL
+ORDER BY raw ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListWords(ctx context.Context, arg database.ListWordsParams) ([]database.ListWordsRow, error) {
+	rows, err := s.db.QueryContext(ctx, listWords,
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordsRow
+	for rows.Next() {
+		var (
+			i         database.ListWordsRow
+			createdAt string
+		)
+		if err := rows.Scan(&i.ID, &i.Value, &createdAt); err != nil {
+			return nil, err
+		}
+		if i.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listWordFrequencies = `SELECT
+    v.raw AS value,
+    SUM(o.count) AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL` + termFilters + batchFilters + `
+GROUP BY v.raw
+ORDER BY total ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListWordFrequencies(ctx context.Context, arg database.ListWordFrequenciesParams) ([]database.ListWordFrequenciesRow, error) {
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	rows, err := s.db.QueryContext(ctx, listWordFrequencies, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordFrequenciesRow
+	for rows.Next() {
+		var i database.ListWordFrequenciesRow
+		if err := rows.Scan(&i.Value, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listLemmaFrequencies = `SELECT
+    COALESCE(NULLIF(v.lemma, ''), v.raw) AS lemma,
+    SUM(o.count) AS total
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL` + termFilters + batchFilters + `
+GROUP BY COALESCE(NULLIF(v.lemma, ''), v.raw)
+ORDER BY total ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListLemmaFrequencies(ctx context.Context, arg database.ListLemmaFrequenciesParams) ([]database.ListLemmaFrequenciesRow, error) {
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	rows, err := s.db.QueryContext(ctx, listLemmaFrequencies, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListLemmaFrequenciesRow
+	for rows.Next() {
+		var i database.ListLemmaFrequenciesRow
+		if err := rows.Scan(&i.Lemma, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listWordRankings = `SELECT
+    v.raw AS value,
+    ROW_NUMBER() OVER (ORDER BY SUM(o.count) DESC) AS ranking
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL` + termFilters + batchFilters + `
+GROUP BY v.raw
+ORDER BY ranking ASC
+LIMIT @limit OFFSET @offset`
+
+func (s *Store) ListWordRankings(ctx context.Context, arg database.ListWordRankingsParams) ([]database.ListWordRankingsRow, error) {
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+	rows, err := s.db.QueryContext(ctx, listWordRankings, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordRankingsRow
+	for rows.Next() {
+		var i database.ListWordRankingsRow
+		if err := rows.Scan(&i.Value, &i.Ranking); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const listBatchWordSets = `SELECT
+    wb.id AS batch_id,
+    JSON_GROUP_ARRAY(DISTINCT v.normalized) AS words
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL` + termFilters + batchFilters + `
+    AND (
+        @skills IS NULL
+        OR v.normalized IN (SELECT value FROM JSON_EACH(@skills))
+    )
+GROUP BY wb.id
+ORDER BY wb.id ASC`
+
+// ListBatchWordSets returns distinct words of batches sorted like ARRAY_AGG(DISTINCT) of Postgres.
+func (s *Store) ListBatchWordSets(ctx context.Context, arg database.ListBatchWordSetsParams) ([]database.ListBatchWordSetsRow, error) {
+	var skills any
+	if arg.Skills != nil {
+		skills = list(arg.Skills)
+	}
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("skills", skills),
+	)
+	rows, err := s.db.QueryContext(ctx, listBatchWordSets, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListBatchWordSetsRow
+	for rows.Next() {
+		var (
+			i     database.ListBatchWordSetsRow
+			words string
+		)
+		if err := rows.Scan(&i.BatchID, &words); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(words), &i.Words); err != nil {
+			return nil, fmt.Errorf("unmarshal words: %w", err)
+		}
+		slices.Sort(i.Words)
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// bucket truncates time column to start of @bucket in UTC, weeks start on Monday
+// like with DATE_TRUNC of Postgres.
+func bucket(column string) string {
+	return `CASE @bucket
+        WHEN 'day' THEN STRFTIME('%Y-%m-%dT00:00:00.000Z', ` + column + `)
+        WHEN 'week' THEN STRFTIME('%Y-%m-%dT00:00:00.000Z', ` + column + `, 'weekday 0', '-6 days')
+        WHEN 'month' THEN STRFTIME('%Y-%m-01T00:00:00.000Z', ` + column + `)
+    END`
+}
+
+var listWordTrend = `WITH bucket_postings AS (
+    SELECT
+        ` + bucket("wb.created_at") + ` AS bucket,
+        COUNT(*) AS postings
+    FROM word_batches AS wb
+    WHERE
+        wb.deleted_at IS NULL
+        AND wb.created_at >= @since` + batchFilters + `
+    GROUP BY 1
+),
+
+filtered AS (
+    SELECT
+        ` + bucket("o.created_at") + ` AS bucket,
+        v.normalized,
+        o.batch_id,
+        o.count
+    FROM occurrences AS o
+    INNER JOIN vocabulary AS v ON o.term_id = v.id
+    LEFT JOIN word_batches AS wb ON o.batch_id = wb.id
+    WHERE
+        o.deleted_at IS NULL
+        AND o.created_at >= @since
+        AND (
+            JSON_ARRAY_LENGTH(@words) = 0
+            OR v.normalized IN (SELECT value FROM JSON_EACH(@words))
+        )` + termFilters + batchFilters + `
+)
+
+SELECT
+    f.bucket,
+    f.normalized AS value,
+    SUM(f.count) AS total,
+    COUNT(DISTINCT f.batch_id) AS postings,
+    COALESCE(MAX(bp.postings), 0) AS bucket_postings
+FROM filtered AS f
+LEFT JOIN bucket_postings AS bp ON f.bucket = bp.bucket
+GROUP BY f.bucket, f.normalized
+ORDER BY f.bucket ASC, f.normalized ASC`
+
+var ErrUnknownBucket = errors.New("unknown bucket")
+
+func (s *Store) ListWordTrend(ctx context.Context, arg database.ListWordTrendParams) ([]database.ListWordTrendRow, error) {
+	switch arg.Bucket {
+	case "day", "week", "month":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBucket, arg.Bucket)
+	}
+
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("bucket", arg.Bucket),
+		sql.Named("since", formatTime(arg.Since.Time)),
+		sql.Named("words", list(arg.Words)),
+	)
+	rows, err := s.db.QueryContext(ctx, listWordTrend, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordTrendRow
+	for rows.Next() {
+		var (
+			i      database.ListWordTrendRow
+			bucket string
+		)
+		if err := rows.Scan(&bucket, &i.Value, &i.Total, &i.Postings, &i.BucketPostings); err != nil {
+			return nil, err
+		}
+		if i.Bucket, err = parseTime(bucket); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const updateWordBatchAttributes = `UPDATE word_batches
+SET
+    seniority = CASE
+        WHEN @seniority IS NULL THEN seniority
+        ELSE NULLIF(@seniority, '')
+    END,
+    location = CASE
+        WHEN @location IS NULL THEN location
+        ELSE NULLIF(@location, '')
+    END,
+    work_mode = CASE
+        WHEN @work_mode IS NULL THEN work_mode
+        ELSE NULLIF(@work_mode, '')
+    END
+WHERE name = @name AND deleted_at IS NULL
+RETURNING
+    id,
+    name,
+    COALESCE(seniority, '') AS seniority,
+    COALESCE(location, '') AS location,
+    COALESCE(work_mode, '') AS work_mode`
+
+func (s *Store) UpdateWordBatchAttributes(ctx context.Context, arg database.UpdateWordBatchAttributesParams) (database.UpdateWordBatchAttributesRow, error) {
+	var i database.UpdateWordBatchAttributesRow
+	err := s.db.QueryRowContext(ctx, updateWordBatchAttributes,
+		sql.Named("seniority", text(arg.Seniority)),
+		sql.Named("location", text(arg.Location)),
+		sql.Named("work_mode", text(arg.WorkMode)),
+		sql.Named("name", arg.Name),
+	).Scan(&i.ID, &i.Name, &i.Seniority, &i.Location, &i.WorkMode)
+
+	return i, pgError(err)
+}
+
+// CreateWordsBatch adds a batch with its words and returns the occurrence of its first
+// term. Like in Postgres the batch is kept without words, though pgx.ErrNoRows is
+// returned then.
+func (s *Store) CreateWordsBatch(ctx context.Context, arg database.CreateWordsBatchParams) (database.CreateWordsBatchRow, error) {
+	words := make([]database.IngestedWord, 0, len(arg.Words))
+	for i, value := range arg.Words {
+		w := database.IngestedWord{Value: value}
+		if i < len(arg.Lemmas) {
+			w.Lemma = arg.Lemmas[i]
+		}
+		if i < len(arg.Languages) {
+			w.Language = arg.Languages[i]
+		}
+		words = append(words, w)
+	}
+	terms := countTerms(words)
+	slices.SortFunc(terms, func(a, b *ingestedTerm) int {
+		if c := strings.Compare(a.Value, b.Value); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Language, b.Language)
+	})
+
+	var row database.CreateWordsBatchRow
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		id, err := createWordBatchIn(ctx, tx, database.CreateWordBatchParams{
+			Name:      arg.Name,
+			Seniority: arg.Seniority,
+			Location:  arg.Location,
+			WorkMode:  arg.WorkMode,
+			PostingID: arg.PostingID,
+		})
+		if err != nil {
+			return err
+		}
+		batchID := pgtype.Int8{Int64: id, Valid: true}
+
+		for i, t := range terms {
+			var termID int64
+			err := tx.QueryRowContext(ctx, upsertTerm,
+				sql.Named("value", t.Value),
+				sql.Named("normalized", strings.ToLower(t.Value)),
+				sql.Named("lemma", t.Lemma),
+				sql.Named("language", t.Language),
+			).Scan(&termID, new(string), new(string), new(string), new(string))
+			if err != nil {
+				return fmt.Errorf("upsert term: %w", pgError(err))
+			}
+			_, err = tx.ExecContext(ctx, upsertBatchOccurrence,
+				sql.Named("term_id", termID),
+				sql.Named("batch_id", id),
+				sql.Named("count", t.count),
+			)
+			if err != nil {
+				return fmt.Errorf("upsert occurrence: %w", pgError(err))
+			}
+			if i == 0 {
+				row = database.CreateWordsBatchRow{TermID: termID, BatchID: batchID}
+			}
+		}
+
+		return nil
+	})
+	if err == nil && len(terms) == 0 {
+		err = pgx.ErrNoRows
+	}
+
+	return row, err
+}
+
+const (
+	deleteWord = `UPDATE vocabulary
+SET deleted_at = @now
+WHERE id = @id AND deleted_at IS NULL
+RETURNING id`
+
+	deleteWordRows = `UPDATE occurrences
+SET deleted_at = @now
+WHERE term_id = @id AND deleted_at IS NULL`
+
+	deletedWord = `SELECT deleted_at
+FROM vocabulary
+WHERE id = @id AND deleted_at IS NOT NULL`
+
+	restoreWord = `UPDATE vocabulary
+SET deleted_at = NULL
+WHERE id = @id;
+
+UPDATE occurrences
+SET deleted_at = NULL
+WHERE term_id = @id AND deleted_at = @deleted_at`
+)
+
+// DeleteWord soft deletes a term with its occurrences.
+func (s *Store) DeleteWord(ctx context.Context, id int64) (int64, error) {
+	return s.softDelete(ctx, deleteWord, deleteWordRows, id)
+}
+
+// RestoreWord restores a term with occurrences deleted with it.
+func (s *Store) RestoreWord(ctx context.Context, id int64) (int64, error) {
+	return s.restore(ctx, deletedWord, restoreWord, id)
+}
+
+// Word batches of a set, all batches if @batches is empty.
+const setFilters = `
+    AND (
+        JSON_ARRAY_LENGTH(@batches) = 0
+        OR wb.name IN (SELECT value FROM JSON_EACH(@batches))
+    )
+    AND (@created_from IS NULL OR wb.created_at >= @created_from)
+    AND (@created_to IS NULL OR wb.created_at < @created_to)`
+
+const listWordCountsInSet = `SELECT
+    v.normalized AS value,
+    SUM(o.count) AS total,
+    COUNT(DISTINCT o.batch_id) AS postings
+FROM occurrences AS o
+INNER JOIN vocabulary AS v ON o.term_id = v.id
+INNER JOIN word_batches AS wb ON o.batch_id = wb.id
+WHERE
+    o.deleted_at IS NULL
+    AND wb.deleted_at IS NULL` + setFilters + termFilters + batchFilters + `
+GROUP BY v.normalized
+ORDER BY v.normalized ASC`
+
+func (s *Store) ListWordCountsInSet(ctx context.Context, arg database.ListWordCountsInSetParams) ([]database.ListWordCountsInSetRow, error) {
+	args := append(filterArgs(arg.Language, arg.Seniority, arg.Location, arg.WorkMode, arg.Tag, arg.Collection, arg.Excluded),
+		sql.Named("batches", list(arg.Batches)),
+		sql.Named("created_from", optionalTime(arg.CreatedFrom)),
+		sql.Named("created_to", optionalTime(arg.CreatedTo)),
+	)
+	rows, err := s.db.QueryContext(ctx, listWordCountsInSet, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []database.ListWordCountsInSetRow
+	for rows.Next() {
+		var i database.ListWordCountsInSetRow
+		if err := rows.Scan(&i.Value, &i.Total, &i.Postings); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+const countWordBatchesInSet = `SELECT COUNT(*) AS total
+FROM word_batches AS wb
+WHERE
+    wb.deleted_at IS NULL` + setFilters + batchFilters
+
+func (s *Store) CountWordBatchesInSet(ctx context.Context, arg database.CountWordBatchesInSetParams) (int64, error) {
+	var total int64
+	err := s.db.QueryRowContext(ctx, countWordBatchesInSet,
+		sql.Named("batches", list(arg.Batches)),
+		sql.Named("created_from", optionalTime(arg.CreatedFrom)),
+		sql.Named("created_to", optionalTime(arg.CreatedTo)),
+		sql.Named("seniority", text(arg.Seniority)),
+		sql.Named("location", text(arg.Location)),
+		sql.Named("work_mode", text(arg.WorkMode)),
+		sql.Named("tag", text(arg.Tag)),
+		sql.Named("collection", text(arg.Collection)),
+	).Scan(&total)
+
+	return total, err
+}
@@ -0,0 +1,82 @@
+// Package storage keeps data of CLI commands either in Postgres or in a local SQLite
+// file, selected by config.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage/sqlite"
+	"github.com/kndrad/piccrack/pkg/retry"
+)
+
+// Storage runs every query of database.Store, so CLI commands run against either
+// storage.
+type Storage interface {
+	database.Store
+	Close() error
+}
+
+var (
+	_ Storage = (*Postgres)(nil)
+	_ Storage = (*sqlite.Store)(nil)
+)
+
+// Postgres is a Storage of a Postgres connection pool.
+type Postgres struct {
+	*database.Repository
+
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{
+		Repository: database.NewRepository(pool),
+		pool:       pool,
+	}
+}
+
+// Close closes connection pool.
+func (p *Postgres) Close() error {
+	p.pool.Close()
+
+	return nil
+}
+
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// Open returns storage selected by cfg. Postgres schema must be migrated beforehand,
+// SQLite file is created and migrated if needed.
+func Open(ctx context.Context, cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "", "postgres":
+		return openPostgres(ctx, cfg.Database)
+	case "sqlite":
+		s, err := sqlite.Open(ctx, cfg.Storage.Path)
+		if err != nil {
+			return nil, fmt.Errorf("open sqlite: %w", err)
+		}
+
+		return s, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, cfg.Storage.Driver)
+	}
+}
+
+func openPostgres(ctx context.Context, cfg config.DatabaseConfig) (*Postgres, error) {
+	pool, err := database.Pool(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("database pool: %w", err)
+	}
+	if err := retry.Ping(ctx, pool, retry.MaxRetries); err != nil {
+		pool.Close()
+
+		return nil, fmt.Errorf("database ping: %w", err)
+	}
+
+	return NewPostgres(pool), nil
+}
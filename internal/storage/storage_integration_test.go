@@ -0,0 +1,78 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kndrad/piccrack/config"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/kndrad/piccrack/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// TestPostgresConformance runs the conformance suites with a new database of each test.
+func TestPostgresConformance(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := postgres.Run(ctx,
+		"postgres:17",
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpassword"),
+		postgres.WithDatabase("piccrack"),
+		postgres.BasicWaitStrategies(),
+		postgres.WithSQLDriver("pgx"),
+	)
+	require.NoError(t, err)
+	defer container.Terminate(ctx)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	conn, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+	defer conn.Close(ctx)
+
+	var n int
+	open := func(t *testing.T) *storage.Postgres {
+		t.Helper()
+
+		n++
+		name := fmt.Sprintf("conformance_%d", n)
+		_, err := conn.Exec(ctx, "CREATE DATABASE "+name)
+		require.NoError(t, err)
+
+		u, err := url.Parse(dsn)
+		require.NoError(t, err)
+		u.Path = "/" + name
+
+		m, err := database.NewMigrator(u.String())
+		require.NoError(t, err)
+		require.NoError(t, m.Up())
+		require.NoError(t, m.Close())
+
+		pool, err := database.Pool(ctx, config.DatabaseConfig{DSN: u.String()})
+		require.NoError(t, err)
+
+		return storage.NewPostgres(pool)
+	}
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		t.Helper()
+
+		return open(t)
+	})
+	storagetest.RunStore(t, func(t *testing.T) database.Store {
+		t.Helper()
+
+		s := open(t)
+		t.Cleanup(func() { s.Close() })
+
+		return s
+	})
+}
@@ -0,0 +1,364 @@
+// Package storagetest is a conformance test suite of storage implementations, so that
+// all of them keep semantics of Postgres queries.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/kndrad/piccrack/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// Opener returns an empty migrated storage, closed by the suite.
+type Opener func(t *testing.T) storage.Storage
+
+// Run runs conformance tests, each of them against a storage of open.
+func Run(t *testing.T, open Opener) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"CreateWord", testCreateWord},
+		{"ListWordsPagination", testListWordsPagination},
+		{"Ingest", testIngest},
+		{"IngestErrors", testIngestErrors},
+		{"Frequencies", testFrequencies},
+		{"Rankings", testRankings},
+		{"BatchWordSets", testBatchWordSets},
+		{"Trend", testTrend},
+		{"UpdateWordBatchAttributes", testUpdateWordBatchAttributes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := open(t)
+			defer s.Close()
+
+			tt.fn(t, s)
+		})
+	}
+}
+
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: true}
+}
+
+func words(values ...string) []database.IngestedWord {
+	words := make([]database.IngestedWord, 0, len(values))
+	for _, v := range values {
+		words = append(words, database.IngestedWord{Value: v, Lemma: v, Language: "en"})
+	}
+
+	return words
+}
+
+type ingester interface {
+	Ingest(ctx context.Context, in database.Ingestion) (database.IngestResult, error)
+}
+
+// ingest stores two batches:
+//   - kraków: go ×3, kubernetes, docker ×2; senior and remote
+//   - warszawa: go, rust; junior
+func ingest(t *testing.T, s ingester) (int64, int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	krakow, err := s.Ingest(ctx, database.Ingestion{
+		Name:      "kraków",
+		Seniority: "senior",
+		Location:  "Kraków",
+		WorkMode:  "remote",
+		Words:     words("go", "kubernetes", "go", "docker", "go", "docker"),
+	})
+	require.NoError(t, err)
+	warszawa, err := s.Ingest(ctx, database.Ingestion{
+		Name:      "warszawa",
+		Seniority: "junior",
+		Location:  "Warszawa",
+		Words:     words("go", "rust"),
+	})
+	require.NoError(t, err)
+
+	return krakow.WordBatchID.Int64, warszawa.WordBatchID.Int64
+}
+
+func testCreateWord(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	first, err := s.CreateWord(ctx, database.CreateWordParams{Value: "golang", Lemma: "", Language: "en"})
+	require.NoError(t, err)
+	require.Equal(t, "golang", first.Value)
+	require.True(t, first.CreatedAt.Valid)
+
+	// Words are counted again and get lemma if they had none
+	again, err := s.CreateWord(ctx, database.CreateWordParams{Value: "golang", Lemma: "go", Language: "en"})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, again.ID)
+	require.Equal(t, "go", again.Lemma)
+
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordFrequenciesRow{{Value: "golang", Total: 2}}, rows)
+}
+
+func testListWordsPagination(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	for _, v := range []string{"delta", "alpha", "charlie", "bravo"} {
+		_, err := s.CreateWord(ctx, database.CreateWordParams{Value: v})
+		require.NoError(t, err)
+	}
+
+	rows, err := s.ListWords(ctx, database.ListWordsParams{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "bravo", rows[0].Value)
+	require.Equal(t, "charlie", rows[1].Value)
+
+	rows, err = s.ListWords(ctx, database.ListWordsParams{Limit: 10, Offset: 4})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func testIngest(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	res, err := s.Ingest(ctx, database.Ingestion{
+		Name:  "first",
+		Words: words("go", "go", "sql"),
+	})
+	require.NoError(t, err)
+	require.True(t, res.WordBatchID.Valid)
+	require.Equal(t, int64(3), res.Words)
+	require.Equal(t, int64(2), res.Terms)
+	require.Equal(t, int64(2), res.Occurrences)
+
+	// Known terms aren't added again
+	res, err = s.Ingest(ctx, database.Ingestion{Words: words("go", "rust")})
+	require.NoError(t, err)
+	require.False(t, res.WordBatchID.Valid)
+	require.Equal(t, int64(2), res.Words)
+	require.Equal(t, int64(1), res.Terms)
+	require.Equal(t, int64(2), res.Occurrences)
+
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []database.ListWordFrequenciesRow{
+		{Value: "go", Total: 3},
+		{Value: "sql", Total: 1},
+		{Value: "rust", Total: 1},
+	}, rows)
+}
+
+func testIngestErrors(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	_, err := s.Ingest(ctx, database.Ingestion{
+		Words:   words("go"),
+		Phrases: []database.IngestedPhrase{{Value: "Go developer"}},
+	})
+	require.ErrorIs(t, err, database.ErrNoBatchName)
+
+	_, err = s.Ingest(ctx, database.Ingestion{
+		Name:     "salaries",
+		Salaries: []database.CreateSalaryParams{{Currency: "PLN"}},
+	})
+	require.ErrorIs(t, err, database.ErrSalariesWithWords)
+
+	rows, err := s.ListWords(ctx, database.ListWordsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func testFrequencies(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{
+		Limit:    10,
+		Location: text("KRAKÓW"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordFrequenciesRow{
+		{Value: "kubernetes", Total: 1},
+		{Value: "docker", Total: 2},
+		{Value: "go", Total: 3},
+	}, rows)
+
+	rows, err = s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{
+		Limit:    10,
+		Excluded: []string{"kubernetes", "docker"},
+		Language: text("en"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordFrequenciesRow{
+		{Value: "rust", Total: 1},
+		{Value: "go", Total: 4},
+	}, rows)
+
+	rows, err = s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{
+		Limit:     10,
+		Seniority: text("junior"),
+		Offset:    1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	for _, params := range []database.ListWordFrequenciesParams{
+		{Limit: 10, WorkMode: text("office")},
+		{Limit: 10, Language: text("pl")},
+		{Limit: 10, Tag: text("missing")},
+		{Limit: 10, Collection: text("missing")},
+	} {
+		rows, err = s.ListWordFrequencies(ctx, params)
+		require.NoError(t, err)
+		require.Empty(t, rows, params)
+	}
+
+	lemmas, err := s.ListLemmaFrequencies(ctx, database.ListLemmaFrequenciesParams{
+		Limit:    10,
+		WorkMode: text("remote"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListLemmaFrequenciesRow{
+		{Lemma: "kubernetes", Total: 1},
+		{Lemma: "docker", Total: 2},
+		{Lemma: "go", Total: 3},
+	}, lemmas)
+}
+
+func testRankings(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	rows, err := s.ListWordRankings(ctx, database.ListWordRankingsParams{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordRankingsRow{
+		{Value: "go", Ranking: 1},
+		{Value: "docker", Ranking: 2},
+	}, rows)
+
+	rows, err = s.ListWordRankings(ctx, database.ListWordRankingsParams{
+		Limit:     10,
+		Seniority: text("senior"),
+		Excluded:  []string{"go"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordRankingsRow{
+		{Value: "docker", Ranking: 1},
+		{Value: "kubernetes", Ranking: 2},
+	}, rows)
+}
+
+func testBatchWordSets(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	krakow, warszawa := ingest(t, s)
+
+	// Words without a batch aren't in sets
+	_, err := s.CreateWord(ctx, database.CreateWordParams{Value: "java"})
+	require.NoError(t, err)
+
+	rows, err := s.ListBatchWordSets(ctx, database.ListBatchWordSetsParams{})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListBatchWordSetsRow{
+		{BatchID: krakow, Words: []string{"docker", "go", "kubernetes"}},
+		{BatchID: warszawa, Words: []string{"go", "rust"}},
+	}, rows)
+
+	rows, err = s.ListBatchWordSets(ctx, database.ListBatchWordSetsParams{
+		Location: text("warszawa"),
+		Excluded: []string{"rust"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListBatchWordSetsRow{
+		{BatchID: warszawa, Words: []string{"go"}},
+	}, rows)
+
+	rows, err = s.ListBatchWordSets(ctx, database.ListBatchWordSetsParams{
+		Skills: []string{"docker", "rust"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListBatchWordSetsRow{
+		{BatchID: krakow, Words: []string{"docker"}},
+		{BatchID: warszawa, Words: []string{"rust"}},
+	}, rows)
+}
+
+func testTrend(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	now := time.Now().UTC()
+	since := pgtype.Timestamptz{Time: now.Add(-24 * time.Hour), Valid: true}
+
+	rows, err := s.ListWordTrend(ctx, database.ListWordTrendParams{
+		Bucket: "day",
+		Since:  since,
+		Words:  []string{"go", "docker"},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	require.True(t, rows[0].Bucket.Time.Equal(day), rows[0].Bucket.Time)
+	require.Equal(t, "docker", rows[0].Value)
+	require.Equal(t, int64(2), rows[0].Total)
+	require.Equal(t, int64(1), rows[0].Postings)
+	require.Equal(t, int64(2), rows[0].BucketPostings)
+	require.Equal(t, "go", rows[1].Value)
+	require.Equal(t, int64(4), rows[1].Total)
+	require.Equal(t, int64(2), rows[1].Postings)
+
+	// Weeks start on Monday
+	rows, err = s.ListWordTrend(ctx, database.ListWordTrendParams{
+		Bucket:    "week",
+		Since:     since,
+		Seniority: text("junior"),
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	require.True(t, rows[0].Bucket.Time.Equal(monday), rows[0].Bucket.Time)
+	require.Equal(t, int64(1), rows[0].BucketPostings)
+
+	rows, err = s.ListWordTrend(ctx, database.ListWordTrendParams{
+		Bucket: "month",
+		Since:  pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func testUpdateWordBatchAttributes(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	row, err := s.UpdateWordBatchAttributes(ctx, database.UpdateWordBatchAttributesParams{
+		Name:     "warszawa",
+		WorkMode: text("hybrid"),
+		Location: text(""),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "junior", row.Seniority)
+	require.Equal(t, "", row.Location)
+	require.Equal(t, "hybrid", row.WorkMode)
+
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{
+		Limit:    10,
+		WorkMode: text("hybrid"),
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	_, err = s.UpdateWordBatchAttributes(ctx, database.UpdateWordBatchAttributesParams{
+		Name:     "missing",
+		WorkMode: text("hybrid"),
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,760 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/kndrad/piccrack/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+// StoreOpener returns an empty migrated store of all queries, closed by cleanup of t.
+type StoreOpener func(t *testing.T) database.Store
+
+// RunStore runs conformance tests of queries of batches, phrases, tags, collections,
+// postings, salaries and documents, which only full stores implement, each of them
+// against a store of open.
+func RunStore(t *testing.T, open StoreOpener) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s database.Store)
+	}{
+		{"Batches", testBatches},
+		{"MergeSplitBatches", testMergeSplitBatches},
+		{"DeleteRestoreBatches", testDeleteRestoreBatches},
+		{"Phrases", testPhrases},
+		{"Tags", testTags},
+		{"Collections", testCollections},
+		{"Sets", testSets},
+		{"SearchWords", testSearchWords},
+		{"SearchPhrases", testSearchPhrases},
+		{"Postings", testPostings},
+		{"Salaries", testSalaries},
+		{"Documents", testDocuments},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, open(t))
+		})
+	}
+}
+
+// requireCode requires err to be a Postgres error of code.
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), err)
+	require.Equal(t, code, pgErr.Code)
+}
+
+func id(v int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: v, Valid: true}
+}
+
+func testBatches(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	krakow, warszawa := ingest(t, s)
+
+	rows, err := s.ListBatches(ctx, database.ListBatchesParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, krakow, rows[0].ID)
+	require.Equal(t, "words", rows[0].Kind)
+	require.Equal(t, "kraków", rows[0].Name)
+	require.Equal(t, "remote", rows[0].WorkMode)
+	require.Equal(t, int64(3), rows[0].Items)
+	require.Equal(t, int64(6), rows[0].Total)
+	require.Equal(t, warszawa, rows[1].ID)
+	require.Equal(t, "", rows[1].WorkMode)
+
+	rows, err = s.ListBatches(ctx, database.ListBatchesParams{Limit: 10, Prefix: text("war")})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, warszawa, rows[0].ID)
+
+	rows, err = s.ListBatches(ctx, database.ListBatchesParams{Limit: 10, Kind: text("phrases")})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	batch, err := s.GetWordBatch(ctx, warszawa)
+	require.NoError(t, err)
+	require.Equal(t, "junior", batch.Seniority)
+	require.Equal(t, int64(2), batch.Items)
+
+	// Words are ordered by count, then by value
+	words, err := s.ListBatchWords(ctx, krakow)
+	require.NoError(t, err)
+	require.Len(t, words, 3)
+	for i, want := range []struct {
+		value string
+		count int32
+	}{{"go", 3}, {"docker", 2}, {"kubernetes", 1}} {
+		require.Equal(t, want.value, words[i].Value)
+		require.Equal(t, want.count, words[i].Count)
+	}
+
+	renamed, err := s.RenameWordBatch(ctx, database.RenameWordBatchParams{ID: krakow, Name: "krakow"})
+	require.NoError(t, err)
+	require.Equal(t, krakow, renamed)
+	byName, err := s.ListWordsByBatchName(ctx, "krakow")
+	require.NoError(t, err)
+	require.Len(t, byName, 3)
+
+	_, err = s.RenameWordBatch(ctx, database.RenameWordBatchParams{ID: krakow + warszawa, Name: "missing"})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = s.GetWordBatch(ctx, krakow+warszawa)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testMergeSplitBatches(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	krakow, warszawa := ingest(t, s)
+
+	// Counts of terms in both batches are summed
+	moved, err := s.MergeWordBatches(ctx, database.MergeWordBatchesParams{Source: warszawa, Target: krakow})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), moved)
+
+	batch, err := s.GetWordBatch(ctx, krakow)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), batch.Items)
+	require.Equal(t, int64(8), batch.Total)
+	_, err = s.GetWordBatch(ctx, warszawa)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = s.MergeWordBatches(ctx, database.MergeWordBatchesParams{Source: krakow, Target: krakow})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Split batch keeps attributes of its source
+	split, err := s.SplitWordBatch(ctx, database.SplitWordBatchParams{
+		ID:     krakow,
+		Name:   "go",
+		Values: []string{"go", "java"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), split.Moved)
+
+	batch, err = s.GetWordBatch(ctx, split.ID)
+	require.NoError(t, err)
+	require.Equal(t, "go", batch.Name)
+	require.Equal(t, "senior", batch.Seniority)
+	require.Equal(t, int64(1), batch.Items)
+	require.Equal(t, int64(4), batch.Total)
+
+	_, err = s.SplitWordBatch(ctx, database.SplitWordBatchParams{ID: warszawa, Name: "rust", Values: []string{"rust"}})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testDeleteRestoreBatches(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	krakow, warszawa := ingest(t, s)
+
+	deleted, err := s.DeleteWordBatch(ctx, warszawa)
+	require.NoError(t, err)
+	require.Equal(t, warszawa, deleted)
+	_, err = s.DeleteWordBatch(ctx, warszawa)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Words of deleted batches aren't counted
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordFrequenciesRow{
+		{Value: "kubernetes", Total: 1},
+		{Value: "docker", Total: 2},
+		{Value: "go", Total: 3},
+	}, rows)
+
+	restored, err := s.RestoreWordBatch(ctx, warszawa)
+	require.NoError(t, err)
+	require.Equal(t, warszawa, restored)
+	batches, err := s.ListBatches(ctx, database.ListBatchesParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	// Only rows deleted before the time are purged
+	_, err = s.DeleteWordBatch(ctx, krakow)
+	require.NoError(t, err)
+	purged, err := s.PurgeDeleted(ctx, pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, database.PurgeDeletedRow{}, purged)
+
+	purged, err = s.PurgeDeleted(ctx, pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, database.PurgeDeletedRow{WordBatches: 1, Occurrences: 3}, purged)
+
+	_, err = s.RestoreWordBatch(ctx, krakow)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testPhrases(t *testing.T, s database.Store) {
+	ctx := context.Background()
+
+	created, err := s.CreatePhrasesBatch(ctx, database.CreatePhrasesBatchParams{
+		Name:      "phrases",
+		Phrases:   []string{"Go developer", "Rust engineer", "go developer"},
+		Languages: []string{"en", "en", "en"},
+	})
+	require.NoError(t, err)
+	require.True(t, created.BatchID.Valid)
+	batch := created.BatchID.Int64
+
+	_, err = s.CreatePhrasesBatch(ctx, database.CreatePhrasesBatchParams{
+		Name:      "phrases",
+		Phrases:   []string{"Go developer"},
+		Languages: []string{"en"},
+	})
+	requireCode(t, err, "23505")
+
+	phrases, err := s.ListBatchPhrases(ctx, batch)
+	require.NoError(t, err)
+	require.Len(t, phrases, 3)
+	require.Equal(t, "Go developer", phrases[0].Value)
+	require.Equal(t, "go developer", phrases[2].Value)
+
+	rows, err := s.ListBatches(ctx, database.ListBatchesParams{Limit: 10, Kind: text("phrases")})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(3), rows[0].Items)
+	require.Equal(t, int64(3), rows[0].Total)
+
+	split, err := s.SplitPhraseBatch(ctx, database.SplitPhraseBatchParams{
+		ID:     batch,
+		Name:   "rust",
+		Values: []string{"Rust engineer"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), split.Moved)
+
+	// Counts are case insensitive
+	counts, err := s.ListPhraseCountsInSet(ctx, database.ListPhraseCountsInSetParams{})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListPhraseCountsInSetRow{
+		{Value: "go developer", Total: 2, Postings: 1},
+		{Value: "rust engineer", Total: 1, Postings: 1},
+	}, counts)
+
+	moved, err := s.MergePhraseBatches(ctx, database.MergePhraseBatchesParams{Source: split.ID, Target: batch})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), moved)
+	_, err = s.GetPhraseBatch(ctx, split.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	res, err := s.Ingest(ctx, database.Ingestion{
+		Name:    "ingested",
+		Words:   words("go"),
+		Phrases: []database.IngestedPhrase{{Value: "Go developer", Language: "en"}},
+	})
+	require.NoError(t, err)
+	require.True(t, res.PhraseBatchID.Valid)
+	require.Equal(t, int64(1), res.Phrases)
+
+	counts, err = s.ListPhraseCountsInSet(ctx, database.ListPhraseCountsInSetParams{Batches: []string{"phrases", "ingested"}})
+	require.NoError(t, err)
+	require.Equal(t, database.ListPhraseCountsInSetRow{Value: "go developer", Total: 3, Postings: 2}, counts[0])
+}
+
+func testTags(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	krakow, warszawa := ingest(t, s)
+
+	// Tags are created on first use and adding a member again does nothing
+	for range 2 {
+		_, err := s.AddTagMember(ctx, database.AddTagMemberParams{Name: "backend", WordBatchID: id(krakow)})
+		require.NoError(t, err)
+	}
+	tags, err := s.ListTags(ctx, database.ListTagsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.Equal(t, "backend", tags[0].Name)
+	require.Equal(t, int64(1), tags[0].WordBatches)
+
+	_, err = s.CreateTag(ctx, "backend")
+	requireCode(t, err, "23505")
+	_, err = s.AddTagMember(ctx, database.AddTagMemberParams{Name: "backend", WordBatchID: id(krakow + warszawa)})
+	requireCode(t, err, "23503")
+	_, err = s.AddTagMember(ctx, database.AddTagMemberParams{
+		Name:          "backend",
+		WordBatchID:   id(krakow),
+		PhraseBatchID: id(krakow),
+	})
+	requireCode(t, err, "23514")
+
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10, Tag: text("backend")})
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	members, err := s.ListTagMembers(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, "words", members[0].Type)
+	require.Equal(t, "kraków", members[0].Name)
+
+	_, err = s.RemoveTagMember(ctx, database.RemoveTagMemberParams{Name: "backend", WordBatchID: id(krakow)})
+	require.NoError(t, err)
+	_, err = s.RemoveTagMember(ctx, database.RemoveTagMemberParams{Name: "backend", WordBatchID: id(krakow)})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = s.DeleteTag(ctx, "backend")
+	require.NoError(t, err)
+	_, err = s.GetTag(ctx, "backend")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testCollections(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	_, warszawa := ingest(t, s)
+
+	// Collections aren't created on first use
+	_, err := s.AddCollectionMember(ctx, database.AddCollectionMemberParams{Name: "juniors", WordBatchID: id(warszawa)})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = s.CreateCollection(ctx, database.CreateCollectionParams{Name: "juniors", Description: "Junior offers"})
+	require.NoError(t, err)
+	_, err = s.CreateCollection(ctx, database.CreateCollectionParams{Name: "juniors"})
+	requireCode(t, err, "23505")
+
+	_, err = s.AddCollectionMember(ctx, database.AddCollectionMemberParams{Name: "juniors", WordBatchID: id(warszawa)})
+	require.NoError(t, err)
+
+	collection, err := s.GetCollection(ctx, "juniors")
+	require.NoError(t, err)
+	require.Equal(t, "Junior offers", collection.Description)
+	require.Equal(t, int64(1), collection.WordBatches)
+
+	n, err := s.CountWordBatchesInSet(ctx, database.CountWordBatchesInSetParams{Collection: text("juniors")})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	// Deleted batches aren't listed
+	_, err = s.DeleteWordBatch(ctx, warszawa)
+	require.NoError(t, err)
+	members, err := s.ListCollectionMembers(ctx, "juniors")
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	_, err = s.DeleteCollection(ctx, "juniors")
+	require.NoError(t, err)
+	_, err = s.DeleteCollection(ctx, "juniors")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testSets(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	rows, err := s.ListWordCountsInSet(ctx, database.ListWordCountsInSetParams{})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordCountsInSetRow{
+		{Value: "docker", Total: 2, Postings: 1},
+		{Value: "go", Total: 4, Postings: 2},
+		{Value: "kubernetes", Total: 1, Postings: 1},
+		{Value: "rust", Total: 1, Postings: 1},
+	}, rows)
+
+	rows, err = s.ListWordCountsInSet(ctx, database.ListWordCountsInSetParams{
+		Batches:  []string{"warszawa"},
+		Excluded: []string{"rust"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListWordCountsInSetRow{{Value: "go", Total: 1, Postings: 1}}, rows)
+
+	n, err := s.CountWordBatchesInSet(ctx, database.CountWordBatchesInSetParams{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	n, err = s.CountWordBatchesInSet(ctx, database.CountWordBatchesInSetParams{Location: text("kraków")})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
+
+func testSearchWords(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	ingest(t, s)
+
+	rows, err := s.SearchWords(ctx, database.SearchWordsParams{Limit: 10, Query: "Kubernetes"})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, "kubernetes", rows[0].Value)
+	require.Equal(t, int64(1), rows[0].Total)
+	require.InDelta(t, 1, rows[0].Similarity, 0.001)
+
+	rows, err = s.SearchWords(ctx, database.SearchWordsParams{Limit: 10, Query: "rust", Language: text("pl")})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func testSearchPhrases(t *testing.T, s database.Store) {
+	ctx := context.Background()
+
+	created, err := s.CreatePhrasesBatch(ctx, database.CreatePhrasesBatchParams{
+		Name:      "phrases",
+		Phrases:   []string{"Remote work from Gdansk", "Office in Warsaw"},
+		Languages: []string{"en", "en"},
+	})
+	require.NoError(t, err)
+
+	rows, err := s.SearchPhrases(ctx, database.SearchPhrasesParams{Limit: 10, Query: "remote work"})
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+	require.Equal(t, "Remote work from Gdansk", rows[0].Value)
+	require.Equal(t, "phrases", rows[0].BatchName)
+	require.Positive(t, rows[0].Rank)
+	require.Contains(t, rows[0].Snippet, "<mark>Remote</mark>")
+
+	// Misspelled queries match similar phrases
+	rows, err = s.SearchPhrases(ctx, database.SearchPhrasesParams{Limit: 10, Query: "Ofice in Warsw"})
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+	require.Equal(t, "Office in Warsaw", rows[0].Value)
+
+	rows, err = s.SearchPhrases(ctx, database.SearchPhrasesParams{Limit: 10, Query: "remote work", Language: text("pl")})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	_, err = s.DeletePhraseBatch(ctx, created.BatchID.Int64)
+	require.NoError(t, err)
+	rows, err = s.SearchPhrases(ctx, database.SearchPhrasesParams{Limit: 10, Query: "remote work"})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func testPostings(t *testing.T, s database.Store) {
+	ctx := context.Background()
+	captured := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Batches of ingested postings are named after them
+	res, err := s.Ingest(ctx, database.Ingestion{
+		Posting:  &database.CreatePostingParams{Title: "Go Developer", Company: "Acme", CapturedAt: pgtype.Timestamptz{Time: captured, Valid: true}},
+		Words:    words("go", "docker"),
+		Phrases:  []database.IngestedPhrase{{Value: "Remote work", Language: "en"}},
+		Salaries: []database.CreateSalaryParams{{MonthlyMin: 20000, MonthlyMax: 30000, Currency: "PLN", Period: "month"}},
+	})
+	require.NoError(t, err)
+	require.True(t, res.PostingID.Valid)
+	require.Equal(t, int64(1), res.Salaries)
+	posting := res.PostingID.Int64
+	name := database.PostingBatchName(posting)
+
+	got, err := s.GetPosting(ctx, posting)
+	require.NoError(t, err)
+	require.Equal(t, "Go Developer", got.Title)
+	require.Equal(t, res.WordBatchID, got.WordBatchID)
+	require.Equal(t, text(name), got.WordBatchName)
+	require.Equal(t, res.PhraseBatchID, got.PhraseBatchID)
+	batch, err := s.GetWordBatch(ctx, res.WordBatchID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, res.PostingID, batch.PostingID)
+
+	other, err := s.CreatePosting(ctx, database.CreatePostingParams{
+		Title:      "Java Developer",
+		Company:    "Other",
+		CapturedAt: pgtype.Timestamptz{Time: captured.Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Latest captured postings are listed first
+	postings, err := s.ListPostings(ctx, database.ListPostingsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, postings, 2)
+	require.Equal(t, other.ID, postings[0].ID)
+	require.False(t, postings[0].WordBatchID.Valid)
+	require.Equal(t, res.WordBatchID, postings[1].WordBatchID)
+	postings, err = s.ListPostings(ctx, database.ListPostingsParams{Limit: 10, Company: text("acme")})
+	require.NoError(t, err)
+	require.Len(t, postings, 1)
+	require.Equal(t, posting, postings[0].ID)
+
+	updated, err := s.UpdatePosting(ctx, database.UpdatePostingParams{ID: posting, Title: text("Senior Go Developer")})
+	require.NoError(t, err)
+	require.Equal(t, "Senior Go Developer", updated.Title)
+	require.Equal(t, "Acme", updated.Company)
+	_, err = s.UpdatePosting(ctx, database.UpdatePostingParams{ID: posting + other.ID, Title: text("Missing")})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Posting owns a single batch of each kind and it must exist
+	_, err = s.Ingest(ctx, database.Ingestion{Name: "second", PostingID: id(posting), Words: words("go")})
+	requireCode(t, err, "23505")
+	_, err = s.Ingest(ctx, database.Ingestion{Name: "missing", PostingID: id(posting + other.ID), Words: words("go")})
+	requireCode(t, err, "23503")
+
+	// Batches of tagged postings are tagged
+	_, err = s.AddTagMember(ctx, database.AddTagMemberParams{Name: "backend", PostingID: id(posting)})
+	require.NoError(t, err)
+	_, err = s.AddTagMember(ctx, database.AddTagMemberParams{Name: "backend", PostingID: id(posting + other.ID)})
+	requireCode(t, err, "23503")
+	rows, err := s.ListWordFrequencies(ctx, database.ListWordFrequenciesParams{Limit: 10, Tag: text("backend")})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	members, err := s.ListTagMembers(ctx, "backend")
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, "posting", members[0].Type)
+	require.Equal(t, "Senior Go Developer", members[0].Name)
+
+	// Deleted postings take their batches and salaries with them
+	_, err = s.DeletePosting(ctx, posting)
+	require.NoError(t, err)
+	_, err = s.GetPosting(ctx, posting)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = s.DeletePosting(ctx, posting)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	salaries, err := s.ListSalariesByBatchName(ctx, name)
+	require.NoError(t, err)
+	require.Empty(t, salaries)
+	_, err = s.RestoreWordBatch(ctx, res.WordBatchID.Int64)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	members, err = s.ListTagMembers(ctx, "backend")
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	_, err = s.RestorePosting(ctx, posting)
+	require.NoError(t, err)
+	got, err = s.GetPosting(ctx, posting)
+	require.NoError(t, err)
+	require.Equal(t, res.WordBatchID, got.WordBatchID)
+	require.Equal(t, res.PhraseBatchID, got.PhraseBatchID)
+	salaries, err = s.ListSalariesByBatchName(ctx, name)
+	require.NoError(t, err)
+	require.Len(t, salaries, 1)
+
+	// Batches of reprocessed postings are replaced in the same transaction, so they're kept
+	// if ingestion fails
+	_, err = s.Ingest(ctx, database.Ingestion{
+		Name:           name,
+		PostingID:      id(posting),
+		ReplaceBatches: true,
+		Words:          words("go"),
+		Phrases:        []database.IngestedPhrase{{Value: "Go", Language: "en"}},
+	})
+	requireCode(t, err, "23505")
+	got, err = s.GetPosting(ctx, posting)
+	require.NoError(t, err)
+	require.Equal(t, res.WordBatchID, got.WordBatchID)
+	require.Equal(t, res.PhraseBatchID, got.PhraseBatchID)
+
+	// Reprocessed postings own new batches, while replaced ones can't be restored
+	reprocessed, err := s.Ingest(ctx, database.Ingestion{Name: "reprocessed", PostingID: id(posting), ReplaceBatches: true, Words: words("go")})
+	require.NoError(t, err)
+	got, err = s.GetPosting(ctx, posting)
+	require.NoError(t, err)
+	require.Equal(t, reprocessed.WordBatchID, got.WordBatchID)
+	require.False(t, got.PhraseBatchID.Valid)
+	require.NoError(t, s.DeletePostingBatches(ctx, posting))
+	got, err = s.GetPosting(ctx, posting)
+	require.NoError(t, err)
+	require.False(t, got.WordBatchID.Valid)
+	_, err = s.RestoreWordBatch(ctx, reprocessed.WordBatchID.Int64)
+	require.NoError(t, err)
+	_, err = s.RestoreWordBatch(ctx, res.WordBatchID.Int64)
+	requireCode(t, err, "23505")
+
+	_, err = s.DeletePosting(ctx, posting)
+	require.NoError(t, err)
+	purged, err := s.PurgeDeleted(ctx, pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged.Postings)
+	require.Equal(t, int64(2), purged.WordBatches)
+	require.Equal(t, int64(1), purged.Salaries)
+	_, err = s.GetWordBatch(ctx, reprocessed.WordBatchID.Int64)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	tag, err := s.GetTag(ctx, "backend")
+	require.NoError(t, err)
+	require.Zero(t, tag.Postings)
+}
+
+func testSalaries(t *testing.T, s database.Store) {
+	ctx := context.Background()
+
+	salary := func(min, max float64, contract, seniority string) []database.CreateSalaryParams {
+		return []database.CreateSalaryParams{{
+			MinAmount:  min,
+			MaxAmount:  max,
+			Currency:   "PLN",
+			Period:     "month",
+			MonthlyMin: min,
+			MonthlyMax: max,
+			Contract:   contract,
+			Seniority:  seniority,
+			Raw:        "salary",
+		}}
+	}
+	a, err := s.Ingest(ctx, database.Ingestion{Name: "a", Seniority: "senior", Words: words("go", "docker"), Salaries: salary(10000, 20000, "b2b", "")})
+	require.NoError(t, err)
+	b, err := s.Ingest(ctx, database.Ingestion{Name: "b", Words: words("go"), Salaries: salary(20000, 30000, "uop", "mid")})
+	require.NoError(t, err)
+	c, err := s.Ingest(ctx, database.Ingestion{Name: "c", Words: words("go", "rust"), Salaries: salary(30000, 40000, "b2b", "")})
+	require.NoError(t, err)
+
+	// Skills are ordered by their numbers of salaries, then by name
+	bySkill, err := s.ListSalaryMediansBySkill(ctx, database.ListSalaryMediansBySkillParams{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListSalaryMediansBySkillRow{
+		{Skill: "go", Currency: "PLN", Total: 3, Median: 25000},
+		{Skill: "docker", Currency: "PLN", Total: 1, Median: 15000},
+		{Skill: "rust", Currency: "PLN", Total: 1, Median: 35000},
+	}, bySkill)
+
+	// Medians of even numbers of salaries are interpolated
+	bySkill, err = s.ListSalaryMediansBySkill(ctx, database.ListSalaryMediansBySkillParams{
+		Limit:    10,
+		Skill:    text("go"),
+		Contract: text("b2b"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListSalaryMediansBySkillRow{{Skill: "go", Currency: "PLN", Total: 2, Median: 25000}}, bySkill)
+
+	bySkill, err = s.ListSalaryMediansBySkill(ctx, database.ListSalaryMediansBySkillParams{Limit: 10, Excluded: []string{"go"}})
+	require.NoError(t, err)
+	require.Len(t, bySkill, 2)
+
+	// Seniority of batches takes precedence over seniority of salaries
+	bySeniority, err := s.ListSalaryMediansBySeniority(ctx, pgtype.Text{})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListSalaryMediansBySeniorityRow{
+		{Seniority: "", Currency: "PLN", Total: 1, Median: 35000},
+		{Seniority: "mid", Currency: "PLN", Total: 1, Median: 25000},
+		{Seniority: "senior", Currency: "PLN", Total: 1, Median: 15000},
+	}, bySeniority)
+
+	salaries, err := s.ListSalariesByBatchName(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, salaries, 1)
+	require.Equal(t, "b2b", salaries[0].Contract)
+	require.Equal(t, "", salaries[0].Basis)
+
+	created, err := s.CreateSalary(ctx, database.CreateSalaryParams{BatchID: a.WordBatchID.Int64, Currency: "EUR", Period: "hour", Contract: "b2b"})
+	require.NoError(t, err)
+	require.Equal(t, text("b2b"), created.Contract)
+	require.False(t, created.Basis.Valid)
+	_, err = s.CreateSalary(ctx, database.CreateSalaryParams{BatchID: a.WordBatchID.Int64 + b.WordBatchID.Int64 + c.WordBatchID.Int64, Currency: "PLN"})
+	requireCode(t, err, "23503")
+
+	// Salaries are deleted and restored with their batches
+	_, err = s.DeleteWordBatch(ctx, a.WordBatchID.Int64)
+	require.NoError(t, err)
+	bySeniority, err = s.ListSalaryMediansBySeniority(ctx, text("b2b"))
+	require.NoError(t, err)
+	require.Equal(t, []database.ListSalaryMediansBySeniorityRow{{Seniority: "", Currency: "PLN", Total: 1, Median: 35000}}, bySeniority)
+	_, err = s.RestoreWordBatch(ctx, a.WordBatchID.Int64)
+	require.NoError(t, err)
+	salaries, err = s.ListSalariesByBatchName(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, salaries, 2)
+
+	// Merged batches move their salaries
+	_, err = s.MergeWordBatches(ctx, database.MergeWordBatchesParams{Source: c.WordBatchID.Int64, Target: b.WordBatchID.Int64})
+	require.NoError(t, err)
+	salaries, err = s.ListSalariesByBatchName(ctx, "b")
+	require.NoError(t, err)
+	require.Len(t, salaries, 2)
+
+	// Words of known skills are grouped under canonical names, other words are skipped
+	_, err = s.Ingest(ctx, database.Ingestion{Name: "d", Words: words("golang", "experience"), Salaries: salary(40000, 50000, "b2b", "")})
+	require.NoError(t, err)
+	bySkill, err = s.ListSalaryMediansBySkill(ctx, database.ListSalaryMediansBySkillParams{
+		Limit:           10,
+		Skills:          []string{"docker", "go", "golang"},
+		CanonicalSkills: []string{"docker", "go", "go"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []database.ListSalaryMediansBySkillRow{
+		{Skill: "go", Currency: "PLN", Total: 4, Median: 30000},
+		{Skill: "docker", Currency: "EUR", Total: 1, Median: 0},
+		{Skill: "docker", Currency: "PLN", Total: 1, Median: 15000},
+		{Skill: "go", Currency: "EUR", Total: 1, Median: 0},
+	}, bySkill)
+}
+
+func testDocuments(t *testing.T, s database.Store) {
+	ctx := context.Background()
+
+	posting, err := s.CreatePosting(ctx, database.CreatePostingParams{Title: "Go Developer"})
+	require.NoError(t, err)
+	require.True(t, posting.CapturedAt.Valid)
+
+	// Images are unique by hash and content is kept once it's stored
+	img, err := s.CreateImage(ctx, database.CreateImageParams{Hash: "hash", Width: 640, Height: 480, Format: "png"})
+	require.NoError(t, err)
+	again, err := s.CreateImage(ctx, database.CreateImageParams{Hash: "hash", Format: "png", Content: []byte("png")})
+	require.NoError(t, err)
+	require.Equal(t, img.ID, again.ID)
+	require.Equal(t, int32(640), again.Width)
+
+	_, err = s.CreateOcrDocument(ctx, database.CreateOcrDocumentParams{
+		PostingID: id(posting.ID),
+		Text:      "Go",
+		Engine:    "text",
+		Settings:  []byte(`{}`),
+	})
+	require.NoError(t, err)
+	doc, err := s.CreateOcrDocument(ctx, database.CreateOcrDocumentParams{
+		PostingID: id(posting.ID),
+		ImageID:   id(img.ID),
+		Text:      "Senior Go",
+		Engine:    "tesseract",
+		Languages: []string{"eng"},
+		Settings:  []byte(`{"psm": 3}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"eng"}, doc.Languages)
+
+	latest, err := s.GetLatestOcrDocument(ctx, posting.ID)
+	require.NoError(t, err)
+	require.Equal(t, doc.ID, latest.ID)
+	require.Equal(t, "Senior Go", latest.Text)
+	require.Equal(t, text("png"), latest.ImageFormat)
+	require.Equal(t, []byte("png"), latest.ImageContent)
+
+	docs, err := s.ListOcrDocuments(ctx, posting.ID)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, doc.ID, docs[0].ID)
+	require.Equal(t, int32(9), docs[0].TextLength)
+	require.Equal(t, text("hash"), docs[0].ImageHash)
+	require.False(t, docs[1].ImageHash.Valid)
+	require.Empty(t, docs[1].Languages)
+
+	_, err = s.CreateOcrDocument(ctx, database.CreateOcrDocumentParams{PostingID: id(posting.ID), Engine: "text", Settings: []byte(`{`)})
+	requireCode(t, err, "22P02")
+	_, err = s.CreateOcrDocument(ctx, database.CreateOcrDocumentParams{PostingID: id(posting.ID + 1), Engine: "text", Settings: []byte(`{}`)})
+	requireCode(t, err, "23503")
+
+	// Documents are deleted and restored with their postings
+	_, err = s.DeletePosting(ctx, posting.ID)
+	require.NoError(t, err)
+	_, err = s.GetLatestOcrDocument(ctx, posting.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = s.RestorePosting(ctx, posting.ID)
+	require.NoError(t, err)
+	docs, err = s.ListOcrDocuments(ctx, posting.ID)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	// Documents are ingested with postings, all or nothing
+	ingested := &database.IngestedDocument{
+		Image:    &database.CreateImageParams{Hash: "ingested", Format: "png"},
+		Document: database.CreateOcrDocumentParams{Text: "Rust", Engine: "tesseract", Settings: []byte(`{`)},
+	}
+	_, err = s.Ingest(ctx, database.Ingestion{Posting: &database.CreatePostingParams{Title: "Rust Developer"}, Words: words("rust"), Document: ingested})
+	requireCode(t, err, "22P02")
+	postings, err := s.ListPostings(ctx, database.ListPostingsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, postings, 1)
+
+	ingested.Document.Settings = []byte(`{}`)
+	res, err := s.Ingest(ctx, database.Ingestion{Posting: &database.CreatePostingParams{Title: "Rust Developer"}, Words: words("rust"), Document: ingested})
+	require.NoError(t, err)
+	require.True(t, res.DocumentID.Valid)
+	docs, err = s.ListOcrDocuments(ctx, res.PostingID.Int64)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, res.DocumentID.Int64, docs[0].ID)
+	require.Equal(t, text("ingested"), docs[0].ImageHash)
+}
@@ -0,0 +1,102 @@
+// Package textsearch approximates trigram similarity of pg_trgm and full text search of
+// Postgres for storages without them.
+//
+// Full text search is approximated, as text search configurations of Postgres aren't
+// emulated. Values match if they contain every word of a query, without stemming, and
+// they're ranked by the share of their words in the query.
+package textsearch
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SimilarityThreshold is the default threshold of the % operator of pg_trgm.
+const SimilarityThreshold = 0.3
+
+// trigrams returns trigrams of s like pg_trgm does. Words of s are its runs of letters and
+// digits, lowercased and padded with two spaces before and a space after.
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range Words(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+
+	return set
+}
+
+// Similarity is the similarity function of pg_trgm, the number of shared trigrams of
+// a and b divided by the number of their distinct trigrams.
+func Similarity(a, b string) float32 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+
+	return float32(shared) / float32(len(ta)+len(tb)-shared)
+}
+
+// Words returns lowercased runs of letters and digits of s, which are words of text search.
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Rank returns share of words of value which are words of query, zero if value misses
+// any word of query.
+func Rank(value []string, query []string) float32 {
+	if len(query) == 0 {
+		return 0
+	}
+	for _, w := range query {
+		if !slices.Contains(value, w) {
+			return 0
+		}
+	}
+	matched := 0
+	for _, w := range value {
+		if slices.Contains(query, w) {
+			matched++
+		}
+	}
+
+	return float32(matched) / float32(len(value))
+}
+
+// Headline marks words of value which are words of query, like TS_HEADLINE with
+// HighlightAll option.
+func Headline(value string, query []string) string {
+	var b strings.Builder
+	runes := []rune(value)
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+			j++
+		}
+		if j == i {
+			b.WriteRune(runes[i])
+			i++
+
+			continue
+		}
+		word := string(runes[i:j])
+		if slices.Contains(query, strings.ToLower(word)) {
+			word = "<mark>" + word + "</mark>"
+		}
+		b.WriteString(word)
+		i = j
+	}
+
+	return b.String()
+}
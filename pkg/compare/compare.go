@@ -0,0 +1,183 @@
+// Package compare finds terms whose frequency differs between two sets of batches.
+// Differences are scored with log-odds ratio and chi-square test.
+package compare
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Status of a term in set B relative to set A.
+type Status string
+
+const (
+	Appeared    Status = "appeared"
+	Disappeared Status = "disappeared"
+	Rose        Status = "rose"
+	Fell        Status = "fell"
+)
+
+var ErrUnknownStatus = errors.New("unknown status")
+
+// ParseStatus returns status named s. Empty s is a valid, empty status.
+func ParseStatus(s string) (Status, error) {
+	switch st := Status(strings.ToLower(s)); st {
+	case "", Appeared, Disappeared, Rose, Fell:
+		return st, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownStatus, s)
+	}
+}
+
+// Term counted in both sets.
+type Term struct {
+	Term string `json:"term"`
+	A    int64  `json:"a"`
+	B    int64  `json:"b"`
+	// Occurrences per 10 000 terms of a set.
+	FreqA float64 `json:"freq_a"`
+	FreqB float64 `json:"freq_b"`
+	// Z-score of log-odds ratio, positive if term is more frequent in B.
+	LogOdds float64 `json:"log_odds"`
+	// Chi-square statistic of 2x2 contingency table (term, other terms) x (A, B).
+	ChiSquare float64 `json:"chi_square"`
+	Status    Status  `json:"status"`
+}
+
+// Options of comparison.
+type Options struct {
+	// Minimal count of a term in the set it appeared in or disappeared from.
+	// Terms rarer than that in both sets are skipped.
+	MinCount int64
+	// Minimal absolute log-odds z-score of a term which rose or fell. 1.96 is 95% confidence.
+	MinScore float64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MinCount: 2,
+		MinScore: 1.96,
+	}
+}
+
+// Result of comparison.
+type Result struct {
+	// Terms in set A and set B.
+	TotalA int64  `json:"total_a"`
+	TotalB int64  `json:"total_b"`
+	Terms  []Term `json:"terms"`
+}
+
+// Filter returns terms of status, all terms if status is empty.
+func (r Result) Filter(status Status) []Term {
+	if status == "" {
+		return r.Terms
+	}
+
+	return slices.DeleteFunc(slices.Clone(r.Terms), func(t Term) bool {
+		return t.Status != status
+	})
+}
+
+// Compare counts of terms in set a to counts in set b. Only terms which appeared,
+// disappeared or significantly changed are returned, ordered by absolute log-odds score.
+func Compare(a, b map[string]int64, opts Options) Result {
+	res := Result{
+		TotalA: sum(a),
+		TotalB: sum(b),
+		Terms:  make([]Term, 0),
+	}
+
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, counts := range []map[string]int64{a, b} {
+		for term := range counts {
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+
+			t := Term{
+				Term:      term,
+				A:         a[term],
+				B:         b[term],
+				FreqA:     perTenThousand(a[term], res.TotalA),
+				FreqB:     perTenThousand(b[term], res.TotalB),
+				LogOdds:   logOdds(a[term], res.TotalA, b[term], res.TotalB),
+				ChiSquare: chiSquare(a[term], res.TotalA, b[term], res.TotalB),
+			}
+			switch {
+			case max(t.A, t.B) < opts.MinCount:
+				continue
+			case t.A == 0:
+				t.Status = Appeared
+			case t.B == 0:
+				t.Status = Disappeared
+			case t.LogOdds >= opts.MinScore:
+				t.Status = Rose
+			case t.LogOdds <= -opts.MinScore:
+				t.Status = Fell
+			default:
+				continue
+			}
+			res.Terms = append(res.Terms, t)
+		}
+	}
+	slices.SortFunc(res.Terms, func(x, y Term) int {
+		if c := cmp.Compare(math.Abs(y.LogOdds), math.Abs(x.LogOdds)); c != 0 {
+			return c
+		}
+
+		return strings.Compare(x.Term, y.Term)
+	})
+
+	return res
+}
+
+func sum(counts map[string]int64) int64 {
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+
+	return total
+}
+
+func perTenThousand(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(n) / float64(total) * 10000
+}
+
+// logOdds returns z-score of log-odds ratio of term counted a times in set of totalA
+// terms and b times in set of totalB terms. Counts are smoothed by 0.5 so that terms
+// missing from one of the sets have finite score.
+func logOdds(a, totalA, b, totalB int64) float64 {
+	const prior = 0.5
+
+	ya, na := float64(a)+prior, float64(totalA-a)+prior
+	yb, nb := float64(b)+prior, float64(totalB-b)+prior
+
+	delta := math.Log(yb/nb) - math.Log(ya/na)
+	variance := 1/ya + 1/na + 1/yb + 1/nb
+
+	return delta / math.Sqrt(variance)
+}
+
+// chiSquare returns chi-square statistic of 2x2 table of term and other terms in both sets.
+func chiSquare(a, totalA, b, totalB int64) float64 {
+	n := float64(totalA + totalB)
+	term := float64(a + b)
+	other := n - term
+	if term == 0 || other == 0 || totalA == 0 || totalB == 0 {
+		return 0
+	}
+	d := float64(a)*float64(totalB-b) - float64(b)*float64(totalA-a)
+
+	return n * d * d / (term * other * float64(totalA) * float64(totalB))
+}
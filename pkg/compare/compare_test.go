@@ -0,0 +1,86 @@
+package compare
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	a := map[string]int64{
+		"java":   100,
+		"go":     20,
+		"php":    30,
+		"docker": 50,
+		"perl":   1,
+	}
+	b := map[string]int64{
+		"java":   20,
+		"go":     100,
+		"docker": 50,
+		"rust":   10,
+		"zig":    1,
+	}
+
+	res := Compare(a, b, DefaultOptions())
+	require.Equal(t, int64(201), res.TotalA)
+	require.Equal(t, int64(181), res.TotalB)
+
+	statuses := make(map[string]Status)
+	for _, term := range res.Terms {
+		statuses[term.Term] = term.Status
+	}
+	require.Equal(t, map[string]Status{
+		"go":   Rose,
+		"java": Fell,
+		"php":  Disappeared,
+		"rust": Appeared,
+	}, statuses)
+
+	for i := 1; i < len(res.Terms); i++ {
+		require.GreaterOrEqual(t, math.Abs(res.Terms[i-1].LogOdds), math.Abs(res.Terms[i].LogOdds))
+	}
+
+	require.Equal(t, []Term{res.Terms[indexOf(res.Terms, "rust")]}, res.Filter(Appeared))
+	require.Len(t, res.Filter(""), 4)
+}
+
+func TestCompareScores(t *testing.T) {
+	t.Parallel()
+
+	res := Compare(
+		map[string]int64{"go": 10, "java": 90},
+		map[string]int64{"go": 30, "java": 70},
+		Options{MinScore: 0},
+	)
+	term := res.Terms[indexOf(res.Terms, "go")]
+	require.Greater(t, term.LogOdds, 0.0)
+	require.InDelta(t, 1000, term.FreqA, 0.0001)
+	require.InDelta(t, 3000, term.FreqB, 0.0001)
+	// 200 * (10 * 70 - 30 * 90)^2 / (40 * 160 * 100 * 100)
+	require.InDelta(t, 12.5, term.ChiSquare, 0.0001)
+}
+
+func TestParseStatus(t *testing.T) {
+	t.Parallel()
+
+	s, err := ParseStatus("Rose")
+	require.NoError(t, err)
+	require.Equal(t, Rose, s)
+
+	_, err = ParseStatus("grew")
+	require.ErrorIs(t, err, ErrUnknownStatus)
+}
+
+func indexOf(terms []Term, term string) int {
+	for i, t := range terms {
+		if t.Term == term {
+			return i
+		}
+	}
+
+	return -1
+}
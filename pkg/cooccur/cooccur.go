@@ -0,0 +1,149 @@
+// Package cooccur counts how often words appear together in the same batch
+// and scores pairs with support, lift and Jaccard index.
+package cooccur
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Pair of words appearing together in Count batches.
+type Pair struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Count  int    `json:"count"`
+	// Share of all batches containing both words.
+	Support float64 `json:"support"`
+	// How many times more often words appear together than if they were independent.
+	Lift float64 `json:"lift"`
+	// Batches containing both words divided by batches containing any of them.
+	Jaccard float64 `json:"jaccard"`
+}
+
+// Metric pairs are ordered by.
+type Metric string
+
+const (
+	MetricCount   Metric = "count"
+	MetricSupport Metric = "support"
+	MetricLift    Metric = "lift"
+	MetricJaccard Metric = "jaccard"
+)
+
+var ErrUnknownMetric = errors.New("unknown metric")
+
+// ParseMetric returns metric named s, MetricCount if s is empty.
+func ParseMetric(s string) (Metric, error) {
+	switch m := Metric(strings.ToLower(s)); m {
+	case "":
+		return MetricCount, nil
+	case MetricCount, MetricSupport, MetricLift, MetricJaccard:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownMetric, s)
+	}
+}
+
+func (m Metric) value(p Pair) float64 {
+	switch m {
+	case MetricSupport:
+		return p.Support
+	case MetricLift:
+		return p.Lift
+	case MetricJaccard:
+		return p.Jaccard
+	default:
+		return float64(p.Count)
+	}
+}
+
+// Matrix counts words and pairs of words appearing in the same batch.
+type Matrix struct {
+	batches int
+	counts  map[string]int
+	pairs   map[[2]string]int
+}
+
+// New creates matrix from sets of words of every batch. Words repeated in a set are counted once.
+func New(sets [][]string) *Matrix {
+	m := &Matrix{
+		counts: make(map[string]int),
+		pairs:  make(map[[2]string]int),
+	}
+	for _, set := range sets {
+		m.Add(set)
+	}
+
+	return m
+}
+
+// Add counts words of a single batch.
+func (m *Matrix) Add(set []string) {
+	words := slices.Clone(set)
+	slices.Sort(words)
+	words = slices.Compact(words)
+	words = slices.DeleteFunc(words, func(w string) bool { return w == "" })
+
+	m.batches++
+	for i, a := range words {
+		m.counts[a]++
+		for _, b := range words[i+1:] {
+			m.pairs[[2]string{a, b}]++
+		}
+	}
+}
+
+// Batches returns number of batches counted.
+func (m *Matrix) Batches() int {
+	return m.batches
+}
+
+// Count returns number of batches containing word.
+func (m *Matrix) Count(word string) int {
+	return m.counts[word]
+}
+
+// Pairs returns pairs of words appearing together in at least minCount batches,
+// ordered by metric descending, then by source and target.
+func (m *Matrix) Pairs(minCount int, metric Metric) []Pair {
+	pairs := make([]Pair, 0)
+	for k, together := range m.pairs {
+		if together < minCount {
+			continue
+		}
+		pairs = append(pairs, m.pair(k[0], k[1], together))
+	}
+
+	slices.SortFunc(pairs, func(a, b Pair) int {
+		va, vb := metric.value(a), metric.value(b)
+		switch {
+		case va > vb:
+			return -1
+		case va < vb:
+			return 1
+		}
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Target, b.Target)
+	})
+
+	return pairs
+}
+
+func (m *Matrix) pair(a, b string, together int) Pair {
+	ca, cb := m.counts[a], m.counts[b]
+	total := float64(m.batches)
+
+	return Pair{
+		Source:  a,
+		Target:  b,
+		Count:   together,
+		Support: float64(together) / total,
+		Lift:    float64(together) * total / float64(ca*cb),
+		Jaccard: float64(together) / float64(ca+cb-together),
+	}
+}
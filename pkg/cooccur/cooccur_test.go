@@ -0,0 +1,69 @@
+package cooccur
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testMatrix() *Matrix {
+	return New([][]string{
+		{"go", "kubernetes", "grpc"},
+		{"go", "kubernetes", "go"},
+		{"python", "django"},
+		{"python", "django", "kubernetes"},
+	})
+}
+
+func TestMatrixPairs(t *testing.T) {
+	t.Parallel()
+
+	m := testMatrix()
+	require.Equal(t, 4, m.Batches())
+	require.Equal(t, 3, m.Count("kubernetes"))
+
+	pairs := m.Pairs(2, MetricCount)
+	require.Len(t, pairs, 2)
+
+	p := pairs[1]
+	require.Equal(t, "go", p.Source)
+	require.Equal(t, "kubernetes", p.Target)
+	require.Equal(t, 2, p.Count)
+	require.InDelta(t, 0.5, p.Support, 0.0001)
+	// 2 * 4 / (2 * 3)
+	require.InDelta(t, 4.0/3.0, p.Lift, 0.0001)
+	// 2 / (2 + 3 - 2)
+	require.InDelta(t, 2.0/3.0, p.Jaccard, 0.0001)
+}
+
+func TestMatrixPairsOrderedByMetric(t *testing.T) {
+	t.Parallel()
+
+	m := testMatrix()
+
+	// Pairs with equal metric are ordered by words
+	byCount := m.Pairs(1, MetricCount)
+	require.Equal(t, "django", byCount[0].Source)
+	require.Equal(t, "go", byCount[1].Source)
+
+	// django and python always appear together, so their jaccard index is the highest
+	byJaccard := m.Pairs(1, MetricJaccard)
+	require.Equal(t, "django", byJaccard[0].Source)
+	require.Equal(t, "python", byJaccard[0].Target)
+	require.InDelta(t, 1.0, byJaccard[0].Jaccard, 0.0001)
+}
+
+func TestParseMetric(t *testing.T) {
+	t.Parallel()
+
+	m, err := ParseMetric("")
+	require.NoError(t, err)
+	require.Equal(t, MetricCount, m)
+
+	m, err = ParseMetric("Lift")
+	require.NoError(t, err)
+	require.Equal(t, MetricLift, m)
+
+	_, err = ParseMetric("pagerank")
+	require.ErrorIs(t, err, ErrUnknownMetric)
+}
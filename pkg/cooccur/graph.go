@@ -0,0 +1,209 @@
+package cooccur
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Node is a word of the graph with number of batches it appears in.
+type Node struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"`
+}
+
+// Graph of words connected by pairs appearing together.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Pair `json:"edges"`
+}
+
+// Graph returns graph of pairs and words they connect.
+func (m *Matrix) Graph(pairs []Pair) Graph {
+	ids := make([]string, 0, len(pairs)*2)
+	for _, p := range pairs {
+		ids = append(ids, p.Source, p.Target)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	nodes := make([]Node, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, Node{ID: id, Count: m.counts[id]})
+	}
+
+	return Graph{Nodes: nodes, Edges: pairs}
+}
+
+// Format of an exported graph.
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatGraphML Format = "graphml"
+	FormatDOT     Format = "dot"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ParseFormat returns format named s.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatJSON, FormatGraphML, FormatDOT:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+	}
+}
+
+// ContentType returns MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatGraphML:
+		return "application/graphml+xml"
+	case FormatDOT:
+		return "text/vnd.graphviz"
+	default:
+		return "application/json"
+	}
+}
+
+// Write writes graph g to w in format f.
+func Write(w io.Writer, g Graph, f Format) error {
+	switch f {
+	case FormatJSON:
+		return WriteJSON(w, g)
+	case FormatGraphML:
+		return WriteGraphML(w, g)
+	case FormatDOT:
+		return WriteDOT(w, g)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+}
+
+// WriteJSON writes graph as a JSON object with nodes and edges.
+func WriteJSON(w io.Writer, g Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// WriteGraphML writes graph as an undirected GraphML document, readable by Gephi, yEd or Cytoscape.
+func WriteGraphML(w io.Writer, g Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "n_count", For: "node", Name: "count", Type: "int"},
+			{ID: "e_count", For: "edge", Name: "count", Type: "int"},
+			{ID: "e_support", For: "edge", Name: "support", Type: "double"},
+			{ID: "e_lift", For: "edge", Name: "lift", Type: "double"},
+			{ID: "e_jaccard", For: "edge", Name: "jaccard", Type: "double"},
+		},
+	}
+	doc.Graph.ID = "cooccurrence"
+	doc.Graph.EdgeDefault = "undirected"
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   n.ID,
+			Data: []graphMLData{{Key: "n_count", Value: strconv.Itoa(n.Count)}},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.Source,
+			Target: e.Target,
+			Data: []graphMLData{
+				{Key: "e_count", Value: strconv.Itoa(e.Count)},
+				{Key: "e_support", Value: formatFloat(e.Support)},
+				{Key: "e_lift", Value: formatFloat(e.Lift)},
+				{Key: "e_jaccard", Value: formatFloat(e.Jaccard)},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode xml: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDOT writes graph in Graphviz DOT language. Edge weight is the number of batches
+// both words appear in.
+func WriteDOT(w io.Writer, g Graph) error {
+	b := new(strings.Builder)
+	b.WriteString("graph cooccurrence {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(b, "  %s [count=%d];\n", strconv.Quote(n.ID), n.Count)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(b, "  %s -- %s [weight=%d, support=%s, lift=%s, jaccard=%s];\n",
+			strconv.Quote(e.Source), strconv.Quote(e.Target), e.Count,
+			formatFloat(e.Support), formatFloat(e.Lift), formatFloat(e.Jaccard),
+		)
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,73 @@
+package cooccur
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGraph(t *testing.T) {
+	t.Parallel()
+
+	m := testMatrix()
+	// Skip django and python pair, it has the highest lift
+	g := m.Graph(m.Pairs(2, MetricLift)[1:])
+	require.Equal(t, []Node{{ID: "go", Count: 2}, {ID: "kubernetes", Count: 3}}, g.Nodes)
+
+	testCases := []struct {
+		desc   string
+		format Format
+		check  func(t *testing.T, data []byte)
+	}{
+		{
+			desc:   "json",
+			format: FormatJSON,
+			check: func(t *testing.T, data []byte) {
+				var got Graph
+				require.NoError(t, json.Unmarshal(data, &got))
+				require.Equal(t, g, got)
+			},
+		},
+		{
+			desc:   "graphml",
+			format: FormatGraphML,
+			check: func(t *testing.T, data []byte) {
+				var doc graphML
+				require.NoError(t, xml.Unmarshal(data, &doc))
+				require.Len(t, doc.Graph.Nodes, 2)
+				require.Len(t, doc.Graph.Edges, 1)
+				require.Equal(t, "go", doc.Graph.Edges[0].Source)
+			},
+		},
+		{
+			desc:   "dot",
+			format: FormatDOT,
+			check: func(t *testing.T, data []byte) {
+				require.Contains(t, string(data), `"go" -- "kubernetes" [weight=2, support=0.5000, lift=1.3333, jaccard=0.6667];`)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := new(bytes.Buffer)
+			require.NoError(t, Write(buf, g, tC.format))
+			tC.check(t, buf.Bytes())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFormat("GraphML")
+	require.NoError(t, err)
+	require.Equal(t, FormatGraphML, f)
+
+	_, err = ParseFormat("svg")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
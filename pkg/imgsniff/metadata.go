@@ -0,0 +1,45 @@
+package imgsniff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register jpeg decoder
+	_ "image/png"  // Register png decoder
+)
+
+// Metadata of an image.
+type Metadata struct {
+	// Hex encoded SHA-256 of content.
+	Hash   string `json:"hash"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// Format name, "png" or "jpeg".
+	Format string `json:"format"`
+	Size   int64  `json:"size"`
+}
+
+// Hash returns hex encoded SHA-256 of content.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Inspect returns metadata of a png or jpeg image without decoding whole image.
+func Inspect(content []byte) (Metadata, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("decode image config: %w", err)
+	}
+
+	return Metadata{
+		Hash:   Hash(content),
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Format: format,
+		Size:   int64(len(content)),
+	}, nil
+}
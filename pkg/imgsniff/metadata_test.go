@@ -0,0 +1,39 @@
+package imgsniff
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		Hash(nil),
+	)
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, image.NewGray(image.Rect(0, 0, 32, 16))))
+	content := buf.Bytes()
+
+	m, err := Inspect(content)
+	require.NoError(t, err)
+	require.Equal(t, 32, m.Width)
+	require.Equal(t, 16, m.Height)
+	require.Equal(t, "png", m.Format)
+	require.Equal(t, int64(len(content)), m.Size)
+	require.Equal(t, Hash(content), m.Hash)
+	require.Len(t, m.Hash, 64)
+
+	_, err = Inspect([]byte("not an image"))
+	require.Error(t, err)
+}
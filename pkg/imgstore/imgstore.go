@@ -0,0 +1,112 @@
+// Package imgstore keeps content of captured images, so they can be scanned again
+// with a better OCR setup later.
+package imgstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mode tells where image content is kept.
+type Mode string
+
+const (
+	// Content isn't kept, only image metadata is stored.
+	ModeNone Mode = "none"
+	// Content is stored in the database.
+	ModePostgres Mode = "postgres"
+	// Content is written to a file in a directory.
+	ModeDisk Mode = "disk"
+)
+
+var (
+	ErrUnknownMode = errors.New("unknown image storage mode")
+	ErrNotStored   = errors.New("image content not stored")
+)
+
+// ParseMode returns mode named s, ModeNone if s is empty.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case "":
+		return ModeNone, nil
+	case ModeNone, ModePostgres, ModeDisk:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownMode, s)
+	}
+}
+
+// Store decides where content of images is kept. Nil store keeps nothing.
+type Store struct {
+	mode Mode
+	dir  string
+}
+
+// New creates store keeping images according to mode. Dir is required by ModeDisk only.
+func New(mode Mode, dir string) (*Store, error) {
+	if _, err := ParseMode(string(mode)); err != nil {
+		return nil, err
+	}
+	if mode == ModeDisk && dir == "" {
+		return nil, fmt.Errorf("%w: disk storage requires a directory", ErrUnknownMode)
+	}
+
+	return &Store{mode: mode, dir: filepath.Clean(dir)}, nil
+}
+
+// Mode returns mode of the store.
+func (s *Store) Mode() Mode {
+	if s == nil || s.mode == "" {
+		return ModeNone
+	}
+
+	return s.mode
+}
+
+// Stored content of an image. Content is set if image should be stored in the database,
+// Path if it was written to disk. Both are empty if content isn't kept.
+type Stored struct {
+	Content []byte
+	Path    string
+}
+
+// Put keeps content of image identified by hash. File written to disk is named
+// <hash>.<format>, so storing the same image again overwrites it.
+func (s *Store) Put(hash, format string, content []byte) (Stored, error) {
+	switch s.Mode() {
+	case ModePostgres:
+		return Stored{Content: content}, nil
+	case ModeDisk:
+		if err := os.MkdirAll(s.dir, 0o750); err != nil {
+			return Stored{}, fmt.Errorf("create image dir: %w", err)
+		}
+		path := filepath.Join(s.dir, hash+"."+format)
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			return Stored{}, fmt.Errorf("write image: %w", err)
+		}
+
+		return Stored{Path: path}, nil
+	default:
+		return Stored{}, nil
+	}
+}
+
+// Load returns stored content of an image, reading it from path if content
+// wasn't stored in the database.
+func Load(stored Stored) ([]byte, error) {
+	if len(stored.Content) > 0 {
+		return stored.Content, nil
+	}
+	if stored.Path == "" {
+		return nil, ErrNotStored
+	}
+	content, err := os.ReadFile(filepath.Clean(stored.Path))
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+
+	return content, nil
+}
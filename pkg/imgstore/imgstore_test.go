@@ -0,0 +1,92 @@
+package imgstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePut(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("image")
+	dir := t.TempDir()
+
+	testCases := []struct {
+		desc string
+
+		mode        Mode
+		wantContent []byte
+		wantPath    string
+	}{
+		{
+			desc: "none_keeps_nothing",
+
+			mode: ModeNone,
+		},
+		{
+			desc: "postgres_keeps_content",
+
+			mode:        ModePostgres,
+			wantContent: content,
+		},
+		{
+			desc: "disk_writes_file_named_by_hash",
+
+			mode:     ModeDisk,
+			wantPath: filepath.Join(dir, "abc.png"),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := New(tC.mode, dir)
+			require.NoError(t, err)
+
+			stored, err := s.Put("abc", "png", content)
+			require.NoError(t, err)
+			require.Equal(t, tC.wantContent, stored.Content)
+			require.Equal(t, tC.wantPath, stored.Path)
+
+			loaded, err := Load(stored)
+			if tC.mode == ModeNone {
+				require.ErrorIs(t, err, ErrNotStored)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, content, loaded)
+		})
+	}
+}
+
+func TestNilStoreKeepsNothing(t *testing.T) {
+	t.Parallel()
+
+	var s *Store
+	require.Equal(t, ModeNone, s.Mode())
+
+	stored, err := s.Put("abc", "png", []byte("image"))
+	require.NoError(t, err)
+	require.Equal(t, Stored{}, stored)
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	m, err := ParseMode("")
+	require.NoError(t, err)
+	require.Equal(t, ModeNone, m)
+
+	m, err = ParseMode("Disk")
+	require.NoError(t, err)
+	require.Equal(t, ModeDisk, m)
+
+	_, err = ParseMode("s3")
+	require.ErrorIs(t, err, ErrUnknownMode)
+
+	_, err = New(ModeDisk, "")
+	require.ErrorIs(t, err, ErrUnknownMode)
+}
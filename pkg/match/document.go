@@ -0,0 +1,57 @@
+package match
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Kind of a CV document.
+type Kind string
+
+const (
+	KindText  Kind = "text"
+	KindPDF   Kind = "pdf"
+	KindImage Kind = "image"
+)
+
+var ErrUnsupportedDocument = errors.New("unsupported document")
+
+// DetectKind returns kind of document content.
+func DetectKind(content []byte) (Kind, error) {
+	contentType := http.DetectContentType(content)
+	switch {
+	case contentType == "application/pdf":
+		return KindPDF, nil
+	case contentType == "image/png", contentType == "image/jpeg":
+		return KindImage, nil
+	case strings.HasPrefix(contentType, "text/plain") && utf8.Valid(content):
+		return KindText, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedDocument, contentType)
+	}
+}
+
+// PDFText returns plain text of all pages of PDF content.
+func PDFText(content []byte) (string, error) {
+	r, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", fmt.Errorf("new pdf reader: %w", err)
+	}
+	text, err := r.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("get plain text: %w", err)
+	}
+	data, err := io.ReadAll(text)
+	if err != nil {
+		return "", fmt.Errorf("read all: %w", err)
+	}
+
+	return string(data), nil
+}
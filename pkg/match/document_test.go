@@ -0,0 +1,53 @@
+package match
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectKind(t *testing.T) {
+	t.Parallel()
+
+	pdfContent, err := os.ReadFile(filepath.Join("testdata", "cv.pdf"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc    string
+		content []byte
+		kind    Kind
+		err     error
+	}{
+		{desc: "text", content: []byte("Go developer, 5 years of experience"), kind: KindText},
+		{desc: "pdf", content: pdfContent, kind: KindPDF},
+		{desc: "png", content: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), kind: KindImage},
+		{desc: "binary", content: []byte{0x00, 0x01, 0x02, 0xFF}, err: ErrUnsupportedDocument},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			kind, err := DetectKind(tC.content)
+			if tC.err != nil {
+				require.ErrorIs(t, err, tC.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tC.kind, kind)
+		})
+	}
+}
+
+func TestPDFText(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "cv.pdf"))
+	require.NoError(t, err)
+
+	text, err := PDFText(content)
+	require.NoError(t, err)
+	require.Contains(t, text, "Golang, Kubernetes, PostgreSQL")
+}
@@ -0,0 +1,146 @@
+// Package match compares skills of a CV to skills the market asks for in job postings.
+package match
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+)
+
+// Skill asked for in Postings batches.
+type Skill struct {
+	Skill    string `json:"skill"`
+	Postings int64  `json:"postings"`
+	// Percentage of all batches asking for the skill.
+	Share float64 `json:"share"`
+}
+
+// Market is demand for skills in a set of batches.
+type Market struct {
+	Batches int64
+	// Skills ordered by postings, most demanded first.
+	Skills []Skill
+}
+
+// NewMarket creates market of batches from postings of words. Words which aren't known
+// skills are left out. Aliases of skills are merged under their canonical name, postings
+// of merged words are summed and capped at batches.
+func NewMarket(batches int64, postings map[string]int64) Market {
+	merged := make(map[string]int64, len(postings))
+	for word, n := range postings {
+		if textproc.IsKnownSkill(word) {
+			merged[textproc.CanonicalSkill(word)] += n
+		}
+	}
+
+	m := Market{
+		Batches: batches,
+		Skills:  make([]Skill, 0, len(merged)),
+	}
+	for skill, n := range merged {
+		n = min(n, batches)
+		s := Skill{Skill: skill, Postings: n}
+		if batches > 0 {
+			s.Share = float64(n) / float64(batches) * 100
+		}
+		m.Skills = append(m.Skills, s)
+	}
+	slices.SortFunc(m.Skills, func(a, b Skill) int {
+		if c := cmp.Compare(b.Postings, a.Postings); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Skill, b.Skill)
+	})
+
+	return m
+}
+
+// Skill returns demand for skill, zero skill if market doesn't ask for it.
+func (m Market) Skill(skill string) Skill {
+	for _, s := range m.Skills {
+		if s.Skill == skill {
+			return s
+		}
+	}
+
+	return Skill{Skill: skill}
+}
+
+// Options of matching.
+type Options struct {
+	// Number of most demanded skills coverage is computed for.
+	Top int
+	// Maximal number of reported missing skills.
+	Missing int
+	// Skills of CV asked for in less than RareShare percent of batches are rare.
+	RareShare float64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Top:       30,
+		Missing:   10,
+		RareShare: 2,
+	}
+}
+
+// Report of CV matched against market.
+type Report struct {
+	Batches int64 `json:"batches"`
+	// Percentage of demand for top skills covered by CV. Skills are weighted by share.
+	Coverage float64 `json:"coverage"`
+	// Top skills in CV, most demanded first.
+	Matched []Skill `json:"matched"`
+	// Top skills missing from CV, most demanded first.
+	Missing []Skill `json:"missing"`
+	// Skills of CV the market rarely asks for, rarest first.
+	Rare []Skill `json:"rare"`
+}
+
+// Match words of a CV against market. Only known skills are skills of CV, aliases are
+// canonicalized.
+func Match(words []string, m Market, opts Options) Report {
+	cv := make(map[string]bool)
+	for _, skill := range textproc.Skills(words) {
+		cv[skill] = true
+	}
+
+	r := Report{
+		Batches: m.Batches,
+		Matched: make([]Skill, 0),
+		Missing: make([]Skill, 0),
+		Rare:    make([]Skill, 0),
+	}
+
+	var demand, covered float64
+	for _, s := range m.Skills[:min(opts.Top, len(m.Skills))] {
+		demand += s.Share
+		if cv[s.Skill] {
+			covered += s.Share
+			r.Matched = append(r.Matched, s)
+		} else if len(r.Missing) < opts.Missing {
+			r.Missing = append(r.Missing, s)
+		}
+	}
+	if demand > 0 {
+		r.Coverage = covered / demand * 100
+	}
+
+	for skill := range cv {
+		if s := m.Skill(skill); s.Share < opts.RareShare {
+			r.Rare = append(r.Rare, s)
+		}
+	}
+	slices.SortFunc(r.Rare, func(a, b Skill) int {
+		if c := cmp.Compare(a.Share, b.Share); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Skill, b.Skill)
+	})
+
+	return r
+}
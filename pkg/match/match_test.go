@@ -0,0 +1,72 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testMarket() Market {
+	return NewMarket(10, map[string]int64{
+		"go":         8,
+		"golang":     1,
+		"kubernetes": 6,
+		"docker":     5,
+		"aws":        4,
+		"cobol":      0,
+		"perl":       1,
+		"experience": 10,
+		"team":       7,
+	})
+}
+
+func TestNewMarket(t *testing.T) {
+	t.Parallel()
+
+	m := testMarket()
+	require.Equal(t, int64(10), m.Batches)
+	require.Equal(t, Skill{Skill: "go", Postings: 9, Share: 90}, m.Skills[0])
+	require.Equal(t, "kubernetes", m.Skills[1].Skill)
+	require.Equal(t, Skill{Skill: "rust"}, m.Skill("rust"))
+	// Generic words and unknown "cobol" aren't skills
+	require.Len(t, m.Skills, 5)
+	require.Equal(t, Skill{Skill: "experience"}, m.Skill("experience"))
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Top: 4, Missing: 1, RareShare: 15}
+	r := Match([]string{"Golang", "k8s", "Perl", "rust", "university"}, testMarket(), opts)
+
+	require.Equal(t, int64(10), r.Batches)
+	// (90 + 60) / (90 + 60 + 50 + 40)
+	require.InDelta(t, 62.5, r.Coverage, 0.0001)
+	require.Equal(t, []Skill{
+		{Skill: "go", Postings: 9, Share: 90},
+		{Skill: "kubernetes", Postings: 6, Share: 60},
+	}, r.Matched)
+	require.Equal(t, []Skill{{Skill: "docker", Postings: 5, Share: 50}}, r.Missing)
+	require.Equal(t, []Skill{
+		{Skill: "rust"},
+		{Skill: "perl", Postings: 1, Share: 10},
+	}, r.Rare)
+}
+
+func TestMatchKnownSkillMissingFromMarket(t *testing.T) {
+	t.Parallel()
+
+	r := Match([]string{"Python", "team"}, testMarket(), DefaultOptions())
+	require.Empty(t, r.Matched)
+	require.Equal(t, []Skill{{Skill: "python"}}, r.Rare)
+}
+
+func TestMatchGenericWords(t *testing.T) {
+	t.Parallel()
+
+	r := Match([]string{"Go", "experience", "team"}, testMarket(), DefaultOptions())
+	require.Equal(t, []Skill{{Skill: "go", Postings: 9, Share: 90}}, r.Matched)
+	for _, s := range append(r.Missing, r.Rare...) {
+		require.NotContains(t, []string{"experience", "team"}, s.Skill)
+	}
+}
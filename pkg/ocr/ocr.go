@@ -7,11 +7,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kndrad/piccrack/pkg/imgsniff"
 	"github.com/kndrad/piccrack/pkg/pproc"
+	"github.com/kndrad/piccrack/pkg/textproc"
 	"github.com/otiai10/gosseract/v2"
 )
 
@@ -29,6 +32,34 @@ func NewClient() *gosseract.Client {
 
 var ErrNotAnImage = errors.New("not an image")
 
+// Engine is name of OCR engine recognizing text.
+const Engine = "tesseract"
+
+// EngineVersion returns version of tesseract library.
+func EngineVersion() string {
+	return gosseract.Version()
+}
+
+// Settings of tesseract client text was recognized with.
+type Settings struct {
+	Languages []string          `json:"languages"`
+	Variables map[string]string `json:"variables"`
+	Trim      bool              `json:"trim"`
+}
+
+func clientSettings(tc *gosseract.Client) Settings {
+	vars := make(map[string]string, len(tc.Variables))
+	for k, v := range tc.Variables {
+		vars[string(k)] = v
+	}
+
+	return Settings{
+		Languages: slices.Clone(tc.Languages),
+		Variables: vars,
+		Trim:      tc.Trim,
+	}
+}
+
 // scan is a wrapper around tesseract client with additional content validation
 // performed before returning text.
 func scan(tc *gosseract.Client, content []byte) (string, error) {
@@ -54,6 +85,42 @@ func scan(tc *gosseract.Client, content []byte) (string, error) {
 	return text, nil
 }
 
+// confidences returns lowest confidence of every word recognized in image previously
+// set on tc and mean confidence of all words. Confidences are optional,
+// so nil and zero are returned on failure.
+func confidences(tc *gosseract.Client) (map[string]float64, float64) {
+	boxes, err := tc.GetBoundingBoxes(gosseract.RIL_WORD)
+	if err != nil {
+		return nil, 0
+	}
+
+	return boxConfidences(boxes)
+}
+
+// boxConfidences returns lowest confidence of every word of boxes keyed by its
+// textproc.ConfidenceKey and mean confidence of all words.
+func boxConfidences(boxes []gosseract.BoundingBox) (map[string]float64, float64) {
+	m := make(map[string]float64, len(boxes))
+	var sum float64
+	var n int
+	for _, box := range boxes {
+		word := textproc.ConfidenceKey(box.Word)
+		if word == "" {
+			continue
+		}
+		sum += box.Confidence
+		n++
+		if v, exists := m[word]; !exists || box.Confidence < v {
+			m[word] = box.Confidence
+		}
+	}
+	if n == 0 {
+		return m, 0
+	}
+
+	return m, sum / float64(n)
+}
+
 // ScanFile performs OCR on an image file.
 // Image content validation is performed before ocr.
 func ScanFile(tc *gosseract.Client, path string) (*Result, error) {
@@ -70,18 +137,38 @@ func ScanFile(tc *gosseract.Client, path string) (*Result, error) {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
+	return newResult(tc, path, content)
+}
+
+// newResult scans content and collects confidences, settings and duration of the scan.
+func newResult(tc *gosseract.Client, path string, content []byte) (*Result, error) {
+	start := time.Now()
 	text, err := scan(tc, content)
 	if err != nil {
 		return nil, fmt.Errorf("scan: %w", err)
 	}
+	duration := time.Since(start)
+	words, mean := confidences(tc)
 
-	return &Result{path: path, content: content, text: text}, nil
+	return &Result{
+		path:        path,
+		content:     content,
+		text:        text,
+		confidences: words,
+		confidence:  mean,
+		settings:    clientSettings(tc),
+		duration:    duration,
+	}, nil
 }
 
 type Result struct {
-	path    string
-	content []byte
-	text    string
+	path        string
+	content     []byte
+	text        string
+	confidences map[string]float64
+	confidence  float64
+	settings    Settings
+	duration    time.Duration
 }
 
 func (res *Result) String() string {
@@ -96,6 +183,44 @@ func (res *Result) Text() string {
 	return res.text
 }
 
+// Confidence returns OCR confidence (0-100) of a word found in the text.
+func (res *Result) Confidence(word string) (float64, bool) {
+	if res == nil {
+		return 0, false
+	}
+	v, exists := res.confidences[textproc.ConfidenceKey(word)]
+
+	return v, exists
+}
+
+// MeanConfidence returns mean OCR confidence (0-100) of all recognized words,
+// zero if confidences are unknown.
+func (res *Result) MeanConfidence() float64 {
+	if res == nil {
+		return 0
+	}
+
+	return res.confidence
+}
+
+// Settings returns settings of tesseract client text was recognized with.
+func (res *Result) Settings() Settings {
+	if res == nil {
+		return Settings{}
+	}
+
+	return res.settings
+}
+
+// Duration returns time it took to recognize text.
+func (res *Result) Duration() time.Duration {
+	if res == nil {
+		return 0
+	}
+
+	return res.duration
+}
+
 func (res *Result) Words() <-chan string {
 	var wg sync.WaitGroup
 
@@ -165,16 +290,7 @@ func ScanFrom(tc *gosseract.Client, r io.Reader) (*Result, error) {
 		return nil, fmt.Errorf("read full: %w", err)
 	}
 
-	text, err := scan(tc, content)
-	if err != nil {
-		return nil, fmt.Errorf("scan: %w", err)
-	}
-
-	return &Result{
-		path:    "",
-		text:    text,
-		content: content,
-	}, nil
+	return newResult(tc, "", content)
 }
 
 func readFull(r io.Reader) ([]byte, error) {
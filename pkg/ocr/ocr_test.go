@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/otiai10/gosseract/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,6 +111,25 @@ func TestResultWords(t *testing.T) {
 	}
 }
 
+func TestResultConfidenceOfPunctuatedBoxWord(t *testing.T) {
+	t.Parallel()
+
+	words, mean := boxConfidences([]gosseract.BoundingBox{
+		{Word: "Kubemetes,", Confidence: 40},
+		{Word: "(Docker)", Confidence: 80},
+		{Word: "docker", Confidence: 60},
+	})
+	require.InDelta(t, 60.0, mean, 0.001)
+
+	res := &Result{confidences: words}
+	conf, ok := res.Confidence("Kubemetes")
+	require.True(t, ok)
+	require.InDelta(t, 40.0, conf, 0)
+	conf, ok = res.Confidence("Docker")
+	require.True(t, ok)
+	require.InDelta(t, 60.0, conf, 0)
+}
+
 func TestScanDir(t *testing.T) {
 	t.Parallel()
 
@@ -5,16 +5,29 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/kndrad/piccrack/pkg/ocr"
 	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/pemistahl/lingua-go"
 )
 
 type Phrase struct {
 	value string
+	lang  lingua.Language
+}
+
+// newPhrase creates phrase from a line of text, detecting language of the line.
+func newPhrase(line string) *Phrase {
+	lang, exists := textproc.DetectLanguage(line)
+	if !exists {
+		lang = lingua.Unknown
+	}
+
+	return &Phrase{value: line, lang: lang}
 }
 
 func (ph *Phrase) String() string {
@@ -25,6 +38,28 @@ func (ph *Phrase) String() string {
 	return ph.value
 }
 
+// Language returns language detected for the phrase.
+func (ph *Phrase) Language() lingua.Language {
+	if ph == nil {
+		return lingua.Unknown
+	}
+
+	return ph.lang
+}
+
+// Line returns phrase as a line of text with its language.
+func (ph *Phrase) Line() textproc.Line {
+	return textproc.Line{Text: ph.String(), Language: ph.Language()}
+}
+
+// correctedText returns text of res with OCR misreads corrected.
+func correctedText(res *ocr.Result) string {
+	text, corrections := textproc.DefaultCorrector().CorrectText(res.Text(), res.Confidence)
+	textproc.LogCorrections(slog.Default(), corrections)
+
+	return text
+}
+
 // ScanAt uses ocr client to scan for phrases found in image located at path.
 func ScanAt(ctx context.Context, path string) (<-chan *Phrase, error) {
 	tc := ocr.NewClient()
@@ -38,11 +73,11 @@ func ScanAt(ctx context.Context, path string) (<-chan *Phrase, error) {
 	}
 
 	var wg sync.WaitGroup
-	for line := range textproc.ScanLines(res.Text()) {
+	for line := range textproc.ScanLines(correctedText(res)) {
 		wg.Add(1)
 		go func() {
 			select {
-			case sentences <- &Phrase{line}:
+			case sentences <- newPhrase(line):
 			case <-ctx.Done():
 			}
 			wg.Done()
@@ -78,7 +113,7 @@ func ScanDir(ctx context.Context, dir string) (<-chan *Phrase, error) {
 		return nil, fmt.Errorf("ocr dir: %w", err)
 	}
 	for _, res := range results {
-		texts = append(texts, res.Text())
+		texts = append(texts, correctedText(res))
 	}
 
 	out := make(chan *Phrase)
@@ -88,7 +123,7 @@ func ScanDir(ctx context.Context, dir string) (<-chan *Phrase, error) {
 		wg.Add(1)
 		go func() {
 			for line := range textproc.ScanLines(text) {
-				out <- &Phrase{line}
+				out <- newPhrase(line)
 			}
 			wg.Done()
 		}()
@@ -113,12 +148,12 @@ func ScanReader(ctx context.Context, r io.Reader) (<-chan *Phrase, error) {
 	out := make(chan *Phrase)
 
 	var wg sync.WaitGroup
-	for line := range textproc.ScanLines(res.Text()) {
+	for line := range textproc.ScanLines(correctedText(res)) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			out <- &Phrase{line}
+			out <- newPhrase(line)
 		}()
 	}
 
@@ -0,0 +1,163 @@
+package textproc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Work modes.
+const (
+	WorkModeRemote = "remote"
+	WorkModeHybrid = "hybrid"
+	WorkModeOnSite = "onsite"
+)
+
+// Attributes of a job posting used to slice word statistics.
+// Empty attribute is unknown.
+type Attributes struct {
+	Seniority string `json:"seniority"`
+	Location  string `json:"location"`
+	WorkMode  string `json:"work_mode"`
+}
+
+// ExtractAttributes detects seniority, location and work mode of a posting from its text.
+func ExtractAttributes(text string) Attributes {
+	return Attributes{
+		Seniority: DetectSeniority(text),
+		Location:  DetectLocation(text),
+		WorkMode:  DetectWorkMode(text),
+	}
+}
+
+// cityKeywords maps lowercase city names, including english names and polish inflections,
+// to canonical names. Polish cities use polish names, other cities english names.
+var cityKeywords = map[string]string{
+	"warszawa": "Warszawa", "warszawie": "Warszawa", "warsaw": "Warszawa",
+	"kraków": "Kraków", "krakow": "Kraków", "krakowie": "Kraków", "cracow": "Kraków",
+	"wrocław": "Wrocław", "wroclaw": "Wrocław", "wrocławiu": "Wrocław", "wroclawiu": "Wrocław",
+	"gdańsk": "Gdańsk", "gdansk": "Gdańsk", "gdańsku": "Gdańsk", "gdansku": "Gdańsk",
+	"gdynia": "Gdynia", "gdyni": "Gdynia",
+	"sopot": "Sopot", "sopocie": "Sopot",
+	"poznań": "Poznań", "poznan": "Poznań", "poznaniu": "Poznań",
+	"łódź": "Łódź", "lodz": "Łódź", "łodzi": "Łódź", "lodzi": "Łódź",
+	"katowice": "Katowice", "katowicach": "Katowice",
+	"gliwice": "Gliwice", "gliwicach": "Gliwice",
+	"szczecin": "Szczecin", "szczecinie": "Szczecin",
+	"lublin": "Lublin", "lublinie": "Lublin",
+	"białystok": "Białystok", "bialystok": "Białystok", "białymstoku": "Białystok",
+	"bydgoszcz": "Bydgoszcz", "bydgoszczy": "Bydgoszcz",
+	"toruń": "Toruń", "torun": "Toruń", "toruniu": "Toruń",
+	"rzeszów": "Rzeszów", "rzeszow": "Rzeszów", "rzeszowie": "Rzeszów",
+	"kielce": "Kielce", "kielcach": "Kielce",
+	"olsztyn": "Olsztyn", "olsztynie": "Olsztyn",
+	"opole": "Opole", "opolu": "Opole",
+	"berlin": "Berlin", "berlinie": "Berlin",
+	"london": "London", "londyn": "London", "londynie": "London",
+	"amsterdam": "Amsterdam", "amsterdamie": "Amsterdam",
+	"prague": "Prague", "praga": "Prague", "pradze": "Prague",
+	"vienna": "Vienna", "wiedeń": "Vienna", "wiedniu": "Vienna",
+	"dublin": "Dublin", "dublinie": "Dublin",
+	"lisbon": "Lisbon", "lizbona": "Lisbon", "lizbonie": "Lisbon",
+	"barcelona": "Barcelona", "barcelonie": "Barcelona",
+	"madrid": "Madrid", "madryt": "Madrid", "madrycie": "Madrid",
+	"paris": "Paris", "paryż": "Paris", "paryżu": "Paris",
+	"munich": "Munich", "monachium": "Munich",
+}
+
+// DetectLocation returns canonical name of the city mentioned most often in text,
+// preferring the city mentioned first on ties. Empty string is returned if none is mentioned.
+func DetectLocation(text string) string {
+	return mostMentioned(text, cityKeywords)
+}
+
+// NormalizeLocation returns canonical name of a known city or trimmed location otherwise.
+func NormalizeLocation(location string) string {
+	location = strings.TrimSpace(location)
+	if city, exists := cityKeywords[strings.ToLower(location)]; exists {
+		return city
+	}
+
+	return location
+}
+
+var workModeKeywords = map[string]string{
+	"remote":       WorkModeRemote,
+	"remotely":     WorkModeRemote,
+	"zdalna":       WorkModeRemote,
+	"zdalnie":      WorkModeRemote,
+	"zdalny":       WorkModeRemote,
+	"hybrid":       WorkModeHybrid,
+	"hybrydowa":    WorkModeHybrid,
+	"hybrydowo":    WorkModeHybrid,
+	"hybrydowy":    WorkModeHybrid,
+	"onsite":       WorkModeOnSite,
+	"office":       WorkModeOnSite,
+	"stacjonarna":  WorkModeOnSite,
+	"stacjonarnie": WorkModeOnSite,
+	"biurze":       WorkModeOnSite,
+}
+
+// DetectWorkMode returns work mode mentioned in text. Hybrid postings usually mention
+// both remote work and office, so hybrid wins over remote, and remote wins over on-site.
+func DetectWorkMode(text string) string {
+	text = strings.NewReplacer("on-site", "onsite", "on site", "onsite").Replace(strings.ToLower(text))
+
+	found := make(map[string]bool)
+	for _, token := range tokens(text) {
+		if mode, exists := workModeKeywords[token]; exists {
+			found[mode] = true
+		}
+	}
+
+	for _, mode := range []string{WorkModeHybrid, WorkModeRemote, WorkModeOnSite} {
+		if found[mode] {
+			return mode
+		}
+	}
+
+	return ""
+}
+
+// IsWorkMode reports whether s is a known work mode.
+func IsWorkMode(s string) bool {
+	switch s {
+	case WorkModeRemote, WorkModeHybrid, WorkModeOnSite:
+		return true
+	default:
+		return false
+	}
+}
+
+// mostMentioned returns value of keywords mentioned most often in text, preferring
+// the value mentioned first on ties.
+func mostMentioned(text string, keywords map[string]string) string {
+	counts := make(map[string]int)
+	first := make([]string, 0)
+
+	for _, token := range tokens(text) {
+		value, exists := keywords[token]
+		if !exists {
+			continue
+		}
+		if counts[value] == 0 {
+			first = append(first, value)
+		}
+		counts[value]++
+	}
+
+	result := ""
+	for _, value := range first {
+		if counts[value] > counts[result] {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// tokens returns lowercase words of text made of letters.
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
@@ -0,0 +1,72 @@
+package textproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAttributes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		text  string
+		attrs Attributes
+	}{
+		{
+			desc: "english_posting",
+			text: "Senior Backend Engineer (Go)\nWarsaw, Poland\nHybrid: 2 days in the office, rest remote",
+			attrs: Attributes{
+				Seniority: SenioritySenior,
+				Location:  "Warszawa",
+				WorkMode:  WorkModeHybrid,
+			},
+		},
+		{
+			desc: "polish_posting",
+			text: "Junior Programista Java\nPraca stacjonarna w biurze we Wrocławiu",
+			attrs: Attributes{
+				Seniority: SeniorityJunior,
+				Location:  "Wrocław",
+				WorkMode:  WorkModeOnSite,
+			},
+		},
+		{
+			desc: "remote_without_city",
+			text: "Lead Developer\n100% remote",
+			attrs: Attributes{
+				Seniority: SeniorityLead,
+				WorkMode:  WorkModeRemote,
+			},
+		},
+		{
+			desc: "on_site_with_dash",
+			text: "Mid Go Developer, on-site in Kraków",
+			attrs: Attributes{
+				Seniority: SeniorityMid,
+				Location:  "Kraków",
+				WorkMode:  WorkModeOnSite,
+			},
+		},
+		{
+			desc:  "nothing_mentioned",
+			text:  "Go Developer",
+			attrs: Attributes{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tC.attrs, ExtractAttributes(tC.text))
+		})
+	}
+}
+
+func TestNormalizeLocation(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Kraków", NormalizeLocation(" krakow "))
+	require.Equal(t, "Tczew", NormalizeLocation("Tczew"))
+}
@@ -0,0 +1,385 @@
+package textproc
+
+import (
+	"bufio"
+	_ "embed"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+)
+
+// Tesseract confidence (0-100) at or above which a word is trusted and never corrected.
+const TrustedConfidence = 90.0
+
+// Words shorter than minCorrectLen runes are never corrected, because too many
+// valid short words are one edit away from each other.
+const minCorrectLen = 4
+
+//go:embed vocabulary.txt
+var vocabularyTxt string
+
+// Vocabulary returns words of the embedded vocabulary: tech terms and common english and polish words.
+func Vocabulary() []string {
+	words := make([]string, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(vocabularyTxt))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, strings.ToLower(line))
+	}
+
+	return words
+}
+
+// confusions holds characters tesseract commonly misreads and their likely originals.
+var confusions = []struct {
+	misread  string
+	original string
+}{
+	{"0", "o"},
+	{"1", "l"},
+	{"1", "i"},
+	{"5", "s"},
+	{"8", "b"},
+	{"m", "rn"},
+	{"rn", "m"},
+	{"vv", "w"},
+	{"cl", "d"},
+	{"o", "q"},
+	{"l", "i"},
+	{"i", "l"},
+}
+
+// Correction is a single word changed by the corrector.
+type Correction struct {
+	Original  string `json:"original"`
+	Corrected string `json:"corrected"`
+	// Edit distance between the lowercased original and the corrected word.
+	Distance int `json:"distance"`
+	// OCR confidence of the original word or -1 if unknown.
+	Confidence float64 `json:"confidence"`
+}
+
+// Corrector fixes OCR misreads ("Kubemetes", "Pyth0n", "PostgreSOL") by matching words
+// against a vocabulary of known words. Only high-confidence misreads are corrected:
+// a word is changed only if neither it nor its lemma is in the vocabulary, and either
+// a commonly misread character explains it or, for words recognized with low OCR
+// confidence, exactly one vocabulary word is the closest match within the allowed
+// edit distance. Goroutine safe.
+type Corrector struct {
+	vocab  map[string]struct{}
+	byLen  map[int][]string
+	maxLen int
+	// Lemmas of vocabulary words, so that their inflections aren't corrected.
+	lemmas map[string]struct{}
+}
+
+// NewCorrector creates corrector using vocab words.
+func NewCorrector(vocab []string) *Corrector {
+	c := &Corrector{
+		vocab:  make(map[string]struct{}, len(vocab)),
+		byLen:  make(map[int][]string),
+		lemmas: make(map[string]struct{}, 2*len(vocab)),
+	}
+	for _, w := range vocab {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w == "" {
+			continue
+		}
+		if _, exists := c.vocab[w]; exists {
+			continue
+		}
+		c.vocab[w] = struct{}{}
+		n := utf8.RuneCountInString(w)
+		c.byLen[n] = append(c.byLen[n], w)
+		c.maxLen = max(c.maxLen, n)
+		c.lemmas[english.Stem(w, false)] = struct{}{}
+		c.lemmas[stemPolish(w)] = struct{}{}
+	}
+
+	return c
+}
+
+var (
+	defaultCorrector     *Corrector
+	defaultCorrectorOnce sync.Once
+)
+
+// DefaultCorrector returns corrector using embedded vocabulary, created once and shared.
+func DefaultCorrector() *Corrector {
+	defaultCorrectorOnce.Do(func() {
+		defaultCorrector = NewCorrector(Vocabulary())
+	})
+
+	return defaultCorrector
+}
+
+// Known reports whether word is in the vocabulary.
+func (c *Corrector) Known(word string) bool {
+	_, exists := c.vocab[strings.ToLower(word)]
+
+	return exists
+}
+
+// inflected reports whether word is an inflection of a vocabulary word, e.g. "doświadczeniem"
+// of "doświadczenie".
+func (c *Corrector) inflected(word string) bool {
+	if _, exists := c.lemmas[english.Stem(word, false)]; exists {
+		return true
+	}
+	_, exists := c.lemmas[stemPolish(word)]
+
+	return exists
+}
+
+// Correct returns corrected word and true if word is a high-confidence misread.
+// Confidence is OCR confidence of the word (0-100), negative if unknown.
+// Words recognized with confidence of at least TrustedConfidence are kept as they are,
+// edit distance is only used for words recognized with a known lower confidence.
+// Names like "Node.js", "C++" or "C#" are never corrected.
+func (c *Corrector) Correct(word string, confidence float64) (Correction, bool) {
+	lower := strings.ToLower(word)
+	if utf8.RuneCountInString(lower) < minCorrectLen || c.Known(lower) {
+		return Correction{}, false
+	}
+	if isNumber(lower) || !strings.ContainsFunc(lower, unicode.IsLetter) || strings.ContainsAny(lower, ".+#") {
+		return Correction{}, false
+	}
+	if confidence >= TrustedConfidence || c.inflected(lower) {
+		return Correction{}, false
+	}
+
+	corrected, distance, ok := c.confused(lower)
+	if !ok && confidence >= 0 {
+		corrected, distance, ok = c.closest(lower, maxDistance(lower, confidence))
+	}
+	if !ok {
+		return Correction{}, false
+	}
+
+	return Correction{
+		Original:   word,
+		Corrected:  matchCase(word, corrected),
+		Distance:   distance,
+		Confidence: confidence,
+	}, true
+}
+
+// maxDistance returns edit distance allowed for word. Longer words and words
+// with low OCR confidence may be corrected further.
+func maxDistance(word string, confidence float64) int {
+	n := utf8.RuneCountInString(word)
+	d := 1
+	if n >= 8 {
+		d = 2
+	}
+	if confidence >= 0 && confidence < 50 && n >= 6 {
+		d++
+	}
+
+	return d
+}
+
+// confused returns vocabulary word obtained by replacing a single commonly misread
+// character sequence of word.
+func (c *Corrector) confused(word string) (string, int, bool) {
+	found := ""
+	for _, cf := range confusions {
+		for i := 0; ; {
+			j := strings.Index(word[i:], cf.misread)
+			if j < 0 {
+				break
+			}
+			j += i
+			candidate := word[:j] + cf.original + word[j+len(cf.misread):]
+			if c.Known(candidate) {
+				if found != "" && found != candidate {
+					return "", 0, false
+				}
+				found = candidate
+			}
+			i = j + 1
+		}
+	}
+	// All digits of a word like "Pyth0n" or "J4va" may be misreads, replace them all at once
+	if found == "" {
+		if candidate := replaceDigits(word); candidate != word && c.Known(candidate) {
+			found = candidate
+		}
+	}
+	if found == "" {
+		return "", 0, false
+	}
+
+	return found, distance(word, found), true
+}
+
+func replaceDigits(word string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '0':
+			return 'o'
+		case '1':
+			return 'l'
+		case '4':
+			return 'a'
+		case '5':
+			return 's'
+		case '8':
+			return 'b'
+		default:
+			return r
+		}
+	}, word)
+}
+
+// closest returns the only vocabulary word closest to word within maxDist edits.
+// Ties are ambiguous and word is not corrected.
+func (c *Corrector) closest(word string, maxDist int) (string, int, bool) {
+	n := utf8.RuneCountInString(word)
+
+	best, bestDist, ties := "", maxDist+1, 0
+	for l := max(1, n-maxDist); l <= min(c.maxLen, n+maxDist); l++ {
+		for _, candidate := range c.byLen[l] {
+			// Inflections ("developer", "developers") are not misreads
+			if strings.HasPrefix(candidate, word) || strings.HasPrefix(word, candidate) {
+				continue
+			}
+			d := distance(word, candidate)
+			switch {
+			case d < bestDist:
+				best, bestDist, ties = candidate, d, 0
+			case d == bestDist:
+				ties++
+			}
+		}
+	}
+	if best == "" || ties > 0 {
+		return "", 0, false
+	}
+
+	return best, bestDist, true
+}
+
+// ConfidenceKey returns word as OCR confidences are looked up by: trimmed of surrounding
+// punctuation and lowercased, so that box word "Kubernetes," matches word "Kubernetes".
+func ConfidenceKey(word string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(word), punctuation))
+}
+
+// CorrectText corrects every word of text, keeping lines and surrounding punctuation.
+// Confidence returns OCR confidence of a word by its ConfidenceKey, nil if confidences are unknown.
+func (c *Corrector) CorrectText(text string, confidence func(word string) (float64, bool)) (string, []Correction) {
+	corrections := make([]Correction, 0)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		changed := false
+		for j, field := range fields {
+			word := strings.Trim(field, punctuation)
+			if word == "" {
+				continue
+			}
+			conf := -1.0
+			if confidence != nil {
+				if v, ok := confidence(ConfidenceKey(word)); ok {
+					conf = v
+				}
+			}
+			corr, ok := c.Correct(word, conf)
+			if !ok {
+				continue
+			}
+			fields[j] = strings.Replace(field, word, corr.Corrected, 1)
+			corrections = append(corrections, corr)
+			changed = true
+		}
+		if changed {
+			lines[i] = strings.Join(fields, " ")
+		}
+	}
+
+	return strings.Join(lines, "\n"), corrections
+}
+
+// LogCorrections logs every correction, so that corrections can be reviewed.
+func LogCorrections(l *slog.Logger, corrections []Correction) {
+	for _, c := range corrections {
+		l.Info("Corrected OCR misread",
+			slog.String("original", c.Original),
+			slog.String("corrected", c.Corrected),
+			slog.Int("distance", c.Distance),
+			slog.Float64("confidence", c.Confidence),
+		)
+	}
+}
+
+// matchCase returns word written with letter case of original. Case is copied rune by rune
+// when both have the same length, otherwise word is uppercased, capitalized or kept lowercase.
+func matchCase(original, word string) string {
+	ro, rw := []rune(original), []rune(word)
+	switch {
+	case len(ro) == len(rw):
+		for i, r := range ro {
+			if unicode.IsUpper(r) {
+				rw[i] = unicode.ToUpper(rw[i])
+			}
+		}
+
+		return string(rw)
+	case original == strings.ToUpper(original):
+		return strings.ToUpper(word)
+	case startsUpper(original):
+		rw[0] = unicode.ToUpper(rw[0])
+
+		return string(rw)
+	default:
+		return word
+	}
+}
+
+func startsUpper(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+
+	return unicode.IsUpper(r)
+}
+
+// distance returns Damerau-Levenshtein (optimal string alignment) distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(
+				rows[i-1][j]+1,
+				rows[i][j-1]+1,
+				rows[i-1][j-1]+cost,
+			)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,173 @@
+package textproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrectorCorrect(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc       string
+		word       string
+		confidence float64
+		corrected  string
+		ok         bool
+	}{
+		{
+			desc:       "rn_misread_as_m",
+			word:       "Kubemetes",
+			confidence: -1,
+			corrected:  "Kubernetes",
+			ok:         true,
+		},
+		{
+			desc:       "digit_misread_as_letter",
+			word:       "Pyth0n",
+			confidence: -1,
+			corrected:  "Python",
+			ok:         true,
+		},
+		{
+			desc:       "q_misread_as_o",
+			word:       "PostgreSOL",
+			confidence: -1,
+			corrected:  "PostgreSQL",
+			ok:         true,
+		},
+		{
+			desc:       "low_confidence_misread",
+			word:       "terrafom",
+			confidence: 40,
+			corrected:  "terraform",
+			ok:         true,
+		},
+		{
+			desc:       "trusted_confidence_is_kept",
+			word:       "Kubemetes",
+			confidence: TrustedConfidence,
+			ok:         false,
+		},
+		{
+			desc:       "known_word_is_kept",
+			word:       "golang",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "inflection_is_kept",
+			word:       "engineerings",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "short_word_is_kept",
+			word:       "jav",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "distant_word_is_kept",
+			word:       "piccrack",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "polish_inflection_is_kept",
+			word:       "doświadczeniem",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "unknown_confidence_is_not_edited",
+			word:       "mentoring",
+			confidence: -1,
+			ok:         false,
+		},
+		{
+			desc:       "dotted_name_is_kept",
+			word:       "Node.js",
+			confidence: 40,
+			ok:         false,
+		},
+		{
+			desc:       "symbol_name_is_kept",
+			word:       "Notepad++",
+			confidence: 40,
+			ok:         false,
+		},
+		{
+			desc:       "number_is_kept",
+			word:       "2024",
+			confidence: -1,
+			ok:         false,
+		},
+	}
+	c := DefaultCorrector()
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			corr, ok := c.Correct(tC.word, tC.confidence)
+			require.Equal(t, tC.ok, ok)
+			if tC.ok {
+				require.Equal(t, tC.word, corr.Original)
+				require.Equal(t, tC.corrected, corr.Corrected)
+			}
+		})
+	}
+}
+
+func TestCorrectorCorrectText(t *testing.T) {
+	t.Parallel()
+
+	c := NewCorrector([]string{"kubernetes", "python", "docker"})
+	confidences := map[string]float64{"dockr": 95}
+
+	text, corrections := c.CorrectText("Kubemetes, Pyth0n\nDockr and Go", func(word string) (float64, bool) {
+		v, ok := confidences[word]
+
+		return v, ok
+	})
+	require.Equal(t, "Kubernetes, Python\nDockr and Go", text)
+	require.Len(t, corrections, 2)
+	require.Equal(t, "Kubemetes", corrections[0].Original)
+	require.InDelta(t, -1.0, corrections[0].Confidence, 0)
+}
+
+func TestCorrectorCorrectTextLooksUpPunctuatedBoxWords(t *testing.T) {
+	t.Parallel()
+
+	c := NewCorrector([]string{"kubernetes"})
+	// Tesseract box words keep punctuation of the text
+	confidences := map[string]float64{ConfidenceKey("Kubemetes,"): 20}
+
+	text, corrections := c.CorrectText("Kubemetes, Go", func(word string) (float64, bool) {
+		v, ok := confidences[word]
+
+		return v, ok
+	})
+	require.Equal(t, "Kubernetes, Go", text)
+	require.Len(t, corrections, 1)
+	require.InDelta(t, 20.0, corrections[0].Confidence, 0)
+}
+
+func TestCorrectorCorrectTextKeepsValidWords(t *testing.T) {
+	t.Parallel()
+
+	text := "Szukamy osoby z doświadczeniem\nmentoring, Node.js"
+	corrected, corrections := DefaultCorrector().CorrectText(text, nil)
+	require.Equal(t, text, corrected)
+	require.Empty(t, corrections)
+}
+
+func TestDistance(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, distance("go", "go"))
+	require.Equal(t, 1, distance("pyhton", "python"))
+	require.Equal(t, 2, distance("kubemetes", "kubernetes"))
+	require.Equal(t, 3, distance("", "aws"))
+}
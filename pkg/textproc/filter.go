@@ -0,0 +1,171 @@
+package textproc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FilterOptions configures which tokens are dropped as noise.
+type FilterOptions struct {
+	// Extra stop words, e.g. UI noise of job boards ("apply", "save", "share").
+	StopWords []string
+	// Tokens shorter than MinLength runes are dropped. Zero disables the check.
+	MinLength int
+	// Drops tokens made of digits only.
+	DropNumbers bool
+	// Drops tokens where ratio of non-letter runes exceeds MaxNonLetterRatio.
+	// Zero disables the check.
+	MaxNonLetterRatio float64
+	// Tokens matching any of the regular expressions are dropped.
+	Patterns []string
+}
+
+// Filter drops noise tokens: custom stop words and OCR junk.
+// Nil filter keeps every token. Goroutine safe.
+type Filter struct {
+	mu        sync.RWMutex
+	stopWords map[string]struct{}
+
+	minLength         int
+	dropNumbers       bool
+	maxNonLetterRatio float64
+	patterns          []*regexp.Regexp
+}
+
+// NewFilter creates filter from opts, compiling its patterns.
+func NewFilter(opts FilterOptions) (*Filter, error) {
+	patterns := make([]*regexp.Regexp, 0, len(opts.Patterns))
+	for _, p := range opts.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	f := &Filter{
+		minLength:         opts.MinLength,
+		dropNumbers:       opts.DropNumbers,
+		maxNonLetterRatio: opts.MaxNonLetterRatio,
+		patterns:          patterns,
+	}
+	f.SetStopWords(opts.StopWords)
+
+	return f, nil
+}
+
+// SetStopWords replaces stop words of the filter.
+func (f *Filter) SetStopWords(words []string) {
+	if f == nil {
+		return
+	}
+
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		if w = normalizeStopWord(w); w != "" {
+			m[w] = struct{}{}
+		}
+	}
+
+	f.mu.Lock()
+	f.stopWords = m
+	f.mu.Unlock()
+}
+
+// StopWords returns sorted stop words of the filter.
+func (f *Filter) StopWords() []string {
+	if f == nil {
+		return []string{}
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	words := make([]string, 0, len(f.stopWords))
+	for w := range f.stopWords {
+		words = append(words, w)
+	}
+	slices.Sort(words)
+
+	return words
+}
+
+// Keep reports whether token passes the filter.
+func (f *Filter) Keep(token string) bool {
+	if f == nil {
+		return true
+	}
+	token = strings.ToLower(strings.TrimSpace(token))
+	if token == "" {
+		return false
+	}
+
+	f.mu.RLock()
+	_, stop := f.stopWords[token]
+	f.mu.RUnlock()
+	if stop {
+		return false
+	}
+
+	if f.minLength > 0 && utf8.RuneCountInString(token) < f.minLength {
+		return false
+	}
+	if f.dropNumbers && isNumber(token) {
+		return false
+	}
+	if f.maxNonLetterRatio > 0 && nonLetterRatio(token) > f.maxNonLetterRatio {
+		return false
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(token) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Words returns words passing the filter.
+func (f *Filter) Words(words []Word) []Word {
+	if f == nil {
+		return words
+	}
+
+	return slices.DeleteFunc(slices.Clone(words), func(w Word) bool {
+		return !f.Keep(w.Value)
+	})
+}
+
+func normalizeStopWord(w string) string {
+	return strings.ToLower(strings.TrimSpace(w))
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func nonLetterRatio(s string) float64 {
+	total, nonLetters := 0, 0
+	for _, r := range s {
+		total++
+		if !unicode.IsLetter(r) {
+			nonLetters++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return float64(nonLetters) / float64(total)
+}
@@ -0,0 +1,117 @@
+package textproc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterKeep(t *testing.T) {
+	t.Parallel()
+
+	f, err := textproc.NewFilter(textproc.FilterOptions{
+		StopWords:         []string{"Apply", "cookies"},
+		MinLength:         2,
+		DropNumbers:       true,
+		MaxNonLetterRatio: 0.7,
+		Patterns:          []string{`^[0-9]+km$`},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		token string
+		want  bool
+	}{
+		{token: "kubernetes", want: true},
+		{token: "k8s", want: true},
+		{token: "c++", want: true},
+		{token: "apply", want: false},
+		{token: "COOKIES", want: false},
+		{token: "x", want: false},
+		{token: "2024", want: false},
+		{token: "12km", want: false},
+		{token: "#$%1", want: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.token, func(t *testing.T) {
+			require.Equal(t, tC.want, f.Keep(tC.token))
+		})
+	}
+}
+
+func TestNilFilterKeepsEverything(t *testing.T) {
+	t.Parallel()
+
+	var f *textproc.Filter
+
+	require.True(t, f.Keep("apply"))
+	require.Empty(t, f.StopWords())
+	words := []textproc.Word{{Value: "x"}}
+	require.Equal(t, words, f.Words(words))
+}
+
+func TestNewFilterInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := textproc.NewFilter(textproc.FilterOptions{Patterns: []string{"("}})
+	require.Error(t, err)
+}
+
+func TestStopWordsFile(t *testing.T) {
+	t.Parallel()
+
+	file := textproc.NewStopWordsFile(filepath.Join(t.TempDir(), "stopwords.txt"))
+
+	words, err := file.Read()
+	require.NoError(t, err)
+	require.Empty(t, words)
+
+	words, err = file.Add("save", "Share", "save")
+	require.NoError(t, err)
+	require.Equal(t, []string{"save", "share"}, words)
+
+	words, err = file.Remove("save")
+	require.NoError(t, err)
+	require.Equal(t, []string{"share"}, words)
+
+	f, err := textproc.LoadFilter(file, textproc.FilterOptions{})
+	require.NoError(t, err)
+	require.False(t, f.Keep("share"))
+}
+
+func TestStopWordsFileKeepsComments(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	const content = "# Extra stop words, one per line.\n\nzapisz\n# Job boards\napply\nkm\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	file := textproc.NewStopWordsFile(path)
+
+	words, err := file.Add("Cookies", "km")
+	require.NoError(t, err)
+	require.Equal(t, []string{"zapisz", "apply", "km", "cookies"}, words)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content+"cookies\n", string(data))
+
+	words, err = file.Remove("apply", "cookies")
+	require.NoError(t, err)
+	require.Equal(t, []string{"zapisz", "km"}, words)
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "# Extra stop words, one per line.\n\nzapisz\n# Job boards\nkm\n", string(data))
+}
+
+func TestReadStopWords(t *testing.T) {
+	t.Parallel()
+
+	words, err := textproc.ReadStopWords(strings.NewReader("# comment\n\nApply\nkm\napply\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"apply", "km"}, words)
+}
@@ -0,0 +1,98 @@
+package textproc
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+	"github.com/pemistahl/lingua-go"
+)
+
+// Lemma returns base form of a word written in lang, so that different inflections
+// of the same word ("doświadczenie", "doświadczenia", "doświadczeniem") share one value.
+// English words are stemmed with snowball, polish words with a light suffix stemmer.
+// Word is returned lowercased and unchanged for other languages.
+func Lemma(word string, lang lingua.Language) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return ""
+	}
+
+	switch lang {
+	case lingua.English:
+		return english.Stem(word, false)
+	case lingua.Polish:
+		return stemPolish(word)
+	default:
+		return word
+	}
+}
+
+// Minimal length (in runes) of a polish stem left after suffix removal.
+const minPolishStemLen = 4
+
+// polishSuffixes holds inflectional suffixes and their replacements, longest first.
+var polishSuffixes = []struct {
+	suffix      string
+	replacement string
+}{
+	{"ościami", "ość"},
+	{"ościach", "ość"},
+	{"owaniem", "owan"},
+	{"owania", "owan"},
+	{"owaniu", "owan"},
+	{"owanie", "owan"},
+	{"ością", "ość"},
+	{"ości", "ość"},
+	{"iami", ""},
+	{"iach", ""},
+	{"ami", ""},
+	{"ach", ""},
+	{"iom", ""},
+	{"iem", ""},
+	{"ego", ""},
+	{"emu", ""},
+	{"owie", ""},
+	{"owi", ""},
+	{"ymi", ""},
+	{"imi", ""},
+	{"ych", ""},
+	{"ich", ""},
+	{"ej", ""},
+	{"ów", ""},
+	{"om", ""},
+	{"em", ""},
+	{"ie", ""},
+	{"ia", ""},
+	{"iu", ""},
+	{"ią", ""},
+	{"ię", ""},
+	{"ii", ""},
+	{"ym", ""},
+	{"im", ""},
+	{"ą", ""},
+	{"ę", ""},
+	{"a", ""},
+	{"e", ""},
+	{"i", ""},
+	{"o", ""},
+	{"u", ""},
+	{"y", ""},
+}
+
+// stemPolish strips the longest matching inflectional suffix of a lowercased polish word.
+func stemPolish(word string) string {
+	for _, s := range polishSuffixes {
+		if !strings.HasSuffix(word, s.suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(word, s.suffix)
+		if utf8.RuneCountInString(stem) < minPolishStemLen {
+			continue
+		}
+
+		return stem + s.replacement
+	}
+
+	return word
+}
@@ -0,0 +1,61 @@
+package textproc_test
+
+import (
+	"testing"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/pemistahl/lingua-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLemma(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		words []string
+		lang  lingua.Language
+	}{
+		{
+			desc: "polish_inflections_share_lemma",
+
+			words: []string{"doświadczenie", "doświadczenia", "doświadczeniem", "Doświadczeniu"},
+			lang:  lingua.Polish,
+		},
+		{
+			desc: "polish_noun_cases_share_lemma",
+
+			words: []string{"umiejętność", "umiejętności", "umiejętnościami"},
+			lang:  lingua.Polish,
+		},
+		{
+			desc: "english_plurals_share_lemma",
+
+			words: []string{"developer", "developers"},
+			lang:  lingua.English,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			want := textproc.Lemma(tC.words[0], tC.lang)
+			require.NotEmpty(t, want)
+
+			for _, word := range tC.words[1:] {
+				require.Equal(t, want, textproc.Lemma(word, tC.lang), word)
+			}
+		})
+	}
+}
+
+func TestNewWords(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"szukamy", "programisty", "z", "doświadczeniem", "w", "projektach"}
+
+	words := textproc.NewWords(values)
+	require.Len(t, words, len(values))
+	require.Equal(t, textproc.Lemma("doświadczenie", lingua.Polish), words[3].Lemma)
+	require.Equal(t, textproc.Lemma("projekt", lingua.Polish), words[5].Lemma)
+	require.Equal(t, "pl", words[0].Language)
+}
@@ -0,0 +1,147 @@
+package textproc
+
+import (
+	"bufio"
+	"strings"
+	"unicode"
+
+	"github.com/bbalet/stopwords"
+	"github.com/pemistahl/lingua-go"
+)
+
+// Line represents a single line of a text and the language it is written in.
+// Screenshots often mix polish headings with english lists, so language is detected
+// for every line separately.
+type Line struct {
+	Text     string
+	Language lingua.Language
+}
+
+// LanguageCode returns lowercase ISO 639-1 code of the line language
+// or an empty string if language is unknown.
+func (l Line) LanguageCode() string {
+	return LanguageCode(l.Language)
+}
+
+// Words returns lowercased words of the line with stop words of the line language removed.
+func (l Line) Words() []string {
+	code := l.LanguageCode()
+
+	words := make([]string, 0)
+	for _, field := range strings.Fields(strings.ToLower(l.Text)) {
+		word := strings.Trim(field, punctuation)
+		if word == "" || isStopWord(word, code) {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words
+}
+
+// Characters trimmed from both ends of every word.
+const punctuation = ".,;:!?()[]{}\"'`"
+
+// isStopWord reports whether word is on the stop words list of language with code.
+// Words without letters are never stop words, because the list cleaner drops them entirely.
+func isStopWord(word, code string) bool {
+	if code == "" || !strings.ContainsFunc(word, unicode.IsLetter) {
+		return false
+	}
+
+	return strings.TrimSpace(stopwords.CleanString(word, code, false)) == ""
+}
+
+// DetectLines splits text into trimmed, non-empty lines and detects language of each.
+func DetectLines(text string, langs ...lingua.Language) []Line {
+	lines := make([]Line, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" {
+			continue
+		}
+		lang, exists := DetectLanguage(s, langs...)
+		if !exists {
+			lang = lingua.Unknown
+		}
+		lines = append(lines, Line{Text: s, Language: lang})
+	}
+
+	return lines
+}
+
+// LanguageCode returns lowercase ISO 639-1 code of lang or an empty string if lang is unknown.
+func LanguageCode(lang lingua.Language) string {
+	if lang == lingua.Unknown {
+		return ""
+	}
+
+	return strings.ToLower(lang.IsoCode639_1().String())
+}
+
+// Word is a single word of a text with its lemma and language code.
+type Word struct {
+	Value    string `json:"value"`
+	Lemma    string `json:"lemma"`
+	Language string `json:"language"`
+}
+
+// Words splits text into words, detecting language of every line, removing stop words
+// of that language and computing lemmas.
+func Words(text string, langs ...lingua.Language) []Word {
+	words := make([]Word, 0)
+
+	for _, line := range DetectLines(text, langs...) {
+		code := line.LanguageCode()
+		for _, value := range line.Words() {
+			words = append(words, Word{
+				Value:    value,
+				Lemma:    Lemma(value, line.Language),
+				Language: code,
+			})
+		}
+	}
+
+	return words
+}
+
+// NewWord creates a word from a single value, detecting its language from the value alone.
+func NewWord(value string, langs ...lingua.Language) Word {
+	lang, exists := DetectLanguage(value, langs...)
+	if !exists {
+		lang = lingua.Unknown
+	}
+
+	return Word{
+		Value:    value,
+		Lemma:    Lemma(value, lang),
+		Language: LanguageCode(lang),
+	}
+}
+
+// NewWords creates words from values, detecting language of all values joined together.
+// Detecting language of a single word is unreliable, so the whole text is used instead.
+func NewWords(values []string, langs ...lingua.Language) []Word {
+	words := make([]Word, 0, len(values))
+	if len(values) == 0 {
+		return words
+	}
+
+	lang, exists := DetectLanguage(strings.Join(values, " "), langs...)
+	if !exists {
+		lang = lingua.Unknown
+	}
+	for _, value := range values {
+		words = append(words, Word{
+			Value:    value,
+			Lemma:    Lemma(value, lang),
+			Language: LanguageCode(lang),
+		})
+	}
+
+	return words
+}
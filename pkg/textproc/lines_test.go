@@ -0,0 +1,85 @@
+package textproc_test
+
+import (
+	"testing"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/pemistahl/lingua-go"
+	"github.com/stretchr/testify/require"
+)
+
+const mixedText = `Wymagania dotyczące stanowiska i doświadczenie zawodowe
+Strong experience with Kubernetes and Helm in the cloud
+
+Oferujemy pracę zdalną oraz elastyczne godziny pracy
+Knowledge of the k8s ecosystem and c++ is a plus`
+
+func TestDetectLines(t *testing.T) {
+	t.Parallel()
+
+	lines := textproc.DetectLines(mixedText)
+	require.Len(t, lines, 4)
+
+	want := []lingua.Language{lingua.Polish, lingua.English, lingua.Polish, lingua.English}
+	for i, line := range lines {
+		require.Equal(t, want[i], line.Language, line.Text)
+	}
+	require.Equal(t, "pl", lines[0].LanguageCode())
+	require.Equal(t, "en", lines[1].LanguageCode())
+}
+
+func TestLineWords(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc string
+
+		line     textproc.Line
+		want     []string
+		excluded []string
+	}{
+		{
+			desc: "removes_english_stop_words",
+
+			line:     textproc.Line{Text: "Strong experience with Kubernetes and Helm.", Language: lingua.English},
+			want:     []string{"strong", "experience", "kubernetes", "helm"},
+			excluded: []string{"with", "and"},
+		},
+		{
+			desc: "removes_polish_stop_words",
+
+			line:     textproc.Line{Text: "Oferujemy pracę zdalną dla programistów, jest elastyczne godziny", Language: lingua.Polish},
+			want:     []string{"oferujemy", "pracę", "zdalną", "programistów", "elastyczne", "godziny"},
+			excluded: []string{"dla", "jest"},
+		},
+		{
+			desc: "keeps_words_with_digits_and_symbols",
+
+			line: textproc.Line{Text: "k8s c++ 2025", Language: lingua.English},
+			want: []string{"k8s", "c++", "2025"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			words := tC.line.Words()
+			require.Subset(t, words, tC.want)
+			for _, w := range tC.excluded {
+				require.NotContains(t, words, w)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	words := textproc.Words(mixedText)
+	require.NotEmpty(t, words)
+
+	languages := make(map[string]string)
+	for _, w := range words {
+		languages[w.Value] = w.Language
+	}
+	require.Equal(t, "en", languages["kubernetes"])
+	require.Equal(t, "pl", languages["doświadczenie"])
+}
@@ -1,6 +1,8 @@
 package textproc
 
 import (
+	"sync"
+
 	"github.com/bbalet/stopwords"
 	"github.com/pemistahl/lingua-go"
 )
@@ -12,18 +14,44 @@ func defaultLanguages() []lingua.Language {
 	}
 }
 
-func RmStopWords(text string, langs ...lingua.Language) string {
-	if text == "" {
-		return ""
-	}
+var (
+	defaultDetector     lingua.LanguageDetector
+	defaultDetectorOnce sync.Once
+)
+
+// detector returns language detector for langs. Detector built from default languages
+// is created once and shared, because building it is expensive.
+func detector(langs ...lingua.Language) lingua.LanguageDetector {
 	if len(langs) == 0 {
-		langs = defaultLanguages()
+		defaultDetectorOnce.Do(func() {
+			defaultDetector = lingua.NewLanguageDetectorBuilder().
+				FromLanguages(defaultLanguages()...).
+				Build()
+		})
+
+		return defaultDetector
 	}
-	detector := lingua.NewLanguageDetectorBuilder().
+
+	return lingua.NewLanguageDetectorBuilder().
 		FromLanguages(langs...).
 		Build()
+}
+
+// DetectLanguage detects language of text from langs (english and polish by default).
+func DetectLanguage(text string, langs ...lingua.Language) (lingua.Language, bool) {
+	if text == "" {
+		return lingua.Unknown, false
+	}
+
+	return detector(langs...).DetectLanguageOf(text)
+}
+
+func RmStopWords(text string, langs ...lingua.Language) string {
+	if text == "" {
+		return ""
+	}
 
-	lang, exists := detector.DetectLanguageOf(text)
+	lang, exists := DetectLanguage(text, langs...)
 	if !exists {
 		return text
 	}
@@ -0,0 +1,257 @@
+package textproc
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Salary periods.
+const (
+	PeriodHour  = "hour"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// Contract types.
+const (
+	ContractB2B = "b2b"
+	ContractUoP = "uop"
+)
+
+// Salary bases.
+const (
+	BasisGross = "gross"
+	BasisNet   = "net"
+)
+
+// Salary ranges with upper amount exceeding lower amount more times are not plausible.
+const maxRangeRatio = 4
+
+// Approximate number of working hours in a month, used to compare hourly rates with monthly salaries.
+const hoursPerMonth = 168
+
+// Salary is a salary range found in a text.
+type Salary struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency"`
+	// One of PeriodHour, PeriodMonth, PeriodYear. Guessed from the amount if not written.
+	Period string `json:"period"`
+	// ContractB2B, ContractUoP or empty if unknown.
+	Contract string `json:"contract"`
+	// BasisGross, BasisNet or empty if unknown.
+	Basis string `json:"basis"`
+	// Text the salary was extracted from.
+	Text string `json:"text"`
+}
+
+// Monthly returns salary range converted to a monthly amount.
+func (s Salary) Monthly() (float64, float64) {
+	switch s.Period {
+	case PeriodHour:
+		return s.Min * hoursPerMonth, s.Max * hoursPerMonth
+	case PeriodYear:
+		return s.Min / 12, s.Max / 12
+	default:
+		return s.Min, s.Max
+	}
+}
+
+const (
+	amountPattern   = `\d{1,3}(?:[ \x{00a0}.,]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d{1,2})?`
+	currencyPattern = `pln|zł|zl|eur|€|usd|\$`
+)
+
+// salaryRe matches a single amount or a range of amounts with currency written before or after them:
+// "15 000 - 20 000 PLN", "15k-20k zł", "$120,000 to $150,000", "EUR 5000".
+// Amounts may also be separated by a space only, since OCR drops dashes.
+var salaryRe = regexp.MustCompile(`(?i)(?:(?P<cur1>` + currencyPattern + `)\s*)?` +
+	`(?P<min>` + amountPattern + `)(?:\s*(?P<mink>k))?` +
+	`(?:\s*(?:-|–|—|to|do|\s)\s*(?:` + currencyPattern + `)?\s*(?P<max>` + amountPattern + `)(?:\s*(?P<maxk>k))?)?` +
+	`(?:\s*(?P<cur2>` + currencyPattern + `))?`)
+
+var (
+	periodKeywords = map[string][]string{
+		PeriodHour:  {"h", "hour", "hourly", "hr", "godz", "godzina", "godzinę", "godzine", "godzinowa"},
+		PeriodMonth: {"m", "month", "monthly", "mth", "mies", "miesiąc", "miesiac", "miesięcznie", "miesiecznie", "msc", "mc"},
+		PeriodYear:  {"y", "yr", "year", "yearly", "annual", "annually", "annum", "rok", "rocznie"},
+	}
+	contractKeywords = map[string][]string{
+		ContractB2B: {"b2b"},
+		// Bare "praca" or "employment" is common in postings ("Oferujemy pracę zdalną",
+		// "Employment type: B2B"), so only names of the contract count.
+		ContractUoP: {
+			"uop", "uopp", "permanent",
+			"umowa o pracę", "umowę o pracę", "umowy o pracę", "umowie o pracę",
+			"umowa o prace", "umowe o prace", "umowy o prace", "umowie o prace",
+			"employment contract", "contract of employment",
+		},
+	}
+	basisKeywords = map[string][]string{
+		BasisGross: {"gross", "brutto"},
+		BasisNet:   {"net", "netto", "+vat", "vat"},
+	}
+)
+
+// ExtractSalaries returns salary ranges found in text. Amounts are recognized only
+// if currency is written next to them. Period, contract type and gross or net basis
+// are read from the rest of the line.
+func ExtractSalaries(text string) []Salary {
+	salaries := make([]Salary, 0)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		for _, m := range salaryRe.FindAllStringSubmatchIndex(line, -1) {
+			s, ok := newSalary(line, m)
+			if !ok {
+				continue
+			}
+			rest := strings.ToLower(line[m[1]:])
+			tokens := strings.FieldsFunc(strings.ToLower(line), isSalarySeparator)
+			s.Period = findKeyword(strings.FieldsFunc(rest, isSalarySeparator), periodKeywords)
+			if s.Period == "" {
+				s.Period = guessPeriod(s.Max)
+			}
+			s.Contract = findKeyword(tokens, contractKeywords)
+			s.Basis = findKeyword(tokens, basisKeywords)
+			salaries = append(salaries, s)
+		}
+	}
+
+	return salaries
+}
+
+func newSalary(line string, m []int) (Salary, bool) {
+	group := func(name string) string {
+		i := salaryRe.SubexpIndex(name)
+		if m[2*i] < 0 {
+			return ""
+		}
+
+		return line[m[2*i]:m[2*i+1]]
+	}
+
+	currency := normalizeCurrency(group("cur1"))
+	if currency == "" {
+		currency = normalizeCurrency(group("cur2"))
+	}
+	if currency == "" {
+		return Salary{}, false
+	}
+
+	minimum, ok := parseAmount(group("min"), group("mink") != "")
+	if !ok {
+		return Salary{}, false
+	}
+	maximum := minimum
+	if v := group("max"); v != "" {
+		// "15-20k" means both amounts are in thousands
+		k := group("maxk") != ""
+		maximum, ok = parseAmount(v, k)
+		if !ok {
+			return Salary{}, false
+		}
+		if k && group("mink") == "" && minimum < 1000 {
+			minimum *= 1000
+		}
+	}
+	if minimum <= 0 || maximum < minimum {
+		return Salary{}, false
+	}
+	// Lower amount of an implausibly wide range is a number preceding the salary, e.g. "5 years"
+	if maximum > maxRangeRatio*minimum {
+		minimum = maximum
+	}
+
+	return Salary{
+		Min:      minimum,
+		Max:      maximum,
+		Currency: currency,
+		Text:     strings.TrimSpace(line[m[0]:m[1]]),
+	}, true
+}
+
+func normalizeCurrency(s string) string {
+	switch strings.ToLower(s) {
+	case "pln", "zł", "zl":
+		return "PLN"
+	case "eur", "€":
+		return "EUR"
+	case "usd", "$":
+		return "USD"
+	default:
+		return ""
+	}
+}
+
+// parseAmount parses amounts like "15 000", "15.000,50", "120,000" or "15.5" (with k suffix).
+func parseAmount(s string, thousands bool) (float64, bool) {
+	s = strings.NewReplacer(" ", "", " ", "").Replace(s)
+
+	// Last separator followed by one or two digits is a decimal separator, other separators group thousands
+	decimal := ""
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 <= 2 {
+		decimal = s[i+1:]
+		s = s[:i]
+	}
+	s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	if decimal != "" {
+		s += "." + decimal
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	if thousands {
+		v *= 1000
+	}
+
+	return v, true
+}
+
+// guessPeriod returns likely period of a salary with amount written without a period.
+func guessPeriod(amount float64) string {
+	switch {
+	case amount < 1000:
+		return PeriodHour
+	case amount >= 100000:
+		return PeriodYear
+	default:
+		return PeriodMonth
+	}
+}
+
+// findKeyword returns value of the first keyword found in tokens. Keywords of many words
+// match consecutive tokens.
+func findKeyword(tokens []string, keywords map[string][]string) string {
+	for i := range tokens {
+		for value, words := range keywords {
+			for _, w := range words {
+				if hasPrefixWords(tokens[i:], strings.Fields(w)) {
+					return value
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func hasPrefixWords(tokens, words []string) bool {
+	return len(tokens) >= len(words) && slices.Equal(tokens[:len(words)], words)
+}
+
+func isSalarySeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', ',', ';', '(', ')', '|', '/', '.':
+		return true
+	default:
+		return false
+	}
+}
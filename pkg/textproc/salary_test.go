@@ -0,0 +1,104 @@
+package textproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSalaries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		text     string
+		salaries []Salary
+	}{
+		{
+			desc: "polish_range_with_spaces",
+			text: "Senior Go Developer\n15 000 - 20 000 PLN netto (B2B)",
+			salaries: []Salary{{
+				Min: 15000, Max: 20000, Currency: "PLN", Period: PeriodMonth,
+				Contract: ContractB2B, Basis: BasisNet, Text: "15 000 - 20 000 PLN",
+			}},
+		},
+		{
+			desc: "thousands_suffix_and_hourly_rate",
+			text: "120-150 zł/h + VAT B2B",
+			salaries: []Salary{{
+				Min: 120, Max: 150, Currency: "PLN", Period: PeriodHour,
+				Contract: ContractB2B, Basis: BasisNet, Text: "120-150 zł",
+			}},
+		},
+		{
+			desc: "k_suffix",
+			text: "18k-24k PLN brutto UoP miesięcznie",
+			salaries: []Salary{{
+				Min: 18000, Max: 24000, Currency: "PLN", Period: PeriodMonth,
+				Contract: ContractUoP, Basis: BasisGross, Text: "18k-24k PLN",
+			}},
+		},
+		{
+			desc: "employment_contract_phrase",
+			text: "12 000 - 16 000 PLN brutto, umowa o pracę",
+			salaries: []Salary{{
+				Min: 12000, Max: 16000, Currency: "PLN", Period: PeriodMonth,
+				Contract: ContractUoP, Basis: BasisGross, Text: "12 000 - 16 000 PLN",
+			}},
+		},
+		{
+			desc: "work_noun_isnt_contract",
+			text: "Oferujemy pracę zdalną 12 000 - 16 000 PLN",
+			salaries: []Salary{{
+				Min: 12000, Max: 16000, Currency: "PLN", Period: PeriodMonth, Text: "12 000 - 16 000 PLN",
+			}},
+		},
+		{
+			desc: "employment_type_label_isnt_contract",
+			text: "Employment type: B2B 20 000 - 25 000 PLN",
+			salaries: []Salary{{
+				Min: 20000, Max: 25000, Currency: "PLN", Period: PeriodMonth,
+				Contract: ContractB2B, Text: "20 000 - 25 000 PLN",
+			}},
+		},
+		{
+			desc: "currency_before_amounts_per_year",
+			text: "$120,000 to $150,000 per year, gross",
+			salaries: []Salary{{
+				Min: 120000, Max: 150000, Currency: "USD", Period: PeriodYear,
+				Basis: BasisGross, Text: "$120,000 to $150,000",
+			}},
+		},
+		{
+			desc: "ocr_text_without_dash",
+			text: "5 years of experience 6000 8000 EUR",
+			salaries: []Salary{{
+				Min: 6000, Max: 8000, Currency: "EUR", Period: PeriodMonth, Text: "6000 8000 EUR",
+			}},
+		},
+		{
+			desc:     "amount_without_currency",
+			text:     "5 years of experience, 15000",
+			salaries: []Salary{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tC.salaries, ExtractSalaries(tC.text))
+		})
+	}
+}
+
+func TestSalaryMonthly(t *testing.T) {
+	t.Parallel()
+
+	minimum, maximum := Salary{Min: 100, Max: 150, Period: PeriodHour}.Monthly()
+	require.InDelta(t, 16800.0, minimum, 0.001)
+	require.InDelta(t, 25200.0, maximum, 0.001)
+
+	minimum, maximum = Salary{Min: 120000, Max: 144000, Period: PeriodYear}.Monthly()
+	require.InDelta(t, 10000.0, minimum, 0.001)
+	require.InDelta(t, 12000.0, maximum, 0.001)
+}
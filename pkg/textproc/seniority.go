@@ -0,0 +1,48 @@
+package textproc
+
+// Seniority levels.
+const (
+	SeniorityIntern = "intern"
+	SeniorityJunior = "junior"
+	SeniorityMid    = "mid"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+)
+
+// seniorityKeywords maps lowercase words to seniority level they indicate.
+var seniorityKeywords = map[string]string{
+	"intern":     SeniorityIntern,
+	"internship": SeniorityIntern,
+	"trainee":    SeniorityIntern,
+	"stażysta":   SeniorityIntern,
+	"staż":       SeniorityIntern,
+	"junior":     SeniorityJunior,
+	"jr":         SeniorityJunior,
+	"młodszy":    SeniorityJunior,
+	"mid":        SeniorityMid,
+	"middle":     SeniorityMid,
+	"regular":    SeniorityMid,
+	"senior":     SenioritySenior,
+	"sr":         SenioritySenior,
+	"starszy":    SenioritySenior,
+	"lead":       SeniorityLead,
+	"principal":  SeniorityLead,
+	"staff":      SeniorityLead,
+	"head":       SeniorityLead,
+}
+
+// DetectSeniority returns seniority level mentioned most often in text, preferring
+// the level mentioned first on ties. Empty string is returned if none is mentioned.
+func DetectSeniority(text string) string {
+	return mostMentioned(text, seniorityKeywords)
+}
+
+// IsSeniority reports whether s is a known seniority level.
+func IsSeniority(s string) bool {
+	switch s {
+	case SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior, SeniorityLead:
+		return true
+	default:
+		return false
+	}
+}
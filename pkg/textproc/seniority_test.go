@@ -0,0 +1,45 @@
+package textproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSeniority(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc      string
+		text      string
+		seniority string
+	}{
+		{
+			desc:      "title",
+			text:      "Senior Go Developer\nRemote",
+			seniority: SenioritySenior,
+		},
+		{
+			desc:      "most_mentioned",
+			text:      "Mid/Regular Backend Engineer\nYou will mentor a junior developer\nRegular reviews",
+			seniority: SeniorityMid,
+		},
+		{
+			desc:      "polish",
+			text:      "Starszy programista Java",
+			seniority: SenioritySenior,
+		},
+		{
+			desc:      "none",
+			text:      "Go Developer",
+			seniority: "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tC.seniority, DetectSeniority(tC.text))
+		})
+	}
+}
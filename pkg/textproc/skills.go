@@ -0,0 +1,119 @@
+package textproc
+
+import (
+	"slices"
+	"strings"
+)
+
+// skillAliases maps canonical skill names to other names the skill is written as.
+var skillAliases = map[string][]string{
+	"go":            {"golang"},
+	"kubernetes":    {"k8s", "kube"},
+	"postgresql":    {"postgres", "psql", "pgsql"},
+	"javascript":    {"js", "ecmascript"},
+	"typescript":    {"ts"},
+	"node.js":       {"node", "nodejs", "node-js"},
+	"react":         {"reactjs", "react.js"},
+	"vue":           {"vuejs", "vue.js"},
+	"angular":       {"angularjs", "angular.js"},
+	"next.js":       {"nextjs", "next"},
+	"c#":            {"csharp", "c-sharp"},
+	"c++":           {"cpp", "cplusplus"},
+	"aws":           {"amazon-web-services"},
+	"gcp":           {"google-cloud", "google-cloud-platform"},
+	"azure":         {"ms-azure"},
+	"terraform":     {"tf"},
+	"mongodb":       {"mongo"},
+	"elasticsearch": {"elastic", "es"},
+	"python":        {"py", "python3"},
+	"ci/cd":         {"cicd", "ci-cd"},
+	"grpc":          {"g-rpc"},
+}
+
+// plainSkills are canonical skill names written without aliases.
+var plainSkills = []string{
+	"ansible", "bash", "cassandra", "clickhouse", "clojure", "css", "django", "docker",
+	"dynamodb", "elixir", "erlang", "express", "fastapi", "flask", "flutter", "gin", "git",
+	"graphql", "grafana", "haskell", "helm", "hibernate", "html", "java", "jenkins", "kafka",
+	"keras", "kotlin", "laravel", "linux", "mariadb", "microservices", "mysql", "nestjs",
+	"nginx", "numpy", "openshift", "pandas", "perl", "php", "prometheus", "pytorch",
+	"rabbitmq", "rails", "redis", "redux", "rest", "ruby", "rust", "scala", "serverless",
+	"snowflake", "spring", "sql", "sqlite", "svelte", "swift", "symfony", "tensorflow",
+}
+
+var canonicalSkills = func() map[string]string {
+	m := make(map[string]string)
+	for _, skill := range plainSkills {
+		m[skill] = skill
+	}
+	for skill, aliases := range skillAliases {
+		m[skill] = skill
+		for _, a := range aliases {
+			m[a] = skill
+		}
+	}
+
+	return m
+}()
+
+// CanonicalSkill returns canonical name of a skill written as word ("golang" is "go",
+// "k8s" is "kubernetes"). Other words are returned lowercased.
+func CanonicalSkill(word string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if skill, exists := canonicalSkills[word]; exists {
+		return skill
+	}
+
+	return word
+}
+
+// SkillAliases returns sorted names a skill may be written as, including its canonical name.
+func SkillAliases(word string) []string {
+	skill := CanonicalSkill(word)
+
+	names := append([]string{skill}, skillAliases[skill]...)
+	slices.Sort(names)
+
+	return names
+}
+
+// IsKnownSkill reports whether word is a name or an alias of a known skill.
+func IsKnownSkill(word string) bool {
+	_, exists := canonicalSkills[strings.ToLower(strings.TrimSpace(word))]
+
+	return exists
+}
+
+// SkillNames returns sorted names and aliases of all known skills.
+func SkillNames() []string {
+	names := make([]string, 0, len(canonicalSkills))
+	for name := range canonicalSkills {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// CanonicalSkills returns canonical name of every skill of names, in their order.
+func CanonicalSkills(names []string) []string {
+	skills := make([]string, 0, len(names))
+	for _, name := range names {
+		skills = append(skills, CanonicalSkill(name))
+	}
+
+	return skills
+}
+
+// Skills returns sorted canonical names of known skills among words, without duplicates.
+func Skills(words []string) []string {
+	skills := make([]string, 0, len(words))
+	for _, w := range words {
+		if IsKnownSkill(w) {
+			skills = append(skills, CanonicalSkill(w))
+		}
+	}
+	slices.Sort(skills)
+
+	return slices.Compact(skills)
+}
@@ -0,0 +1,69 @@
+package textproc
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSkill(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		word  string
+		skill string
+	}{
+		{desc: "alias", word: "Golang", skill: "go"},
+		{desc: "canonical", word: "kubernetes", skill: "kubernetes"},
+		{desc: "short_alias", word: "k8s", skill: "kubernetes"},
+		{desc: "unknown_word", word: "Rust", skill: "rust"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tC.skill, CanonicalSkill(tC.word))
+		})
+	}
+}
+
+func TestSkillAliases(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"pgsql", "postgres", "postgresql", "psql"}, SkillAliases("Postgres"))
+	require.Equal(t, []string{"rust"}, SkillAliases("rust"))
+}
+
+func TestIsKnownSkill(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsKnownSkill("K8s"))
+	require.True(t, IsKnownSkill("go"))
+	require.True(t, IsKnownSkill("Docker"))
+	require.False(t, IsKnownSkill("team"))
+}
+
+func TestSkillNames(t *testing.T) {
+	t.Parallel()
+
+	names := SkillNames()
+	require.True(t, slices.IsSorted(names))
+	require.Contains(t, names, "golang")
+	require.Contains(t, names, "kafka")
+	require.NotContains(t, names, "experience")
+}
+
+func TestSkills(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"go", "kubernetes"}, Skills([]string{"team", "golang", "K8s", "go", "experience"}))
+	require.Empty(t, Skills([]string{"team", "experience"}))
+}
+
+func TestCanonicalSkills(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"go", "kubernetes", "go"}, CanonicalSkills([]string{"golang", "k8s", "go"}))
+}
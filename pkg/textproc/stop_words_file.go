@@ -0,0 +1,182 @@
+package textproc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// StopWordsFile is a user managed list of extra stop words stored one per line.
+// Empty lines and lines starting with '#' are ignored. They're kept with the order of
+// other lines when the file is modified, added words are appended. Goroutine safe.
+type StopWordsFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewStopWordsFile(path string) *StopWordsFile {
+	return &StopWordsFile{path: filepath.Clean(path)}
+}
+
+// Path returns path of the file.
+func (f *StopWordsFile) Path() string {
+	return f.path
+}
+
+// Read returns stop words from the file. Missing file contains no stop words.
+func (f *StopWordsFile) Read() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines, err := f.lines()
+	if err != nil {
+		return nil, err
+	}
+
+	return stopWordsOf(lines), nil
+}
+
+// Add appends words missing from the file and returns all stop words.
+func (f *StopWordsFile) Add(words ...string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines, err := f.lines()
+	if err != nil {
+		return nil, err
+	}
+	current := stopWordsOf(lines)
+	for _, w := range words {
+		w = normalizeStopWord(w)
+		if w == "" || slices.Contains(current, w) {
+			continue
+		}
+		current = append(current, w)
+		lines = append(lines, w)
+	}
+
+	if err := f.write(lines); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// Remove deletes lines of words from the file and returns remaining stop words.
+func (f *StopWordsFile) Remove(words ...string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines, err := f.lines()
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[string]bool, len(words))
+	for _, w := range words {
+		remove[normalizeStopWord(w)] = true
+	}
+	lines = slices.DeleteFunc(lines, func(line string) bool {
+		w, ok := stopWordOf(line)
+
+		return ok && remove[w]
+	})
+
+	if err := f.write(lines); err != nil {
+		return nil, err
+	}
+
+	return stopWordsOf(lines), nil
+}
+
+// lines returns lines of the file, comments included. Missing file has no lines.
+func (f *StopWordsFile) lines() ([]string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+
+		return nil, fmt.Errorf("read stop words file: %w", err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+func (f *StopWordsFile) write(lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o750); err != nil {
+		return fmt.Errorf("mkdir all: %w", err)
+	}
+
+	b := new(strings.Builder)
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if err := os.WriteFile(f.path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("write stop words file: %w", err)
+	}
+
+	return nil
+}
+
+// stopWordOf returns stop word of line, false if line is empty or a comment.
+func stopWordOf(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+
+	return normalizeStopWord(line), true
+}
+
+// stopWordsOf returns distinct stop words of lines in their order.
+func stopWordsOf(lines []string) []string {
+	words := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if w, ok := stopWordOf(line); ok && !slices.Contains(words, w) {
+			words = append(words, w)
+		}
+	}
+
+	return words
+}
+
+// ReadStopWords reads stop words, one per line, skipping empty lines and '#' comments.
+func ReadStopWords(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner: %w", err)
+	}
+
+	return stopWordsOf(lines), nil
+}
+
+// LoadFilter creates filter from opts with stop words read from file added to opts.StopWords.
+// Nil file adds no stop words.
+func LoadFilter(file *StopWordsFile, opts FilterOptions) (*Filter, error) {
+	if file != nil {
+		words, err := file.Read()
+		if err != nil {
+			return nil, fmt.Errorf("read stop words: %w", err)
+		}
+		opts.StopWords = append(slices.Clone(opts.StopWords), words...)
+	}
+
+	f, err := NewFilter(opts)
+	if err != nil {
+		return nil, fmt.Errorf("new filter: %w", err)
+	}
+
+	return f, nil
+}
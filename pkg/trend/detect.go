@@ -0,0 +1,110 @@
+package trend
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Direction string
+
+const (
+	Rising  Direction = "rising"
+	Falling Direction = "falling"
+	Stable  Direction = "stable"
+)
+
+// Window compares mean share of the last Recent buckets to mean share of
+// Baseline buckets preceding them.
+type Window struct {
+	Bucket   Bucket
+	Recent   int
+	Baseline int
+	// Minimal relative change of mean share for a word to be rising or falling, 0.2 is 20%.
+	Threshold float64
+}
+
+// DefaultWindow compares last 4 weeks to 8 weeks before them.
+func DefaultWindow() Window {
+	return Window{
+		Bucket:    BucketWeek,
+		Recent:    4,
+		Baseline:  8,
+		Threshold: 0.2,
+	}
+}
+
+// Since returns start of the first baseline bucket of window ending at now.
+func (w Window) Since(now time.Time) time.Time {
+	return w.Bucket.Add(w.Bucket.Truncate(now), -(w.Recent + w.Baseline - 1))
+}
+
+// Trend of a word within window.
+type Trend struct {
+	Word      string    `json:"word"`
+	Direction Direction `json:"direction"`
+	// Mean share of recent buckets.
+	Recent float64 `json:"recent"`
+	// Mean share of baseline buckets.
+	Baseline float64 `json:"baseline"`
+	// Difference between recent and baseline share in percentage points.
+	Change float64 `json:"change"`
+}
+
+// Detect classifies series by comparing its last w.Recent points to w.Baseline points
+// before them. Word absent from baseline but present recently is rising.
+func (w Window) Detect(s Series) Trend {
+	points := s.Points
+	recent := points[max(0, len(points)-w.Recent):]
+	baseline := points[max(0, len(points)-w.Recent-w.Baseline) : len(points)-len(recent)]
+
+	t := Trend{
+		Word:      s.Word,
+		Direction: Stable,
+		Recent:    meanShare(recent),
+		Baseline:  meanShare(baseline),
+	}
+	t.Change = t.Recent - t.Baseline
+
+	switch {
+	case t.Baseline == 0 && t.Recent > 0:
+		t.Direction = Rising
+	case t.Baseline == 0:
+	case t.Change/t.Baseline >= w.Threshold:
+		t.Direction = Rising
+	case t.Change/t.Baseline <= -w.Threshold:
+		t.Direction = Falling
+	}
+
+	return t
+}
+
+// DetectAll returns trends of every series ordered by change, rising words first.
+func (w Window) DetectAll(series []Series) []Trend {
+	trends := make([]Trend, 0, len(series))
+	for _, s := range series {
+		trends = append(trends, w.Detect(s))
+	}
+	slices.SortFunc(trends, func(a, b Trend) int {
+		if c := cmp.Compare(b.Change, a.Change); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Word, b.Word)
+	})
+
+	return trends
+}
+
+func meanShare(points []Point) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range points {
+		sum += p.Share
+	}
+
+	return sum / float64(len(points))
+}
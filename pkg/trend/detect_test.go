@@ -0,0 +1,98 @@
+package trend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSeries(word string, shares ...float64) Series {
+	s := Series{Word: word}
+	for i, share := range shares {
+		s.Points = append(s.Points, Point{
+			Bucket: BucketWeek.Add(date(2024, time.January, 1), i),
+			Share:  share,
+		})
+	}
+
+	return s
+}
+
+func TestWindowDetect(t *testing.T) {
+	t.Parallel()
+
+	w := Window{Bucket: BucketWeek, Recent: 2, Baseline: 3, Threshold: 0.2}
+
+	testCases := []struct {
+		desc      string
+		series    Series
+		direction Direction
+		change    float64
+	}{
+		{
+			desc:      "rising",
+			series:    testSeries("go", 90, 10, 10, 10, 20, 20),
+			direction: Rising,
+			change:    10,
+		},
+		{
+			desc:      "falling",
+			series:    testSeries("php", 40, 40, 40, 10, 10),
+			direction: Falling,
+			change:    -30,
+		},
+		{
+			desc:      "stable",
+			series:    testSeries("java", 50, 50, 50, 55, 50),
+			direction: Stable,
+			change:    2.5,
+		},
+		{
+			desc:      "new_word",
+			series:    testSeries("zig", 0, 0, 0, 0, 5),
+			direction: Rising,
+			change:    2.5,
+		},
+		{
+			desc:      "absent_word",
+			series:    testSeries("cobol", 0, 0, 0, 0, 0),
+			direction: Stable,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			trend := w.Detect(tC.series)
+			require.Equal(t, tC.series.Word, trend.Word)
+			require.Equal(t, tC.direction, trend.Direction)
+			require.InDelta(t, tC.change, trend.Change, 0.0001)
+		})
+	}
+}
+
+func TestWindowDetectAll(t *testing.T) {
+	t.Parallel()
+
+	w := Window{Bucket: BucketWeek, Recent: 1, Baseline: 1, Threshold: 0.2}
+	trends := w.DetectAll([]Series{
+		testSeries("php", 40, 10),
+		testSeries("go", 10, 40),
+		testSeries("java", 20, 20),
+	})
+
+	words := make([]string, 0, len(trends))
+	for _, trend := range trends {
+		words = append(words, trend.Word)
+	}
+	require.Equal(t, []string{"go", "java", "php"}, words)
+}
+
+func TestWindowSince(t *testing.T) {
+	t.Parallel()
+
+	w := DefaultWindow()
+	now := time.Date(2024, time.May, 15, 13, 30, 0, 0, time.UTC)
+	require.Equal(t, date(2024, time.February, 26), w.Since(now))
+}
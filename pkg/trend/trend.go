@@ -0,0 +1,140 @@
+// Package trend builds time series of word counts and detects words which are rising
+// or falling by comparing recent buckets to a baseline.
+package trend
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Bucket is time span counts are grouped by. Values match PostgreSQL date_trunc fields.
+type Bucket string
+
+const (
+	BucketDay   Bucket = "day"
+	BucketWeek  Bucket = "week"
+	BucketMonth Bucket = "month"
+)
+
+var ErrUnknownBucket = errors.New("unknown bucket")
+
+// ParseBucket returns bucket named s, BucketWeek if s is empty.
+func ParseBucket(s string) (Bucket, error) {
+	switch b := Bucket(strings.ToLower(s)); b {
+	case "":
+		return BucketWeek, nil
+	case BucketDay, BucketWeek, BucketMonth:
+		return b, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownBucket, s)
+	}
+}
+
+// Truncate returns start of bucket t is in, in UTC. Weeks start on Monday like in PostgreSQL.
+func (b Bucket) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch b {
+	case BucketWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// Add moves start of bucket t by n buckets.
+func (b Bucket) Add(t time.Time, n int) time.Time {
+	switch b {
+	case BucketWeek:
+		return t.AddDate(0, 0, 7*n)
+	case BucketMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// Count of word in a single bucket.
+type Count struct {
+	Bucket time.Time
+	Word   string
+	// Occurrences of word.
+	Total int64
+	// Batches containing word.
+	Postings int64
+	// All batches created in bucket.
+	BucketPostings int64
+}
+
+// Point of a series.
+type Point struct {
+	Bucket   time.Time `json:"bucket"`
+	Total    int64     `json:"total"`
+	Postings int64     `json:"postings"`
+	// Percentage of batches created in bucket which contain the word.
+	Share float64 `json:"share"`
+}
+
+// Series of word counts, one point per bucket.
+type Series struct {
+	Word   string  `json:"word"`
+	Points []Point `json:"points"`
+}
+
+// Build groups counts into series of words, one point for every bucket from start of
+// bucket containing since to start of bucket containing until. Buckets without counts
+// are zero. Counts of words with the same canonical name are merged; canonical may be nil.
+//
+// Postings of merged words are summed, so a batch containing two names of the same
+// word is counted twice. Share is capped at 100%.
+func Build(counts []Count, b Bucket, since, until time.Time, canonical func(string) string) []Series {
+	if canonical == nil {
+		canonical = func(s string) string { return s }
+	}
+	from, to := b.Truncate(since), b.Truncate(until)
+
+	bucketPostings := make(map[time.Time]int64)
+	words := make(map[string]map[time.Time]Count)
+	for _, c := range counts {
+		key := b.Truncate(c.Bucket)
+		bucketPostings[key] = max(bucketPostings[key], c.BucketPostings)
+
+		word := canonical(c.Word)
+		if words[word] == nil {
+			words[word] = make(map[time.Time]Count)
+		}
+		merged := words[word][key]
+		merged.Total += c.Total
+		merged.Postings += c.Postings
+		words[word][key] = merged
+	}
+
+	series := make([]Series, 0, len(words))
+	for word, byBucket := range words {
+		s := Series{Word: word}
+		for t := from; !t.After(to); t = b.Add(t, 1) {
+			c := byBucket[t]
+			p := Point{
+				Bucket:   t,
+				Total:    c.Total,
+				Postings: c.Postings,
+			}
+			if total := bucketPostings[t]; total > 0 {
+				p.Share = min(100, float64(c.Postings)/float64(total)*100)
+			}
+			s.Points = append(s.Points, p)
+		}
+		series = append(series, s)
+	}
+	slices.SortFunc(series, func(a, b Series) int {
+		return strings.Compare(a.Word, b.Word)
+	})
+
+	return series
+}
@@ -0,0 +1,81 @@
+package trend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kndrad/piccrack/pkg/textproc"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseBucket(t *testing.T) {
+	t.Parallel()
+
+	b, err := ParseBucket("")
+	require.NoError(t, err)
+	require.Equal(t, BucketWeek, b)
+
+	b, err = ParseBucket("Month")
+	require.NoError(t, err)
+	require.Equal(t, BucketMonth, b)
+
+	_, err = ParseBucket("year")
+	require.ErrorIs(t, err, ErrUnknownBucket)
+}
+
+func TestBucketTruncate(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	ts := time.Date(2024, time.May, 15, 13, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc   string
+		bucket Bucket
+		want   time.Time
+	}{
+		{desc: "day", bucket: BucketDay, want: date(2024, time.May, 15)},
+		{desc: "week_starts_on_monday", bucket: BucketWeek, want: date(2024, time.May, 13)},
+		{desc: "month", bucket: BucketMonth, want: date(2024, time.May, 1)},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tC.want, tC.bucket.Truncate(ts))
+		})
+	}
+
+	// Sunday belongs to week started on previous Monday.
+	require.Equal(t, date(2024, time.May, 13), BucketWeek.Truncate(date(2024, time.May, 19)))
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	counts := []Count{
+		{Bucket: date(2024, time.May, 1), Word: "go", Total: 3, Postings: 2, BucketPostings: 4},
+		{Bucket: date(2024, time.May, 1), Word: "golang", Total: 1, Postings: 1, BucketPostings: 4},
+		{Bucket: date(2024, time.May, 1), Word: "rust", Total: 1, Postings: 1, BucketPostings: 4},
+		{Bucket: date(2024, time.July, 1), Word: "rust", Total: 2, Postings: 2, BucketPostings: 2},
+	}
+
+	series := Build(counts, BucketMonth, date(2024, time.May, 10), date(2024, time.July, 3), textproc.CanonicalSkill)
+	require.Len(t, series, 2)
+
+	goSeries := series[0]
+	require.Equal(t, "go", goSeries.Word)
+	require.Len(t, goSeries.Points, 3)
+	require.Equal(t, Point{Bucket: date(2024, time.May, 1), Total: 4, Postings: 3, Share: 75}, goSeries.Points[0])
+	require.Equal(t, Point{Bucket: date(2024, time.June, 1)}, goSeries.Points[1])
+	require.Equal(t, Point{Bucket: date(2024, time.July, 1)}, goSeries.Points[2])
+
+	rustSeries := series[1]
+	require.Equal(t, "rust", rustSeries.Word)
+	require.InDelta(t, 25, rustSeries.Points[0].Share, 0.0001)
+	require.InDelta(t, 100, rustSeries.Points[2].Share, 0.0001)
+}